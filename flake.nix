@@ -20,6 +20,7 @@
           grype = { pkg = pkgs.grype; url = "https://github.com/anchore/grype"; };
           syft = { pkg = pkgs.syft; url = "https://github.com/anchore/syft"; };
           govulncheck = { pkg = pkgs.govulncheck; url = "https://github.com/golang/vuln"; };
+          helm = { pkg = pkgs.kubernetes-helm; url = "https://github.com/helm/helm"; };
         };
 
         # Generate YAML entries for scanner packages
@@ -41,6 +42,7 @@
           version = "1.0.0";
           src = ./src;
           vendorHash = "sha256-tOucnwGew8snht4w5F+HET7cyAHF4k624hznsdS9NjY=";
+          ldflags = [ "-X main.allscanVersion=1.0.0" ];
 
           # Make scanners available at build time
           nativeBuildInputs = scanners;
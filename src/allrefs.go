@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// defaultMaxRefs bounds how many targets an all_tags or all_branches entry
+// expands into when max_refs is unset
+const defaultMaxRefs = 20
+
+// lsRemoteBranches lists a remote's branches. It is a variable so tests can
+// simulate remotes.
+var lsRemoteBranches = func(ctx context.Context, url string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", url)
+	setupGitAuth(cmd, url)
+	cmd.WaitDelay = time.Second
+	return cmd.Output()
+}
+
+// lsRemoteRef is a ref listed by git ls-remote
+type lsRemoteRef struct {
+	name string // without the refs/tags/ or refs/heads/ prefix
+	hash string // for annotated tags, the tagged commit
+}
+
+// parseLsRemoteRefs returns the refs under prefix (e.g. "refs/tags/") in
+// git ls-remote output, in the order listed. As in resolveFromLsRemote, an
+// annotated tag's ^{} line replaces its hash with the commit it points to.
+func parseLsRemoteRefs(output []byte, prefix string) []lsRemoteRef {
+	var refs []lsRemoteRef
+	index := make(map[string]int) // ref name → position in refs
+	derefHashes := make(map[string]string)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(parts[1], prefix)
+		if base, ok := strings.CutSuffix(name, "^{}"); ok {
+			derefHashes[base] = parts[0]
+			continue
+		}
+		if _, seen := index[name]; !seen {
+			index[name] = len(refs)
+			refs = append(refs, lsRemoteRef{name: name, hash: parts[0]})
+		}
+	}
+	for name, hash := range derefHashes {
+		if i, ok := index[name]; ok {
+			refs[i].hash = hash
+		}
+	}
+	return refs
+}
+
+// expandRefs turns an all_tags or all_branches entry into one target per
+// listed ref, keeping the first limit in ls-remote order (newest version
+// first for tags, by name for branches). Tag targets are pinned to the
+// tag's commit like resolved --repo targets; branch targets follow the
+// branch. total is the number of refs listed.
+func expandRefs(repo RepositoryConfig, refs []lsRemoteRef, tags bool, limit int) (targets []RepositoryConfig, total int) {
+	for _, ref := range refs {
+		if len(targets) == limit {
+			break
+		}
+		target := repo
+		target.AllTags, target.AllBranches, target.MaxRefs = false, false, 0
+		if tags {
+			target.Version = ref.name
+			target.Commit = ref.hash
+			if len(target.Commit) > 7 {
+				target.Commit = target.Commit[:7]
+			}
+		} else {
+			target.Branch = ref.name
+		}
+		targets = append(targets, target)
+	}
+	return targets, len(refs)
+}
+
+// expandAllRefs replaces each all_tags/all_branches entry by one target per
+// tag and/or branch of the remote, bounded by its max_refs. Invalid entries
+// are kept as they are, so the scan reports the problem. An entry whose
+// refs can't be listed, or that has none, falls back to branch main.
+func expandAllRefs(repos []RepositoryConfig) []RepositoryConfig {
+	expanded := make([]RepositoryConfig, 0, len(repos))
+	for _, repo := range repos {
+		if (!repo.AllTags && !repo.AllBranches) || ValidateRepositoryConfig(repo) != nil {
+			expanded = append(expanded, repo)
+			continue
+		}
+		limit := repo.MaxRefs
+		if limit == 0 {
+			limit = defaultMaxRefs
+		}
+
+		var targets []RepositoryConfig
+		for _, kind := range []struct {
+			enabled bool
+			tags    bool
+			label   string
+			prefix  string
+			list    func(context.Context, string) ([]byte, error)
+		}{
+			{repo.AllTags, true, "tags", "refs/tags/", lsRemoteTags},
+			{repo.AllBranches, false, "branches", "refs/heads/", lsRemoteBranches},
+		} {
+			if !kind.enabled {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), lsRemoteTimeout)
+			output, err := kind.list(ctx, repo.URL)
+			cancel()
+			if err != nil {
+				log.Printf("⚠️  Could not list %s for %s: %v", kind.label, repo.URL, err)
+				continue
+			}
+			refTargets, total := expandRefs(repo, parseLsRemoteRefs(output, kind.prefix), kind.tags, limit)
+			if total > len(refTargets) {
+				log.Printf("📉 %s: scanning the first %d of %d %s (max_refs)", repo.URL, len(refTargets), total, kind.label)
+			}
+			targets = append(targets, refTargets...)
+		}
+
+		if len(targets) == 0 {
+			log.Printf("ℹ️  No refs found for %s, using branch main", repo.URL)
+			target := repo
+			target.AllTags, target.AllBranches, target.MaxRefs = false, false, 0
+			target.Branch = "main"
+			targets = append(targets, target)
+		} else {
+			log.Printf("🏷️  Expanded %s into %d targets", repo.URL, len(targets))
+		}
+		expanded = append(expanded, targets...)
+	}
+	return expanded
+}
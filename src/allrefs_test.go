@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testLsRemoteTags = `ccc3333333333333333333333333333333333333	refs/tags/v2.0.0
+ddd4444444444444444444444444444444444444	refs/tags/v2.0.0^{}
+bbb2222222222222222222222222222222222222	refs/tags/v1.1.0
+aaa1111111111111111111111111111111111111	refs/tags/v1.0.0
+`
+
+const testLsRemoteBranches = `eee5555555555555555555555555555555555555	refs/heads/develop
+fff6666666666666666666666666666666666666	refs/heads/main
+`
+
+func TestExpandRefs(t *testing.T) {
+	repo := RepositoryConfig{URL: "https://github.com/org/repo", AllTags: true, MaxRefs: 2, Scanners: []string{"grype"}, Path: "api"}
+
+	tests := []struct {
+		name      string
+		output    string
+		prefix    string
+		tags      bool
+		limit     int
+		want      []RepositoryConfig
+		wantTotal int
+	}{
+		{
+			name:   "tags pinned to the dereferenced commit",
+			output: testLsRemoteTags, prefix: "refs/tags/", tags: true, limit: 10,
+			want: []RepositoryConfig{
+				{URL: repo.URL, Version: "v2.0.0", Commit: "ddd4444", Scanners: []string{"grype"}, Path: "api"},
+				{URL: repo.URL, Version: "v1.1.0", Commit: "bbb2222", Scanners: []string{"grype"}, Path: "api"},
+				{URL: repo.URL, Version: "v1.0.0", Commit: "aaa1111", Scanners: []string{"grype"}, Path: "api"},
+			},
+			wantTotal: 3,
+		},
+		{
+			name:   "limited to the newest tags",
+			output: testLsRemoteTags, prefix: "refs/tags/", tags: true, limit: 1,
+			want: []RepositoryConfig{
+				{URL: repo.URL, Version: "v2.0.0", Commit: "ddd4444", Scanners: []string{"grype"}, Path: "api"},
+			},
+			wantTotal: 3,
+		},
+		{
+			name:   "branches follow the branch",
+			output: testLsRemoteBranches, prefix: "refs/heads/", limit: 10,
+			want: []RepositoryConfig{
+				{URL: repo.URL, Branch: "develop", Scanners: []string{"grype"}, Path: "api"},
+				{URL: repo.URL, Branch: "main", Scanners: []string{"grype"}, Path: "api"},
+			},
+			wantTotal: 2,
+		},
+		{
+			name:   "no refs",
+			output: "", prefix: "refs/tags/", tags: true, limit: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := expandRefs(repo, parseLsRemoteRefs([]byte(tt.output), tt.prefix), tt.tags, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("expandRefs() = %+v, %d\nwant %+v, %d", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestExpandAllRefs(t *testing.T) {
+	origTags, origBranches := lsRemoteTags, lsRemoteBranches
+	t.Cleanup(func() { lsRemoteTags, lsRemoteBranches = origTags, origBranches })
+	lsRemoteTags = func(ctx context.Context, url string) ([]byte, error) {
+		if url == "https://github.com/org/unreachable" {
+			return nil, errors.New("exit status 128")
+		}
+		return []byte(testLsRemoteTags), nil
+	}
+	lsRemoteBranches = func(ctx context.Context, url string) ([]byte, error) {
+		return []byte(testLsRemoteBranches), nil
+	}
+
+	repos := []RepositoryConfig{
+		{URL: "https://github.com/org/plain", Branch: "main"},
+		{URL: "https://github.com/org/both", AllTags: true, AllBranches: true, MaxRefs: 2},
+		{URL: "https://github.com/org/unreachable", AllTags: true},
+		{URL: "https://github.com/org/invalid", AllTags: true, Version: "v1.0.0"},
+	}
+	var got []string
+	for _, repo := range expandAllRefs(repos) {
+		_, ref := repoRef(repo)
+		got = append(got, workspaceRepoName(repo.URL)+"@"+ref)
+	}
+
+	want := []string{
+		"org/plain@main",
+		"org/both@v2.0.0", "org/both@v1.1.0", "org/both@develop", "org/both@main",
+		"org/unreachable@main", // ls-remote failed: falls back to main
+		"org/invalid@v1.0.0",   // left for the scan to reject
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandAllRefs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// archiveDirName is the subdirectory of results_dir that archive_results
+// moves expired results into
+const archiveDirName = "archive"
+
+// archive_results values: move expired files as they are, or pack each
+// cleanup's files into one tarball
+const (
+	archiveFormatFiles = "files"
+	archiveFormatTarGz = "tar.gz"
+)
+
+// validateArchiveResults rejects unknown archive_results values
+func validateArchiveResults(format string) error {
+	switch format {
+	case "", archiveFormatFiles, archiveFormatTarGz:
+		return nil
+	}
+	return fmt.Errorf("archive_results must be %q or %q, got %q", archiveFormatFiles, archiveFormatTarGz, format)
+}
+
+// archiveOldFiles moves expired result files (paths relative to resultsDir,
+// SBOMs under sboms/) into the archive directory, keeping their layout, or
+// packs them into archive/results-<time>.tar.gz and removes them. Returns how
+// many files were archived; on a tarball error nothing is removed.
+func archiveOldFiles(resultsDir string, files []string, format string) (int, error) {
+	if len(files) == 0 {
+		return 0, nil
+	}
+	archiveDir := filepath.Join(resultsDir, archiveDirName)
+
+	if format == archiveFormatTarGz {
+		if err := os.MkdirAll(archiveDir, 0750); err != nil {
+			return 0, err
+		}
+		tarball := filepath.Join(archiveDir, "results-"+time.Now().Format("20060102-150405")+".tar.gz")
+		if err := writeArchiveTarball(tarball, resultsDir, files); err != nil {
+			os.Remove(tarball)
+			return 0, err
+		}
+		for _, name := range files {
+			os.Remove(filepath.Join(resultsDir, name))
+		}
+		return len(files), nil
+	}
+
+	moved := 0
+	for _, name := range files {
+		dest := filepath.Join(archiveDir, name)
+		if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+			return moved, err
+		}
+		if err := os.Rename(filepath.Join(resultsDir, name), dest); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// writeArchiveTarball writes files (relative to dir) into a gzipped tarball,
+// keeping their modification times
+func writeArchiveTarball(path, dir string, files []string) error {
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range files {
+		if err := addTarFile(tw, filepath.Join(dir, name), filepath.ToSlash(name)); err != nil {
+			return fmt.Errorf("archiving %s: %w", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// addTarFile writes one file into a tarball under name
+func addTarFile(tw *tar.Writer, path, name string) error {
+	src, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+// extractArchiveTarball unpacks an archive tarball into dir. Entries that
+// aren't regular files or would land outside dir are skipped.
+func extractArchiveTarball(path, dir string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if rel, err := filepath.Rel(dir, dest); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0750); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(filepath.Clean(dest), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		os.Chtimes(dest, header.ModTime, header.ModTime)
+	}
+}
+
+// archivedResultDirs returns the directories holding the archived results of
+// resultsDir: the archive directory itself, plus a temporary directory per
+// tarball it holds, unpacked. cleanup removes the temporary directories.
+func archivedResultDirs(resultsDir string) (dirs []string, cleanup func(), err error) {
+	archiveDir := filepath.Join(resultsDir, archiveDirName)
+	var tempDirs []string
+	cleanup = func() {
+		for _, dir := range tempDirs {
+			os.RemoveAll(dir)
+		}
+	}
+
+	entries, err := os.ReadDir(archiveDir)
+	if os.IsNotExist(err) {
+		return nil, cleanup, nil
+	}
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("reading archive: %w", err)
+	}
+	dirs = append(dirs, archiveDir)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tar.gz") {
+			continue
+		}
+		tempDir, err := os.MkdirTemp("", "allscan-archive-")
+		if err != nil {
+			cleanup()
+			return nil, func() {}, err
+		}
+		tempDirs = append(tempDirs, tempDir)
+		if err := extractArchiveTarball(filepath.Join(archiveDir, entry.Name()), tempDir); err != nil {
+			log.Printf("⚠️  Skipping archive %s: %v", entry.Name(), err)
+			continue
+		}
+		dirs = append(dirs, tempDir)
+	}
+	return dirs, cleanup, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCleanupOldResultsArchive(t *testing.T) {
+	grypeOutput := `{"matches": [{"vulnerability": {"id": "CVE-1", "severity": "Critical"}}]}`
+
+	for _, format := range []string{archiveFormatFiles, archiveFormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			resultsDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(resultsDir, "sboms"), 0750); err != nil {
+				t.Fatal(err)
+			}
+
+			old := time.Now().Add(-resultsMaxAge - time.Hour)
+			oldFiles := []string{"widget_abc1234_grype_20240101.json", "sboms/widget_abc1234.cdx.json"}
+			for _, name := range oldFiles {
+				path := filepath.Join(resultsDir, filepath.FromSlash(name))
+				if err := os.WriteFile(path, []byte(grypeOutput), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatal(err)
+				}
+			}
+			newFile := filepath.Join(resultsDir, "gadget_def5678_grype_20240201.json")
+			if err := os.WriteFile(newFile, []byte(`{"matches": []}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cleanupOldResults(resultsDir, format)
+
+			for _, name := range oldFiles {
+				if _, err := os.Stat(filepath.Join(resultsDir, filepath.FromSlash(name))); !os.IsNotExist(err) {
+					t.Errorf("%s still in the results directory", name)
+				}
+			}
+			if _, err := os.Stat(newFile); err != nil {
+				t.Errorf("recent result was archived: %v", err)
+			}
+
+			archiveDir := filepath.Join(resultsDir, archiveDirName)
+			if format == archiveFormatFiles {
+				for _, name := range oldFiles {
+					info, err := os.Stat(filepath.Join(archiveDir, filepath.FromSlash(name)))
+					if err != nil {
+						t.Errorf("%s not in the archive: %v", name, err)
+					} else if !info.ModTime().Equal(old) {
+						t.Errorf("%s modification time = %v, want %v", name, info.ModTime(), old)
+					}
+				}
+			} else {
+				tarballs, _ := filepath.Glob(filepath.Join(archiveDir, "results-*.tar.gz"))
+				if len(tarballs) != 1 {
+					t.Fatalf("got tarballs %v, want one", tarballs)
+				}
+			}
+
+			// Archived results can still be read back for a report
+			dirs, cleanup, err := archivedResultDirs(resultsDir)
+			if err != nil {
+				t.Fatalf("archivedResultDirs: %v", err)
+			}
+			defer cleanup()
+			config := &Config{Scanners: []ScannerConfig{{Name: "grype"}}}
+			contexts, err := collectResultFiles(config, resultsDir, dirs...)
+			if err != nil {
+				t.Fatalf("collectResultFiles: %v", err)
+			}
+			if len(contexts) != 2 {
+				t.Fatalf("got %d contexts, want 2 (gadget, archived widget)", len(contexts))
+			}
+			widget := contexts[1]
+			if len(widget.Results) != 1 || !widget.Results[0].Success {
+				t.Fatalf("archived widget results = %+v, want one successful result", widget.Results)
+			}
+			if summary, _ := parseScanOutput(widget.Results[0]); summary.Critical != 1 {
+				t.Errorf("archived result critical = %d, want 1", summary.Critical)
+			}
+		})
+	}
+}
+
+func TestArchivedResultDirsWithoutArchive(t *testing.T) {
+	dirs, cleanup, err := archivedResultDirs(t.TempDir())
+	defer cleanup()
+	if err != nil || len(dirs) != 0 {
+		t.Errorf("archivedResultDirs() = %v, %v; want no dirs and no error", dirs, err)
+	}
+}
+
+func TestExtractArchiveTarballSkipsEscapingPaths(t *testing.T) {
+	dir := t.TempDir()
+	tarball := filepath.Join(dir, "results.tar.gz")
+	f, err := os.Create(tarball)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"../escaped.json", "kept.json"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 2, Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	dest := filepath.Join(dir, "out")
+	if err := os.MkdirAll(dest, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractArchiveTarball(tarball, dest); err != nil {
+		t.Fatalf("extractArchiveTarball: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "kept.json")); err != nil {
+		t.Errorf("kept.json not extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.json")); !os.IsNotExist(err) {
+		t.Error("entry escaping the destination was extracted")
+	}
+}
+
+func TestValidateArchiveResults(t *testing.T) {
+	for _, format := range []string{"", "files", "tar.gz"} {
+		if err := validateArchiveResults(format); err != nil {
+			t.Errorf("validateArchiveResults(%q) = %v, want nil", format, err)
+		}
+	}
+	if err := validateArchiveResults("zip"); err == nil || !strings.Contains(err.Error(), "archive_results") {
+		t.Errorf("validateArchiveResults(\"zip\") = %v, want an archive_results error", err)
+	}
+}
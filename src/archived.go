@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// githubAPIURL is the GitHub REST API base URL (replaced in tests)
+var githubAPIURL = "https://api.github.com"
+
+// repoMetadata holds the GitHub repository flags that decide whether a repo
+// is worth scanning
+type repoMetadata struct {
+	Archived bool `json:"archived"`
+	Fork     bool `json:"fork"`
+}
+
+// fetchRepoMetadata reads a repository's archived and fork flags from the
+// GitHub API. Like language detection it needs GITHUB_TOKEN or a GitHub App.
+func fetchRepoMetadata(repoURL string) (*repoMetadata, error) {
+	owner, repo, ok := parseGitHubURL(repoURL)
+	if !ok {
+		return nil, fmt.Errorf("not a GitHub URL: %s", repoURL)
+	}
+
+	token, err := githubToken()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/repos/%s/%s", githubAPIURL, owner, repo), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var meta repoMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	return &meta, nil
+}
+
+// repoSkipReason returns why a repository with the given metadata should not
+// be scanned, or "" to scan it. Archived repos are skipped unless
+// includeArchived; forks only when skipForks.
+func repoSkipReason(meta repoMetadata, includeArchived, skipForks bool) string {
+	switch {
+	case meta.Archived && !includeArchived:
+		return "archived on GitHub (use --include-archived to scan it)"
+	case meta.Fork && skipForks:
+		return "fork on GitHub (skip_forks is set)"
+	}
+	return ""
+}
+
+// skipArchivedRepos drops GitHub repositories that are archived (unless
+// --include-archived, or given explicitly with --repo or --purl) or forks
+// (with skip_forks), logging each one skipped.
+// Repos whose metadata can't be fetched (not on GitHub, no GitHub auth, API
+// errors) are kept, so the check never stops a scan.
+func skipArchivedRepos(targets []RepositoryConfig, global GlobalConfig) []RepositoryConfig {
+	if global.IncludeArchived && !global.SkipForks {
+		return targets
+	}
+	if !githubAuthConfigured() {
+		if global.SkipForks {
+			log.Printf("⚠️  GITHUB_TOKEN not set: forked repositories are not skipped")
+		}
+		return targets
+	}
+
+	kept := make([]RepositoryConfig, 0, len(targets))
+	for _, repo := range targets {
+		if _, _, ok := parseGitHubURL(repo.URL); !ok {
+			kept = append(kept, repo)
+			continue
+		}
+		meta, err := fetchRepoMetadata(repo.URL)
+		if err != nil {
+			log.Printf("⚠️  Could not check whether %s is archived: %v", repo.URL, err)
+			kept = append(kept, repo)
+			continue
+		}
+		if reason := repoSkipReason(*meta, global.IncludeArchived || repo.Explicit, global.SkipForks); reason != "" {
+			log.Printf("⏭️  Skipping %s: %s", repo.URL, reason)
+			continue
+		}
+		kept = append(kept, repo)
+	}
+	return kept
+}
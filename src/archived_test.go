@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRepoSkipReason(t *testing.T) {
+	tests := []struct {
+		name            string
+		meta            repoMetadata
+		includeArchived bool
+		skipForks       bool
+		wantSkip        bool
+	}{
+		{name: "active repo", meta: repoMetadata{}, wantSkip: false},
+		{name: "archived skipped by default", meta: repoMetadata{Archived: true}, wantSkip: true},
+		{name: "archived included", meta: repoMetadata{Archived: true}, includeArchived: true, wantSkip: false},
+		{name: "fork scanned by default", meta: repoMetadata{Fork: true}, wantSkip: false},
+		{name: "fork skipped with skip_forks", meta: repoMetadata{Fork: true}, skipForks: true, wantSkip: true},
+		{name: "archived fork included but skip_forks", meta: repoMetadata{Archived: true, Fork: true}, includeArchived: true, skipForks: true, wantSkip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := repoSkipReason(tt.meta, tt.includeArchived, tt.skipForks)
+			if (reason != "") != tt.wantSkip {
+				t.Errorf("repoSkipReason(%+v) = %q, want skip %v", tt.meta, reason, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestSkipArchivedRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/old":
+			w.Write([]byte(`{"archived": true, "fork": false}`))
+		case "/repos/org/forked":
+			w.Write([]byte(`{"archived": false, "fork": true}`))
+		case "/repos/org/active":
+			w.Write([]byte(`{"archived": false, "fork": false}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	origURL := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = origURL })
+
+	targets := []RepositoryConfig{
+		{URL: "https://github.com/org/old"},
+		{URL: "https://github.com/org/forked"},
+		{URL: "https://github.com/org/active"},
+		{URL: "https://github.com/org/missing"},                                 // API error: kept
+		{URL: "https://gitlab.com/org/other"},                                   // not on GitHub: kept
+		{URL: "https://github.com/org/old", Branch: "explicit", Explicit: true}, // --repo: opted in
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		global GlobalConfig
+		want   []string
+	}{
+		{
+			name:  "archived skipped by default",
+			token: "test-token",
+			want:  []string{"forked", "active", "missing", "other", "old"},
+		},
+		{
+			name:   "skip forks too",
+			token:  "test-token",
+			global: GlobalConfig{SkipForks: true},
+			want:   []string{"active", "missing", "other", "old"},
+		},
+		{
+			name:   "include archived",
+			token:  "test-token",
+			global: GlobalConfig{IncludeArchived: true},
+			want:   []string{"old", "forked", "active", "missing", "other", "old"},
+		},
+		{
+			name:   "no token keeps everything",
+			global: GlobalConfig{SkipForks: true},
+			want:   []string{"old", "forked", "active", "missing", "other", "old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.token)
+			var got []string
+			for _, repo := range skipArchivedRepos(targets, tt.global) {
+				got = append(got, repo.URL[strings.LastIndex(repo.URL, "/")+1:])
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"allscan/parsers"
+)
+
+// runBuiltinScanner runs a scanner implemented inside allscan (a "builtin:"
+// command) and returns a short note on what it found for the completion log,
+// or "" when there is nothing to report. Paths matched by ignore (the repo's
+// .allscanignore) are skipped.
+func runBuiltinScanner(scanner ScannerConfig, args []string, repoPath, outputPath string, sarifMode bool, ignore *parsers.IgnorePatterns) (string, error) {
+	switch scanner.Command {
+	case "builtin:binary-detector":
+		count, err := parsers.RunBinaryDetector(repoPath, outputPath, sarifMode, ignore, followSymlinks)
+		if err != nil || count == 0 {
+			return "", err
+		}
+		return fmt.Sprintf("found %d binaries", count), nil
+	case "builtin:kubernetes-policy-checker":
+		return runKubernetesPolicyChecker(args, repoPath, outputPath, sarifMode, ignore, scanner.timeout)
+	case "builtin:sensitive-files":
+		opts, err := sensitiveFilesArgs(args)
+		if err != nil {
+			return "", err
+		}
+		opts.SarifMode = sarifMode
+		opts.Ignore = ignore
+		opts.FollowSymlinks = followSymlinks
+		count, err := parsers.RunSensitiveFilesDetector(repoPath, outputPath, opts)
+		if err != nil || count == 0 {
+			return "", err
+		}
+		return fmt.Sprintf("found %d sensitive files", count), nil
+	default:
+		return "", fmt.Errorf("unknown built-in scanner %s", scanner.Command)
+	}
+}
+
+// runKubernetesPolicyChecker checks the repo's Kubernetes manifests and Helm
+// charts, rendering each chart within the scanner's timeout. The only
+// supported arg is "--policy <file>", a YAML file that disables built-in
+// rules or changes their severity.
+func runKubernetesPolicyChecker(args []string, repoPath, outputPath string, sarifMode bool, ignore *parsers.IgnorePatterns, timeout time.Duration) (string, error) {
+	if sarifMode {
+		return "", fmt.Errorf("SARIF output not supported")
+	}
+
+	renderChart := func(chartDir string) ([]byte, error) { return renderHelmChart(chartDir, timeout) }
+	opts := parsers.KubernetesCheckOptions{RenderChart: renderChart, Ignore: ignore, FollowSymlinks: followSymlinks}
+	policyPath, err := kubernetesPolicyArg(args)
+	if err != nil {
+		return "", err
+	}
+	if policyPath != "" {
+		policy, err := parsers.LoadKubernetesPolicy(policyPath)
+		if err != nil {
+			return "", fmt.Errorf("loading policy: %w", err)
+		}
+		opts.Policy = policy
+	}
+
+	output, err := parsers.RunKubernetesPolicyChecker(repoPath, outputPath, opts)
+	if err != nil {
+		return "", err
+	}
+	for _, chart := range output.SkippedCharts {
+		log.Printf("    ⚠️  Skipped Helm chart %s: %s", chart.Path, chart.Reason)
+	}
+	if output.Total == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("found %d policy violations", output.Total), nil
+}
+
+// kubernetesPolicyArg extracts the --policy file from the checker's args
+func kubernetesPolicyArg(args []string) (string, error) {
+	var policy string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--policy" && i+1 < len(args):
+			policy = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--policy="):
+			policy = strings.TrimPrefix(args[i], "--policy=")
+		default:
+			return "", fmt.Errorf("unsupported argument %q (only --policy <file> is supported)", args[i])
+		}
+	}
+	return policy, nil
+}
+
+// sensitiveFilesArgs builds the sensitive-files detector's patterns from its
+// args: "--pattern <glob>" adds a sensitive filename pattern, "--exclude
+// <glob>" a pattern never flagged, and "--no-default-patterns" drops the
+// built-in patterns (not the built-in excludes).
+func sensitiveFilesArgs(args []string) (parsers.SensitiveFilesOptions, error) {
+	var patterns, excludes []string
+	useDefaults := true
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--no-default-patterns":
+			useDefaults = false
+		case (args[i] == "--pattern" || args[i] == "--exclude") && i+1 < len(args):
+			if args[i] == "--pattern" {
+				patterns = append(patterns, args[i+1])
+			} else {
+				excludes = append(excludes, args[i+1])
+			}
+			i++
+		case strings.HasPrefix(args[i], "--pattern="):
+			patterns = append(patterns, strings.TrimPrefix(args[i], "--pattern="))
+		case strings.HasPrefix(args[i], "--exclude="):
+			excludes = append(excludes, strings.TrimPrefix(args[i], "--exclude="))
+		default:
+			return parsers.SensitiveFilesOptions{}, fmt.Errorf("unsupported argument %q (supported: --pattern <glob>, --exclude <glob>, --no-default-patterns)", args[i])
+		}
+	}
+
+	opts := parsers.SensitiveFilesOptions{Excludes: append(append([]string{}, parsers.DefaultSensitiveExcludes...), excludes...)}
+	if useDefaults {
+		opts.Patterns = append(opts.Patterns, parsers.DefaultSensitivePatterns...)
+	}
+	opts.Patterns = append(opts.Patterns, patterns...)
+	if len(opts.Patterns) == 0 {
+		return opts, fmt.Errorf("--no-default-patterns needs at least one --pattern")
+	}
+	for _, pattern := range append(opts.Patterns, opts.Excludes...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return opts, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"allscan/parsers"
+)
+
+func TestKubernetesPolicyArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"no args", nil, "", false},
+		{"separate value", []string{"--policy", "k8s-policy.yaml"}, "k8s-policy.yaml", false},
+		{"inline value", []string{"--policy=k8s-policy.yaml"}, "k8s-policy.yaml", false},
+		{"unsupported flag", []string{"--strict"}, "", true},
+		{"missing value", []string{"--policy"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kubernetesPolicyArg(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("kubernetesPolicyArg(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("kubernetesPolicyArg(%v) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensitiveFilesArgs(t *testing.T) {
+	defaults := len(parsers.DefaultSensitivePatterns)
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantPatterns int
+		wantExcludes int
+		wantErr      bool
+	}{
+		{"no args", nil, defaults, len(parsers.DefaultSensitiveExcludes), false},
+		{"extra pattern", []string{"--pattern", "secrets.yml", "--pattern=*.ovpn"}, defaults + 2, len(parsers.DefaultSensitiveExcludes), false},
+		{"extra exclude", []string{"--exclude", "testdata/*.pem"}, defaults, len(parsers.DefaultSensitiveExcludes) + 1, false},
+		{"replace defaults", []string{"--no-default-patterns", "--pattern", ".env"}, 1, len(parsers.DefaultSensitiveExcludes), false},
+		{"no patterns left", []string{"--no-default-patterns"}, 0, 0, true},
+		{"invalid pattern", []string{"--pattern", "[.env"}, 0, 0, true},
+		{"unsupported flag", []string{"--policy", "x.yaml"}, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := sensitiveFilesArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sensitiveFilesArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(opts.Patterns) != tt.wantPatterns || len(opts.Excludes) != tt.wantExcludes {
+				t.Errorf("sensitiveFilesArgs(%v) = %d patterns, %d excludes; want %d, %d",
+					tt.args, len(opts.Patterns), len(opts.Excludes), tt.wantPatterns, tt.wantExcludes)
+			}
+		})
+	}
+}
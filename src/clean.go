@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runClean implements --clean: it deletes every repository clone in the
+// workspace and the scan results and SBOMs older than resultsMaxAge (all of
+// them with --all; archive_results still archives instead of deleting), then
+// logs and returns how many bytes were freed
+func runClean(config *Config, all bool) int64 {
+	workspace, resultsDir := config.Global.Workspace, config.Global.ResultsDir
+	before := dirSize(workspace) + dirSize(resultsDir)
+
+	if removed := removeWorkspaceClones(workspace); removed > 0 {
+		log.Printf("🧹 Removed %d clone(s) from %s", removed, workspace)
+	}
+	cutoff := time.Now().Add(-resultsMaxAge)
+	if all {
+		cutoff = time.Now().Add(time.Minute) // every existing file, allowing for clock skew
+	}
+	cleanupResultsBefore(resultsDir, config.Global.ArchiveResults, cutoff)
+
+	freed := before - (dirSize(workspace) + dirSize(resultsDir))
+	if freed < 0 {
+		freed = 0 // archive tarballs can outgrow what they replace
+	}
+	log.Printf("🧹 Freed %d bytes (%.1f MiB)", freed, float64(freed)/(1024*1024))
+	return freed
+}
+
+// removeWorkspaceClones deletes every <owner>/<repo> git clone in the
+// workspace, whichever run made it, and the owner directories left empty.
+// Anything else in the workspace is left alone, so a workspace pointed at a
+// shared directory by mistake isn't wiped.
+func removeWorkspaceClones(workspace string) int {
+	removed := 0
+	owners, err := os.ReadDir(workspace)
+	if err != nil {
+		return 0
+	}
+	for _, owner := range owners {
+		if !owner.IsDir() {
+			continue
+		}
+		ownerDir := filepath.Join(workspace, owner.Name())
+		repos, err := os.ReadDir(ownerDir)
+		if err != nil {
+			continue
+		}
+		for _, repo := range repos {
+			repoPath := filepath.Join(ownerDir, repo.Name())
+			if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+				continue
+			}
+			if err := os.RemoveAll(repoPath); err != nil {
+				log.Printf("⚠️  Failed to remove clone %s: %v", repoPath, err)
+				continue
+			}
+			removed++
+		}
+		os.Remove(ownerDir) // fails harmlessly while the owner dir isn't empty
+	}
+	return removed
+}
+
+// dirSize returns the total size of the regular files under dir, or 0 when
+// it doesn't exist
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunClean(t *testing.T) {
+	tests := []struct {
+		name string
+		all  bool
+	}{
+		{name: "old results only"},
+		{name: "all results", all: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			workspace, resultsDir := filepath.Join(dir, "workspace"), filepath.Join(dir, "results")
+			old := time.Now().Add(-resultsMaxAge - time.Hour)
+			files := map[string]bool{ // path -> want removed
+				"workspace/org/a/.git/HEAD":              true,
+				"workspace/org/a/main.go":                true,
+				"workspace/other/b/.git/HEAD":            true,
+				"workspace/notes.txt":                    false, // not a clone
+				"workspace/cache/data/file":              false, // no .git
+				"results/grype_old.json":                 true,
+				"results/org/api/gosec_old.json":         true,
+				"results/sboms/app_old.cdx.json":         true,
+				"results/grype_new.json":                 tt.all,
+				"results/sboms/app_new.cdx.json":         tt.all,
+				"results/history.txt":                    false,
+				"workspace/org/a/.git/objects/pack/data": true,
+			}
+			var wantFreed int64
+			for name, removed := range files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+					t.Fatal(err)
+				}
+				content := strings.Repeat("x", 100)
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if strings.Contains(name, "_old.") {
+					if err := os.Chtimes(path, old, old); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if removed {
+					wantFreed += int64(len(content))
+				}
+			}
+
+			config := &Config{Global: GlobalConfig{Workspace: workspace, ResultsDir: resultsDir}}
+			if freed := runClean(config, tt.all); freed != wantFreed {
+				t.Errorf("runClean() freed %d bytes, want %d", freed, wantFreed)
+			}
+
+			for name, wantRemoved := range files {
+				_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+				if removed := os.IsNotExist(err); removed != wantRemoved {
+					t.Errorf("%s removed = %v, want %v", name, removed, wantRemoved)
+				}
+			}
+			for _, owner := range []string{"org", "other"} {
+				if _, err := os.Stat(filepath.Join(workspace, owner)); !os.IsNotExist(err) {
+					t.Errorf("emptied owner dir %s not removed", owner)
+				}
+			}
+		})
+	}
+
+	t.Run("missing directories", func(t *testing.T) {
+		dir := t.TempDir()
+		config := &Config{Global: GlobalConfig{Workspace: filepath.Join(dir, "none"), ResultsDir: filepath.Join(dir, "none-either")}}
+		if freed := runClean(config, true); freed != 0 {
+			t.Errorf("runClean() freed %d bytes, want 0", freed)
+		}
+	})
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"allscan/parsers"
 )
 
 // commitHashPattern matches valid git commit hashes (7-40 hex characters)
@@ -18,19 +27,108 @@ type Config struct {
 	Global       GlobalConfig       `yaml:"global"`
 	Scanners     []ScannerConfig    `yaml:"scanners"`
 	Repositories []RepositoryConfig `yaml:"repositories"`
+
+	configHash string // sha256 of the config files as read, recorded in report metadata
+}
+
+// defaultCoverageScanTypes are the coverage matrix columns used when
+// coverage_scan_types is not set
+var defaultCoverageScanTypes = []string{"SCA", "SAST", "Reachability", "Binary"}
+
+// severityKeys are the internal severities that severity_labels and
+// severity_icons can rename, most severe first
+var severityKeys = []string{"critical", "high", "medium", "low", "info"}
+
+// defaultSeverityLabels are the severity names shown in the summary when
+// severity_labels doesn't override them
+var defaultSeverityLabels = map[string]string{
+	"critical": "Critical",
+	"high":     "High",
+	"medium":   "Medium",
+	"low":      "Low",
+	"info":     "Info",
 }
 
 // GlobalConfig holds global settings for the scanner orchestrator
 type GlobalConfig struct {
-	Workspace       string `yaml:"workspace"`
-	ResultsDir      string `yaml:"results_dir"`
-	UploadEndpoint  string `yaml:"upload_endpoint"`
-	MaxConcurrent   int    `yaml:"max_concurrent"`
-	FailFast        bool   `yaml:"fail_fast"`
-	ProductOverride     string   `yaml:"-"` // CLI-only: overrides auto-detected product name for DefectDojo
-	ProductTypeOverride string   `yaml:"-"` // CLI-only: overrides product_type_name for DefectDojo
-	SarifMode           bool     `yaml:"-"` // CLI-only: output scan results in SARIF format
-	ScanFilter          []string `yaml:"-"` // CLI-only: run only these scanners (overrides enabled status)
+	Workspace                 string              `yaml:"workspace"`
+	WorkspaceCleanupAfterScan bool                `yaml:"workspace_cleanup_after_scan"` // Delete each clone once its scanners finish (default keeps clones as a cache)
+	ResultsDir                string              `yaml:"results_dir"`
+	UploadEndpoint            string              `yaml:"upload_endpoint"`
+	UploadTargets             []UploadTarget      `yaml:"upload_targets"` // Several DefectDojo instances, each for the repos matching its repo_filter (replaces upload_endpoint)
+	MaxConcurrent             int                 `yaml:"max_concurrent"`
+	FailFast                  bool                `yaml:"fail_fast"`
+	Subprojects               bool                `yaml:"subprojects"` // Scan each manifest-rooted sub-project separately (monorepos)
+	Dojo                      DojoConfig          `yaml:"dojo"`
+	GitHubApp                 GitHubAppConfig     `yaml:"github_app"` // Authenticate GitHub API calls as an App installation instead of with GITHUB_TOKEN
+	TLSSkipVerify             bool                `yaml:"tls_skip_verify"`     // Disable TLS certificate verification for uploads (insecure)
+	TLSCACert                 string              `yaml:"tls_ca_cert"`         // Path to a PEM CA certificate used to verify the upload endpoint
+	TLSClientCert             string              `yaml:"tls_client_cert"`     // Path to a PEM client certificate for upload endpoints behind mTLS
+	TLSClientKey              string              `yaml:"tls_client_key"`      // Path to the PEM private key of tls_client_cert
+	ScannerBundles            map[string][]string `yaml:"scanner_bundles"`     // Named scanner lists that repos can select with "bundle"
+	PostRunCommand            []string            `yaml:"post_run_command"`    // Command run once after the run; supports {{report}} and {{results}}
+	PostRunTimeout            string              `yaml:"post_run_timeout"`    // Timeout for post_run_command (default 5m)
+	CoverageScanTypes         []string            `yaml:"coverage_scan_types"` // Scan types shown as columns in the language coverage matrix
+	SeverityLabels            map[string]string   `yaml:"severity_labels"`     // Display names for critical/high/medium/low/info in the summary (e.g. critical: Blocker)
+	SeverityIcons             map[string]string   `yaml:"severity_icons"`      // Display icons for the same severities, replacing the --theme markers
+	MinLanguagePercent        float64             `yaml:"min_language_percent"` // Ignore detected languages below this share of the repo for scanner selection (0 = keep all)
+	LanguageDetectionWorkers  int                 `yaml:"language_detection_workers"` // Goroutines walking large repos for filesystem language detection (0 = one per CPU, 1 = sequential)
+	DetectionIgnore           []string            `yaml:"detection_ignore"`    // .gitignore-style patterns of generated files filesystem language detection doesn't count (default: defaultDetectionIgnore)
+	FollowSymlinks            bool                `yaml:"follow_symlinks"`     // Descend into symlinked directories inside the repo during language detection and binary-detector (never outside it)
+	FindingHistory            string              `yaml:"finding_history"`     // Path of the first-seen store used to report finding ages (disabled when empty)
+	LsRemoteTimeout           string              `yaml:"ls_remote_timeout"`   // Timeout for resolving a repo's latest tag with git ls-remote (default 30s)
+	lsRemoteTimeout           time.Duration       // parsed ls_remote_timeout (unexported)
+	GrypeDBPrefetch           bool                `yaml:"grype_db_prefetch"`         // Run "grype db update" once before scanning and disable auto-update for scanners
+	GrypeDBPrefetchTimeout    string              `yaml:"grype_db_prefetch_timeout"` // Timeout for the grype DB prefetch (default 5m)
+	grypeDBPrefetchTimeout    time.Duration       // parsed grype_db_prefetch_timeout (unexported)
+	RequireCoverage           bool                `yaml:"require_coverage"`         // Fail the run when a target ends up with no scanner actually run
+	TopFindings               int                 `yaml:"top_findings"`             // List up to N Critical/High findings per repo in the summary (0 = off)
+	MergeDuplicateRepos       bool                `yaml:"merge_duplicate_repos"`    // Keep the first of repo entries with the same URL (and path) instead of failing
+	MaxFindingsPerScanner     int                 `yaml:"max_findings_per_scanner"` // Fail a scanner result with more findings than this, as likely misconfigured (0 = unlimited)
+	MinScorecardScore         float64             `yaml:"min_scorecard_score"`      // Fail the run when a target's overall OpenSSF Scorecard score is below this (0 = disabled)
+	ScorecardExcludeChecks    []string            `yaml:"scorecard_exclude_checks"` // Scorecard checks (e.g. CII-Best-Practices) not counted as findings or shown in the report
+	GrypeExcludeDevDeps       bool                `yaml:"grype_exclude_dev_deps"`   // Leave grype matches in dev-scoped dependencies out of the counts (the summary still shows "dev: N")
+	OfflineDBDir              string              `yaml:"offline_db_dir"`           // Pre-downloaded grype DB for air-gapped runs; disables grype/syft update checks
+	ArchiveResults            string              `yaml:"archive_results"`          // Move results past the 7-day cleanup into results_dir/archive ("files") or a tarball there ("tar.gz") instead of deleting them
+	ContainerRuntime          string              `yaml:"container_runtime"`        // Runtime for scanners with an image: docker (default) or podman
+	RecordCommands            bool                `yaml:"record_commands"`          // Record each scanner's executed command (secrets redacted) and tool version in the run report
+	RedactSecrets             bool                `yaml:"redact_secrets"`           // Replace secret values in Secrets scanner output with **** before parsing/upload
+	SBOMUpload                bool                `yaml:"sbom_upload"`              // Upload each target's SBOM to DefectDojo as a CycloneDX scan
+	SBOMDeterministicNames    bool                `yaml:"sbom_deterministic_names"` // Omit the date from SBOM filenames so a commit always maps to the same file
+	SkipForks                 bool                `yaml:"skip_forks"`               // Skip repositories that are forks on GitHub (needs GITHUB_TOKEN)
+	ResultNameTemplate        string              `yaml:"result_name_template"`     // Result filename without extension; tokens {repo} {scanner} {commit} {branch} {ref} {date}
+	ResultsLayout             string              `yaml:"results_layout"`           // "flat" (default): results directly in results_dir; "per-repo": under results_dir/<owner>/<repo>/
+	StrictConfig              bool                `yaml:"strict_config"`            // Fail on unknown keys in scanners.yaml instead of warning (same as --strict-config)
+	ProductOverride           string              `yaml:"-"`                        // CLI-only: overrides auto-detected product name for DefectDojo
+	ProductTypeOverride       string              `yaml:"-"`                        // CLI-only: overrides product_type_name for DefectDojo
+	SarifMode                 bool                `yaml:"-"`                        // CLI-only: output scan results in SARIF format
+	ScanFilter                []string            `yaml:"-"`                        // CLI-only: run only these scanners (overrides enabled status)
+	IncludeDisabled           bool                `yaml:"-"`                        // CLI-only: scan repositories marked disabled
+	IncludeArchived           bool                `yaml:"-"`                        // CLI-only: scan repositories archived on GitHub (--include-archived)
+	OutputPath                string              `yaml:"-"`                        // CLI-only: write a JSON run report to this path (--output)
+	OutputFormat              string              `yaml:"-"`                        // CLI-only: run report format, "json" (default) or "kics" (--output-format)
+	Strict                    bool                `yaml:"-"`                        // CLI-only: fail the run when the post-run hook fails
+	Explain                   bool                `yaml:"-"`                        // CLI-only: log why each scanner was selected or skipped per repo
+	OnlyResults               string              `yaml:"-"`                        // CLI-only: upload and report only "success" or "failed" results (--only)
+	CleanupWorkspaceAfterRun  bool                `yaml:"-"`                        // CLI-only: delete this run's clones once the run completes (--cleanup-workspace-after-run)
+	ParallelRepos             bool                `yaml:"-"`                        // CLI-only: scan up to max_concurrent repositories at once (--parallel-repos)
+}
+
+// DojoConfig holds DefectDojo-specific upload settings
+type DojoConfig struct {
+	Reimport          bool            `yaml:"reimport"`            // Use reimport-scan so repeat scans update the existing test
+	UploadConcurrency int             `yaml:"upload_concurrency"`  // Parallel uploads (default 1 = one at a time)
+	UploadRateLimit   float64         `yaml:"upload_rate_limit"`   // Max uploads started per second across all workers (0 = unlimited)
+	UploadRetries     int             `yaml:"upload_retries"`      // Retry an upload up to N more times on network errors or 5xx responses
+	UploadState       string          `yaml:"upload_state"`        // Optional: file recording confirmed uploads, so reruns skip them
+	AutoCreateContext map[string]bool `yaml:"auto_create_context"` // Per product type name: may uploads create missing products/engagements (unlisted = true)
+}
+
+// UploadTarget is one DefectDojo instance that results are uploaded to
+type UploadTarget struct {
+	Endpoint    string   `yaml:"endpoint"`      // Import or reimport API URL, as for upload_endpoint
+	EnvTokenVar string   `yaml:"env_token_var"` // Env var holding this instance's API token (default VULN_MGMT_API_TOKEN)
+	RepoFilter  []string `yaml:"repo_filter"`   // Glob patterns for repo URLs, e.g. "github.com/org/*" (empty = all repos)
 }
 
 // ScannerConfig defines a security scanner and its execution parameters
@@ -45,22 +143,42 @@ type ScannerConfig struct {
 	FilePatterns          []string      `yaml:"file_patterns"`
 	Languages             []string      `yaml:"languages"`              // Languages with full support (empty = all languages)
 	LanguagesConditional  []string      `yaml:"languages_conditional"`  // Languages with conditional support (requires specific package manager files)
+	RequiredManifests     []string      `yaml:"required_manifests"`     // Run only if one of these manifest/lock files exists (names from manifestLanguages)
 	Timeout      string        `yaml:"timeout"`
 	timeout      time.Duration // parsed timeout (unexported)
 	DojoScanType string        `yaml:"dojo_scan_type"`
 	RequiredEnv  []string      `yaml:"required_env"` // Environment variables that must be set
 	NDJSON       bool          `yaml:"ndjson"`        // Output is NDJSON; convert to JSON array for upload
+	Retries      int           `yaml:"retries"`       // Re-run up to N times on non-zero exit with no output (not on timeout)
+	WorkingDir   string        `yaml:"working_dir"`   // Directory to run in, relative to the repo or "{{repo}}/<path>" (default: repo root)
+	RenderHelm   bool          `yaml:"render_helm"`   // Render Helm charts with `helm template` into {{rendered}} before running
+	SecurityRulesOnly bool     `yaml:"security_rules_only"` // Keep only security findings of a general-purpose linter (e.g. ruff's S rules)
+	MaxFindingsPerScanner int  `yaml:"max_findings_per_scanner"` // Overrides global.max_findings_per_scanner for this scanner (0 = use the global limit, -1 = unlimited)
+	VersionArgs  []string      `yaml:"version_args"`  // Args that print the tool version for record_commands (default: --version)
+	Image        string        `yaml:"image"`         // Run in this container image via global.container_runtime; command then overrides the entrypoint
+	Env          map[string]string `yaml:"env"`       // Extra environment variables, ${VAR} expanded; override the repository's env
 }
 
 // RepositoryConfig defines a target repository to scan
 type RepositoryConfig struct {
-	URL         string   `yaml:"url"`
-	PURL        string   `yaml:"purl,omitempty"`     // Package URL (resolved to URL at load time)
-	Branch      string   `yaml:"branch"`
-	Version     string   `yaml:"version,omitempty"`  // Tag name (e.g., "v1.2.3") - highest precedence
-	Commit      string   `yaml:"commit,omitempty"`   // Commit SHA (7-40 hex chars)
-	Scanners    []string `yaml:"scanners"`           // Optional: specific scanners to run
-	PURLVersion string   `yaml:"-"`                  // Original pURL version (not persisted, used for SBOM naming)
+	URL           string            `yaml:"url"`
+	PURL          string            `yaml:"purl,omitempty"` // Package URL (resolved to URL at load time)
+	Branch        string            `yaml:"branch"`
+	Version       string            `yaml:"version,omitempty"`         // Tag name (e.g., "v1.2.3") - highest precedence
+	Commit        string            `yaml:"commit,omitempty"`          // Commit SHA (7-40 hex chars)
+	Scanners      []string          `yaml:"scanners"`                  // Optional: specific scanners to run
+	Bundle        string            `yaml:"bundle,omitempty"`          // Optional: named scanner bundle (used when scanners is empty)
+	Disabled      bool              `yaml:"disabled,omitempty"`        // Temporarily skip this repo (see --include-disabled)
+	Submodules    bool              `yaml:"submodules,omitempty"`      // Check out git submodules before scanning
+	Path          string            `yaml:"path,omitempty"`            // Optional: scan only this subdirectory of the repo (relative, e.g. "services/api")
+	AllTags       bool              `yaml:"all_tags,omitempty"`        // Scan every tag (newest first), one target each, instead of one ref
+	AllBranches   bool              `yaml:"all_branches,omitempty"`    // Scan every branch, one target each, instead of one ref
+	MaxRefs       int               `yaml:"max_refs,omitempty"`        // Limit all_tags/all_branches to this many refs each (default 20)
+	Env           map[string]string `yaml:"env,omitempty"`             // Extra environment variables for scanners run on this repo, ${VAR} expanded
+	DojoScanTypes map[string]string `yaml:"dojo_scan_types,omitempty"` // Per scanner name: DefectDojo scan type overriding its dojo_scan_type for this repo
+	PURLVersion   string            `yaml:"-"`                         // Original pURL version (not persisted, used for SBOM naming)
+	Subproject    string            `yaml:"-"`                         // Relative sub-project path within the repo (set when scanning monorepos)
+	Explicit      bool              `yaml:"-"`                         // Given with --repo or --purl: scanned even when archived, without --include-archived
 }
 
 // ScanResult holds the outcome of running a scanner on a repository
@@ -72,29 +190,60 @@ type ScanResult struct {
 	Error        error
 	Duration     time.Duration
 	DojoScanType string
-	CommitHash   string // Actual commit hash scanned (short format)
-	BranchTag    string // Branch or tag name (for DefectDojo)
-	IsSarif      bool   // True when output is SARIF format (skip JSON parsing)
-	NDJSON       bool   // True when output is NDJSON (convert to JSON array for upload)
+	CommitHash   string       // Actual commit hash scanned (short format)
+	BranchTag    string       // Branch or tag name (for DefectDojo)
+	Subproject   string       // Relative sub-project path (empty when scanning the whole repo)
+	IsSarif      bool         // True when output is SARIF format (skip JSON parsing)
+	NDJSON       bool         // True when output is NDJSON (convert to JSON array for upload)
+	FindingAges  []FindingAge // Ages of open SCA findings (set when finding_history is configured)
+	SBOMPath     string       // CycloneDX SBOM of the scanned target (empty if generation failed)
+	Command      []string     // Executed command line with secrets redacted (set with record_commands)
+	Version      string       // Scanner version reported by its version_args (set with record_commands)
+	StderrLine   string       // First line the scanner printed on stderr (reported with invalid output)
+	ScanRoot     string       // Absolute directory the scanner ran on, as it saw it; finding paths are made relative to it
 }
 
 // RepoScanContext bundles scan results with the language and scanner metadata
 // needed to render a per-repo coverage matrix in the summary.
 type RepoScanContext struct {
-	RepoURL   string
-	Results   []ScanResult
-	Languages *DetectedLanguages
-	Scanners  []ScannerConfig // scanners selected to run on this repo
-	SBOMPath  string          // path to generated CycloneDX SBOM (empty if generation failed)
+	RepoURL     string
+	Subproject  string // relative sub-project path (empty when scanning the whole repo)
+	Results     []ScanResult
+	Languages   *DetectedLanguages
+	Scanners    []ScannerConfig // scanners selected to run on this repo
+	SBOMPath    string          // path to generated CycloneDX SBOM (empty if generation failed)
+	SBOMError   error           // why SBOM generation failed (nil on success)
+	CoverageGap string          // why no scanner ran on this target (empty when at least one did)
+	CloneError  error           // why the repository couldn't be cloned, or its path isn't in the checkout; nothing else is set
 }
 
 // ValidateRepositoryConfig validates a repository configuration
 func ValidateRepositoryConfig(repo RepositoryConfig) error {
+	// Disabled repos are never scanned, so don't reject incomplete entries
+	if repo.Disabled {
+		return nil
+	}
+
 	// URL is required
 	if repo.URL == "" {
 		return fmt.Errorf("repository URL is required")
 	}
 
+	if err := validateEnv("env", repo.Env); err != nil {
+		return err
+	}
+
+	// all_tags/all_branches pick the refs themselves
+	if repo.AllTags || repo.AllBranches {
+		if repo.Branch != "" || repo.Version != "" || repo.Commit != "" {
+			return fmt.Errorf("all_tags and all_branches can't be combined with branch, version, or commit")
+		}
+		if repo.MaxRefs < 0 {
+			return fmt.Errorf("max_refs must not be negative, got %d", repo.MaxRefs)
+		}
+		return validateRepoPath(repo.Path)
+	}
+
 	// At least one of branch/version/commit must be specified
 	if repo.Branch == "" && repo.Version == "" && repo.Commit == "" {
 		return fmt.Errorf("at least one of branch, version, or commit must be specified")
@@ -107,20 +256,240 @@ func ValidateRepositoryConfig(repo RepositoryConfig) error {
 		}
 	}
 
+	return validateRepoPath(repo.Path)
+}
+
+// validateRepoPath checks that a repository's scan path stays inside the clone
+func validateRepoPath(repoPath string) error {
+	if repoPath == "" {
+		return nil
+	}
+	cleaned := path.Clean(filepath.ToSlash(repoPath))
+	if path.IsAbs(cleaned) || filepath.IsAbs(repoPath) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("invalid path %q: must be a subdirectory relative to the repository root", repoPath)
+	}
 	return nil
 }
 
-// loadConfig reads and parses the scanner configuration file
-func loadConfig(path string) (*Config, error) {
-	path = filepath.Clean(path)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
+// validateRepoBundle checks that a repository's bundle, if any, is defined in
+// global.scanner_bundles. Explicit scanners take precedence, so the bundle is
+// not checked when the repo lists its own scanners.
+func validateRepoBundle(global GlobalConfig, repo RepositoryConfig) error {
+	if repo.Bundle == "" || len(repo.Scanners) > 0 {
+		return nil
+	}
+	if _, ok := global.ScannerBundles[repo.Bundle]; !ok {
+		return fmt.Errorf("unknown scanner bundle %q (define it under global.scanner_bundles)", repo.Bundle)
+	}
+	return nil
+}
+
+// validateUploadTargets checks that every upload target has an endpoint and
+// that its repo_filter patterns are valid globs
+func validateUploadTargets(targets []UploadTarget) error {
+	for i, target := range targets {
+		if target.Endpoint == "" {
+			return fmt.Errorf("upload_targets[%d]: endpoint is required", i)
+		}
+		for _, pattern := range target.RepoFilter {
+			if _, err := path.Match(repoFilterPattern(pattern), ""); err != nil {
+				return fmt.Errorf("upload_targets[%d]: invalid repo_filter %q: %w", i, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRequiredManifests checks that every scanner's required_manifests
+// are files (or manifestPatterns patterns) language detection records; any
+// other name would never be found and the scanner would silently never run
+func validateRequiredManifests(scanners []ScannerConfig) error {
+	for _, scanner := range scanners {
+		for _, name := range scanner.RequiredManifests {
+			_, known := manifestLanguages[name]
+			if _, pattern := manifestPatterns[name]; !known && !pattern {
+				return fmt.Errorf("scanner %s: required_manifests: %q is not a known manifest file", scanner.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSecurityRulesOnly checks that scanners with security_rules_only
+// have a parser that can tell security findings from the rest
+func validateSecurityRulesOnly(scanners []ScannerConfig) error {
+	for _, scanner := range scanners {
+		if !scanner.SecurityRulesOnly {
+			continue
+		}
+		parser, _ := parsers.Get(scanner.Name)
+		if _, ok := parser.(parsers.SecurityRuleFilter); !ok {
+			return fmt.Errorf("scanner %s: security_rules_only is not supported (only for linters such as ruff)", scanner.Name)
+		}
+	}
+	return nil
+}
+
+// validateMaxFindings rejects negative max_findings_per_scanner limits, other
+// than a scanner's -1 (unlimited)
+func validateMaxFindings(globalMax int, scanners []ScannerConfig) error {
+	if globalMax < 0 {
+		return fmt.Errorf("max_findings_per_scanner must not be negative, got %d", globalMax)
+	}
+	for _, scanner := range scanners {
+		if scanner.MaxFindingsPerScanner < unlimitedFindings {
+			return fmt.Errorf("scanner %s: max_findings_per_scanner must be -1 (unlimited) or more, got %d", scanner.Name, scanner.MaxFindingsPerScanner)
+		}
+	}
+	return nil
+}
+
+// validateSeverityDisplay rejects severity_labels or severity_icons (named by
+// key) entries for unknown severities or with empty values
+func validateSeverityDisplay(key string, values map[string]string) error {
+	for severity, value := range values {
+		if _, ok := defaultSeverityLabels[severity]; !ok {
+			return fmt.Errorf("%s: unknown severity %q (available: %s)", key, severity, strings.Join(severityKeys, ", "))
+		}
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%s: %s must not be empty", key, severity)
+		}
 	}
+	return nil
+}
+
+// unknownFieldPattern matches the unknown-field errors of a yaml.v3 decoder
+// with KnownFields set
+var unknownFieldPattern = regexp.MustCompile(`field (\S+) not found in type main\.(\w+)`)
 
+// configSections names the config section each struct is decoded from
+var configSections = map[string]string{
+	"Config":           "top level",
+	"GlobalConfig":     "global",
+	"DojoConfig":       "global.dojo",
+	"UploadTarget":     "global.upload_targets",
+	"ScannerConfig":    "scanners",
+	"RepositoryConfig": "repositories",
+}
+
+// unknownConfigKeys decodes a config document with KnownFields and returns
+// the keys no config field matches, e.g. "time_out (in scanners)"
+func unknownConfigKeys(data []byte) []string {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("parsing YAML: %w", err)
+	err := dec.Decode(&config)
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	var unknown []string
+	for _, msg := range typeErr.Errors {
+		m := unknownFieldPattern.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		section := configSections[m[2]]
+		if section == "" {
+			section = m[2]
+		}
+		unknown = append(unknown, fmt.Sprintf("%s (in %s)", m[1], section))
+	}
+	return unknown
+}
+
+// dojoScanType returns the DefectDojo scan type of a scanner's results on a
+// repository: the repo's dojo_scan_types entry for the scanner when there is
+// one, else the scanner's dojo_scan_type
+func dojoScanType(scanner ScannerConfig, repo RepositoryConfig) string {
+	if scanType, ok := repo.DojoScanTypes[scanner.Name]; ok {
+		return scanType
+	}
+	return scanner.DojoScanType
+}
+
+// repoScannerNames returns the scanner names requested for a repository.
+// Precedence: explicit scanners > bundle > nil (all enabled scanners).
+func repoScannerNames(global GlobalConfig, repo RepositoryConfig) []string {
+	if len(repo.Scanners) > 0 {
+		return repo.Scanners
+	}
+	if repo.Bundle != "" {
+		return global.ScannerBundles[repo.Bundle]
+	}
+	return nil
+}
+
+// loadConfig reads and parses the scanner configuration files. Later files
+// are overlays deep-merged onto the earlier ones (see mergeConfigs).
+// Unknown keys are logged as warnings, unless the config sets
+// global.strict_config, which rejects them like loadConfigStrict.
+func loadConfig(paths ...string) (*Config, error) {
+	return loadConfigMode(false, paths...)
+}
+
+// loadConfigStrict is loadConfig that fails on unknown keys (--strict-config),
+// so typos like time_out for timeout don't go unnoticed
+func loadConfigStrict(paths ...string) (*Config, error) {
+	return loadConfigMode(true, paths...)
+}
+
+// loadConfigMode implements loadConfig and loadConfigStrict
+func loadConfigMode(strict bool, paths ...string) (*Config, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config file given")
+	}
+
+	var config Config
+	var data []byte
+	var merged map[string]any
+	hash := sha256.New()
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		hash.Write(fileData)
+		hash.Write([]byte{0}) // so moving bytes between overlays changes the hash
+
+		// Decode every file on its own, so a type error names the file and
+		// the line it is on rather than a line of the merged document
+		config = Config{}
+		if err := yaml.Unmarshal(fileData, &config); err != nil {
+			return nil, fmt.Errorf("parsing YAML in %s: %w", path, err)
+		}
+		data = fileData
+		if len(paths) > 1 {
+			var overlay map[string]any
+			if err := yaml.Unmarshal(fileData, &overlay); err != nil {
+				return nil, fmt.Errorf("parsing YAML in %s: %w", path, err)
+			}
+			merged = mergeConfigs(merged, overlay)
+		}
+	}
+
+	// With overlays, decode the merged document; a single file's decode
+	// above is the config
+	if len(paths) > 1 {
+		var err error
+		if data, err = yaml.Marshal(merged); err != nil {
+			return nil, fmt.Errorf("encoding merged config: %w", err)
+		}
+		config = Config{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("parsing merged YAML: %w", err)
+		}
+	}
+	config.configHash = hex.EncodeToString(hash.Sum(nil))
+	if unknown := unknownConfigKeys(data); len(unknown) > 0 {
+		if strict || config.Global.StrictConfig {
+			return nil, fmt.Errorf("unknown config keys: %s", strings.Join(unknown, "; "))
+		}
+		for _, key := range unknown {
+			log.Printf("⚠️  Unknown config key ignored: %s", key)
+		}
 	}
 
 	// Set defaults
@@ -133,18 +502,151 @@ func loadConfig(path string) (*Config, error) {
 	if config.Global.MaxConcurrent == 0 {
 		config.Global.MaxConcurrent = 3
 	}
+	if len(config.Global.CoverageScanTypes) == 0 {
+		config.Global.CoverageScanTypes = defaultCoverageScanTypes
+	}
+
+	if pct := config.Global.MinLanguagePercent; pct < 0 || pct >= 100 {
+		return nil, fmt.Errorf("min_language_percent must be between 0 and 100, got %g", pct)
+	}
+	if n := config.Global.TopFindings; n < 0 {
+		return nil, fmt.Errorf("top_findings must not be negative, got %d", n)
+	}
+	if workers := config.Global.LanguageDetectionWorkers; workers < 0 {
+		return nil, fmt.Errorf("language_detection_workers must not be negative, got %d", workers)
+	}
+	if config.Global.DetectionIgnore == nil {
+		config.Global.DetectionIgnore = defaultDetectionIgnore
+	}
+	if err := validateDetectionIgnore(config.Global.DetectionIgnore); err != nil {
+		return nil, err
+	}
+	if err := validateUploadTargets(config.Global.UploadTargets); err != nil {
+		return nil, err
+	}
+	if err := validateClientCert(config.Global.TLSClientCert, config.Global.TLSClientKey); err != nil {
+		return nil, err
+	}
+	if err := validateGitHubApp(config.Global.GitHubApp); err != nil {
+		return nil, err
+	}
+	if err := validateResultNameTemplate(config.Global.ResultNameTemplate); err != nil {
+		return nil, err
+	}
+	if err := validateResultsLayout(config.Global.ResultsLayout); err != nil {
+		return nil, err
+	}
+	if err := validateRequiredManifests(config.Scanners); err != nil {
+		return nil, err
+	}
+	if err := validateSecurityRulesOnly(config.Scanners); err != nil {
+		return nil, err
+	}
+	if err := validateMaxFindings(config.Global.MaxFindingsPerScanner, config.Scanners); err != nil {
+		return nil, err
+	}
+	if min := config.Global.MinScorecardScore; min < 0 || min > 10 {
+		return nil, fmt.Errorf("min_scorecard_score must be between 0 and 10, got %g", min)
+	}
+	if err := validateContainerScanners(config.Scanners); err != nil {
+		return nil, err
+	}
+	if err := validateScannerEnv(config.Scanners); err != nil {
+		return nil, err
+	}
+	if err := validateArchiveResults(config.Global.ArchiveResults); err != nil {
+		return nil, err
+	}
+	if err := validateSeverityDisplay("severity_labels", config.Global.SeverityLabels); err != nil {
+		return nil, err
+	}
+	if err := validateSeverityDisplay("severity_icons", config.Global.SeverityIcons); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
 
-// loadRepositories reads and parses the repositories configuration file
-func loadRepositories(path string) ([]RepositoryConfig, error) {
+// mergeConfigs deep-merges an overlay config document onto base and returns
+// the result. Mappings (global, dojo, scanner_bundles, ...) merge key by key,
+// scanners merge by name, and anything else set in the overlay (scalars,
+// lists such as args) replaces the base value. Keys the overlay sets
+// explicitly win even when false or empty, so an overlay can disable a scanner.
+func mergeConfigs(base, overlay map[string]any) map[string]any {
+	if base == nil {
+		base = make(map[string]any)
+	}
+	for key, value := range overlay {
+		if key == "scanners" {
+			base[key] = mergeScannerLists(base[key], value)
+			continue
+		}
+		base[key] = mergeConfigValue(base[key], value)
+	}
+	return base
+}
+
+// mergeConfigValue merges two values of a config document: nested mappings
+// merge recursively, everything else is replaced by the overlay
+func mergeConfigValue(base, overlay any) any {
+	baseMap, ok1 := base.(map[string]any)
+	overlayMap, ok2 := overlay.(map[string]any)
+	if !ok1 || !ok2 {
+		return overlay
+	}
+	for key, value := range overlayMap {
+		baseMap[key] = mergeConfigValue(baseMap[key], value)
+	}
+	return baseMap
+}
+
+// mergeScannerLists merges overlay scanner entries onto base entries with the
+// same name, field by field. Scanners new in the overlay are appended.
+func mergeScannerLists(base, overlay any) any {
+	baseList, _ := base.([]any)
+	overlayList, ok := overlay.([]any)
+	if !ok {
+		return overlay
+	}
+
+	index := make(map[string]int)
+	for i, entry := range baseList {
+		if scanner, ok := entry.(map[string]any); ok {
+			if name, ok := scanner["name"].(string); ok {
+				index[name] = i
+			}
+		}
+	}
+	for _, entry := range overlayList {
+		scanner, ok := entry.(map[string]any)
+		name, _ := scanner["name"].(string)
+		if i, found := index[name]; ok && found {
+			baseList[i] = mergeConfigValue(baseList[i], scanner)
+			continue
+		}
+		if ok && name != "" {
+			index[name] = len(baseList)
+		}
+		baseList = append(baseList, entry)
+	}
+	return baseList
+}
+
+// loadRepositories reads and parses the repositories configuration file.
+// Repos marked disabled are dropped unless includeDisabled is true.
+func loadRepositories(path string, includeDisabled bool) ([]RepositoryConfig, error) {
 	path = filepath.Clean(path)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading repositories file: %w", err)
 	}
+	return parseRepositories(data, includeDisabled)
+}
 
+// parseRepositories parses repositories YAML (the repositories.yaml format,
+// also accepted inline via --repos-yaml). Repos marked disabled are dropped
+// unless includeDisabled is true.
+func parseRepositories(data []byte, includeDisabled bool) ([]RepositoryConfig, error) {
 	var repoConfig struct {
 		Repositories []RepositoryConfig `yaml:"repositories"`
 	}
@@ -153,11 +655,109 @@ func loadRepositories(path string) ([]RepositoryConfig, error) {
 		return nil, fmt.Errorf("parsing YAML: %w", err)
 	}
 
-	return repoConfig.Repositories, nil
+	if includeDisabled {
+		return repoConfig.Repositories, nil
+	}
+
+	repos := make([]RepositoryConfig, 0, len(repoConfig.Repositories))
+	disabled := 0
+	for _, repo := range repoConfig.Repositories {
+		if repo.Disabled {
+			disabled++
+			continue
+		}
+		repos = append(repos, repo)
+	}
+	if disabled > 0 {
+		log.Printf("⏭️  Skipping %d disabled repo(s) (use --include-disabled to scan them)", disabled)
+	}
+
+	return repos, nil
+}
+
+// repoIdentity is the key under which two repository entries count as the same
+// target: the URL normalized for scheme, .git suffix and case, plus the path
+// when the entry scans a subdirectory. Entries given as a purl are checked
+// before it is resolved to a URL, so they are keyed by the purl itself.
+func repoIdentity(repo RepositoryConfig) string {
+	identity := strings.ToLower(normalizeGitURL(repo.URL))
+	if repo.URL == "" && repo.PURL != "" {
+		identity = "purl:" + repo.PURL
+	}
+	if scope := scopePath(repo); scope != "" {
+		identity += "#" + scope
+	}
+	return identity
+}
+
+// describeRepoEntry names a repository entry for duplicate messages: its
+// 1-based position and ref
+func describeRepoEntry(index int, repo RepositoryConfig) string {
+	refType, ref := repoRef(repo)
+	return fmt.Sprintf("entry %d (%s %s)", index+1, refType, ref)
+}
+
+// checkDuplicateRepositories finds entries listing the same repository
+// (see repoIdentity), whatever their refs. Scanning them all would repeat
+// the work and upload the results twice, so this is an error unless merge is
+// set (global.merge_duplicate_repos), which keeps the first entry and logs
+// the ones dropped. Disabled entries are never duplicates.
+func checkDuplicateRepositories(repos []RepositoryConfig, merge bool) ([]RepositoryConfig, error) {
+	first := make(map[string]int)
+	kept := make([]RepositoryConfig, 0, len(repos))
+	var collisions []string
+	for i, repo := range repos {
+		if repo.Disabled {
+			kept = append(kept, repo)
+			continue
+		}
+		identity := repoIdentity(repo)
+		name := repo.URL
+		if name == "" {
+			name = repo.PURL
+		}
+		j, seen := first[identity]
+		if !seen {
+			first[identity] = i
+			kept = append(kept, repo)
+			continue
+		}
+		if merge {
+			log.Printf("⚠️  Duplicate repository %s: keeping %s, dropping %s", name, describeRepoEntry(j, repos[j]), describeRepoEntry(i, repo))
+			continue
+		}
+		collisions = append(collisions, fmt.Sprintf("%s: %s and %s", name, describeRepoEntry(j, repos[j]), describeRepoEntry(i, repo)))
+	}
+	if len(collisions) > 0 {
+		for _, collision := range collisions {
+			log.Printf("❌ Duplicate repository %s", collision)
+		}
+		return nil, fmt.Errorf("%d duplicate repository entries (remove them or set global.merge_duplicate_repos to keep the first of each)", len(collisions))
+	}
+	return kept, nil
 }
 
 // parseTimeouts parses timeout strings into time.Duration for each scanner
+// and for git ls-remote resolution
 func parseTimeouts(config *Config) error {
+	config.Global.lsRemoteTimeout = defaultLsRemoteTimeout
+	if config.Global.LsRemoteTimeout != "" {
+		duration, err := time.ParseDuration(config.Global.LsRemoteTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid ls_remote_timeout: %w", err)
+		}
+		config.Global.lsRemoteTimeout = duration
+	}
+
+	config.Global.grypeDBPrefetchTimeout = defaultGrypeDBPrefetchTimeout
+	if config.Global.GrypeDBPrefetchTimeout != "" {
+		duration, err := time.ParseDuration(config.Global.GrypeDBPrefetchTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid grype_db_prefetch_timeout: %w", err)
+		}
+		config.Global.grypeDBPrefetchTimeout = duration
+	}
+
 	for i := range config.Scanners {
 		if config.Scanners[i].Timeout == "" {
 			config.Scanners[i].timeout = 5 * time.Minute
@@ -64,6 +64,28 @@ func TestParseTimeouts(t *testing.T) {
 		})
 	}
 
+	t.Run("ls_remote_timeout", func(t *testing.T) {
+		for _, tt := range []struct {
+			value   string
+			want    time.Duration
+			wantErr bool
+		}{
+			{"", defaultLsRemoteTimeout, false},
+			{"90s", 90 * time.Second, false},
+			{"soon", 0, true},
+		} {
+			config := &Config{Global: GlobalConfig{LsRemoteTimeout: tt.value}}
+			err := parseTimeouts(config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTimeouts(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+				continue
+			}
+			if !tt.wantErr && config.Global.lsRemoteTimeout != tt.want {
+				t.Errorf("lsRemoteTimeout = %v, want %v", config.Global.lsRemoteTimeout, tt.want)
+			}
+		}
+	})
+
 	// Verify second scanner in "multiple scanners" case
 	t.Run("multiple scanners second timeout", func(t *testing.T) {
 		config := &Config{Scanners: []ScannerConfig{
@@ -183,6 +205,113 @@ scanners:
 		if config.Global.MaxConcurrent != 3 {
 			t.Errorf("MaxConcurrent default = %d, want %d", config.Global.MaxConcurrent, 3)
 		}
+		if got := strings.Join(config.Global.CoverageScanTypes, ","); got != "SCA,SAST,Reachability,Binary" {
+			t.Errorf("CoverageScanTypes default = %q, want %q", got, "SCA,SAST,Reachability,Binary")
+		}
+	})
+
+	t.Run("max_findings_per_scanner must not be negative", func(t *testing.T) {
+		for _, yaml := range []string{
+			"global:\n  max_findings_per_scanner: -1\n",
+			"scanners:\n  - name: semgrep\n    max_findings_per_scanner: -5\n",
+		} {
+			configPath := filepath.Join(t.TempDir(), "scanners.yaml")
+			os.WriteFile(configPath, []byte(yaml), 0644)
+			if _, err := loadConfig(configPath); err == nil {
+				t.Errorf("expected an error for:\n%s", yaml)
+			}
+		}
+
+		// A scanner's -1 means unlimited
+		configPath := filepath.Join(t.TempDir(), "scanners.yaml")
+		os.WriteFile(configPath, []byte("global:\n  max_findings_per_scanner: 1000\nscanners:\n  - name: semgrep\n    max_findings_per_scanner: -1\n"), 0644)
+		if _, err := loadConfig(configPath); err != nil {
+			t.Errorf("scanner max_findings_per_scanner -1: unexpected error %v", err)
+		}
+	})
+
+	t.Run("min_language_percent must be a percentage", func(t *testing.T) {
+		for _, value := range []string{"-1", "100", "150"} {
+			configPath := filepath.Join(t.TempDir(), "scanners.yaml")
+			os.WriteFile(configPath, []byte("global:\n  min_language_percent: "+value+"\n"), 0644)
+			if _, err := loadConfig(configPath); err == nil {
+				t.Errorf("min_language_percent %s: expected an error", value)
+			}
+		}
+	})
+
+	t.Run("top_findings must not be negative", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "scanners.yaml")
+		os.WriteFile(configPath, []byte("global:\n  top_findings: -1\n"), 0644)
+		if _, err := loadConfig(configPath); err == nil || !strings.Contains(err.Error(), "top_findings") {
+			t.Errorf("loadConfig() error = %v, want a top_findings error", err)
+		}
+	})
+
+	t.Run("detection_ignore defaults and validation", func(t *testing.T) {
+		tests := []struct {
+			yaml    string
+			want    int // number of patterns
+			wantErr bool
+		}{
+			{yaml: "global: {}\n", want: len(defaultDetectionIgnore)},
+			{yaml: "global:\n  detection_ignore: []\n", want: 0},
+			{yaml: "global:\n  detection_ignore: [\"*.gen.go\"]\n", want: 1},
+			{yaml: "global:\n  detection_ignore: [\"[*.go\"]\n", wantErr: true},
+		}
+		for _, tt := range tests {
+			configPath := filepath.Join(t.TempDir(), "scanners.yaml")
+			os.WriteFile(configPath, []byte(tt.yaml), 0644)
+			config, err := loadConfig(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadConfig(%q) error = %v, wantErr %v", tt.yaml, err, tt.wantErr)
+				continue
+			}
+			if err == nil && len(config.Global.DetectionIgnore) != tt.want {
+				t.Errorf("loadConfig(%q) DetectionIgnore = %v, want %d patterns", tt.yaml, config.Global.DetectionIgnore, tt.want)
+			}
+		}
+	})
+
+	t.Run("coverage scan types parsed", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "scanners.yaml")
+		yaml := `
+global:
+  coverage_scan_types: ["SCA", "SAST", "IaC"]
+`
+		os.WriteFile(configPath, []byte(yaml), 0644)
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+		if got := strings.Join(config.Global.CoverageScanTypes, ","); got != "SCA,SAST,IaC" {
+			t.Errorf("CoverageScanTypes = %q, want %q", got, "SCA,SAST,IaC")
+		}
+	})
+
+	t.Run("scanner bundles parsed", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "scanners.yaml")
+		yaml := `
+global:
+  scanner_bundles:
+    full: ["gosec", "grype", "trufflehog"]
+    light: ["grype"]
+`
+		os.WriteFile(configPath, []byte(yaml), 0644)
+
+		config, err := loadConfig(configPath)
+		if err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+		if got := config.Global.ScannerBundles["full"]; len(got) != 3 || got[0] != "gosec" {
+			t.Errorf("ScannerBundles[full] = %v, want [gosec grype trufflehog]", got)
+		}
+		if got := config.Global.ScannerBundles["light"]; len(got) != 1 || got[0] != "grype" {
+			t.Errorf("ScannerBundles[light] = %v, want [grype]", got)
+		}
 	})
 
 	t.Run("non-existent file returns error", func(t *testing.T) {
@@ -204,6 +333,153 @@ scanners:
 	})
 }
 
+func TestLoadConfigOverlays(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("scanners.yaml", `
+global:
+  workspace: "/base/workspace"
+  results_dir: "/base/results"
+  fail_fast: true
+  dojo:
+    reimport: true
+    upload_concurrency: 2
+scanners:
+  - name: "grype"
+    enabled: true
+    command: "grype"
+    args: ["dir:{{repo}}", "-o", "json"]
+    timeout: "5m"
+    required_env: ["GRYPE_DB"]
+  - name: "gosec"
+    enabled: true
+    command: "gosec"
+`)
+	prod := write("prod.yaml", `
+global:
+  results_dir: "/prod/results"
+  fail_fast: false
+  dojo:
+    upload_concurrency: 8
+scanners:
+  - name: "grype"
+    args: ["sbom:{{sbom}}", "-o", "json"]
+    timeout: "15m"
+  - name: "gosec"
+    enabled: false
+  - name: "semgrep"
+    enabled: true
+    command: "semgrep"
+`)
+	ci := write("ci.yaml", `
+global:
+  results_dir: "/ci/results"
+scanners:
+  - name: "grype"
+    timeout: "20m"
+`)
+
+	config, err := loadConfig(base, prod, ci)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	global := config.Global
+	if global.Workspace != "/base/workspace" {
+		t.Errorf("Workspace = %q, want base value kept", global.Workspace)
+	}
+	if global.ResultsDir != "/ci/results" {
+		t.Errorf("ResultsDir = %q, want last overlay to win", global.ResultsDir)
+	}
+	if global.FailFast {
+		t.Error("FailFast = true, want overlay's explicit false")
+	}
+	if !global.Dojo.Reimport || global.Dojo.UploadConcurrency != 8 {
+		t.Errorf("Dojo = %+v, want reimport kept and upload_concurrency 8", global.Dojo)
+	}
+
+	scanners := make(map[string]ScannerConfig)
+	var names []string
+	for _, s := range config.Scanners {
+		scanners[s.Name] = s
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "grype,gosec,semgrep" {
+		t.Errorf("scanners = %s, want base order with new scanners appended", got)
+	}
+	grype := scanners["grype"]
+	if got := strings.Join(grype.Args, " "); got != "sbom:{{sbom}} -o json" {
+		t.Errorf("grype args = %q, want overlay args", got)
+	}
+	if grype.Timeout != "20m" {
+		t.Errorf("grype timeout = %q, want 20m from the last overlay", grype.Timeout)
+	}
+	if grype.Command != "grype" || !grype.Enabled || len(grype.RequiredEnv) != 1 {
+		t.Errorf("grype = %+v, want unset fields kept from base", grype)
+	}
+	if scanners["gosec"].Enabled {
+		t.Error("gosec enabled, want disabled by overlay")
+	}
+
+	t.Run("overlay errors name the file", func(t *testing.T) {
+		bad := write("bad.yaml", "global: [unterminated")
+		_, err := loadConfig(base, bad)
+		if err == nil || !strings.Contains(err.Error(), "bad.yaml") {
+			t.Errorf("loadConfig() error = %v, want it to name bad.yaml", err)
+		}
+	})
+
+	t.Run("overlay type errors name the file and line", func(t *testing.T) {
+		bad := write("typed.yaml", "global:\n  results_dir: ./out\n  fail_fast: sometimes\n")
+		_, err := loadConfig(base, bad)
+		if err == nil || !strings.Contains(err.Error(), "typed.yaml") || !strings.Contains(err.Error(), "line 3") {
+			t.Errorf("loadConfig() error = %v, want it to name typed.yaml and line 3", err)
+		}
+	})
+}
+
+func TestConfigHash(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	hash := func(paths ...string) string {
+		t.Helper()
+		config, err := loadConfig(paths...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config.configHash
+	}
+	base := hash(write("a.yaml", "global:\n  max_concurrent: 2\n"))
+
+	if len(base) != 64 {
+		t.Errorf("configHash = %q, want a hex sha256", base)
+	}
+	if got := hash(write("copy.yaml", "global:\n  max_concurrent: 2\n")); got != base {
+		t.Errorf("identical config hashed to %s, want %s", got, base)
+	}
+	if got := hash(write("changed.yaml", "global:\n  max_concurrent: 3\n")); got == base {
+		t.Error("changed config has the same hash")
+	}
+	overlay := write("overlay.yaml", "global:\n  fail_fast: true\n")
+	if got := hash(filepath.Join(dir, "a.yaml"), overlay); got == base {
+		t.Error("overlay doesn't change the hash")
+	}
+}
+
 func TestLoadRepositories(t *testing.T) {
 	t.Run("valid repositories", func(t *testing.T) {
 		dir := t.TempDir()
@@ -220,7 +496,7 @@ repositories:
 `
 		os.WriteFile(repoPath, []byte(yaml), 0644)
 
-		repos, err := loadRepositories(repoPath)
+		repos, err := loadRepositories(repoPath, false)
 		if err != nil {
 			t.Fatalf("loadRepositories() error = %v", err)
 		}
@@ -238,8 +514,43 @@ repositories:
 		}
 	})
 
+	t.Run("disabled repositories", func(t *testing.T) {
+		dir := t.TempDir()
+		repoPath := filepath.Join(dir, "repositories.yaml")
+		yaml := `
+repositories:
+  - url: "https://github.com/org/repo1"
+    branch: "main"
+  - url: "https://github.com/org/repo2"
+    branch: "main"
+    disabled: true
+  - url: "https://github.com/org/repo3"
+    disabled: true
+`
+		os.WriteFile(repoPath, []byte(yaml), 0644)
+
+		repos, err := loadRepositories(repoPath, false)
+		if err != nil {
+			t.Fatalf("loadRepositories() error = %v", err)
+		}
+		if len(repos) != 1 || repos[0].URL != "https://github.com/org/repo1" {
+			t.Errorf("loadRepositories(includeDisabled=false) = %v, want only repo1", repos)
+		}
+
+		repos, err = loadRepositories(repoPath, true)
+		if err != nil {
+			t.Fatalf("loadRepositories() error = %v", err)
+		}
+		if len(repos) != 3 {
+			t.Fatalf("loadRepositories(includeDisabled=true) len = %d, want 3", len(repos))
+		}
+		if !repos[1].Disabled || !repos[2].Disabled {
+			t.Errorf("disabled flags = %v, %v, want true, true", repos[1].Disabled, repos[2].Disabled)
+		}
+	})
+
 	t.Run("non-existent file returns error", func(t *testing.T) {
-		_, err := loadRepositories("/nonexistent/repos.yaml")
+		_, err := loadRepositories("/nonexistent/repos.yaml", false)
 		if err == nil {
 			t.Error("loadRepositories() expected error for non-existent file, got nil")
 		}
@@ -283,6 +594,16 @@ func TestValidateRepositoryConfig(t *testing.T) {
 			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Branch: "main", Version: "v1.0.0", Commit: "abc1234"},
 			wantErr: false,
 		},
+		{
+			name:    "disabled repo skips validation",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Commit: "abc", Disabled: true},
+			wantErr: false,
+		},
+		{
+			name:    "valid subdirectory path",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Branch: "main", Path: "services/api"},
+			wantErr: false,
+		},
 		// Negative cases
 		{
 			name:    "missing URL",
@@ -314,6 +635,45 @@ func TestValidateRepositoryConfig(t *testing.T) {
 			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Commit: "abc-123"},
 			wantErr: true,
 		},
+		{
+			name:    "absolute path",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Branch: "main", Path: "/etc"},
+			wantErr: true,
+		},
+		{
+			name:    "path escaping the repo",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Branch: "main", Path: "services/../../other"},
+			wantErr: true,
+		},
+		{
+			name:    "all tags without a ref",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", AllTags: true, AllBranches: true, MaxRefs: 5},
+			wantErr: false,
+		},
+		{
+			name:    "all tags with a version",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", AllTags: true, Version: "v1.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "all branches with negative max_refs",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", AllBranches: true, MaxRefs: -1},
+			wantErr: true,
+		},
+		{
+			name:    "all tags with path escaping the repo",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", AllTags: true, Path: "../other"},
+			wantErr: true,
+		},
+		{
+			name: "env",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo", Branch: "main", Env: map[string]string{"SNYK_ORG": "payments"}},
+		},
+		{
+			name:    "env with invalid name",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Branch: "main", Env: map[string]string{"SNYK=ORG": "payments"}},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -344,7 +704,7 @@ repositories:
 `
 		os.WriteFile(repoPath, []byte(yaml), 0644)
 
-		repos, err := loadRepositories(repoPath)
+		repos, err := loadRepositories(repoPath, false)
 		if err != nil {
 			t.Fatalf("loadRepositories() error = %v", err)
 		}
@@ -382,3 +742,394 @@ repositories:
 		}
 	})
 }
+
+func TestRepoScannerNames(t *testing.T) {
+	global := GlobalConfig{
+		ScannerBundles: map[string][]string{
+			"full":  {"gosec", "grype", "trufflehog"},
+			"light": {"grype"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		repo RepositoryConfig
+		want []string
+	}{
+		{
+			name: "no scanners or bundle means all enabled",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo"},
+			want: nil,
+		},
+		{
+			name: "bundle expands to its scanner list",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "full"},
+			want: []string{"gosec", "grype", "trufflehog"},
+		},
+		{
+			name: "explicit scanners take precedence over bundle",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "full", Scanners: []string{"gosec"}},
+			want: []string{"gosec"},
+		},
+		{
+			name: "unknown bundle expands to nothing",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "missing"},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repoScannerNames(global, tt.repo)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("repoScannerNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRepoBundle(t *testing.T) {
+	global := GlobalConfig{
+		ScannerBundles: map[string][]string{"light": {"grype"}},
+	}
+
+	tests := []struct {
+		name    string
+		repo    RepositoryConfig
+		wantErr bool
+	}{
+		{
+			name: "no bundle",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo"},
+		},
+		{
+			name: "known bundle",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "light"},
+		},
+		{
+			name:    "unknown bundle",
+			repo:    RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "tier1"},
+			wantErr: true,
+		},
+		{
+			name: "unknown bundle ignored when scanners are explicit",
+			repo: RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "tier1", Scanners: []string{"grype"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRepoBundle(global, tt.repo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRepoBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUploadTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []UploadTarget
+		wantErr bool
+	}{
+		{name: "none"},
+		{
+			name: "valid targets",
+			targets: []UploadTarget{
+				{Endpoint: "https://prod/api/v2/reimport-scan/", EnvTokenVar: "PROD_TOKEN", RepoFilter: []string{"github.com/org/*"}},
+				{Endpoint: "https://dev/api/v2/reimport-scan/"},
+			},
+		},
+		{
+			name:    "missing endpoint",
+			targets: []UploadTarget{{EnvTokenVar: "PROD_TOKEN"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid repo_filter",
+			targets: []UploadTarget{{Endpoint: "https://prod/", RepoFilter: []string{"github.com/org/[a-"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateUploadTargets(tt.targets); (err != nil) != tt.wantErr {
+				t.Errorf("validateUploadTargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRequiredManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		scanners []ScannerConfig
+		wantErr  bool
+	}{
+		{name: "none", scanners: []ScannerConfig{{Name: "gosec"}}},
+		{
+			name:     "known manifests",
+			scanners: []ScannerConfig{{Name: "grype", RequiredManifests: []string{"go.mod", "poetry.lock", "Cargo.lock"}}},
+		},
+		{
+			name:     "manifest pattern",
+			scanners: []ScannerConfig{{Name: "grype", RequiredManifests: []string{"requirements-*.txt", "*.rockspec"}}},
+		},
+		{
+			name:     "unknown manifest",
+			scanners: []ScannerConfig{{Name: "grype", RequiredManifests: []string{"requirements-dev.txt"}}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRequiredManifests(tt.scanners); (err != nil) != tt.wantErr {
+				t.Errorf("validateRequiredManifests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSecurityRulesOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		scanners []ScannerConfig
+		wantErr  bool
+	}{
+		{name: "unset", scanners: []ScannerConfig{{Name: "gosec"}, {Name: "custom-linter"}}},
+		{name: "ruff", scanners: []ScannerConfig{{Name: "ruff", SecurityRulesOnly: true}}},
+		{name: "parser without a filter", scanners: []ScannerConfig{{Name: "gosec", SecurityRulesOnly: true}}, wantErr: true},
+		{name: "no parser", scanners: []ScannerConfig{{Name: "custom-linter", SecurityRulesOnly: true}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSecurityRulesOnly(tt.scanners); (err != nil) != tt.wantErr {
+				t.Errorf("validateSecurityRulesOnly() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSeverityDisplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "custom labels", values: map[string]string{"critical": "Blocker", "medium": "Major"}},
+		{name: "unknown severity", values: map[string]string{"blocker": "Blocker"}, wantErr: true},
+		{name: "empty label", values: map[string]string{"high": " "}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSeverityDisplay("severity_labels", tt.values); (err != nil) != tt.wantErr {
+				t.Errorf("validateSeverityDisplay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	typo := write("typo.yaml", `
+global:
+  results_dir: ./out
+  dojo:
+    reimprt: true
+scanners:
+  - name: gosec
+    command: gosec
+    time_out: 5m
+`)
+	clean := write("clean.yaml", `
+global:
+  results_dir: ./out
+scanners:
+  - name: gosec
+    command: gosec
+    timeout: 5m
+`)
+	strictInFile := write("strict.yaml", `
+global:
+  strict_config: true
+  max_concurrnt: 4
+`)
+
+	t.Run("unknown keys are reported with their section", func(t *testing.T) {
+		data, err := os.ReadFile(typo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := unknownConfigKeys(data)
+		want := []string{"reimprt (in global.dojo)", "time_out (in scanners)"}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("unknownConfigKeys() = %v, want %v", got, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		load    func(...string) (*Config, error)
+		paths   []string
+		wantErr string
+	}{
+		{name: "default mode warns and loads", load: loadConfig, paths: []string{typo}},
+		{name: "strict mode rejects typos", load: loadConfigStrict, paths: []string{typo}, wantErr: "time_out (in scanners)"},
+		{name: "strict mode accepts a clean config", load: loadConfigStrict, paths: []string{clean}},
+		{name: "strict_config in the file", load: loadConfig, paths: []string{strictInFile}, wantErr: "max_concurrnt (in global)"},
+		{name: "typo in an overlay", load: loadConfigStrict, paths: []string{clean, typo}, wantErr: "reimprt (in global.dojo)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.load(tt.paths...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("load error = %v, want nil", err)
+				}
+				if config.Global.ResultsDir != "./out" {
+					t.Errorf("ResultsDir = %q, want ./out", config.Global.ResultsDir)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("load error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("shipped scanners.yaml has no unknown keys", func(t *testing.T) {
+		if _, err := loadConfigStrict("../scanners.yaml"); err != nil {
+			t.Errorf("loadConfigStrict(../scanners.yaml) error = %v", err)
+		}
+	})
+
+	t.Run("shipped scanners.yaml lists each required manifest once", func(t *testing.T) {
+		config, err := loadConfigStrict("../scanners.yaml")
+		if err != nil {
+			t.Fatalf("loadConfigStrict(../scanners.yaml) error = %v", err)
+		}
+		for _, scanner := range config.Scanners {
+			seen := make(map[string]bool)
+			for _, manifest := range scanner.RequiredManifests {
+				if seen[manifest] {
+					t.Errorf("%s: required_manifests lists %q twice", scanner.Name, manifest)
+				}
+				seen[manifest] = true
+			}
+		}
+	})
+}
+
+func TestCheckDuplicateRepositories(t *testing.T) {
+	repo := func(url, branch string) RepositoryConfig { return RepositoryConfig{URL: url, Branch: branch} }
+	sub := func(url, path string) RepositoryConfig { return RepositoryConfig{URL: url, Branch: "main", Path: path} }
+	disabled := RepositoryConfig{URL: "https://github.com/org/api", Branch: "old", Disabled: true}
+
+	tests := []struct {
+		name     string
+		repos    []RepositoryConfig
+		wantErr  bool
+		wantKept []string // branches kept when merging
+	}{
+		{
+			name:     "distinct repos",
+			repos:    []RepositoryConfig{repo("https://github.com/org/api", "main"), repo("https://github.com/org/web", "main")},
+			wantKept: []string{"main", "main"},
+		},
+		{
+			name:     "exact duplicates",
+			repos:    []RepositoryConfig{repo("https://github.com/org/api", "main"), repo("https://github.com/org/api", "main")},
+			wantErr:  true,
+			wantKept: []string{"main"},
+		},
+		{
+			name:     ".git suffix and case",
+			repos:    []RepositoryConfig{repo("https://github.com/org/api", "main"), repo("https://GitHub.com/Org/API.git", "main")},
+			wantErr:  true,
+			wantKept: []string{"main"},
+		},
+		{
+			name:     "distinct branches of the same URL",
+			repos:    []RepositoryConfig{repo("https://github.com/org/api", "main"), repo("git@github.com:org/api.git", "develop")},
+			wantErr:  true,
+			wantKept: []string{"main"},
+		},
+		{
+			name:     "different paths of the same URL",
+			repos:    []RepositoryConfig{sub("https://github.com/org/mono", "services/api"), sub("https://github.com/org/mono", "services/web")},
+			wantKept: []string{"main", "main"},
+		},
+		{
+			name:     "disabled entries are not duplicates",
+			repos:    []RepositoryConfig{repo("https://github.com/org/api", "main"), disabled},
+			wantKept: []string{"main", "old"},
+		},
+		{
+			name:     "distinct unresolved purls",
+			repos:    []RepositoryConfig{{PURL: "pkg:github/gin-gonic/gin@v1.10.0", Branch: "gin"}, {PURL: "pkg:npm/express@4.18.2", Branch: "express"}},
+			wantKept: []string{"gin", "express"},
+		},
+		{
+			name:     "same purl twice",
+			repos:    []RepositoryConfig{{PURL: "pkg:npm/express@4.18.2", Branch: "a"}, {PURL: "pkg:npm/express@4.18.2", Branch: "b"}},
+			wantErr:  true,
+			wantKept: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := checkDuplicateRepositories(tt.repos, false); (err != nil) != tt.wantErr {
+				t.Errorf("checkDuplicateRepositories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			kept, err := checkDuplicateRepositories(tt.repos, true)
+			if err != nil {
+				t.Fatalf("checkDuplicateRepositories(merge) error = %v", err)
+			}
+			var branches []string
+			for _, repo := range kept {
+				branches = append(branches, repo.Branch)
+			}
+			if strings.Join(branches, ",") != strings.Join(tt.wantKept, ",") {
+				t.Errorf("merged branches = %v, want %v", branches, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestPURLEntriesAreNotDuplicates(t *testing.T) {
+	// The pURL example from the README, checked before the entries resolve
+	data := []byte(`repositories:
+  - purl: "pkg:github/gin-gonic/gin@v1.10.0"
+  - purl: "pkg:npm/express@4.18.2"
+  - purl: "pkg:docker/nginx@1.25?repository_url=https://github.com/nginx/nginx"
+  - purl: "pkg:github/foo/bar@v2.0.0"
+    version: "v1.0.0"
+`)
+	repos, err := parseRepositories(data, false)
+	if err != nil {
+		t.Fatalf("parseRepositories() error = %v", err)
+	}
+	kept, err := checkDuplicateRepositories(repos, false)
+	if err != nil {
+		t.Fatalf("checkDuplicateRepositories() error = %v", err)
+	}
+	if len(kept) != 4 {
+		t.Errorf("kept %d entries, want all 4", len(kept))
+	}
+}
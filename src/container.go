@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// defaultContainerRuntime runs image scanners when container_runtime is unset
+const defaultContainerRuntime = "docker"
+
+// Paths the host directories are mounted at inside a scanner container
+const (
+	containerRepoDir      = "/src"      // repository (or sub-project), read-only
+	containerResultsDir   = "/results"  // results directory, writable for {{output}}
+	containerRenderedDir  = "/rendered" // rendered Helm charts, read-only
+	containerSBOMDir      = "/sbom"     // SBOM directory when outside the results directory
+	containerOfflineDBDir = "/grype-db" // offline_db_dir, read-only
+)
+
+// containerRemoveTimeout bounds removing a scanner container killed at its
+// timeout
+const containerRemoveTimeout = 30 * time.Second
+
+// containerSeq numbers the scanner containers of this process, so each run
+// gets a unique name
+var containerSeq atomic.Int64
+
+// containerRuntime returns the runtime that runs image scanners
+func containerRuntime(global GlobalConfig) string {
+	if global.ContainerRuntime != "" {
+		return global.ContainerRuntime
+	}
+	return defaultContainerRuntime
+}
+
+// containerMount is a host directory bind-mounted into a scanner container
+type containerMount struct {
+	Host      string
+	Container string
+	ReadOnly  bool
+}
+
+// volumeFlag renders the mount as a -v value, e.g. "/work/repo:/src:ro"
+func (m containerMount) volumeFlag() string {
+	flag := m.Host + ":" + m.Container
+	if m.ReadOnly {
+		flag += ":ro"
+	}
+	return flag
+}
+
+// containerPath maps a host path inside one of the mounts to the same file
+// in the container. The most specific mount wins, so in --local mode an
+// output file in ./scan-results maps to the writable results mount rather
+// than into the read-only repo.
+func containerPath(hostPath string, mounts []containerMount) (string, bool) {
+	mapped, longest := "", -1
+	for _, m := range mounts {
+		rel, err := filepath.Rel(m.Host, hostPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(m.Host) > longest {
+			mapped, longest = filepath.ToSlash(filepath.Join(m.Container, rel)), len(m.Host)
+		}
+	}
+	return mapped, longest >= 0
+}
+
+// containerName returns a unique name for a scanner's container, so it can be
+// removed by name when the scanner times out
+func containerName(scanner string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, scanner)
+	return fmt.Sprintf("allscan-%s-%d-%d", safe, os.Getpid(), containerSeq.Add(1))
+}
+
+// removeContainer force-removes a scanner container. Killing the runtime
+// client at the scanner's timeout leaves the container itself running.
+func removeContainer(runtime, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+	defer cancel()
+	if output, err := exec.CommandContext(ctx, runtime, "rm", "-f", name).CombinedOutput(); err != nil {
+		log.Printf("    ⚠️  Failed to remove container %s: %v %s", name, err, strings.TrimSpace(string(output)))
+	}
+}
+
+// containerMounts returns the mounts for an image scanner: the repository
+// read-only, the results directory writable, and the rendered Helm charts, an
+// SBOM outside the results directory and the offline_db_dir read-only when in
+// use
+func containerMounts(repoPath, resultsDir, sbomPath, renderedDir string) []containerMount {
+	mounts := []containerMount{
+		{Host: repoPath, Container: containerRepoDir, ReadOnly: true},
+		{Host: resultsDir, Container: containerResultsDir},
+	}
+	if renderedDir != "" {
+		mounts = append(mounts, containerMount{Host: renderedDir, Container: containerRenderedDir, ReadOnly: true})
+	}
+	if sbomPath != "" {
+		if _, ok := containerPath(sbomPath, mounts); !ok {
+			mounts = append(mounts, containerMount{Host: filepath.Dir(sbomPath), Container: containerSBOMDir, ReadOnly: true})
+		}
+	}
+	if offlineDBDir != "" {
+		mounts = append(mounts, containerMount{Host: offlineDBDir, Container: containerOfflineDBDir, ReadOnly: true})
+	}
+	return mounts
+}
+
+// containerRunArgs builds the container runtime arguments that run an image
+// scanner: "run --rm" with the container name, the scanner's working
+// directory mapped into the mounted repo, its required env vars and env
+// passed through by name (so their values never appear on the command line),
+// the offline and prefetched grype DB settings, the volume mounts, the
+// command as entrypoint when set, then the image and the scanner args.
+// Template variables in the args refer to the container paths, so
+// {{output}} lands in the mounted results directory.
+func containerRunArgs(runtime, name string, scanner ScannerConfig, selectedArgs []string, repo RepositoryConfig, repoPath, workDir, resultsDir, outputPath, sbomPath, commitHash, renderedDir string) ([]string, error) {
+	mounts := containerMounts(repoPath, resultsDir, sbomPath, renderedDir)
+
+	containerWorkDir, ok := containerPath(workDir, mounts)
+	if !ok {
+		return nil, fmt.Errorf("working directory %s is outside the repository", workDir)
+	}
+	containerOutput, ok := containerPath(outputPath, mounts)
+	if !ok {
+		return nil, fmt.Errorf("output path %s is outside the results directory", outputPath)
+	}
+	var containerSBOM, containerRendered string
+	if sbomPath != "" {
+		containerSBOM, _ = containerPath(sbomPath, mounts)
+	}
+	if renderedDir != "" {
+		containerRendered = containerRenderedDir
+	}
+
+	args := []string{"run", "--rm", "--name", name, "-w", containerWorkDir}
+	// Docker runs as root by default; run as the invoking user so result files
+	// can be rewritten (redact_secrets) and cleaned up. Rootless podman
+	// already maps container root to the invoking user.
+	if filepath.Base(runtime) == "docker" && os.Getuid() >= 0 {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	for _, env := range scanner.RequiredEnv {
+		args = append(args, "-e", env)
+	}
+	for _, env := range envNames(scanner.Env) {
+		if !containsString(scanner.RequiredEnv, env) {
+			args = append(args, "-e", env)
+		}
+	}
+	// Set on the host by setOfflineDBEnv and setGrypeDBEnv; the values hold
+	// no secrets, and the DB path is the mounted offline_db_dir
+	var dbEnv []string
+	if offlineDBDir != "" {
+		dbEnv = offlineDBEnv(containerOfflineDBDir)
+	}
+	for _, v := range append(dbEnv, grypeDBEnv()...) {
+		args = append(args, "-e", v)
+	}
+	for _, m := range mounts {
+		args = append(args, "-v", m.volumeFlag())
+	}
+	if scanner.Command != "" {
+		args = append(args, "--entrypoint", scanner.Command)
+	}
+	args = append(args, scanner.Image)
+	return append(args, expandArgTemplates(selectedArgs, repo, containerOutput, containerSBOM, commitHash, containerRendered)...), nil
+}
+
+// validateContainerScanners rejects image scanners whose command is a
+// built-in scanner, which runs inside allscan and can't use an image
+func validateContainerScanners(scanners []ScannerConfig) error {
+	for _, scanner := range scanners {
+		if scanner.Image != "" && strings.HasPrefix(scanner.Command, "builtin:") {
+			return fmt.Errorf("scanner %s: image can't be used with built-in command %s", scanner.Name, scanner.Command)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContainerPath(t *testing.T) {
+	mounts := []containerMount{
+		{Host: "/work/repo", Container: containerRepoDir, ReadOnly: true},
+		{Host: "/work/repo/scan-results", Container: containerResultsDir},
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"repo root", "/work/repo", "/src", true},
+		{"repo subdirectory", "/work/repo/backend", "/src/backend", true},
+		{"nested mount wins", "/work/repo/scan-results/out.json", "/results/out.json", true},
+		{"sibling with common prefix", "/work/repo2/file", "", false},
+		{"outside every mount", "/tmp/file", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := containerPath(tt.path, mounts)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("containerPath(%q) = (%q, %v), want (%q, %v)", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestContainerRunArgs(t *testing.T) {
+	user := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	repo := RepositoryConfig{URL: "https://github.com/org/widget"}
+
+	tests := []struct {
+		name        string
+		runtime     string
+		scanner     ScannerConfig
+		workDir     string
+		resultsDir  string
+		outputPath  string
+		sbomPath    string
+		renderedDir string
+		want        []string
+	}{
+		{
+			name:       "docker runs as the invoking user",
+			runtime:    "docker",
+			scanner:    ScannerConfig{Image: "example/scanner:1.0", Args: []string{"scan", "--out", "{{output}}", "."}},
+			workDir:    "/work/widget",
+			resultsDir: "/data/results",
+			outputPath: "/data/results/widget_abc1234_scanner_20240115.json",
+			want: []string{"run", "--rm", "--name", "allscan-test", "-w", "/src", "--user", user,
+				"-v", "/work/widget:/src:ro", "-v", "/data/results:/results",
+				"example/scanner:1.0", "scan", "--out", "/results/widget_abc1234_scanner_20240115.json", "."},
+		},
+		{
+			name:       "podman with entrypoint, env and working dir",
+			runtime:    "/usr/bin/podman",
+			scanner:    ScannerConfig{Image: "example/scanner:1.0", Command: "scanner", RequiredEnv: []string{"SCANNER_TOKEN"}, Env: map[string]string{"SCANNER_TOKEN": "x", "SCANNER_ORG": "payments"}, Args: []string{"{{output}}"}},
+			workDir:    "/work/widget/backend",
+			resultsDir: "/data/results",
+			outputPath: "/data/results/out.json",
+			want: []string{"run", "--rm", "--name", "allscan-test", "-w", "/src/backend", "-e", "SCANNER_TOKEN", "-e", "SCANNER_ORG",
+				"-v", "/work/widget:/src:ro", "-v", "/data/results:/results",
+				"--entrypoint", "scanner", "example/scanner:1.0", "/results/out.json"},
+		},
+		{
+			name:       "local mode results inside the repo",
+			runtime:    "podman",
+			scanner:    ScannerConfig{Image: "example/grype", Args: []string{"sbom:{{sbom}}", "-o", "json", "--file", "{{output}}"}},
+			workDir:    "/work/widget",
+			resultsDir: "/work/widget/scan-results",
+			outputPath: "/work/widget/scan-results/out.json",
+			sbomPath:   "/work/widget/scan-results/sboms/widget.cdx.json",
+			want: []string{"run", "--rm", "--name", "allscan-test", "-w", "/src",
+				"-v", "/work/widget:/src:ro", "-v", "/work/widget/scan-results:/results",
+				"example/grype", "sbom:/results/sboms/widget.cdx.json", "-o", "json", "--file", "/results/out.json"},
+		},
+		{
+			name:        "sbom outside results and rendered charts",
+			runtime:     "podman",
+			scanner:     ScannerConfig{Image: "example/kubescape", Args: []string{"{{rendered}}", "{{sbom}}"}},
+			workDir:     "/work/widget",
+			resultsDir:  "/data/results",
+			outputPath:  "/data/results/out.json",
+			sbomPath:    "/tmp/sboms/widget.cdx.json",
+			renderedDir: "/tmp/helm-render-123",
+			want: []string{"run", "--rm", "--name", "allscan-test", "-w", "/src",
+				"-v", "/work/widget:/src:ro", "-v", "/data/results:/results",
+				"-v", "/tmp/helm-render-123:/rendered:ro", "-v", "/tmp/sboms:/sbom:ro",
+				"example/kubescape", "/rendered", "/sbom/widget.cdx.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := containerRunArgs(tt.runtime, "allscan-test", tt.scanner, tt.scanner.Args, repo, "/work/widget", tt.workDir, tt.resultsDir, tt.outputPath, tt.sbomPath, "abc1234", tt.renderedDir)
+			if err != nil {
+				t.Fatalf("containerRunArgs() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("containerRunArgs() =\n  %q\nwant\n  %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("output outside the results directory", func(t *testing.T) {
+		scanner := ScannerConfig{Image: "example/scanner"}
+		if _, err := containerRunArgs("podman", "allscan-test", scanner, nil, repo, "/work/widget", "/work/widget", "/data/results", "/elsewhere/out.json", "", "abc1234", ""); err == nil {
+			t.Error("expected an error for an output path outside the results directory")
+		}
+	})
+
+	grypeDBTests := []struct {
+		name       string
+		offlineDir string
+		prefetched bool
+		want       []string
+	}{
+		{
+			name:       "offline DB mounted read-only",
+			offlineDir: "/opt/grype-db",
+			want: []string{"run", "--rm", "--name", "allscan-test", "-w", "/src",
+				"-e", "GRYPE_DB_CACHE_DIR=/grype-db", "-e", "GRYPE_DB_AUTO_UPDATE=false", "-e", "GRYPE_DB_VALIDATE_AGE=false",
+				"-e", "GRYPE_CHECK_FOR_APP_UPDATE=false", "-e", "SYFT_CHECK_FOR_APP_UPDATE=false",
+				"-v", "/work/widget:/src:ro", "-v", "/data/results:/results", "-v", "/opt/grype-db:/grype-db:ro",
+				"anchore/grype", "."},
+		},
+		{
+			name:       "prefetched DB",
+			prefetched: true,
+			want: []string{"run", "--rm", "--name", "allscan-test", "-w", "/src", "-e", "GRYPE_DB_AUTO_UPDATE=false",
+				"-v", "/work/widget:/src:ro", "-v", "/data/results:/results", "anchore/grype", "."},
+		},
+	}
+	for _, tt := range grypeDBTests {
+		t.Run(tt.name, func(t *testing.T) {
+			origDir, origPrefetched := offlineDBDir, grypeDBPrefetched
+			offlineDBDir, grypeDBPrefetched = tt.offlineDir, tt.prefetched
+			t.Cleanup(func() { offlineDBDir, grypeDBPrefetched = origDir, origPrefetched })
+
+			scanner := ScannerConfig{Image: "anchore/grype", Args: []string{"."}}
+			got, err := containerRunArgs("podman", "allscan-test", scanner, scanner.Args, repo, "/work/widget", "/work/widget", "/data/results", "/data/results/out.json", "", "abc1234", "")
+			if err != nil {
+				t.Fatalf("containerRunArgs() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("containerRunArgs() =\n  %q\nwant\n  %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerName(t *testing.T) {
+	first, second := containerName("osv scanner"), containerName("osv scanner")
+	if first == second {
+		t.Errorf("containerName() returned %q twice", first)
+	}
+	if prefix := fmt.Sprintf("allscan-osv-scanner-%d-", os.Getpid()); !strings.HasPrefix(first, prefix) {
+		t.Errorf("containerName() = %q, want prefix %q", first, prefix)
+	}
+}
+
+func TestRunScannerInContainer(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "runtime-args")
+	runtime := filepath.Join(dir, "fake-runtime.sh")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\necho '{\"matches\": []}'\n"
+	if err := os.WriteFile(runtime, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := ScannerConfig{
+		Name:    "fake",
+		Enabled: true,
+		Image:   "example/scanner:1.0",
+		Args:    []string{"scan", "."},
+		timeout: 10 * time.Second,
+	}
+	resultsDir := filepath.Join(dir, "results")
+	config := &Config{Global: GlobalConfig{ResultsDir: resultsDir, ContainerRuntime: runtime, RecordCommands: true}}
+	repo := RepositoryConfig{URL: "https://github.com/org/repo"}
+
+	result := runScanner(config, scanner, repo, dir, "abc1234", "main", "", nil, nil)
+	if !result.Success {
+		t.Fatalf("runScanner() failed: %v", result.Error)
+	}
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("runtime was not run: %v", err)
+	}
+	for _, want := range []string{"run --rm --name allscan-fake-", "-v " + dir + ":/src:ro", "-v " + resultsDir + ":/results", "example/scanner:1.0 scan ."} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("runtime args %q missing %q", data, want)
+		}
+	}
+	if result.Command[0] != runtime || result.Version != "example/scanner:1.0" {
+		t.Errorf("recorded command %q, version %q; want the runtime and image", result.Command, result.Version)
+	}
+}
+
+func TestRunScannerInContainerTimeout(t *testing.T) {
+	dir := t.TempDir()
+	removed := filepath.Join(dir, "removed")
+	runtime := filepath.Join(dir, "fake-runtime.sh")
+	script := "#!/bin/sh\nif [ \"$1\" = rm ]; then echo \"$@\" > " + removed + "; exit 0; fi\nexec sleep 5\n"
+	if err := os.WriteFile(runtime, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := ScannerConfig{Name: "slow", Enabled: true, Image: "example/slow", Args: []string{"."}, timeout: 100 * time.Millisecond}
+	config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results"), ContainerRuntime: runtime}}
+
+	result := runScanner(config, scanner, RepositoryConfig{URL: "https://github.com/org/repo"}, dir, "abc1234", "main", "", nil, nil)
+	if result.Success {
+		t.Fatal("runScanner() succeeded, want a timeout")
+	}
+	data, err := os.ReadFile(removed)
+	if err != nil {
+		t.Fatalf("timed-out container was not removed: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); !strings.HasPrefix(got, "rm -f allscan-slow-") {
+		t.Errorf("runtime called with %q, want rm -f of the scanner's container", got)
+	}
+}
+
+func TestValidateContainerScanners(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanner ScannerConfig
+		wantErr bool
+	}{
+		{"image scanner", ScannerConfig{Name: "grype", Image: "anchore/grype"}, false},
+		{"image with entrypoint", ScannerConfig{Name: "grype", Image: "anchore/grype", Command: "/grype"}, false},
+		{"builtin without image", ScannerConfig{Name: "licenses", Command: "builtin:licenses"}, false},
+		{"builtin with image", ScannerConfig{Name: "licenses", Command: "builtin:licenses", Image: "example/x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateContainerScanners([]ScannerConfig{tt.scanner})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateContainerScanners() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// layerEnv returns a repository's env vars with the scanner's own env
+// layered on top, so a scanner-level value wins over a repo-level one
+func layerEnv(repoEnv, scannerEnv map[string]string) map[string]string {
+	if len(repoEnv) == 0 {
+		return scannerEnv
+	}
+	env := make(map[string]string, len(repoEnv)+len(scannerEnv))
+	for name, value := range repoEnv {
+		env[name] = value
+	}
+	for name, value := range scannerEnv {
+		env[name] = value
+	}
+	return env
+}
+
+// envVars returns env as NAME=value pairs sorted by name, with ${VAR} and
+// $VAR references in the values expanded from allscan's own environment
+func envVars(env map[string]string) []string {
+	names := envNames(env)
+	vars := make([]string, 0, len(names))
+	for _, name := range names {
+		vars = append(vars, name+"="+os.ExpandEnv(env[name]))
+	}
+	return vars
+}
+
+// envNames returns the names set in env, sorted
+func envNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateEnv rejects env var names that can't be set on a process
+func validateEnv(field string, env map[string]string) error {
+	for _, name := range envNames(env) {
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			return fmt.Errorf("%s: invalid environment variable name %q", field, name)
+		}
+	}
+	return nil
+}
+
+// validateScannerEnv checks the env of every scanner
+func validateScannerEnv(scanners []ScannerConfig) error {
+	for _, scanner := range scanners {
+		if err := validateEnv("scanner "+scanner.Name+" env", scanner.Env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
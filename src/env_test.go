@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunScannerEnv(t *testing.T) {
+	t.Setenv("ALLSCAN_TEST_SNYK_ORG", "acme-payments")
+
+	tests := []struct {
+		name       string
+		repoEnv    map[string]string
+		scannerEnv map[string]string
+		required   []string
+		want       string
+	}{
+		{name: "repo env reaches the scanner", repoEnv: map[string]string{"SNYK_ORG": "payments", "LEVEL": "repo"}, want: "payments repo"},
+		{name: "scanner env overrides repo env", repoEnv: map[string]string{"SNYK_ORG": "payments", "LEVEL": "repo"}, scannerEnv: map[string]string{"LEVEL": "scanner"}, want: "payments scanner"},
+		{name: "scanner env without repo env", scannerEnv: map[string]string{"LEVEL": "scanner"}, want: " scanner"},
+		{name: "${VAR} expands from allscan's environment", repoEnv: map[string]string{"SNYK_ORG": "${ALLSCAN_TEST_SNYK_ORG}"}, want: "acme-payments "},
+		{name: "repo env supplies required_env", repoEnv: map[string]string{"SNYK_ORG": "payments"}, required: []string{"SNYK_ORG"}, want: "payments "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			scanner := ScannerConfig{
+				Name:        "fake",
+				Enabled:     true,
+				Command:     writeFakeScanner(t, dir, `printf '%s %s' "$SNYK_ORG" "$LEVEL" > "$1"`),
+				Args:        []string{"{{output}}", filepath.Join(dir, "attempts")},
+				Env:         tt.scannerEnv,
+				RequiredEnv: tt.required,
+				timeout:     10 * time.Second,
+			}
+			config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+			repo := RepositoryConfig{URL: "https://github.com/org/repo", Env: tt.repoEnv}
+
+			result := runScanner(config, scanner, repo, dir, "abc1234", "main", "", nil, nil)
+			if !result.Success {
+				t.Fatalf("runScanner() failed: %v", result.Error)
+			}
+			got, err := os.ReadFile(result.OutputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("scanner saw SNYK_ORG, LEVEL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "valid", env: map[string]string{"SNYK_ORG": "payments", "EMPTY": ""}},
+		{name: "empty name", env: map[string]string{"": "x"}, wantErr: true},
+		{name: "name with =", env: map[string]string{"A=B": "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := validateEnv("env", tt.env)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateEnv() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !strings.HasPrefix(err.Error(), "env: ") {
+			t.Errorf("%s: error %q doesn't name the field", tt.name, err)
+		}
+	}
+}
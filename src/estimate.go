@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// loadScannerDurations reads a run report written by --output and returns
+// each scanner's mean recorded duration across its results. Reports without
+// durations (older ones, or from --parse-only) yield an empty map.
+func loadScannerDurations(path string) (map[string]time.Duration, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading previous run report: %w", err)
+	}
+
+	var report RunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parsing previous run report: %w", err)
+	}
+
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, entry := range report.Results {
+		if entry.DurationMs <= 0 {
+			continue
+		}
+		totals[entry.Scanner] += time.Duration(entry.DurationMs) * time.Millisecond
+		counts[entry.Scanner]++
+	}
+	durations := make(map[string]time.Duration, len(totals))
+	for name, total := range totals {
+		durations[name] = total / time.Duration(counts[name])
+	}
+	return durations, nil
+}
+
+// RunEstimate is the expected wall-clock time of a run (--list-repos)
+type RunEstimate struct {
+	Total        time.Duration
+	Repos        int
+	AvgScanners  float64
+	FromTimeouts []string // Scanners without a recorded duration, estimated at their timeout
+}
+
+// repoEstimateScanners returns the scanners a run would start for a repo
+// before language filtering, as counted by repoScannerCount: the --scan
+// selection, or the enabled scanners the repo selects (all when it selects
+// none)
+func repoEstimateScanners(config *Config, repo RepositoryConfig) []ScannerConfig {
+	var scanners []ScannerConfig
+	if filter := config.Global.ScanFilter; len(filter) > 0 {
+		for _, scanner := range config.Scanners {
+			if containsString(filter, scanner.Name) {
+				scanners = append(scanners, scanner)
+			}
+		}
+		return scanners
+	}
+	names := repoScannerNames(config.Global, repo)
+	for _, scanner := range config.Scanners {
+		if scanner.Enabled && (len(names) == 0 || containsString(names, scanner.Name)) {
+			scanners = append(scanners, scanner)
+		}
+	}
+	return scanners
+}
+
+// estimateRun estimates how long scanning repos takes. Each scanner costs its
+// mean duration in durations, or its timeout when it has none. Scanners in a
+// repo run one after another; repos are handed in order to the
+// repoScanWorkers workers, each taking the next repo as it becomes free.
+// Disabled repos are left out unless --include-disabled is set.
+func estimateRun(config *Config, repos []RepositoryConfig, durations map[string]time.Duration) RunEstimate {
+	var estimate RunEstimate
+	var repoTimes []time.Duration
+	scannerCount := 0
+	for _, repo := range repos {
+		if repo.Disabled && !config.Global.IncludeDisabled {
+			continue
+		}
+		var repoTime time.Duration
+		for _, scanner := range repoEstimateScanners(config, repo) {
+			duration, ok := durations[scanner.Name]
+			if !ok {
+				duration = scanner.timeout
+				if !containsString(estimate.FromTimeouts, scanner.Name) {
+					estimate.FromTimeouts = append(estimate.FromTimeouts, scanner.Name)
+				}
+			}
+			repoTime += duration
+			scannerCount++
+		}
+		repoTimes = append(repoTimes, repoTime)
+	}
+
+	estimate.Repos = len(repoTimes)
+	if estimate.Repos == 0 {
+		return estimate
+	}
+	estimate.AvgScanners = float64(scannerCount) / float64(estimate.Repos)
+
+	workers := make([]time.Duration, repoScanWorkers(config.Global.ParallelRepos, config.Global.MaxConcurrent, len(repoTimes)))
+	for _, repoTime := range repoTimes {
+		free := slices.Index(workers, slices.Min(workers))
+		workers[free] += repoTime
+	}
+	estimate.Total = slices.Max(workers)
+	slices.Sort(estimate.FromTimeouts)
+	return estimate
+}
+
+// formatEstimate formats an estimated duration to the minute, or to the
+// second below a minute
+func formatEstimate(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return formatTimeout(d.Round(time.Minute))
+}
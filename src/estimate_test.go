@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEstimateRun(t *testing.T) {
+	scanners := []ScannerConfig{
+		{Name: "gosec", Enabled: true, timeout: 5 * time.Minute},
+		{Name: "grype", Enabled: true, timeout: 15 * time.Minute},
+		{Name: "trivy", Enabled: true, timeout: 10 * time.Minute},
+		{Name: "retired", Enabled: false, timeout: time.Hour},
+	}
+	repos := []RepositoryConfig{
+		{URL: "https://github.com/org/a"},                              // gosec, grype, trivy
+		{URL: "https://github.com/org/b", Scanners: []string{"grype"}}, // grype
+		{URL: "https://github.com/org/c", Scanners: []string{"gosec"}}, // gosec
+		{URL: "https://github.com/org/d", Scanners: []string{"gosec"}}, // gosec
+		{URL: "https://github.com/org/e", Disabled: true},              // not scanned
+	}
+	history := map[string]time.Duration{"gosec": time.Minute, "grype": 4 * time.Minute, "trivy": 3 * time.Minute}
+
+	tests := []struct {
+		name          string
+		parallel      bool
+		maxConcurrent int
+		scanFilter    []string
+		durations     map[string]time.Duration
+		want          RunEstimate
+	}{
+		{
+			name:      "sequential repos add up",
+			durations: history,
+			want:      RunEstimate{Total: 14 * time.Minute, Repos: 4, AvgScanners: 1.5},
+		},
+		{
+			// a (8m) and b (4m) start together; c and d follow b: 4+1+1 = 6m
+			name:          "parallel repos take the longest worker",
+			parallel:      true,
+			maxConcurrent: 2,
+			durations:     history,
+			want:          RunEstimate{Total: 8 * time.Minute, Repos: 4, AvgScanners: 1.5},
+		},
+		{
+			name:          "more workers than repos",
+			parallel:      true,
+			maxConcurrent: 10,
+			durations:     history,
+			want:          RunEstimate{Total: 8 * time.Minute, Repos: 4, AvgScanners: 1.5},
+		},
+		{
+			name:      "no history falls back to timeouts",
+			durations: nil,
+			want:      RunEstimate{Total: 55 * time.Minute, Repos: 4, AvgScanners: 1.5, FromTimeouts: []string{"gosec", "grype", "trivy"}},
+		},
+		{
+			name:      "partial history",
+			durations: map[string]time.Duration{"gosec": time.Minute},
+			want:      RunEstimate{Total: 43 * time.Minute, Repos: 4, AvgScanners: 1.5, FromTimeouts: []string{"grype", "trivy"}},
+		},
+		{
+			name:       "--scan selection runs on every repo, even disabled scanners",
+			scanFilter: []string{"gosec", "retired"},
+			durations:  history,
+			want:       RunEstimate{Total: 4*time.Minute + 4*time.Hour, Repos: 4, AvgScanners: 2, FromTimeouts: []string{"retired"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Global:   GlobalConfig{ParallelRepos: tt.parallel, MaxConcurrent: tt.maxConcurrent, ScanFilter: tt.scanFilter},
+				Scanners: scanners,
+			}
+			if got := estimateRun(config, repos, tt.durations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("estimateRun() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no repos", func(t *testing.T) {
+		config := &Config{Scanners: scanners}
+		if got := estimateRun(config, nil, history); got.Repos != 0 || got.Total != 0 {
+			t.Errorf("estimateRun() = %+v, want an empty estimate", got)
+		}
+	})
+}
+
+func TestLoadScannerDurations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.json")
+	report := `{"results": [
+		{"repository": "https://github.com/org/a", "scanner": "grype", "success": true, "duration_ms": 60000},
+		{"repository": "https://github.com/org/b", "scanner": "grype", "success": false, "duration_ms": 180000},
+		{"repository": "https://github.com/org/a", "scanner": "gosec", "success": true, "duration_ms": 1500},
+		{"repository": "https://github.com/org/a", "scanner": "trivy", "success": true}
+	]}`
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadScannerDurations(path)
+	if err != nil {
+		t.Fatalf("loadScannerDurations() error = %v", err)
+	}
+	want := map[string]time.Duration{"grype": 2 * time.Minute, "gosec": 1500 * time.Millisecond}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadScannerDurations() = %v, want %v", got, want)
+	}
+
+	if _, err := loadScannerDurations(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadScannerDurations() of a missing file succeeded")
+	}
+}
+
+func TestFormatEstimate(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{42 * time.Second, "42s"},
+		{42*time.Minute + 20*time.Second, "42m"},
+		{42*time.Minute + 40*time.Second, "43m"},
+		{90 * time.Minute, "1h30m"},
+		{2 * time.Hour, "2h"},
+	}
+	for _, tt := range tests {
+		if got := formatEstimate(tt.d); got != tt.want {
+			t.Errorf("formatEstimate(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
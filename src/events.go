@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"allscan/parsers"
+)
+
+// Event types written to the --events-jsonl stream
+const (
+	eventRepoStart    = "repo-start"
+	eventCloneDone    = "clone-done"
+	eventScannerStart = "scanner-start"
+	eventScannerDone  = "scanner-done"
+	eventRepoDone     = "repo-done"
+)
+
+// Event is one line of the --events-jsonl stream
+type Event struct {
+	Time       time.Time               `json:"time"`
+	Event      string                  `json:"event"`
+	Repository string                  `json:"repository"`
+	Subproject string                  `json:"subproject,omitempty"`
+	Scanner    string                  `json:"scanner,omitempty"`
+	Commit     string                  `json:"commit,omitempty"`      // clone-done: short commit hash checked out
+	Ref        string                  `json:"ref,omitempty"`         // clone-done: branch or tag checked out
+	Success    *bool                   `json:"success,omitempty"`     // clone-done, scanner-done, repo-done
+	Error      string                  `json:"error,omitempty"`       // why a clone, scanner or repo failed
+	DurationMS int64                   `json:"duration_ms,omitempty"` // scanner-done: scanner run time
+	Summary    *parsers.FindingSummary `json:"summary,omitempty"`     // scanner-done: parsed findings
+	Scanners   int                     `json:"scanners,omitempty"`    // repo-done: scanners run across all targets
+	Failed     int                     `json:"failed,omitempty"`      // repo-done: scanners that failed
+}
+
+// events is the run's event stream; nil (no --events-jsonl) emits nothing
+var events *eventStream
+
+// eventStream writes events as JSON lines. Repos scanned in parallel emit
+// concurrently, so each event is written whole under a lock.
+type eventStream struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer // nil when writing to stdout
+}
+
+// openEventStream opens the --events-jsonl destination: "-" for stdout
+// (main then moves the human output to stderr), otherwise a file that is
+// created or truncated
+func openEventStream(path string) (*eventStream, error) {
+	if path == "-" {
+		return &eventStream{out: os.Stdout}, nil
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return nil, fmt.Errorf("creating events directory: %w", err)
+		}
+	}
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	return &eventStream{out: f, closer: f}, nil
+}
+
+// emit writes an event, stamping its time. Write errors are ignored: the
+// stream is best-effort and must never fail a scan.
+func (s *eventStream) emit(event Event) {
+	if s == nil {
+		return
+	}
+	event.Time = time.Now().UTC()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
+
+// close closes the event file (stdout is left open)
+func (s *eventStream) close() error {
+	if s == nil || s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// errorString returns err's message, or "" for nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// cloneDoneEvent describes the outcome of cloning or updating a repository
+func cloneDoneEvent(repo RepositoryConfig, commitHash, branchTag string, err error) Event {
+	success := err == nil
+	return Event{
+		Event:      eventCloneDone,
+		Repository: repo.URL,
+		Commit:     commitHash,
+		Ref:        branchTag,
+		Success:    &success,
+		Error:      errorString(err),
+	}
+}
+
+// scannerDoneEvent describes a finished scanner with its parsed findings
+// (none for failed or SARIF results)
+func scannerDoneEvent(repo RepositoryConfig, result ScanResult) Event {
+	event := Event{
+		Event:      eventScannerDone,
+		Repository: repo.URL,
+		Subproject: repo.Subproject,
+		Scanner:    result.Scanner,
+		Success:    &result.Success,
+		Error:      errorString(result.Error),
+		DurationMS: result.Duration.Milliseconds(),
+	}
+	if result.Success && !result.IsSarif {
+		if summary, parser := parseScanOutput(result); parser != nil {
+			event.Summary = &summary
+		}
+	}
+	return event
+}
+
+// repoDoneEvent sums up a repository's targets once all of them are scanned.
+// A repo that produced no targets (invalid config or failed clone) failed.
+func repoDoneEvent(repo RepositoryConfig, contexts []RepoScanContext) Event {
+	event := Event{Event: eventRepoDone, Repository: repo.URL}
+	for _, ctx := range contexts {
+		if ctx.CloneError != nil {
+			event.Error = "clone failed: " + ctx.CloneError.Error()
+		}
+		for _, result := range ctx.Results {
+			event.Scanners++
+			if !result.Success {
+				event.Failed++
+			}
+		}
+	}
+	success := len(contexts) > 0 && event.Failed == 0 && event.Error == ""
+	if len(contexts) == 0 {
+		event.Error = "repository was not scanned"
+	}
+	event.Success = &success
+	return event
+}
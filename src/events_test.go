@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captureEvents routes the event stream to a buffer for the rest of the test
+func captureEvents(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	events = &eventStream{out: &buf}
+	t.Cleanup(func() { events = nil })
+	return &buf
+}
+
+// decodeEvents parses a JSON lines event stream
+func decodeEvents(t *testing.T, data []byte) []Event {
+	t.Helper()
+	var got []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid event line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, event)
+	}
+	return got
+}
+
+func TestEventsForScannerRun(t *testing.T) {
+	buf := captureEvents(t)
+
+	dir := t.TempDir()
+	repoPath := filepath.Join(dir, "repo")
+	if err := os.MkdirAll(repoPath, 0750); err != nil {
+		t.Fatal(err)
+	}
+	// writeFakeScanner names every script the same, so give each its own dir
+	scannerDir := func(name string) string {
+		d := filepath.Join(dir, name)
+		if err := os.MkdirAll(d, 0750); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	attempts := filepath.Join(dir, "attempts")
+	config := &Config{
+		Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")},
+		Scanners: []ScannerConfig{
+			{
+				Name:    "gosec",
+				Enabled: true,
+				Command: writeFakeScanner(t, scannerDir("gosec"), `echo '{"Issues": [{"severity": "HIGH"}, {"severity": "LOW"}]}' > "$1"`),
+				Args:    []string{"{{output}}", attempts},
+				timeout: 5 * time.Second,
+			},
+			{
+				Name:    "broken",
+				Enabled: true,
+				Command: writeFakeScanner(t, scannerDir("broken"), "exit 3"),
+				Args:    []string{"{{output}}", attempts},
+				timeout: 5 * time.Second,
+			},
+		},
+	}
+	repo := RepositoryConfig{URL: "local://" + repoPath}
+
+	events.emit(Event{Event: eventRepoStart, Repository: repo.URL})
+	ctx := runScannersOnRepo(config, repo, repoPath, "abc1234", "main", "")
+	events.emit(repoDoneEvent(repo, []RepoScanContext{ctx}))
+
+	got := decodeEvents(t, buf.Bytes())
+	want := []struct {
+		event, scanner string
+		success        *bool
+	}{
+		{eventRepoStart, "", nil},
+		{eventScannerStart, "gosec", nil},
+		{eventScannerDone, "gosec", boolPtr(true)},
+		{eventScannerStart, "broken", nil},
+		{eventScannerDone, "broken", boolPtr(false)},
+		{eventRepoDone, "", boolPtr(false)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		e := got[i]
+		if e.Event != w.event || e.Scanner != w.scanner || e.Repository != repo.URL {
+			t.Errorf("event %d = %s/%s/%s, want %s/%s/%s", i, e.Event, e.Scanner, e.Repository, w.event, w.scanner, repo.URL)
+		}
+		if (e.Success == nil) != (w.success == nil) || (e.Success != nil && *e.Success != *w.success) {
+			t.Errorf("event %d (%s %s) success = %v, want %v", i, e.Event, e.Scanner, e.Success, w.success)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("event %d has no time", i)
+		}
+	}
+
+	if s := got[2].Summary; s == nil || s.High != 1 || s.Low != 1 || s.Total != 2 {
+		t.Errorf("gosec scanner-done summary = %+v, want 1 high and 1 low", s)
+	}
+	if got[4].Error == "" || got[4].Summary != nil {
+		t.Errorf("broken scanner-done = %+v, want an error and no summary", got[4])
+	}
+	if got[5].Scanners != 2 || got[5].Failed != 1 {
+		t.Errorf("repo-done = %+v, want 2 scanners with 1 failed", got[5])
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestCloneAndRepoDoneEvents(t *testing.T) {
+	repo := RepositoryConfig{URL: "https://github.com/org/repo"}
+
+	ok := cloneDoneEvent(repo, "abc1234", "v1.2.0", nil)
+	if ok.Success == nil || !*ok.Success || ok.Commit != "abc1234" || ok.Ref != "v1.2.0" || ok.Error != "" {
+		t.Errorf("cloneDoneEvent(success) = %+v", ok)
+	}
+	failed := cloneDoneEvent(repo, "", "", errors.New("authentication failed"))
+	if failed.Success == nil || *failed.Success || failed.Error != "authentication failed" {
+		t.Errorf("cloneDoneEvent(failure) = %+v", failed)
+	}
+
+	notScanned := repoDoneEvent(repo, nil)
+	if notScanned.Success == nil || *notScanned.Success || notScanned.Error == "" {
+		t.Errorf("repoDoneEvent(no contexts) = %+v, want a failure", notScanned)
+	}
+	clean := repoDoneEvent(repo, []RepoScanContext{
+		{Results: []ScanResult{{Success: true}}},
+		{Subproject: "web", Results: []ScanResult{{Success: true}}},
+	})
+	if clean.Success == nil || !*clean.Success || clean.Scanners != 2 || clean.Failed != 0 {
+		t.Errorf("repoDoneEvent(sub-projects) = %+v, want 2 successful scanners", clean)
+	}
+	cloneFailed := repoDoneEvent(repo, []RepoScanContext{{RepoURL: repo.URL, CloneError: errors.New("repository not found")}})
+	if cloneFailed.Success == nil || *cloneFailed.Success || cloneFailed.Error != "clone failed: repository not found" {
+		t.Errorf("repoDoneEvent(clone failure) = %+v, want a failure with the clone error", cloneFailed)
+	}
+}
+
+func TestEventStreamConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "events.jsonl")
+	stream, err := openEventStream(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stream.emit(Event{Event: eventScannerStart, Repository: fmt.Sprintf("https://github.com/org/repo%d", i), Scanner: "grype"})
+		}(i)
+	}
+	wg.Wait()
+	if err := stream.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeEvents(t, data); len(got) != 50 {
+		t.Errorf("got %d events, want 50", len(got))
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// explainStep is one check in a scanner selection decision
+type explainStep struct {
+	Check  string // "requested", "enabled", "languages", "required_manifests", "file_patterns", "required_env"
+	Passed bool
+	Detail string
+}
+
+// scannerDecision traces why a scanner was or wasn't selected for a repo.
+// It mirrors getScannersForRepo and isScannerCompatible for --explain.
+type scannerDecision struct {
+	Scanner  string
+	Selected bool // chosen by getScannersForRepo
+	Steps    []explainStep
+}
+
+// step returns the named check of the decision, if it was evaluated
+func (d scannerDecision) step(check string) (explainStep, bool) {
+	for _, s := range d.Steps {
+		if s.Check == check {
+			return s, true
+		}
+	}
+	return explainStep{}, false
+}
+
+// explainScannerSelection evaluates every configured scanner against a repo
+// and returns one decision per scanner, in config order. All checks are
+// recorded even after one fails, so the trace shows every reason at once.
+// Required env is checked at run time rather than selection time, so a
+// selected scanner with missing env is still skipped when the scan starts.
+func explainScannerSelection(config *Config, repo RepositoryConfig, detected *DetectedLanguages) []scannerDecision {
+	scanFilter := config.Global.ScanFilter
+	repoNames := repoScannerNames(config.Global, repo)
+
+	// Describe where the requested scanner list comes from
+	var requested []string
+	var source string
+	switch {
+	case len(scanFilter) > 0:
+		requested, source = scanFilter, "--scan"
+	case len(repo.Scanners) > 0:
+		requested, source = repoNames, "repo scanners"
+	case len(repoNames) > 0:
+		requested, source = repoNames, "bundle "+repo.Bundle
+	}
+
+	decisions := make([]scannerDecision, 0, len(config.Scanners))
+	for _, scanner := range config.Scanners {
+		d := scannerDecision{Scanner: scanner.Name}
+
+		// Included by --scan, the repo's scanner list, or its bundle?
+		if requested == nil {
+			d.Steps = append(d.Steps, explainStep{"requested", true, "all enabled scanners (no repo list or bundle)"})
+		} else if containsString(requested, scanner.Name) {
+			d.Steps = append(d.Steps, explainStep{"requested", true, "listed in " + source})
+		} else {
+			d.Steps = append(d.Steps, explainStep{"requested", false, "not listed in " + source})
+		}
+
+		// Enabled? (--scan overrides the enabled flag)
+		switch {
+		case len(scanFilter) > 0:
+			d.Steps = append(d.Steps, explainStep{"enabled", true, "overridden by --scan"})
+		case scanner.Enabled:
+			d.Steps = append(d.Steps, explainStep{"enabled", true, "enabled"})
+		default:
+			d.Steps = append(d.Steps, explainStep{"enabled", false, "disabled in config"})
+		}
+
+		// Language-compatible?
+		d.Steps = append(d.Steps, explainLanguages(scanner, detected))
+
+		// Required manifest or lock file present?
+		if len(scanner.RequiredManifests) > 0 {
+			d.Steps = append(d.Steps, explainManifests(scanner, detected))
+		}
+
+		// File patterns are documentation only; they don't affect selection
+		if len(scanner.FilePatterns) > 0 {
+			d.Steps = append(d.Steps, explainStep{"file_patterns", true,
+				strings.Join(scanner.FilePatterns, ", ") + " (informational, not used for selection)"})
+		}
+
+		// Required env present?
+		if missing := checkRequiredEnv(scanner.RequiredEnv, layerEnv(repo.Env, scanner.Env)); missing != "" {
+			d.Steps = append(d.Steps, explainStep{"required_env", false, missing + " not set (skipped at run time)"})
+		} else if len(scanner.RequiredEnv) > 0 {
+			d.Steps = append(d.Steps, explainStep{"required_env", true, strings.Join(scanner.RequiredEnv, ", ") + " set"})
+		}
+
+		d.Selected = true
+		for _, s := range d.Steps {
+			if !s.Passed && s.Check != "required_env" {
+				d.Selected = false
+			}
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// explainLanguages reports which detected languages a scanner matched or
+// missed, following the rules of isScannerCompatible
+func explainLanguages(scanner ScannerConfig, detected *DetectedLanguages) explainStep {
+	if len(scanner.Languages) == 0 {
+		return explainStep{"languages", true, "universal (no language restriction)"}
+	}
+
+	var matched, conditional []string
+	for _, lang := range scanner.Languages {
+		if detected.hasLanguage(lang) {
+			matched = append(matched, lang)
+		}
+	}
+	for _, lang := range scanner.LanguagesConditional {
+		if detected.hasLanguage(lang) {
+			conditional = append(conditional, lang)
+		}
+	}
+
+	detectedList := "none"
+	if len(detected.Languages) > 0 {
+		detectedList = strings.Join(detected.Languages, ", ")
+	}
+	switch {
+	case len(matched) > 0:
+		return explainStep{"languages", true, "matched " + strings.Join(matched, ", ")}
+	case len(conditional) > 0:
+		return explainStep{"languages", true, "conditionally matched " + strings.Join(conditional, ", ")}
+	default:
+		supported := append(append([]string{}, scanner.Languages...), scanner.LanguagesConditional...)
+		return explainStep{"languages", false,
+			fmt.Sprintf("supports %s; detected %s", strings.Join(supported, ", "), detectedList)}
+	}
+}
+
+// explainManifests reports which of a scanner's required manifests were found
+func explainManifests(scanner ScannerConfig, detected *DetectedLanguages) explainStep {
+	var found []string
+	for _, name := range scanner.RequiredManifests {
+		if detected.hasManifest(name) {
+			found = append(found, name)
+		}
+	}
+	if len(found) == 0 {
+		return explainStep{"required_manifests", false, "none of " + strings.Join(scanner.RequiredManifests, ", ") + " found"}
+	}
+	return explainStep{"required_manifests", true, "found " + strings.Join(found, ", ")}
+}
+
+// containsString reports whether s is one of list
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// formatScannerDecisions renders the --explain trace for one repo
+func formatScannerDecisions(name string, decisions []scannerDecision) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  🔎 Scanner selection for %s:\n", name)
+	for _, d := range decisions {
+		verdict := "✅ " + d.Scanner + ": selected"
+		if !d.Selected {
+			verdict = "⏭️  " + d.Scanner + ": skipped"
+		} else if env, ok := d.step("required_env"); ok && !env.Passed {
+			verdict = "⚠️  " + d.Scanner + ": selected, but required env is missing"
+		}
+		fmt.Fprintf(&b, "    %s\n", verdict)
+		for _, s := range d.Steps {
+			mark := "✔"
+			if !s.Passed {
+				mark = "✘"
+			}
+			fmt.Fprintf(&b, "       %s %-13s %s\n", mark, s.Check+":", s.Detail)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// logScannerDecisions prints the --explain trace for a repo as a single log
+// entry so traces of concurrently scanned repos don't interleave
+func logScannerDecisions(config *Config, repo RepositoryConfig, detected *DetectedLanguages) {
+	decisions := explainScannerSelection(config, repo, detected)
+	log.Print(formatScannerDecisions(repoName(repo), decisions))
+}
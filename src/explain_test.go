@@ -0,0 +1,191 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestExplainScannerSelection(t *testing.T) {
+	t.Setenv("EXPLAIN_TEST_TOKEN", "")
+
+	allScanners := []ScannerConfig{
+		{Name: "grype", Enabled: true, FilePatterns: []string{"go.mod"}},                                // universal
+		{Name: "gosec", Enabled: true, Languages: []string{"go"}},                                       // go-specific
+		{Name: "disabled", Enabled: false},                                                              // disabled
+		{Name: "osv", Enabled: true, Languages: []string{"go"}, LanguagesConditional: []string{"java"}}, // conditional java
+		{Name: "scorecard", Enabled: true, RequiredEnv: []string{"EXPLAIN_TEST_TOKEN"}},                 // needs env
+	}
+	global := GlobalConfig{ScannerBundles: map[string][]string{"light": {"grype"}}}
+
+	tests := []struct {
+		name       string
+		repo       RepositoryConfig
+		scanFilter []string
+		detected   []string
+		scanner    string
+		wantSel    bool
+		wantSteps  map[string]string // check → "pass:<detail substring>" or "fail:<detail substring>"
+	}{
+		{
+			name:     "universal scanner selected",
+			detected: []string{"go"},
+			scanner:  "grype",
+			wantSel:  true,
+			wantSteps: map[string]string{
+				"requested":     "pass:all enabled scanners",
+				"enabled":       "pass:enabled",
+				"languages":     "pass:universal",
+				"file_patterns": "pass:not used for selection",
+			},
+		},
+		{
+			name:      "disabled scanner skipped",
+			detected:  []string{"go"},
+			scanner:   "disabled",
+			wantSel:   false,
+			wantSteps: map[string]string{"enabled": "fail:disabled in config"},
+		},
+		{
+			name:     "language mismatch lists supported and detected",
+			detected: []string{"python", "rust"},
+			scanner:  "gosec",
+			wantSel:  false,
+			wantSteps: map[string]string{
+				"languages": "fail:supports go; detected python, rust",
+			},
+		},
+		{
+			name:      "language match",
+			detected:  []string{"go", "python"},
+			scanner:   "gosec",
+			wantSel:   true,
+			wantSteps: map[string]string{"languages": "pass:matched go"},
+		},
+		{
+			name:      "conditional language match",
+			detected:  []string{"java"},
+			scanner:   "osv",
+			wantSel:   true,
+			wantSteps: map[string]string{"languages": "pass:conditionally matched java"},
+		},
+		{
+			name:      "not in repo scanner list",
+			repo:      RepositoryConfig{Scanners: []string{"gosec"}},
+			detected:  []string{"go"},
+			scanner:   "grype",
+			wantSel:   false,
+			wantSteps: map[string]string{"requested": "fail:not listed in repo scanners"},
+		},
+		{
+			name:      "included by bundle",
+			repo:      RepositoryConfig{Bundle: "light"},
+			detected:  []string{"go"},
+			scanner:   "grype",
+			wantSel:   true,
+			wantSteps: map[string]string{"requested": "pass:listed in bundle light"},
+		},
+		{
+			name:      "excluded by bundle",
+			repo:      RepositoryConfig{Bundle: "light"},
+			detected:  []string{"go"},
+			scanner:   "gosec",
+			wantSel:   false,
+			wantSteps: map[string]string{"requested": "fail:not listed in bundle light"},
+		},
+		{
+			name:       "--scan overrides disabled",
+			scanFilter: []string{"disabled"},
+			detected:   []string{"go"},
+			scanner:    "disabled",
+			wantSel:    true,
+			wantSteps: map[string]string{
+				"requested": "pass:listed in --scan",
+				"enabled":   "pass:overridden by --scan",
+			},
+		},
+		{
+			name:      "missing required env is reported but does not deselect",
+			detected:  []string{"go"},
+			scanner:   "scorecard",
+			wantSel:   true,
+			wantSteps: map[string]string{"required_env": "fail:EXPLAIN_TEST_TOKEN not set"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := global
+			g.ScanFilter = tt.scanFilter
+			config := &Config{Global: g, Scanners: allScanners}
+			tt.repo.URL = "https://github.com/org/repo"
+			detected := &DetectedLanguages{Languages: tt.detected}
+
+			decisions := explainScannerSelection(config, tt.repo, detected)
+			if len(decisions) != len(allScanners) {
+				t.Fatalf("got %d decisions, want one per scanner (%d)", len(decisions), len(allScanners))
+			}
+
+			var d scannerDecision
+			for _, dec := range decisions {
+				if dec.Scanner == tt.scanner {
+					d = dec
+				}
+			}
+			if d.Selected != tt.wantSel {
+				t.Errorf("%s Selected = %v, want %v (steps %+v)", tt.scanner, d.Selected, tt.wantSel, d.Steps)
+			}
+			for check, want := range tt.wantSteps {
+				step, ok := d.step(check)
+				if !ok {
+					t.Errorf("missing %q step in %+v", check, d.Steps)
+					continue
+				}
+				wantPass, detail, _ := strings.Cut(want, ":")
+				if step.Passed != (wantPass == "pass") {
+					t.Errorf("%s passed = %v, want %s", check, step.Passed, wantPass)
+				}
+				if !strings.Contains(step.Detail, detail) {
+					t.Errorf("%s detail = %q, want it to contain %q", check, step.Detail, detail)
+				}
+			}
+
+			// The trace must agree with the actual selection
+			var traced, selected []string
+			for _, dec := range decisions {
+				if dec.Selected {
+					traced = append(traced, dec.Scanner)
+				}
+			}
+			for _, s := range getScannersForRepo(config, tt.repo, detected) {
+				selected = append(selected, s.Name)
+			}
+			sort.Strings(traced)
+			sort.Strings(selected)
+			if strings.Join(traced, ",") != strings.Join(selected, ",") {
+				t.Errorf("trace selected %v, getScannersForRepo selected %v", traced, selected)
+			}
+		})
+	}
+}
+
+func TestFormatScannerDecisions(t *testing.T) {
+	decisions := []scannerDecision{
+		{Scanner: "grype", Selected: true, Steps: []explainStep{{"languages", true, "universal"}}},
+		{Scanner: "gosec", Selected: false, Steps: []explainStep{{"languages", false, "supports go; detected rust"}}},
+		{Scanner: "scorecard", Selected: true, Steps: []explainStep{{"required_env", false, "GITHUB_TOKEN not set"}}},
+	}
+	out := formatScannerDecisions("repo", decisions)
+
+	for _, want := range []string{
+		"Scanner selection for repo",
+		"✅ grype: selected",
+		"⏭️  gosec: skipped",
+		"✘ languages:",
+		"⚠️  scorecard: selected, but required env is missing",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace missing %q:\n%s", want, out)
+		}
+	}
+}
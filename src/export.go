@@ -0,0 +1,422 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"allscan/parsers"
+)
+
+// allscanVersion is the allscan release recorded in report metadata, set at
+// build time with -ldflags "-X main.allscanVersion=<version>"
+var allscanVersion = "dev"
+
+// RunReport is the JSON report written by --output. A later run can load it
+// with --previous-run to show finding trends in the summary.
+type RunReport struct {
+	Metadata    RunMetadata      `json:"metadata"`
+	GeneratedAt time.Time        `json:"generated_at"` // Same as metadata.generated_at, kept for existing consumers
+	Results     []RunReportEntry `json:"results"`
+	SBOMs       []RunReportSBOM  `json:"sboms,omitempty"`
+	FailedRepos []FailedRepo     `json:"failed_repos,omitempty"` // Repositories that couldn't be cloned, so weren't scanned
+}
+
+// FailedRepo is a repository the run couldn't clone
+type FailedRepo struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// RunMetadata identifies the run a report came from, so reports from
+// different hosts, allscan builds or config revisions can be told apart
+type RunMetadata struct {
+	GeneratedAt    time.Time `json:"generated_at"`
+	Hostname       string    `json:"hostname,omitempty"`
+	AllscanVersion string    `json:"allscan_version"`
+	ConfigSHA256   string    `json:"config_sha256,omitempty"` // of the config files as read, overlays included
+	Repositories   int       `json:"repositories"`            // distinct repositories scanned
+	Scanners       int       `json:"scanners"`                // distinct scanners that produced a result
+}
+
+// newRunMetadata describes the run that scanned contexts with config
+func newRunMetadata(config *Config, contexts []RepoScanContext) RunMetadata {
+	metadata := RunMetadata{
+		GeneratedAt:    time.Now().UTC(),
+		AllscanVersion: allscanVersion,
+		ConfigSHA256:   config.configHash,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		metadata.Hostname = hostname
+	}
+	repos := make(map[string]bool)
+	scanners := make(map[string]bool)
+	for _, ctx := range contexts {
+		if ctx.CloneError != nil {
+			continue
+		}
+		repos[ctx.RepoURL] = true
+		for _, result := range ctx.Results {
+			scanners[result.Scanner] = true
+		}
+	}
+	metadata.Repositories, metadata.Scanners = len(repos), len(scanners)
+	return metadata
+}
+
+// RunReportSBOM records the SBOM generated for one scanned target, or why
+// generation failed
+type RunReportSBOM struct {
+	Repository string `json:"repository"`
+	Subproject string `json:"subproject,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RunReportEntry records the parsed findings of one scanner on one repository
+type RunReportEntry struct {
+	Repository    string                 `json:"repository"`
+	Subproject    string                 `json:"subproject,omitempty"`
+	Scanner       string                 `json:"scanner"`
+	Success       bool                   `json:"success"`
+	Summary       parsers.FindingSummary `json:"summary"`
+	Findings      []FindingAge           `json:"findings,omitempty"`       // Open SCA findings with their age (finding_history)
+	Error         string                 `json:"error,omitempty"`          // Why the scanner failed
+	InvalidOutput bool                   `json:"invalid_output,omitempty"` // Output was binary, not UTF-8 or malformed JSON
+	TimedOut      bool                   `json:"timed_out,omitempty"`      // Scanner was killed at its timeout
+	Heuristic     bool                   `json:"heuristic,omitempty"`      // No parser; summary counted from severity fields
+	DurationMs    int64                  `json:"duration_ms,omitempty"`    // How long the scanner ran (--list-repos estimates from it)
+	Command       []string               `json:"command,omitempty"`        // Executed command, secrets redacted (record_commands)
+	Version       string                 `json:"version,omitempty"`        // Scanner version (record_commands)
+}
+
+// trendKey builds the "{repo}:{scanner}" key used to match results across runs.
+// Sub-projects are appended to the repo as "#<path>" so they don't collide.
+func trendKey(repo, subproject, scanner string) string {
+	if subproject != "" {
+		repo += "#" + subproject
+	}
+	return repo + ":" + scanner
+}
+
+// buildRunReport collects the finding summaries of all parsed scan results,
+// the SBOM outcome of each target and the repositories that failed to clone.
+// Failed and SARIF results are recorded without a summary.
+func buildRunReport(contexts []RepoScanContext) RunReport {
+	report := RunReport{GeneratedAt: time.Now().UTC()}
+	for _, ctx := range contexts {
+		if ctx.CloneError != nil {
+			report.FailedRepos = append(report.FailedRepos, FailedRepo{URL: ctx.RepoURL, Error: ctx.CloneError.Error()})
+			continue
+		}
+		if ctx.SBOMPath != "" || ctx.SBOMError != nil {
+			sbom := RunReportSBOM{Repository: ctx.RepoURL, Subproject: ctx.Subproject, Path: ctx.SBOMPath}
+			if ctx.SBOMError != nil {
+				sbom.Error = sbomFailureReason(ctx.SBOMError)
+			}
+			report.SBOMs = append(report.SBOMs, sbom)
+		}
+		for _, result := range ctx.Results {
+			entry := RunReportEntry{
+				Repository: result.Repository,
+				Subproject: result.Subproject,
+				Scanner:    result.Scanner,
+				Success:    result.Success,
+				Findings:   result.FindingAges,
+				Error:      errorString(result.Error),
+				DurationMs: result.Duration.Milliseconds(),
+				Command:    result.Command,
+				Version:    result.Version,
+			}
+			var invalid *invalidOutputError
+			entry.InvalidOutput = errors.As(result.Error, &invalid)
+			var timeout *scannerTimeoutError
+			entry.TimedOut = errors.As(result.Error, &timeout)
+			if result.Success && !result.IsSarif {
+				var parser parsers.ResultParser
+				entry.Summary, parser = parseScanOutput(result)
+				if parser == nil {
+					entry.Summary, entry.Heuristic = heuristicScanOutput(result)
+				}
+			}
+			report.Results = append(report.Results, entry)
+		}
+	}
+	return report
+}
+
+// writeRunReport writes the JSON run report for contexts to path, headed by
+// the run's metadata
+func writeRunReport(path string, contexts []RepoScanContext, metadata RunMetadata) error {
+	report := buildRunReport(contexts)
+	report.Metadata = metadata
+	if !metadata.GeneratedAt.IsZero() {
+		report.GeneratedAt = metadata.GeneratedAt
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run report: %w", err)
+	}
+	return writeReportFile(path, data)
+}
+
+// writeReportFile writes a report to path, creating its directory if needed
+func writeReportFile(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("creating report directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing run report: %w", err)
+	}
+	return nil
+}
+
+// Report formats accepted by --output-format
+const (
+	outputFormatJSON = "json" // native run report (readable by --previous-run)
+	outputFormatKICS = "kics" // KICS results.json layout for unified dashboards
+)
+
+// kicsCompatVersion is the KICS release whose results.json layout the KICS
+// export follows
+const kicsCompatVersion = "v1.7.13"
+
+// KICSReport is a run report in the layout of KICS's results.json, so
+// dashboards built for KICS can ingest findings from every scanner
+type KICSReport struct {
+	Metadata         *RunMetadata   `json:"metadata,omitempty"` // allscan's run metadata, not part of the KICS layout
+	KICSVersion      string         `json:"kics_version"`
+	FilesScanned     int            `json:"files_scanned"` // scanned repos and sub-projects (allscan doesn't count files)
+	QueriesTotal     int            `json:"queries_total"`
+	SeverityCounters map[string]int `json:"severity_counters"`
+	TotalCounter     int            `json:"total_counter"`
+	Paths            []string       `json:"paths"`
+	Queries          []KICSQuery    `json:"queries"`
+}
+
+// KICSQuery groups the findings of one rule or advisory from one scanner
+type KICSQuery struct {
+	QueryName   string     `json:"query_name"`
+	QueryID     string     `json:"query_id"` // "<scanner>:<finding ID>"
+	Severity    string     `json:"severity"`
+	Platform    string     `json:"platform"`      // scanner name
+	Category    string     `json:"category"`      // scanner type (SCA, IaC, ...)
+	CWE         string     `json:"cwe,omitempty"` // weakness category, e.g. "CWE-22"
+	Description string     `json:"description"`
+	Files       []KICSFile `json:"files"`
+}
+
+// KICSFile is one occurrence of a query's finding
+type KICSFile struct {
+	FileName     string `json:"file_name"` // "<owner>/<repo>[/<sub-project>]/<file>"
+	SimilarityID string `json:"similarity_id"`
+	Line         int    `json:"line"`
+	IssueType    string `json:"issue_type"`
+	ActualValue  string `json:"actual_value"`
+}
+
+// buildKICSReport converts the findings of every parser that implements
+// parsers.DetailedParser into KICS queries. Results of other scanners, failed
+// results and SARIF output are left out.
+func buildKICSReport(contexts []RepoScanContext) KICSReport {
+	report := KICSReport{
+		KICSVersion:      kicsCompatVersion,
+		SeverityCounters: map[string]int{"CRITICAL": 0, "HIGH": 0, "MEDIUM": 0, "LOW": 0, "INFO": 0, "TRACE": 0},
+		Paths:            []string{},
+		Queries:          []KICSQuery{},
+	}
+
+	queries := make(map[string]*KICSQuery)
+	for _, ctx := range contexts {
+		target := workspaceRepoName(ctx.RepoURL)
+		if ctx.Subproject != "" {
+			target = path.Join(target, ctx.Subproject)
+		}
+		report.Paths = append(report.Paths, target)
+
+		for _, result := range ctx.Results {
+			if !result.Success || result.IsSarif {
+				continue
+			}
+			parser, ok := parsers.Get(result.Scanner)
+			if !ok {
+				continue
+			}
+			detailed, ok := parser.(parsers.DetailedParser)
+			if !ok {
+				continue
+			}
+			data, err := os.ReadFile(result.OutputPath)
+			if err != nil {
+				continue
+			}
+			findings, err := parsers.ListFindings(detailed, data, result.ScanRoot)
+			if err != nil {
+				log.Printf("⚠️  Skipping %s findings for %s in KICS report: %v", result.Scanner, target, err)
+				continue
+			}
+
+			for _, f := range findings {
+				severity := strings.ToUpper(f.Severity)
+				id := result.Scanner + ":" + f.ID
+				query, ok := queries[id]
+				if !ok {
+					query = &KICSQuery{
+						QueryName:   f.ID,
+						QueryID:     id,
+						Severity:    severity,
+						Platform:    result.Scanner,
+						Category:    parser.Type(),
+						CWE:         f.CWE,
+						Description: fmt.Sprintf("%s reported %s", result.Scanner, f.ID),
+					}
+					queries[id] = query
+				} else if kicsSeverityRank(severity) > kicsSeverityRank(query.Severity) {
+					query.Severity = severity
+				}
+				if query.CWE == "" {
+					query.CWE = f.CWE
+				}
+
+				// Paths are repo-relative unless the scan root is unknown
+				// (--parse-only), in which case they stay as reported
+				fileName := target
+				if f.File != "" {
+					fileName = f.File
+					if !filepath.IsAbs(f.File) {
+						fileName = path.Join(target, filepath.ToSlash(f.File))
+					}
+				}
+				similarity := sha256.Sum256([]byte(strings.Join([]string{
+					id, ctx.RepoURL, ctx.Subproject, f.File, strconv.Itoa(f.Line), f.Title}, "\x00")))
+				query.Files = append(query.Files, KICSFile{
+					FileName:     fileName,
+					SimilarityID: hex.EncodeToString(similarity[:]),
+					Line:         f.Line,
+					IssueType:    "IncorrectValue",
+					ActualValue:  f.Title,
+				})
+				report.SeverityCounters[severity]++
+				report.TotalCounter++
+			}
+		}
+	}
+
+	for _, query := range queries {
+		report.Queries = append(report.Queries, *query)
+	}
+	sort.Slice(report.Queries, func(i, j int) bool {
+		a, b := report.Queries[i], report.Queries[j]
+		if ra, rb := kicsSeverityRank(a.Severity), kicsSeverityRank(b.Severity); ra != rb {
+			return ra > rb
+		}
+		return a.QueryID < b.QueryID
+	})
+	report.FilesScanned = len(report.Paths)
+	report.QueriesTotal = len(report.Queries)
+	return report
+}
+
+// kicsSeverityRank orders KICS severities, most severe highest
+func kicsSeverityRank(severity string) int {
+	switch severity {
+	case "CRITICAL":
+		return 5
+	case "HIGH":
+		return 4
+	case "MEDIUM":
+		return 3
+	case "LOW":
+		return 2
+	case "INFO":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ExportKICSFormat writes the findings of contexts to outputPath in KICS
+// results.json format (see buildKICSReport), headed by the run's metadata
+func ExportKICSFormat(contexts []RepoScanContext, metadata RunMetadata, outputPath string) error {
+	report := buildKICSReport(contexts)
+	report.Metadata = &metadata
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding KICS report: %w", err)
+	}
+	return writeReportFile(outputPath, data)
+}
+
+// defaultRunReportName is the report written to the results dir when a post-run
+// hook is configured without --output, so {{report}} always points at a file.
+const defaultRunReportName = "run-report.json"
+
+// runReportPath returns where the run report should be written: the --output
+// path, else a default in the results dir when a post-run hook is configured.
+// Returns "" when no report is needed.
+func runReportPath(config *Config) string {
+	if config.Global.OutputPath != "" {
+		return config.Global.OutputPath
+	}
+	if len(config.Global.PostRunCommand) > 0 {
+		return filepath.Join(config.Global.ResultsDir, defaultRunReportName)
+	}
+	return ""
+}
+
+// saveRunReport writes the run report, if one is needed, logging the outcome.
+// Returns the report path, or "" if no report was written.
+func saveRunReport(config *Config, contexts []RepoScanContext) string {
+	path := runReportPath(config)
+	if path == "" {
+		return ""
+	}
+	write := writeRunReport
+	if config.Global.OutputFormat == outputFormatKICS {
+		write = func(path string, contexts []RepoScanContext, metadata RunMetadata) error {
+			return ExportKICSFormat(contexts, metadata, path)
+		}
+	}
+	if err := write(path, contexts, newRunMetadata(config, contexts)); err != nil {
+		log.Printf("❌ Failed to write run report: %v", err)
+		return ""
+	}
+	log.Printf("📝 Run report written to %s", path)
+	return path
+}
+
+// loadPreviousRunSummaries reads a run report written by --output and returns
+// the finding summaries of its successful results, keyed by trendKey.
+// The returned error wraps fs.ErrNotExist when the file doesn't exist.
+func loadPreviousRunSummaries(path string) (map[string]parsers.FindingSummary, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading previous run report: %w", err)
+	}
+
+	var report RunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parsing previous run report: %w", err)
+	}
+
+	summaries := make(map[string]parsers.FindingSummary, len(report.Results))
+	for _, entry := range report.Results {
+		if !entry.Success {
+			continue
+		}
+		summaries[trendKey(entry.Repository, entry.Subproject, entry.Scanner)] = entry.Summary
+	}
+	return summaries, nil
+}
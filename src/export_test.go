@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"allscan/parsers"
+)
+
+func TestTrendKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       string
+		subproject string
+		scanner    string
+		want       string
+	}{
+		{
+			name:    "whole repo",
+			repo:    "https://github.com/org/repo",
+			scanner: "grype",
+			want:    "https://github.com/org/repo:grype",
+		},
+		{
+			name:       "sub-project",
+			repo:       "https://github.com/org/repo",
+			subproject: "services/api",
+			scanner:    "gosec",
+			want:       "https://github.com/org/repo#services/api:gosec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trendKey(tt.repo, tt.subproject, tt.scanner); got != tt.want {
+				t.Errorf("trendKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	grypePath := filepath.Join(dir, "grype.json")
+	grypeOutput := `{"matches": [
+		{"vulnerability": {"id": "CVE-1", "severity": "Critical"}},
+		{"vulnerability": {"id": "CVE-2", "severity": "Critical"}},
+		{"vulnerability": {"id": "CVE-3", "severity": "High"}}
+	]}`
+	if err := os.WriteFile(grypePath, []byte(grypeOutput), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := "https://github.com/org/repo"
+	contexts := []RepoScanContext{{
+		RepoURL: repo,
+		Results: []ScanResult{
+			{Scanner: "grype", Repository: repo, Success: true, OutputPath: grypePath},
+			{Scanner: "gosec", Repository: repo, Success: false, Error: errors.New("timeout")},
+			{Scanner: "grype", Repository: repo, Subproject: "web", Success: true, OutputPath: grypePath},
+		},
+	}}
+
+	reportPath := filepath.Join(dir, "reports", "run.json")
+	if err := writeRunReport(reportPath, contexts, RunMetadata{}); err != nil {
+		t.Fatalf("writeRunReport() error = %v", err)
+	}
+
+	summaries, err := loadPreviousRunSummaries(reportPath)
+	if err != nil {
+		t.Fatalf("loadPreviousRunSummaries() error = %v", err)
+	}
+
+	want := parsers.FindingSummary{Critical: 2, High: 1, Total: 3}
+	if got := summaries[trendKey(repo, "", "grype")]; got != want {
+		t.Errorf("grype summary = %+v, want %+v", got, want)
+	}
+	if got := summaries[trendKey(repo, "web", "grype")]; got != want {
+		t.Errorf("grype (web) summary = %+v, want %+v", got, want)
+	}
+	if _, ok := summaries[trendKey(repo, "", "gosec")]; ok {
+		t.Error("failed gosec result should not be loaded as a previous summary")
+	}
+}
+
+func TestRunReportMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scanners.yaml")
+	if err := os.WriteFile(path, []byte("global:\n  max_concurrent: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contexts := []RepoScanContext{
+		{RepoURL: "https://github.com/org/a", Results: []ScanResult{{Scanner: "grype"}, {Scanner: "gosec"}}},
+		{RepoURL: "https://github.com/org/a", Subproject: "web", Results: []ScanResult{{Scanner: "grype"}}},
+		{RepoURL: "https://github.com/org/b", Results: []ScanResult{{Scanner: "trufflehog"}}},
+	}
+
+	for _, format := range []string{outputFormatJSON, outputFormatKICS} {
+		t.Run(format, func(t *testing.T) {
+			config.Global.OutputFormat = format
+			config.Global.OutputPath = filepath.Join(t.TempDir(), "report.json")
+			if saveRunReport(config, contexts) == "" {
+				t.Fatal("saveRunReport() wrote no report")
+			}
+			data, err := os.ReadFile(config.Global.OutputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), "{\n  \"metadata\": {") {
+				t.Errorf("report doesn't start with the metadata block:\n%.200s", data)
+			}
+			var report struct {
+				Metadata    RunMetadata
+				GeneratedAt *time.Time `json:"generated_at"`
+			}
+			if err := json.Unmarshal(data, &report); err != nil {
+				t.Fatal(err)
+			}
+			if format == outputFormatJSON && (report.GeneratedAt == nil || !report.GeneratedAt.Equal(report.Metadata.GeneratedAt)) {
+				t.Errorf("top-level generated_at = %v, want metadata's %v", report.GeneratedAt, report.Metadata.GeneratedAt)
+			}
+			got := report.Metadata
+			if got.GeneratedAt.IsZero() || got.AllscanVersion != allscanVersion || got.ConfigSHA256 != config.configHash {
+				t.Errorf("metadata = %+v, want a timestamp, version %q and config hash %q", got, allscanVersion, config.configHash)
+			}
+			if got.Repositories != 2 || got.Scanners != 3 {
+				t.Errorf("metadata counts = %d repositories, %d scanners; want 2, 3", got.Repositories, got.Scanners)
+			}
+		})
+	}
+}
+
+func TestLoadPreviousRunSummaries_Errors(t *testing.T) {
+	t.Run("missing file wraps fs.ErrNotExist", func(t *testing.T) {
+		_, err := loadPreviousRunSummaries(filepath.Join(t.TempDir(), "missing.json"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("loadPreviousRunSummaries() error = %v, want fs.ErrNotExist", err)
+		}
+	})
+
+	t.Run("invalid JSON returns error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bad.json")
+		os.WriteFile(path, []byte("{not json"), 0644)
+		if _, err := loadPreviousRunSummaries(path); err == nil {
+			t.Error("loadPreviousRunSummaries() expected error for invalid JSON, got nil")
+		}
+	})
+}
+
+func TestExportKICSFormat(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	grypePath := write("grype.json", `{"matches": [
+		{"vulnerability": {"id": "GHSA-aaaa", "severity": "High"},
+		 "relatedVulnerabilities": [{"id": "CVE-2020-8203"}, {"id": "CWE-1321"}],
+		 "artifact": {"name": "lodash", "version": "4.17.20", "locations": [{"path": "/web/package-lock.json"}]}},
+		{"vulnerability": {"id": "CVE-2024-0001", "severity": "Critical"},
+		 "artifact": {"name": "openssl", "version": "3.0.0"}}
+	]}`)
+	k8sPath := write("k8s.json", `{"findings": [
+		{"rule": "K8S001", "severity": "high", "file": "app.yaml", "kind": "Deployment", "name": "web", "container": "app", "message": "container app is privileged"},
+		{"rule": "K8S001", "severity": "high", "file": "jobs/cron.yaml", "kind": "CronJob", "name": "nightly", "container": "job", "message": "container job is privileged"}
+	], "total": 2}`)
+	gosecPath := write("gosec.json", `{"Issues": [{"severity": "MEDIUM", "rule_id": "G304", "details": "Potential file inclusion via variable",
+		"file": "/tmp/scanner-workspace/org/shop/cmd/main.go", "line": "42", "cwe": {"id": "22"}}], "Stats": {"found": 1}}`)
+
+	repo := "https://github.com/org/shop"
+	contexts := []RepoScanContext{
+		{RepoURL: repo, Results: []ScanResult{
+			{Scanner: "grype", Repository: repo, Success: true, OutputPath: grypePath},
+			{Scanner: "gosec", Repository: repo, Success: true, OutputPath: gosecPath, ScanRoot: "/tmp/scanner-workspace/org/shop"},
+			{Scanner: "osv-scanner", Repository: repo, Success: false, Error: errors.New("timeout")},
+		}},
+		{RepoURL: repo, Subproject: "deploy", Results: []ScanResult{
+			{Scanner: "kubernetes-policy-checker", Repository: repo, Subproject: "deploy", Success: true, OutputPath: k8sPath},
+		}},
+	}
+
+	outputPath := filepath.Join(dir, "reports", "results.json")
+	if err := ExportKICSFormat(contexts, RunMetadata{AllscanVersion: "dev"}, outputPath); err != nil {
+		t.Fatalf("ExportKICSFormat() error = %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Similarity IDs are hashes; check them separately, then compare the rest
+	var report KICSReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if report.Metadata == nil || report.Metadata.AllscanVersion != "dev" {
+		t.Errorf("metadata = %+v, want the run metadata", report.Metadata)
+	}
+	report.Metadata = nil
+	seen := make(map[string]bool)
+	for qi := range report.Queries {
+		for fi := range report.Queries[qi].Files {
+			id := report.Queries[qi].Files[fi].SimilarityID
+			if len(id) != 64 || seen[id] {
+				t.Errorf("similarity_id %q is not a unique sha256", id)
+			}
+			seen[id] = true
+			report.Queries[qi].Files[fi].SimilarityID = ""
+		}
+	}
+	got, _ := json.MarshalIndent(report, "", "  ")
+
+	want := `{
+  "kics_version": "v1.7.13",
+  "files_scanned": 2,
+  "queries_total": 4,
+  "severity_counters": {
+    "CRITICAL": 1,
+    "HIGH": 3,
+    "INFO": 0,
+    "LOW": 0,
+    "MEDIUM": 1,
+    "TRACE": 0
+  },
+  "total_counter": 5,
+  "paths": [
+    "org/shop",
+    "org/shop/deploy"
+  ],
+  "queries": [
+    {
+      "query_name": "CVE-2024-0001",
+      "query_id": "grype:CVE-2024-0001",
+      "severity": "CRITICAL",
+      "platform": "grype",
+      "category": "SCA",
+      "description": "grype reported CVE-2024-0001",
+      "files": [
+        {
+          "file_name": "org/shop",
+          "similarity_id": "",
+          "line": 0,
+          "issue_type": "IncorrectValue",
+          "actual_value": "CVE-2024-0001 in openssl@3.0.0"
+        }
+      ]
+    },
+    {
+      "query_name": "GHSA-aaaa",
+      "query_id": "grype:GHSA-aaaa",
+      "severity": "HIGH",
+      "platform": "grype",
+      "category": "SCA",
+      "cwe": "CWE-1321",
+      "description": "grype reported GHSA-aaaa",
+      "files": [
+        {
+          "file_name": "org/shop/web/package-lock.json",
+          "similarity_id": "",
+          "line": 0,
+          "issue_type": "IncorrectValue",
+          "actual_value": "GHSA-aaaa in lodash@4.17.20"
+        }
+      ]
+    },
+    {
+      "query_name": "K8S001",
+      "query_id": "kubernetes-policy-checker:K8S001",
+      "severity": "HIGH",
+      "platform": "kubernetes-policy-checker",
+      "category": "IaC",
+      "description": "kubernetes-policy-checker reported K8S001",
+      "files": [
+        {
+          "file_name": "org/shop/deploy/app.yaml",
+          "similarity_id": "",
+          "line": 0,
+          "issue_type": "IncorrectValue",
+          "actual_value": "container app is privileged"
+        },
+        {
+          "file_name": "org/shop/deploy/jobs/cron.yaml",
+          "similarity_id": "",
+          "line": 0,
+          "issue_type": "IncorrectValue",
+          "actual_value": "container job is privileged"
+        }
+      ]
+    },
+    {
+      "query_name": "G304",
+      "query_id": "gosec:G304",
+      "severity": "MEDIUM",
+      "platform": "gosec",
+      "category": "SAST",
+      "cwe": "CWE-22",
+      "description": "gosec reported G304",
+      "files": [
+        {
+          "file_name": "org/shop/cmd/main.go",
+          "similarity_id": "",
+          "line": 42,
+          "issue_type": "IncorrectValue",
+          "actual_value": "Potential file inclusion via variable"
+        }
+      ]
+    }
+  ]
+}`
+	if string(got) != want {
+		t.Errorf("KICS report mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"allscan/parsers"
+)
+
+// FindingHistory is the persistent first-seen store configured with
+// global.finding_history. Only open findings are kept: a finding missing from
+// a later successful scan is dropped, so a reintroduced finding starts over.
+type FindingHistory struct {
+	UpdatedAt time.Time                       `json:"updated_at"`
+	FirstSeen map[string]map[string]time.Time `json:"first_seen"` // trendKey → finding ID → first seen
+}
+
+// FindingAge annotates an open finding with when it was first seen
+type FindingAge struct {
+	ID        string    `json:"id"`
+	Severity  string    `json:"severity"`
+	FirstSeen time.Time `json:"first_seen"`
+	AgeDays   int       `json:"age_days"`
+}
+
+// extractSCAFindings returns the individual findings of a grype or osv-scanner
+// result. ok is false for other scanners and for unreadable output.
+func extractSCAFindings(result ScanResult) (findings []parsers.SCAFinding, ok bool) {
+	if !result.Success || result.IsSarif {
+		return nil, false
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		return nil, false
+	}
+
+	switch result.Scanner {
+	case "grype":
+		findings, err = parsers.ExtractGrypeFindings(data)
+		if grypeExcludesDevDeps() {
+			findings = runtimeFindings(findings)
+		}
+	case "osv-scanner":
+		findings, err = parsers.ExtractOSVScannerFindings(data)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return nil, false
+	}
+	return findings, true
+}
+
+// grypeExcludesDevDeps reports whether the registered grype parser leaves
+// dev-scoped dependencies out (global.grype_exclude_dev_deps)
+func grypeExcludesDevDeps() bool {
+	parser, _ := parsers.Get("grype")
+	grype, ok := parser.(*parsers.GrypeParser)
+	return ok && grype.ExcludeDevDeps
+}
+
+// runtimeFindings drops the findings in dev-scoped dependencies
+func runtimeFindings(findings []parsers.SCAFinding) []parsers.SCAFinding {
+	runtime := findings[:0]
+	for _, finding := range findings {
+		if !finding.Dev {
+			runtime = append(runtime, finding)
+		}
+	}
+	return runtime
+}
+
+// loadFindingHistory reads the first-seen store. A missing file yields an
+// empty history, as on the first run.
+func loadFindingHistory(path string) (*FindingHistory, error) {
+	history := &FindingHistory{FirstSeen: make(map[string]map[string]time.Time)}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading finding history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("parsing finding history: %w", err)
+	}
+	if history.FirstSeen == nil {
+		history.FirstSeen = make(map[string]map[string]time.Time)
+	}
+	return history, nil
+}
+
+// save writes the history to path, creating the parent directory if needed
+func (h *FindingHistory) save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding finding history: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("creating finding history directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing finding history: %w", err)
+	}
+	return nil
+}
+
+// update records the open findings of one scanner result (keyed by trendKey)
+// and returns their ages at now, oldest first. Findings seen for the first
+// time get now as their first-seen timestamp; findings no longer reported
+// are forgotten. Duplicate IDs keep the highest severity.
+func (h *FindingHistory) update(key string, findings []parsers.SCAFinding, now time.Time) []FindingAge {
+	previous := h.FirstSeen[key]
+	current := make(map[string]time.Time, len(findings))
+	severities := make(map[string]string, len(findings))
+
+	for _, f := range findings {
+		if len(f.IDs) == 0 || f.IDs[0] == "" {
+			continue
+		}
+		id := f.IDs[0]
+		severities[id] = higherSeverity(severities[id], f.Severity)
+		if _, ok := current[id]; ok {
+			continue
+		}
+		if first, ok := previous[id]; ok {
+			current[id] = first
+		} else {
+			current[id] = now
+		}
+	}
+	h.FirstSeen[key] = current
+
+	ages := make([]FindingAge, 0, len(current))
+	for id, first := range current {
+		ages = append(ages, FindingAge{
+			ID:        id,
+			Severity:  severities[id],
+			FirstSeen: first,
+			AgeDays:   findingAgeDays(first, now),
+		})
+	}
+	sort.Slice(ages, func(i, j int) bool {
+		if !ages[i].FirstSeen.Equal(ages[j].FirstSeen) {
+			return ages[i].FirstSeen.Before(ages[j].FirstSeen)
+		}
+		return ages[i].ID < ages[j].ID
+	})
+	return ages
+}
+
+// higherSeverity returns whichever normalized severity ranks higher
+func higherSeverity(a, b string) string {
+	rank := map[string]int{"info": 1, "low": 2, "medium": 3, "high": 4, "critical": 5}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// findingAgeDays returns the number of whole days between first and now
+func findingAgeDays(first, now time.Time) int {
+	if now.Before(first) {
+		return 0
+	}
+	return int(now.Sub(first).Hours() / 24)
+}
+
+// formatAgeDays renders a finding age for the summary ("1 day", "45 days")
+func formatAgeDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// trackFindingAges updates the finding history (when global.finding_history
+// is set) with this run's SCA findings and annotates each result with the ages
+// of its open findings. Failures are logged and leave the results unannotated.
+func trackFindingAges(config *Config, contexts []RepoScanContext) {
+	path := config.Global.FindingHistory
+	if path == "" {
+		return
+	}
+
+	history, err := loadFindingHistory(path)
+	if err != nil {
+		log.Printf("❌ Failed to load finding history: %v", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	for i := range contexts {
+		for j := range contexts[i].Results {
+			result := &contexts[i].Results[j]
+			findings, ok := extractSCAFindings(*result)
+			if !ok {
+				continue
+			}
+			key := trendKey(result.Repository, result.Subproject, result.Scanner)
+			result.FindingAges = history.update(key, findings, now)
+		}
+	}
+
+	history.UpdatedAt = now
+	if err := history.save(path); err != nil {
+		log.Printf("❌ Failed to save finding history: %v", err)
+	}
+}
+
+// oldestOpenFinding returns the oldest finding of the given severity across
+// all results of a repo context
+func oldestOpenFinding(ctx RepoScanContext, severity string) (FindingAge, bool) {
+	var oldest FindingAge
+	found := false
+	for _, result := range ctx.Results {
+		for _, age := range result.FindingAges {
+			if age.Severity != severity {
+				continue
+			}
+			if !found || age.FirstSeen.Before(oldest.FirstSeen) {
+				oldest, found = age, true
+			}
+		}
+	}
+	return oldest, found
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"allscan/parsers"
+)
+
+func TestFindingHistoryUpdate(t *testing.T) {
+	day1 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	day46 := day1.AddDate(0, 0, 45)
+	key := trendKey("https://github.com/acme/widget", "", "grype")
+
+	history := &FindingHistory{FirstSeen: make(map[string]map[string]time.Time)}
+
+	// First run: every finding is first seen now
+	ages := history.update(key, []parsers.SCAFinding{
+		{IDs: []string{"CVE-1"}, Severity: "critical"},
+		{IDs: []string{"CVE-2"}, Severity: "low"},
+		{IDs: []string{"CVE-2"}, Severity: "high"},
+	}, day1)
+	if len(ages) != 2 {
+		t.Fatalf("first run: got %d ages, want 2: %+v", len(ages), ages)
+	}
+	for _, age := range ages {
+		if !age.FirstSeen.Equal(day1) || age.AgeDays != 0 {
+			t.Errorf("first run: %s first seen %v age %d, want %v age 0", age.ID, age.FirstSeen, age.AgeDays, day1)
+		}
+	}
+	if ages[1].ID != "CVE-2" || ages[1].Severity != "high" {
+		t.Errorf("duplicate ID: got %+v, want CVE-2 with highest severity high", ages[1])
+	}
+
+	// Second run: CVE-1 keeps its first-seen time, CVE-2 is fixed, CVE-3 is new
+	ages = history.update(key, []parsers.SCAFinding{
+		{IDs: []string{"CVE-3", "GHSA-3"}, Severity: "medium"},
+		{IDs: []string{"CVE-1"}, Severity: "critical"},
+	}, day46)
+	want := []FindingAge{
+		{ID: "CVE-1", Severity: "critical", FirstSeen: day1, AgeDays: 45},
+		{ID: "CVE-3", Severity: "medium", FirstSeen: day46, AgeDays: 0},
+	}
+	if len(ages) != len(want) {
+		t.Fatalf("second run: got %+v, want %+v", ages, want)
+	}
+	for i := range want {
+		if ages[i].ID != want[i].ID || ages[i].Severity != want[i].Severity ||
+			!ages[i].FirstSeen.Equal(want[i].FirstSeen) || ages[i].AgeDays != want[i].AgeDays {
+			t.Errorf("second run ages[%d] = %+v, want %+v", i, ages[i], want[i])
+		}
+	}
+	if _, ok := history.FirstSeen[key]["CVE-2"]; ok {
+		t.Error("fixed finding CVE-2 still in history")
+	}
+}
+
+func TestTrackFindingAges(t *testing.T) {
+	dir := t.TempDir()
+	historyPath := filepath.Join(dir, "history", "findings.json")
+	grypePath := filepath.Join(dir, "grype.json")
+	if err := os.WriteFile(grypePath, []byte(`{"matches": [
+		{"vulnerability": {"id": "CVE-1", "severity": "Critical"}},
+		{"vulnerability": {"id": "CVE-2", "severity": "Low"}}
+	]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{Global: GlobalConfig{FindingHistory: historyPath}}
+	newContexts := func() []RepoScanContext {
+		return []RepoScanContext{{
+			RepoURL: "https://github.com/acme/widget",
+			Results: []ScanResult{
+				{Scanner: "grype", Repository: "https://github.com/acme/widget", Success: true, OutputPath: grypePath},
+				{Scanner: "gosec", Repository: "https://github.com/acme/widget", Success: true, OutputPath: grypePath},
+			},
+		}}
+	}
+
+	// First run creates the store
+	contexts := newContexts()
+	trackFindingAges(config, contexts)
+	if got := len(contexts[0].Results[0].FindingAges); got != 2 {
+		t.Fatalf("first run: grype has %d finding ages, want 2", got)
+	}
+	if contexts[0].Results[1].FindingAges != nil {
+		t.Errorf("gosec result annotated: %+v", contexts[0].Results[1].FindingAges)
+	}
+
+	// Backdate CVE-1 as if it was first seen 45 days ago
+	history, err := loadFindingHistory(historyPath)
+	if err != nil {
+		t.Fatalf("loadFindingHistory() error = %v", err)
+	}
+	key := trendKey("https://github.com/acme/widget", "", "grype")
+	history.FirstSeen[key]["CVE-1"] = time.Now().UTC().AddDate(0, 0, -45)
+	if err := history.save(historyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Second run computes the age from the stored first-seen time
+	contexts = newContexts()
+	trackFindingAges(config, contexts)
+	oldest, ok := oldestOpenFinding(contexts[0], "critical")
+	if !ok {
+		t.Fatal("oldestOpenFinding() found no critical finding")
+	}
+	if oldest.ID != "CVE-1" || oldest.AgeDays != 45 {
+		t.Errorf("oldest open critical = %s (%d days), want CVE-1 (45 days)", oldest.ID, oldest.AgeDays)
+	}
+}
+
+func TestLoadFindingHistoryMissing(t *testing.T) {
+	history, err := loadFindingHistory(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("loadFindingHistory() error = %v", err)
+	}
+	if history.FirstSeen == nil || len(history.FirstSeen) != 0 {
+		t.Errorf("FirstSeen = %v, want empty map", history.FirstSeen)
+	}
+}
+
+func TestFormatAgeDays(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{0, "0 days"},
+		{1, "1 day"},
+		{45, "45 days"},
+	}
+	for _, tt := range tests {
+		if got := formatAgeDays(tt.days); got != tt.want {
+			t.Errorf("formatAgeDays(%d) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testGitHubApp returns a GitHub App with a fresh key and points the GitHub
+// API at server
+func testGitHubApp(t *testing.T, server *httptest.Server) *githubAppAuth {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	origURL := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = origURL })
+	return &githubAppAuth{appID: 1234, installationID: 42, key: key}
+}
+
+// verifyTestJWT checks an RS256 JWT against key and returns its claims
+func verifyTestJWT(t *testing.T, token string, key *rsa.PublicKey) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT %q doesn't have three parts", token)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decoding signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("JWT signature doesn't verify: %v", err)
+	}
+
+	var header map[string]string
+	data, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	if err := json.Unmarshal(data, &header); err != nil || header["alg"] != "RS256" {
+		t.Errorf("JWT header = %s, want alg RS256", data)
+	}
+	var claims map[string]any
+	data, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(data, &claims); err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	return claims
+}
+
+func TestGitHubAppJWT(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	app := testGitHubApp(t, server)
+
+	now := time.Unix(1700000000, 0)
+	token, err := app.jwt(now)
+	if err != nil {
+		t.Fatalf("jwt() error = %v", err)
+	}
+	claims := verifyTestJWT(t, token, &app.key.PublicKey)
+
+	if claims["iss"] != "1234" {
+		t.Errorf("iss = %v, want the App ID", claims["iss"])
+	}
+	iat, exp := int64(claims["iat"].(float64)), int64(claims["exp"].(float64))
+	if iat > now.Unix() {
+		t.Errorf("iat = %d, want at or before %d to allow for clock drift", iat, now.Unix())
+	}
+	if exp <= now.Unix() || exp-iat > int64((10*time.Minute).Seconds()) {
+		t.Errorf("iat = %d, exp = %d; want a lifetime of at most 10 minutes", iat, exp)
+	}
+}
+
+func TestGitHubAppInstallationToken(t *testing.T) {
+	var requests atomic.Int32
+	var app *githubAppAuth
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if r.Method != "POST" || r.URL.Path != "/app/installations/42/access_tokens" {
+			t.Errorf("request %s %s, want POST /app/installations/42/access_tokens", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/vnd.github+json" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			t.Errorf("Authorization = %q, want a bearer JWT", r.Header.Get("Authorization"))
+		}
+		verifyTestJWT(t, jwt, &app.key.PublicKey)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "ghs_token%d", "expires_at": %q}`, n, time.Now().Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+	app = testGitHubApp(t, server)
+
+	for i := 0; i < 2; i++ {
+		token, err := app.installationToken()
+		if err != nil {
+			t.Fatalf("installationToken() error = %v", err)
+		}
+		if token != "ghs_token1" {
+			t.Errorf("installationToken() = %q, want the cached ghs_token1", token)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("token endpoint called %d times, want 1 (cached)", got)
+	}
+
+	// A token close to expiry is replaced
+	app.expires = time.Now().Add(githubTokenRefreshMargin / 2)
+	if token, err := app.installationToken(); err != nil || token != "ghs_token2" {
+		t.Errorf("installationToken() near expiry = %q, %v; want a new ghs_token2", token, err)
+	}
+}
+
+func TestGitHubAppInstallationTokenError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	githubApp = testGitHubApp(t, server)
+	t.Cleanup(func() { githubApp = nil })
+
+	if _, err := githubToken(); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("githubToken() error = %v, want the token endpoint's status", err)
+	}
+}
+
+func TestDetectLanguagesWithGitHubApp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/installations/42/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "ghs_app", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		case "/repos/org/repo/languages":
+			if got := r.Header.Get("Authorization"); got != "Bearer ghs_app" {
+				t.Errorf("Authorization = %q, want the installation token", got)
+			}
+			w.Write([]byte(`{"Go": 1000}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	githubApp = testGitHubApp(t, server)
+	t.Cleanup(func() { githubApp = nil })
+	t.Setenv("GITHUB_TOKEN", "")
+
+	if !githubAuthConfigured() {
+		t.Error("githubAuthConfigured() = false with a GitHub App")
+	}
+	detected, err := detectLanguagesFromGitHub("https://github.com/org/repo")
+	if err != nil {
+		t.Fatalf("detectLanguagesFromGitHub() error = %v", err)
+	}
+	if !detected.hasLanguage("go") {
+		t.Errorf("Languages = %v, want go", detected.Languages)
+	}
+}
+
+func TestNewGitHubAppAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"pkcs1.pem": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		"pkcs8.pem": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		"bad.pem":   []byte("not a key"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{name: "PKCS#1 key", file: "pkcs1.pem"},
+		{name: "PKCS#8 key", file: "pkcs8.pem"},
+		{name: "not PEM", file: "bad.pem", wantErr: true},
+		{name: "missing file", file: "missing.pem", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := newGitHubAppAuth(GitHubAppConfig{AppID: 1, InstallationID: 2, PrivateKeyFile: filepath.Join(dir, tt.file)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newGitHubAppAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !app.key.Equal(key) {
+				t.Error("loaded key doesn't match")
+			}
+		})
+	}
+
+	if app, err := newGitHubAppAuth(GitHubAppConfig{}); app != nil || err != nil {
+		t.Errorf("newGitHubAppAuth() unconfigured = %v, %v; want nil, nil", app, err)
+	}
+}
+
+func TestValidateGitHubApp(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  GitHubAppConfig
+		wantErr bool
+	}{
+		{name: "unset", config: GitHubAppConfig{}},
+		{name: "complete", config: GitHubAppConfig{AppID: 1, InstallationID: 2, PrivateKeyFile: "app.pem"}},
+		{name: "missing installation", config: GitHubAppConfig{AppID: 1, PrivateKeyFile: "app.pem"}, wantErr: true},
+		{name: "missing key", config: GitHubAppConfig{AppID: 1, InstallationID: 2}, wantErr: true},
+		{name: "missing app ID", config: GitHubAppConfig{InstallationID: 2, PrivateKeyFile: "app.pem"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := validateGitHubApp(tt.config); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateGitHubApp() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
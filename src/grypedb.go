@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// defaultGrypeDBPrefetchTimeout bounds "grype db update" when
+// grype_db_prefetch_timeout is unset
+const defaultGrypeDBPrefetchTimeout = 5 * time.Minute
+
+// grypeDBPrefetched is set once grype_db_prefetch has updated the grype DB
+// before the scans. Scanner commands then run with DB auto-update off, so
+// concurrent grype runs don't race to replace the same DB cache.
+var grypeDBPrefetched bool
+
+// grypeCommand returns the command of the first grype scanner the run may
+// use (enabled, or selected with --scan), or "" when grype won't run
+func grypeCommand(config *Config) string {
+	selected := make(map[string]bool)
+	for _, name := range config.Global.ScanFilter {
+		selected[name] = true
+	}
+	for _, scanner := range config.Scanners {
+		if filepath.Base(scanner.Command) != "grype" {
+			continue
+		}
+		if selected[scanner.Name] || (len(selected) == 0 && scanner.Enabled) {
+			return scanner.Command
+		}
+	}
+	return ""
+}
+
+// grypeDBUpdateCommand builds the "grype db update" command for a grype
+// binary, killed when ctx ends
+func grypeDBUpdateCommand(ctx context.Context, grype string) *exec.Cmd {
+	return exec.CommandContext(ctx, grype, "db", "update")
+}
+
+// prefetchGrypeDB updates the grype vulnerability DB once before scanners fan
+// out, when grype_db_prefetch is set and grype will run. Offline runs
+// (offline_db_dir) already have their DB and are left alone. A failed or
+// timed-out update only warns: grype then updates the DB itself as usual.
+func prefetchGrypeDB(config *Config) {
+	if !config.Global.GrypeDBPrefetch || offlineDBDir != "" {
+		return
+	}
+	grype := grypeCommand(config)
+	if grype == "" {
+		return
+	}
+
+	timeout := config.Global.grypeDBPrefetchTimeout
+	log.Printf("⏳ Updating the grype vulnerability DB (timeout %v)...", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	output, err := grypeDBUpdateCommand(ctx, grype).CombinedOutput()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		log.Printf("⚠️  grype DB update timed out after %v; scanners will update the DB themselves", timeout)
+	case err != nil:
+		log.Printf("⚠️  grype DB update failed (%v); scanners will update the DB themselves\n%s", err, output)
+	default:
+		grypeDBPrefetched = true
+		log.Printf("✅ grype vulnerability DB is up to date")
+	}
+}
+
+// grypeDBEnv returns the environment that turns off grype's DB auto-update
+// once the DB has been prefetched for this run
+func grypeDBEnv() []string {
+	if !grypeDBPrefetched {
+		return nil
+	}
+	return []string{"GRYPE_DB_AUTO_UPDATE=false"}
+}
+
+// setGrypeDBEnv turns off grype's DB auto-update for a scanner command once
+// the DB has been prefetched for this run
+func setGrypeDBEnv(cmd *exec.Cmd) {
+	if env := grypeDBEnv(); len(env) > 0 {
+		appendCmdEnv(cmd, env...)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGrypeCommand(t *testing.T) {
+	scanners := []ScannerConfig{
+		{Name: "gosec", Command: "gosec", Enabled: true},
+		{Name: "grype", Command: "/usr/local/bin/grype", Enabled: true},
+		{Name: "grype-image", Command: "grype", Enabled: false},
+	}
+
+	tests := []struct {
+		name       string
+		scanners   []ScannerConfig
+		scanFilter []string
+		want       string
+	}{
+		{"enabled grype scanner", scanners, nil, "/usr/local/bin/grype"},
+		{"grype disabled", scanners[:1], nil, ""},
+		{"--scan without grype", scanners, []string{"gosec"}, ""},
+		{"--scan selects a disabled grype scanner", scanners, []string{"grype-image"}, "grype"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Scanners: tt.scanners, Global: GlobalConfig{ScanFilter: tt.scanFilter}}
+			if got := grypeCommand(config); got != tt.want {
+				t.Errorf("grypeCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrypeDBUpdateCommand(t *testing.T) {
+	cmd := grypeDBUpdateCommand(context.Background(), "/opt/grype/grype")
+	want := []string{"/opt/grype/grype", "db", "update"}
+	if strings.Join(cmd.Args, " ") != strings.Join(want, " ") {
+		t.Errorf("Args = %v, want %v", cmd.Args, want)
+	}
+}
+
+func TestPrefetchGrypeDB(t *testing.T) {
+	t.Cleanup(func() { grypeDBPrefetched = false })
+
+	// fakeGrype writes a grype stand-in that records its arguments and
+	// exits with the given code
+	fakeGrype := func(t *testing.T, exitCode int) (command, argsFile string) {
+		t.Helper()
+		dir := t.TempDir()
+		argsFile = filepath.Join(dir, "args")
+		command = filepath.Join(dir, "grype")
+		script := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s\nexit %d\n", argsFile, exitCode)
+		if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return command, argsFile
+	}
+	configFor := func(command string, prefetch bool) *Config {
+		return &Config{
+			Global:   GlobalConfig{GrypeDBPrefetch: prefetch, grypeDBPrefetchTimeout: time.Minute},
+			Scanners: []ScannerConfig{{Name: "grype", Command: command, Enabled: true}},
+		}
+	}
+
+	t.Run("successful update disables auto-update", func(t *testing.T) {
+		grypeDBPrefetched = false
+		command, argsFile := fakeGrype(t, 0)
+		prefetchGrypeDB(configFor(command, true))
+
+		args, err := os.ReadFile(argsFile)
+		if err != nil {
+			t.Fatalf("grype was not run: %v", err)
+		}
+		if strings.TrimSpace(string(args)) != "db update" {
+			t.Errorf("grype args = %q, want %q", args, "db update")
+		}
+		if !grypeDBPrefetched {
+			t.Error("grypeDBPrefetched = false after a successful update")
+		}
+	})
+
+	t.Run("failed update leaves auto-update on", func(t *testing.T) {
+		grypeDBPrefetched = false
+		command, _ := fakeGrype(t, 1)
+		prefetchGrypeDB(configFor(command, true))
+		if grypeDBPrefetched {
+			t.Error("grypeDBPrefetched = true after a failed update")
+		}
+	})
+
+	t.Run("option off", func(t *testing.T) {
+		grypeDBPrefetched = false
+		command, argsFile := fakeGrype(t, 0)
+		prefetchGrypeDB(configFor(command, false))
+		if _, err := os.Stat(argsFile); err == nil {
+			t.Error("grype db update ran without grype_db_prefetch")
+		}
+	})
+
+	t.Run("offline DB is not updated", func(t *testing.T) {
+		grypeDBPrefetched = false
+		offlineDBDir = t.TempDir()
+		t.Cleanup(func() { offlineDBDir = "" })
+		command, argsFile := fakeGrype(t, 0)
+		prefetchGrypeDB(configFor(command, true))
+		if _, err := os.Stat(argsFile); err == nil {
+			t.Error("grype db update ran with offline_db_dir set")
+		}
+	})
+}
+
+func TestScannerCommandGrypeAutoUpdate(t *testing.T) {
+	t.Cleanup(func() { grypeDBPrefetched = false })
+	scanner := ScannerConfig{Name: "grype", Command: "grype"}
+	const disabled = "GRYPE_DB_AUTO_UPDATE=false"
+
+	hasEnv := func(env []string) bool {
+		for _, kv := range env {
+			if kv == disabled {
+				return true
+			}
+		}
+		return false
+	}
+
+	grypeDBPrefetched = false
+	if cmd := scannerCommand(context.Background(), scanner, nil, t.TempDir()); hasEnv(cmd.Env) {
+		t.Errorf("scanner command has %s without a prefetched DB", disabled)
+	}
+
+	grypeDBPrefetched = true
+	cmd := scannerCommand(context.Background(), scanner, []string{"sbom:x.json"}, t.TempDir())
+	if !hasEnv(cmd.Env) {
+		t.Errorf("scanner command missing %s after the DB was prefetched", disabled)
+	}
+	if len(cmd.Env) < 2 {
+		t.Error("scanner command dropped the inherited environment")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// helmTemplateArgs builds the `helm template` arguments that render the chart
+// in chartDir, named after its directory. With outputDir set, each template is
+// written to a file under it; otherwise the manifests go to stdout.
+func helmTemplateArgs(chartDir, outputDir string) []string {
+	args := []string{"template", filepath.Base(chartDir), chartDir}
+	if outputDir != "" {
+		args = append(args, "--output-dir", outputDir)
+	}
+	return args
+}
+
+// runHelmTemplate runs helm with args and returns its stdout, or an error
+// carrying helm's stderr. Helm is killed after timeout, which can happen
+// when a chart's dependencies are fetched from an unreachable repository.
+func runHelmTemplate(args []string, timeout time.Duration) ([]byte, error) {
+	if _, err := exec.LookPath("helm"); err != nil {
+		return nil, fmt.Errorf("helm not found in PATH")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	cmd.WaitDelay = time.Second // don't wait on plugins still holding stdout
+	out, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("helm template timed out after %v", timeout)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("helm template failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("helm template failed: %w", err)
+	}
+	return out, nil
+}
+
+// renderHelmChart renders a chart with `helm template` so its manifests can be
+// checked, giving up after timeout. Charts are skipped when helm isn't
+// installed.
+func renderHelmChart(chartDir string, timeout time.Duration) ([]byte, error) {
+	return runHelmTemplate(helmTemplateArgs(chartDir, ""), timeout)
+}
+
+// renderHelmCharts renders the charts under dir into a new temporary
+// directory (the {{rendered}} arg template), one subdirectory per chart
+// mirroring its path below dir. charts are the chart directories language
+// detection found in the checkout at repoPath, relative to it, so no second
+// walk is needed. Each render is bounded by timeout; charts that fail to
+// render are skipped with a warning. The caller removes the directory.
+func renderHelmCharts(repoPath, dir string, charts []string, timeout time.Duration) (string, error) {
+	renderedDir, err := os.MkdirTemp("", "allscan-helm-")
+	if err != nil {
+		return "", fmt.Errorf("creating rendered manifests directory: %w", err)
+	}
+
+	rendered := 0
+	for _, chart := range charts {
+		chartDir := filepath.Join(repoPath, chart)
+		rel, err := filepath.Rel(dir, chartDir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue // outside the scanner's working_dir
+		}
+		if _, err := runHelmTemplate(helmTemplateArgs(chartDir, filepath.Join(renderedDir, rel)), timeout); err != nil {
+			log.Printf("    ⚠️  Skipped Helm chart %s: %v", rel, err)
+			continue
+		}
+		rendered++
+	}
+	if rendered > 0 {
+		log.Printf("    📐 Rendered %d Helm chart(s)", rendered)
+	}
+	return renderedDir, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHelmTemplateArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		chartDir  string
+		outputDir string
+		want      []string
+	}{
+		{"to stdout", "/repo/charts/api", "", []string{"template", "api", "/repo/charts/api"}},
+		{"to output dir", "/repo/charts/api", "/tmp/rendered/charts/api", []string{"template", "api", "/repo/charts/api", "--output-dir", "/tmp/rendered/charts/api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helmTemplateArgs(tt.chartDir, tt.outputDir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("helmTemplateArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeHelmFixture creates charts/api (with a subchart), charts/broken and a
+// vendored chart under node_modules in a new repo dir
+func writeHelmFixture(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	for _, chart := range []string{"charts/api", "charts/api/charts/redis", "charts/broken", "node_modules/pkg/chart"} {
+		dir := filepath.Join(repo, filepath.FromSlash(chart))
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: "+filepath.Base(dir)+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestDetectHelmCharts(t *testing.T) {
+	repo := writeHelmFixture(t)
+	want := []string{filepath.Join("charts", "api"), filepath.Join("charts", "broken")}
+	_, _, sequential, err := walkLanguages(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, parallel := walkLanguagesParallel(repo, 4, nil)
+	for name, charts := range map[string][]string{"sequential": sequential, "parallel": parallel} {
+		if got := topLevelCharts(charts); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s walk: charts = %v, want %v", name, got, want)
+		}
+	}
+
+	t.Run("chart at the root", func(t *testing.T) {
+		if got := topLevelCharts([]string{filepath.Join("charts", "redis"), "."}); !reflect.DeepEqual(got, []string{"."}) {
+			t.Errorf("topLevelCharts() = %v, want only the root chart", got)
+		}
+	})
+}
+
+func TestRunHelmTemplateTimeout(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "helm"), []byte("#!/bin/sh\nsleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	start := time.Now()
+	_, err := runHelmTemplate([]string{"template", "api", "."}, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("runHelmTemplate() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("runHelmTemplate() took %v, want it killed at the timeout", elapsed)
+	}
+}
+
+func TestRenderHelmForScanner(t *testing.T) {
+	// Fake helm: `helm template <name> <chart> --output-dir <dir>` writes one
+	// manifest, like the real layout (<dir>/<name>/templates/...); fails for "broken"
+	binDir := t.TempDir()
+	helm := `#!/bin/sh
+[ "$2" = "broken" ] && { echo "missing dependencies" >&2; exit 1; }
+mkdir -p "$5/$2/templates" && echo "kind: Pod" > "$5/$2/templates/pod.yaml"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "helm"), []byte(helm), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	repo := writeHelmFixture(t)
+	dir := t.TempDir()
+	scanner := ScannerConfig{
+		Name: "fake",
+		// Record the rendered dir and the manifests in it as the scanner output
+		Command:    writeFakeScanner(t, dir, `echo "$3" > "$1"; cd "$3" && find . -name '*.yaml' | sort >> "$1"`),
+		Args:       []string{"{{output}}", filepath.Join(dir, "attempts"), "{{rendered}}"},
+		RenderHelm: true,
+		timeout:    5 * time.Second,
+	}
+	config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+
+	detected, err := detectLanguagesIgnoring(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := runScanner(config, scanner, RepositoryConfig{URL: "https://github.com/org/repo"}, repo, "abc1234", "main", "", nil, detected.HelmCharts)
+	if !result.Success {
+		t.Fatalf("runScanner() failed: %v", result.Error)
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	renderedDir := lines[0]
+	if got, want := lines[1:], []string{"./charts/api/api/templates/pod.yaml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rendered manifests = %v, want %v", got, want)
+	}
+	if !strings.HasPrefix(renderedDir, os.TempDir()) || strings.HasPrefix(renderedDir, repo) {
+		t.Errorf("{{rendered}} = %q, want a temp dir outside the repo", renderedDir)
+	}
+	if _, err := os.Stat(renderedDir); !os.IsNotExist(err) {
+		t.Errorf("rendered dir %s not removed after the scan", renderedDir)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"allscan/parsers"
+)
+
+// defaultPostRunTimeout bounds the post-run hook when post_run_timeout is unset
+const defaultPostRunTimeout = 5 * time.Minute
+
+// expandHookTokens substitutes the post-run hook template tokens:
+//
+//	{{report}}  - path to the JSON run report
+//	{{results}} - results directory
+func expandHookTokens(args []string, reportPath, resultsDir string) []string {
+	replacer := strings.NewReplacer(
+		"{{report}}", reportPath,
+		"{{results}}", resultsDir,
+	)
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = replacer.Replace(arg)
+	}
+	return expanded
+}
+
+// runPostRunHook executes global.post_run_command once after a run, logging its
+// combined output. It is a no-op when no command is configured.
+func runPostRunHook(config *Config, reportPath string) error {
+	if len(config.Global.PostRunCommand) == 0 {
+		return nil
+	}
+
+	timeout := defaultPostRunTimeout
+	if config.Global.PostRunTimeout != "" {
+		parsed, err := time.ParseDuration(config.Global.PostRunTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid post_run_timeout: %w", err)
+		}
+		timeout = parsed
+	}
+
+	args := expandHookTokens(config.Global.PostRunCommand, reportPath, config.Global.ResultsDir)
+	log.Printf("🪝 Running post-run hook: %s", strings.Join(args, " "))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	output, err := cmd.CombinedOutput()
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		log.Printf("    │ %s", scanner.Text())
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("post-run hook timed out after %v", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("post-run hook: %w", err)
+	}
+
+	log.Printf("    ✅ Post-run hook completed in %v", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// finishRun saves the run report (of the results selected by --only) and runs
+// the post-run hook. A failing hook is logged as a warning, or fails the run
+// with --strict. The run also fails if no repository could be cloned, with
+// require_coverage if any target had no scanner run (a repository that
+// failed to clone included), and with min_scorecard_score if any target's
+// scorecard score is below it. The caller exits on the returned error, after
+// its own cleanup.
+func finishRun(config *Config, contexts []RepoScanContext) error {
+	reportPath := saveRunReport(config, filterContextResults(contexts, config.Global.OnlyResults))
+	if err := runPostRunHook(config, reportPath); err != nil {
+		if config.Global.Strict {
+			return err
+		}
+		log.Printf("⚠️  %v", err)
+	}
+	if cloneFailures(contexts) == len(contexts) && len(contexts) > 0 {
+		return fmt.Errorf("None of the %d repositories could be cloned", len(contexts))
+	}
+	if config.Global.RequireCoverage {
+		if uncovered := uncoveredTargets(contexts); len(uncovered) > 0 {
+			return fmt.Errorf("No scanners ran on %d target(s) with require_coverage set: %s",
+				len(uncovered), strings.Join(uncovered, ", "))
+		}
+	}
+	if min := config.Global.MinScorecardScore; min > 0 {
+		if low := lowScorecardTargets(contexts, min); len(low) > 0 {
+			return fmt.Errorf("Scorecard score below min_scorecard_score %g on %d target(s): %s",
+				min, len(low), strings.Join(low, ", "))
+		}
+	}
+	return nil
+}
+
+// lowScorecardTargets lists the targets (as in uncoveredTargets, with the
+// score) whose overall scorecard score is below min. Scorecard results that
+// failed, are SARIF, or can't be read are skipped with a warning, as are
+// inconclusive (negative) scores, so only a measured low score fails the run.
+func lowScorecardTargets(contexts []RepoScanContext, min float64) []string {
+	var low []string
+	for _, ctx := range contexts {
+		for _, result := range ctx.Results {
+			if result.Scanner != "scorecard" || !result.Success || result.IsSarif {
+				continue
+			}
+			data, err := os.ReadFile(result.OutputPath)
+			var score float64
+			if err == nil {
+				score, err = parsers.ScorecardScore(data)
+			}
+			if err != nil {
+				log.Printf("⚠️  Can't check the scorecard score of %s: %v", ctx.RepoURL, err)
+				continue
+			}
+			if score < 0 || score >= min {
+				continue
+			}
+			target := ctx.RepoURL
+			if ctx.Subproject != "" {
+				target += "#" + ctx.Subproject
+			}
+			low = append(low, fmt.Sprintf("%s (%g)", target, score))
+		}
+	}
+	return low
+}
+
+// cloneFailures counts the repositories that couldn't be cloned
+func cloneFailures(contexts []RepoScanContext) int {
+	failures := 0
+	for _, ctx := range contexts {
+		if ctx.CloneError != nil {
+			failures++
+		}
+	}
+	return failures
+}
+
+// uncoveredTargets lists the targets (repo URL, plus "#<path>" for
+// sub-projects) on which no scanner ran, repositories that failed to clone
+// included
+func uncoveredTargets(contexts []RepoScanContext) []string {
+	var uncovered []string
+	for _, ctx := range contexts {
+		if ctx.CoverageGap == "" && ctx.CloneError == nil {
+			continue
+		}
+		target := ctx.RepoURL
+		if ctx.Subproject != "" {
+			target += "#" + ctx.Subproject
+		}
+		uncovered = append(uncovered, target)
+	}
+	return uncovered
+}
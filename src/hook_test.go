@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandHookTokens(t *testing.T) {
+	args := []string{"notify", "--report={{report}}", "{{results}}/sboms", "--static"}
+	got := expandHookTokens(args, "/out/run.json", "/scan-results")
+	want := []string{"notify", "--report=/out/run.json", "/scan-results/sboms", "--static"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("expandHookTokens() = %v, want %v", got, want)
+	}
+	if args[1] != "--report={{report}}" {
+		t.Errorf("expandHookTokens() modified its input: %v", args)
+	}
+}
+
+func TestRunPostRunHook(t *testing.T) {
+	t.Run("no command is a no-op", func(t *testing.T) {
+		if err := runPostRunHook(&Config{}, "/out/run.json"); err != nil {
+			t.Errorf("runPostRunHook() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("invokes command with substituted tokens", func(t *testing.T) {
+		dir := t.TempDir()
+		marker := filepath.Join(dir, "hook.txt")
+		config := &Config{Global: GlobalConfig{
+			ResultsDir:     dir,
+			PostRunCommand: []string{"sh", "-c", `printf '%s %s' "$1" "$2" > "$3"`, "hook", "{{report}}", "{{results}}", marker},
+		}}
+
+		if err := runPostRunHook(config, "/out/run.json"); err != nil {
+			t.Fatalf("runPostRunHook() error = %v", err)
+		}
+		data, err := os.ReadFile(marker)
+		if err != nil {
+			t.Fatalf("hook did not run: %v", err)
+		}
+		if want := "/out/run.json " + dir; string(data) != want {
+			t.Errorf("hook received %q, want %q", data, want)
+		}
+	})
+
+	t.Run("non-zero exit returns error", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{PostRunCommand: []string{"sh", "-c", "echo boom; exit 3"}}}
+		if err := runPostRunHook(config, ""); err == nil {
+			t.Error("runPostRunHook() expected error for failing command, got nil")
+		}
+	})
+
+	t.Run("timeout returns error", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{
+			PostRunCommand: []string{"sleep", "5"},
+			PostRunTimeout: "50ms",
+		}}
+		err := runPostRunHook(config, "")
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Errorf("runPostRunHook() error = %v, want timeout error", err)
+		}
+	})
+
+	t.Run("invalid timeout returns error", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{PostRunCommand: []string{"true"}, PostRunTimeout: "soon"}}
+		if err := runPostRunHook(config, ""); err == nil {
+			t.Error("runPostRunHook() expected error for invalid timeout, got nil")
+		}
+	})
+}
+
+func TestRunReportPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		global GlobalConfig
+		want   string
+	}{
+		{name: "no report needed", global: GlobalConfig{ResultsDir: "/results"}, want: ""},
+		{name: "explicit --output", global: GlobalConfig{ResultsDir: "/results", OutputPath: "/tmp/run.json", PostRunCommand: []string{"true"}}, want: "/tmp/run.json"},
+		{name: "hook without --output uses results dir", global: GlobalConfig{ResultsDir: "/results", PostRunCommand: []string{"true"}}, want: "/results/run-report.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runReportPath(&Config{Global: tt.global}); got != tt.want {
+				t.Errorf("runReportPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLowScorecardTargets(t *testing.T) {
+	dir := t.TempDir()
+	scorecard := func(name, output string) ScanResult {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return ScanResult{Scanner: "scorecard", OutputPath: path, Success: true}
+	}
+
+	tests := []struct {
+		name   string
+		result ScanResult
+		want   []string
+	}{
+		{"below the threshold", scorecard("below", `{"score": 4.9}`), []string{"https://github.com/org/repo (4.9)"}},
+		{"at the threshold", scorecard("at", `{"score": 5.0}`), nil},
+		{"above the threshold", scorecard("above", `{"score": 5.1}`), nil},
+		{"inconclusive score", scorecard("inconclusive", `{"score": -1}`), nil},
+		{"missing scorecard file", ScanResult{Scanner: "scorecard", OutputPath: filepath.Join(dir, "missing.json"), Success: true}, nil},
+		{"unparsable output", scorecard("garbage", "not json"), nil},
+		{"failed scan", ScanResult{Scanner: "scorecard", OutputPath: filepath.Join(dir, "below.json")}, nil},
+		{"other scanner", ScanResult{Scanner: "grype", OutputPath: filepath.Join(dir, "below.json"), Success: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contexts := []RepoScanContext{{RepoURL: "https://github.com/org/repo", Results: []ScanResult{tt.result}}}
+			if got := lowScorecardTargets(contexts, 5); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lowScorecardTargets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"allscan/parsers"
 )
 
 // languageExtensions maps file extensions to language names
@@ -127,10 +136,14 @@ var manifestLanguages = map[string]string{
 	"pyproject.toml": "python",
 	"Pipfile":        "python",
 	"Pipfile.lock":   "python",
+	"poetry.lock":    "python",
+	"uv.lock":        "python",
+	"pdm.lock":       "python",
 	"pom.xml":        "java",
 	"build.gradle":   "java",
 	"build.gradle.kts": "kotlin",
 	"settings.gradle": "java",
+	"gradle.lockfile": "java",
 	"Gemfile":        "ruby",
 	"Gemfile.lock":   "ruby",
 	"composer.json":  "php",
@@ -138,14 +151,50 @@ var manifestLanguages = map[string]string{
 	"Cargo.toml":     "rust",
 	"Cargo.lock":     "rust",
 	"Package.swift":  "swift",
+	"Package.resolved": "swift",
+	"Podfile.lock":   "swift",
 	"build.sbt":      "scala",
 	"mix.exs":        "elixir",
+	"mix.lock":       "elixir",
 	"rebar.config":   "erlang",
+	"rebar.lock":     "erlang",
 	"pubspec.yaml":   "dart",
+	"pubspec.yml":    "dart",
+	"pubspec.lock":   "dart",
+	"packages.lock.json": "csharp",
+	"conan.lock":     "cpp",
+	"conanfile.txt":  "cpp",
+	"cabal.project.freeze": "haskell",
+	"stack.yaml":     "haskell",
+	"stack.yaml.lock": "haskell",
+	"DESCRIPTION":    "r", // R package metadata
+	"Chart.yaml":     "helm",
 	"Makefile":       "c", // Often indicates C/C++ projects
 	"CMakeLists.txt": "c",
 }
 
+// manifestPatterns maps manifest file name patterns (filepath.Match syntax)
+// to languages, for manifests whose names vary
+var manifestPatterns = map[string]string{
+	"requirements-*.txt": "python", // e.g. requirements-dev.txt
+	"*.gemspec":          "ruby",
+	"*.rockspec":         "lua", // LuaRocks
+}
+
+// manifestLanguage returns the language of a manifest file, matching
+// manifestLanguages first and then manifestPatterns
+func manifestLanguage(name string) (string, bool) {
+	if lang, ok := manifestLanguages[name]; ok {
+		return lang, true
+	}
+	for pattern, lang := range manifestPatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
 // githubLanguageMap maps GitHub's language names to our internal names
 var githubLanguageMap = map[string]string{
 	"Go":          "go",
@@ -179,9 +228,12 @@ var githubLanguageMap = map[string]string{
 
 // DetectedLanguages holds the result of language detection
 type DetectedLanguages struct {
-	Languages  []string       // List of detected languages
-	FileCounts map[string]int // Count of files per language (bytes for GitHub API)
-	Source     string         // "github-api" or "filesystem"
+	Languages     []string        // List of detected languages
+	FileCounts    map[string]int  // Count of files per language (bytes for GitHub API)
+	Source        string          // "github-api" or "filesystem"
+	ManifestFiles map[string]bool // manifest file names (see manifestLanguage) found in the checkout
+	Ignored       []string        // Languages below min_language_percent (still in FileCounts, not used for scanner selection)
+	HelmCharts    []string        // Directories holding a Chart.yaml, relative to the checkout (subcharts left out)
 }
 
 // parseGitHubURL extracts owner and repo from a GitHub URL
@@ -202,6 +254,13 @@ func parseGitHubURL(repoURL string) (owner, repo string, ok bool) {
 	return "", "", false
 }
 
+// githubLanguagesTimeout bounds a languages API call, reading the body included
+const githubLanguagesTimeout = 10 * time.Second
+
+// githubLanguagesMaxBody caps the languages API response that is read. Real
+// responses are a few hundred bytes.
+const githubLanguagesMaxBody = 1 << 20
+
 // detectLanguagesFromGitHub uses GitHub's API to detect repository languages
 // Returns nil if the API call fails or the repo is not on GitHub
 func detectLanguagesFromGitHub(repoURL string) (*DetectedLanguages, error) {
@@ -210,17 +269,21 @@ func detectLanguagesFromGitHub(repoURL string) (*DetectedLanguages, error) {
 		return nil, fmt.Errorf("not a GitHub URL: %s", repoURL)
 	}
 
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN not set")
+	token, err := githubToken()
+	if err != nil {
+		return nil, err
 	}
 
 	// Build API URL: https://api.github.com/repos/{owner}/{repo}/languages
-	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/languages", owner, repo)
-
-	// Create request with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("GET", apiURL, nil)
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/languages", githubAPIURL, owner, repo)
+
+	// Create request with timeout. The client timeout covers the whole
+	// exchange, reading the body included; the request context is only an
+	// extra bound with the same deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), githubLanguagesTimeout)
+	defer cancel()
+	client := &http.Client{Timeout: githubLanguagesTimeout}
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -241,9 +304,18 @@ func detectLanguagesFromGitHub(repoURL string) (*DetectedLanguages, error) {
 		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
+	// Read at most githubLanguagesMaxBody, so a huge response can't exhaust memory
+	body, err := io.ReadAll(io.LimitReader(resp.Body, githubLanguagesMaxBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if len(body) > githubLanguagesMaxBody {
+		return nil, fmt.Errorf("response exceeds %d bytes", githubLanguagesMaxBody)
+	}
+
 	// Parse response: {"Go": 12345, "Python": 6789, ...}
 	var langBytes map[string]int
-	if err := json.NewDecoder(resp.Body).Decode(&langBytes); err != nil {
+	if err := json.Unmarshal(body, &langBytes); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
 
@@ -270,11 +342,13 @@ func detectLanguagesFromGitHub(repoURL string) (*DetectedLanguages, error) {
 
 // detectLanguages detects languages in a repository
 // For GitHub repos, it tries the API first for speed, then falls back to filesystem scan
-func detectLanguages(repoPath string, repoURL string) (*DetectedLanguages, error) {
+// Filesystem detection skips the paths matched by ignore (the .allscanignore).
+func detectLanguages(repoPath string, repoURL string, ignore *parsers.IgnorePatterns) (*DetectedLanguages, error) {
 	// Try GitHub API first if we have a GitHub URL
 	if repoURL != "" && !strings.HasPrefix(repoURL, "local://") {
 		detected, err := detectLanguagesFromGitHub(repoURL)
 		if err == nil {
+			addIaCLanguages(detected, repoPath, ignore)
 			return detected, nil
 		}
 		// Log the fallback reason at debug level
@@ -282,70 +356,383 @@ func detectLanguages(repoPath string, repoURL string) (*DetectedLanguages, error
 	}
 
 	// Fall back to filesystem detection
-	return detectLanguagesFromFilesystem(repoPath)
+	return detectLanguagesIgnoring(repoPath, ignore)
+}
+
+// iacLanguages are infrastructure-as-code "languages" that GitHub's languages
+// API doesn't report (it counts YAML as data), so they are always detected from
+// the checkout
+var iacLanguages = []string{"kubernetes", "helm"}
+
+// addIaCLanguages adds the iacLanguages found in repoPath to languages
+// detected by the GitHub API, so IaC scanners restricted to them still run.
+// Their file counts are left out because API counts are in bytes. The
+// manifest files and Helm charts found are added too, for scanners with
+// required_manifests or render_helm. This is the checkout's only walk.
+func addIaCLanguages(detected *DetectedLanguages, repoPath string, ignore *parsers.IgnorePatterns) {
+	fs, err := detectLanguagesIgnoring(repoPath, ignore)
+	if err != nil {
+		return
+	}
+	detected.ManifestFiles = fs.ManifestFiles
+	detected.HelmCharts = fs.HelmCharts
+	for _, lang := range iacLanguages {
+		if fs.hasLanguage(lang) && !detected.hasLanguage(lang) {
+			detected.Languages = append(detected.Languages, lang)
+		}
+	}
+}
+
+// isSkippedDir reports whether a directory should be skipped during filesystem
+// walks: hidden directories and common dependency or build output directories.
+func isSkippedDir(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	switch name {
+	case "node_modules", "vendor", "__pycache__", "venv", "target", "build", "dist", "bin", "obj":
+		return true
+	}
+	return false
+}
+
+// loadScanIgnore returns the parsed .allscanignore at the root of dir, or nil
+// when there is none. An unreadable file is logged and treated as empty.
+// runScannersOnRepo loads it once per scanned checkout and passes it to
+// language detection and the built-in scanners: the same workspace directory
+// holds a different checkout for every ref (all_tags, all_branches).
+func loadScanIgnore(dir string) *parsers.IgnorePatterns {
+	patterns, err := parsers.LoadIgnoreFile(dir)
+	if err != nil {
+		log.Printf("  ⚠️  Ignoring %s: %v", parsers.IgnoreFileName, err)
+	}
+	return patterns
 }
 
-// detectLanguagesFromFilesystem scans a directory and returns the languages found
+// languageDetectionWorkers is global.language_detection_workers: how many
+// goroutines walk a large repo during filesystem language detection
+// (0 = one per CPU, 1 = always sequential)
+var languageDetectionWorkers int
+
+// followSymlinks is global.follow_symlinks: whether filesystem language
+// detection and the built-in scanners descend into symlinked directories
+// inside the repo. Symlinks resolving outside the repo are never followed.
+var followSymlinks bool
+
+// parallelDetectionThreshold is the number of files and directories above
+// which filesystem language detection walks the repo in parallel. Below it
+// the goroutines cost more than they save, so small repos walk sequentially.
+const parallelDetectionThreshold = 5000
+
+// defaultDetectionIgnore are the generated and minified files that filesystem
+// language detection doesn't count unless global.detection_ignore replaces
+// them: they inflate a language's share without being code anyone wrote
+var defaultDetectionIgnore = []string{
+	"*.min.js", "*.bundle.js",
+	"*_pb2.py", "*_pb2_grpc.py",
+	"*.pb.go", "zz_generated*.go",
+	"*.pb.cc", "*.pb.h",
+	"*.g.dart", "*.freezed.dart",
+	"*.designer.cs", "*.g.cs",
+}
+
+// detectionIgnore is the parsed global.detection_ignore: paths filesystem
+// language detection skips in addition to .allscanignore entries
+var detectionIgnore = parsers.ParseIgnorePatterns(strings.Join(defaultDetectionIgnore, "\n"))
+
+// setDetectionIgnore replaces the patterns filesystem language detection
+// skips; an empty list skips none
+func setDetectionIgnore(patterns []string) {
+	detectionIgnore = parsers.ParseIgnorePatterns(strings.Join(patterns, "\n"))
+}
+
+// validateDetectionIgnore rejects malformed detection_ignore globs, which
+// would otherwise never match
+func validateDetectionIgnore(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(strings.TrimPrefix(pattern, "!"), ""); err != nil {
+			return fmt.Errorf("detection_ignore: invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// detectLanguagesFromFilesystem scans a directory and returns the languages
+// found, skipping the paths in its .allscanignore
 func detectLanguagesFromFilesystem(repoPath string) (*DetectedLanguages, error) {
+	return detectLanguagesIgnoring(repoPath, loadScanIgnore(repoPath))
+}
+
+// detectLanguagesIgnoring scans a directory and returns the languages found,
+// skipping the paths matched by ignore
+func detectLanguagesIgnoring(repoPath string, ignore *parsers.IgnorePatterns) (*DetectedLanguages, error) {
+	workers := languageDetectionWorkers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var languageCounts map[string]int
+	var manifests map[string]bool
+	var charts []string
+	if workers > 1 && hasMoreEntries(repoPath, parallelDetectionThreshold) {
+		languageCounts, manifests, charts = walkLanguagesParallel(repoPath, workers, ignore)
+	} else {
+		var err error
+		languageCounts, manifests, charts, err = walkLanguages(repoPath, ignore)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Convert map to slice of languages
+	languages := make([]string, 0, len(languageCounts))
+	for lang := range languageCounts {
+		languages = append(languages, lang)
+	}
+
+	return &DetectedLanguages{
+		Languages:     languages,
+		FileCounts:    languageCounts,
+		Source:        "filesystem",
+		ManifestFiles: manifests,
+		HelmCharts:    topLevelCharts(charts),
+	}, nil
+}
+
+// topLevelCharts sorts the chart directories found by a language walk and
+// drops subcharts, which helm renders with their parent
+func topLevelCharts(charts []string) []string {
+	slices.Sort(charts)
+	var top []string
+	for _, chart := range charts {
+		if n := len(top); n > 0 && (top[n-1] == "." || strings.HasPrefix(chart, top[n-1]+string(filepath.Separator))) {
+			continue
+		}
+		top = append(top, chart)
+	}
+	return top
+}
+
+// classifyFile returns the language a file counts towards ("" for none) and
+// whether it is a manifest. Manifest names are checked first (higher
+// confidence), then the extension; YAML files count as kubernetes when they
+// look like a manifest.
+func classifyFile(path, name string) (lang string, manifest bool) {
+	if lang, ok := manifestLanguage(name); ok {
+		return lang, true
+	}
+	ext := filepath.Ext(name)
+	if ext == "" {
+		return "", false
+	}
+	if lang, ok := languageExtensions[ext]; ok {
+		return lang, false
+	}
+	if (ext == ".yaml" || ext == ".yml") && isKubernetesManifest(path) {
+		return "kubernetes", false
+	}
+	return "", false
+}
+
+// walkLanguages counts the languages of the files under repoPath in a single
+// parsers.WalkRepo, skipping hidden and non-source directories and the paths
+// matched by ignore. It also returns the directories holding a Chart.yaml,
+// relative to repoPath.
+func walkLanguages(repoPath string, ignore *parsers.IgnorePatterns) (map[string]int, map[string]bool, []string, error) {
 	languageCounts := make(map[string]int)
+	manifests := make(map[string]bool)
+	var charts []string
 
-	err := filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+	err := parsers.WalkRepo(repoPath, followSymlinks, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil // Skip files we can't access
 		}
 
-		// Skip hidden directories and common non-source directories
-		if info.IsDir() {
-			name := info.Name()
-			if strings.HasPrefix(name, ".") ||
-			   name == "node_modules" ||
-			   name == "vendor" ||
-			   name == "__pycache__" ||
-			   name == "venv" ||
-			   name == ".venv" ||
-			   name == "target" ||
-			   name == "build" ||
-			   name == "dist" ||
-			   name == "bin" ||
-			   name == "obj" {
+		// Skip hidden directories, common non-source directories and
+		// .allscanignore entries
+		relPath, _ := filepath.Rel(repoPath, path)
+		if d.IsDir() {
+			if isSkippedDir(d.Name()) || (path != repoPath && (ignore.Match(relPath, true) || detectionIgnore.Match(relPath, true))) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
-
-		// Check manifest files first (higher confidence)
-		filename := info.Name()
-		if lang, ok := manifestLanguages[filename]; ok {
-			languageCounts[lang]++
+		if ignore.Match(relPath, false) || detectionIgnore.Match(relPath, false) {
 			return nil
 		}
 
-		// Check file extension
-		ext := filepath.Ext(filename)
-		if ext != "" {
-			if lang, ok := languageExtensions[ext]; ok {
-				languageCounts[lang]++
+		if lang, manifest := classifyFile(path, d.Name()); lang != "" {
+			languageCounts[lang]++
+			if manifest {
+				manifests[d.Name()] = true
+			}
+			if lang == "helm" {
+				charts = append(charts, filepath.Dir(relPath))
 			}
 		}
-
 		return nil
 	})
+	return languageCounts, manifests, charts, err
+}
 
+// hasMoreEntries reports whether the directory tree under root, without the
+// skipped directories, holds more than limit files and directories. It stops
+// reading as soon as the limit is passed, so it stays cheap on huge repos.
+func hasMoreEntries(root string, limit int) bool {
+	seen := 0
+	queue := []string{root}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && isSkippedDir(entry.Name()) {
+				continue
+			}
+			if seen++; seen > limit {
+				return true
+			}
+			if entry.IsDir() {
+				queue = append(queue, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return false
+}
+
+// walkLanguagesParallel counts the same languages, manifests and charts as
+// walkLanguages with a pool of workers. Workers take directories from a
+// shared queue, read their entries, queue the subdirectories and classify
+// the files, merging their counts into the shared maps under a lock once per
+// directory. Symlinks are handled as in parsers.WalkRepo.
+func walkLanguagesParallel(repoPath string, workers int, ignore *parsers.IgnorePatterns) (map[string]int, map[string]bool, []string) {
+	languageCounts := make(map[string]int)
+	manifests := make(map[string]bool)
+	var charts []string
+	if info, err := os.Lstat(repoPath); err != nil || (info.IsDir() && isSkippedDir(info.Name())) {
+		return languageCounts, manifests, charts // like filepath.Walk skipping the root
+	}
+	realRoot, err := filepath.EvalSymlinks(repoPath)
 	if err != nil {
-		return nil, err
+		return languageCounts, manifests, charts
 	}
 
-	// Convert map to slice of languages
-	languages := make([]string, 0, len(languageCounts))
-	for lang := range languageCounts {
-		languages = append(languages, lang)
+	// walkDir is a queued directory: its path under repoPath, its real path
+	// (they differ below a followed symlink) and the real directories of the
+	// symlinks followed to reach it
+	type walkDir struct {
+		path, real string
+		linkDirs   []string
 	}
+	var (
+		mu     sync.Mutex // guards queue, active and the results
+		cond   = sync.NewCond(&mu)
+		queue  = []walkDir{{path: repoPath, real: realRoot}}
+		active int // directories being read
+		wg     sync.WaitGroup
+	)
+	worker := func() {
+		defer wg.Done()
+		for {
+			mu.Lock()
+			for len(queue) == 0 && active > 0 {
+				cond.Wait()
+			}
+			if len(queue) == 0 {
+				mu.Unlock()
+				return // nothing queued and nobody left to queue more
+			}
+			dir := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			active++
+			mu.Unlock()
+
+			entries, _ := os.ReadDir(dir.real)
+			var subdirs []walkDir
+			counts := make(map[string]int)
+			var found []string
+			chart := false
+			for _, entry := range entries {
+				path := filepath.Join(dir.path, entry.Name())
+				relPath, _ := filepath.Rel(repoPath, path)
+				subdir := walkDir{path: path, real: filepath.Join(dir.real, entry.Name()), linkDirs: dir.linkDirs}
+				isDir := entry.IsDir()
+				if entry.Type()&fs.ModeSymlink != 0 {
+					target, info, ok := parsers.SymlinkTarget(realRoot, subdir.real)
+					if !ok {
+						continue
+					}
+					if isDir = info.IsDir(); isDir {
+						if !followSymlinks || parsers.SymlinkCycle(target, dir.real, dir.linkDirs) {
+							continue
+						}
+						subdir.real, subdir.linkDirs = target, append(slices.Clip(dir.linkDirs), dir.real)
+					}
+				}
+				if isDir {
+					if !isSkippedDir(entry.Name()) && !ignore.Match(relPath, true) && !detectionIgnore.Match(relPath, true) {
+						subdirs = append(subdirs, subdir)
+					}
+					continue
+				}
+				if ignore.Match(relPath, false) || detectionIgnore.Match(relPath, false) {
+					continue
+				}
+				if lang, manifest := classifyFile(path, entry.Name()); lang != "" {
+					counts[lang]++
+					if manifest {
+						found = append(found, entry.Name())
+					}
+					chart = chart || lang == "helm"
+				}
+			}
 
-	return &DetectedLanguages{
-		Languages:  languages,
-		FileCounts: languageCounts,
-		Source:     "filesystem",
-	}, nil
+			mu.Lock()
+			for lang, n := range counts {
+				languageCounts[lang] += n
+			}
+			for _, name := range found {
+				manifests[name] = true
+			}
+			if chart {
+				rel, _ := filepath.Rel(repoPath, dir.path)
+				charts = append(charts, rel)
+			}
+			queue = append(queue, subdirs...)
+			active--
+			cond.Broadcast()
+			mu.Unlock()
+		}
+	}
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go worker()
+	}
+	wg.Wait()
+	return languageCounts, manifests, charts
+}
+
+// isKubernetesManifest reports whether a YAML file looks like a Kubernetes
+// manifest: a top-level apiVersion and kind within its first 8KB
+func isKubernetesManifest(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := f.Read(buf)
+	var hasAPIVersion, hasKind bool
+	for _, line := range strings.Split(string(buf[:n]), "\n") {
+		hasAPIVersion = hasAPIVersion || strings.HasPrefix(line, "apiVersion:")
+		hasKind = hasKind || strings.HasPrefix(line, "kind:")
+	}
+	return hasAPIVersion && hasKind
 }
 
 // Percentages returns raw percentage (0–100) for each language based on FileCounts.
@@ -368,6 +755,55 @@ func (d *DetectedLanguages) Percentages() map[string]float64 {
 	return pcts
 }
 
+// dropMinorLanguages moves languages whose share of FileCounts is below
+// minPercent from Languages to Ignored, so a stray file doesn't pull in that
+// language's scanners. Languages without a count (IaC added to GitHub API
+// results) and languages with a manifest file in the checkout are kept.
+// Returns the languages dropped.
+func (d *DetectedLanguages) dropMinorLanguages(minPercent float64) []string {
+	pcts := d.Percentages()
+	if minPercent <= 0 || pcts == nil {
+		return nil
+	}
+
+	hasManifest := make(map[string]bool)
+	for name := range d.ManifestFiles {
+		lang, _ := manifestLanguage(name)
+		hasManifest[lang] = true
+	}
+
+	kept := make([]string, 0, len(d.Languages))
+	var dropped []string
+	for _, lang := range d.Languages {
+		pct, counted := pcts[lang]
+		if counted && pct < minPercent && !hasManifest[lang] {
+			dropped = append(dropped, lang)
+			continue
+		}
+		kept = append(kept, lang)
+	}
+	d.Languages = kept
+	d.Ignored = append(d.Ignored, dropped...)
+	return dropped
+}
+
+// hasManifest reports whether the manifest file name, or a file matching the
+// manifestPatterns pattern name, was found in the checkout
+func (d *DetectedLanguages) hasManifest(name string) bool {
+	if d.ManifestFiles[name] {
+		return true
+	}
+	if _, ok := manifestPatterns[name]; !ok {
+		return false
+	}
+	for file := range d.ManifestFiles {
+		if ok, _ := filepath.Match(name, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // hasLanguage checks if a specific language was detected
 func (d *DetectedLanguages) hasLanguage(lang string) bool {
 	for _, l := range d.Languages {
@@ -388,18 +824,11 @@ func (d *DetectedLanguages) hasAnyLanguage(languages []string) bool {
 	return false
 }
 
-// logDetectedLanguages logs the detected languages in a friendly format
-func logDetectedLanguages(detected *DetectedLanguages) {
-	if len(detected.Languages) == 0 {
-		log.Printf("  🔍 No specific languages detected")
-		return
-	}
-
-	// Build a summary string with just language names (counts differ between API/filesystem)
-	source := "filesystem"
-	if detected.Source == "github-api" {
-		source = "GitHub API"
+// sourceLabel returns a human-readable name for where the languages came from
+func (d *DetectedLanguages) sourceLabel() string {
+	if d.Source == "github-api" {
+		return "GitHub API"
 	}
-	log.Printf("  🔍 Detected languages (%s): %s", source, strings.Join(detected.Languages, ", "))
+	return "filesystem"
 }
 
@@ -1,6 +1,15 @@
 package main
 
-import "testing"
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestParseGitHubURL(t *testing.T) {
 	tests := []struct {
@@ -146,6 +155,87 @@ func TestPercentages(t *testing.T) {
 	}
 }
 
+func TestDropMinorLanguages(t *testing.T) {
+	tests := []struct {
+		name        string
+		detected    DetectedLanguages
+		minPercent  float64
+		wantKept    []string
+		wantDropped []string
+	}{
+		{
+			name: "stray ruby file in a go repo",
+			detected: DetectedLanguages{
+				Languages:  []string{"go", "ruby"},
+				FileCounts: map[string]int{"go": 199, "ruby": 1},
+			},
+			minPercent:  1,
+			wantKept:    []string{"go"},
+			wantDropped: []string{"ruby"},
+		},
+		{
+			name: "threshold off keeps everything",
+			detected: DetectedLanguages{
+				Languages:  []string{"go", "ruby"},
+				FileCounts: map[string]int{"go": 199, "ruby": 1},
+			},
+			minPercent: 0,
+			wantKept:   []string{"go", "ruby"},
+		},
+		{
+			name: "language at the threshold is kept",
+			detected: DetectedLanguages{
+				Languages:  []string{"go", "python"},
+				FileCounts: map[string]int{"go": 95, "python": 5},
+			},
+			minPercent: 5,
+			wantKept:   []string{"go", "python"},
+		},
+		{
+			name: "language with a manifest is kept",
+			detected: DetectedLanguages{
+				Languages:     []string{"go", "javascript"},
+				FileCounts:    map[string]int{"go": 500, "javascript": 1},
+				ManifestFiles: map[string]bool{"go.mod": true, "package.json": true},
+			},
+			minPercent: 2,
+			wantKept:   []string{"go", "javascript"},
+		},
+		{
+			name: "languages without counts are kept",
+			detected: DetectedLanguages{
+				Languages:  []string{"go", "shell", "kubernetes"},
+				FileCounts: map[string]int{"go": 9800, "shell": 200},
+			},
+			minPercent:  5,
+			wantKept:    []string{"go", "kubernetes"},
+			wantDropped: []string{"shell"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected := tt.detected
+			dropped := detected.dropMinorLanguages(tt.minPercent)
+			if !reflect.DeepEqual(detected.Languages, tt.wantKept) {
+				t.Errorf("Languages = %v, want %v", detected.Languages, tt.wantKept)
+			}
+			if !reflect.DeepEqual(dropped, tt.wantDropped) || !reflect.DeepEqual(detected.Ignored, tt.wantDropped) {
+				t.Errorf("dropped = %v, Ignored = %v, want %v", dropped, detected.Ignored, tt.wantDropped)
+			}
+			// Raw counts are kept for reporting
+			if len(detected.FileCounts) != len(tt.detected.FileCounts) {
+				t.Errorf("FileCounts = %v, want all counts kept", detected.FileCounts)
+			}
+			for _, lang := range tt.wantDropped {
+				if pct := detected.Percentages()[lang]; pct >= tt.minPercent {
+					t.Errorf("dropped %s at %.1f%%, not below %g%%", lang, pct, tt.minPercent)
+				}
+			}
+		})
+	}
+}
+
 func TestHasLanguage(t *testing.T) {
 	detected := &DetectedLanguages{
 		Languages: []string{"go", "python", "javascript"},
@@ -206,3 +296,468 @@ func TestHasAnyLanguage(t *testing.T) {
 		}
 	})
 }
+
+func TestDetectKubernetesAndHelm(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  map[string]int
+	}{
+		{
+			name: "kubernetes manifest",
+			files: map[string]string{
+				"deploy/web.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n",
+			},
+			want: map[string]int{"kubernetes": 1},
+		},
+		{
+			name: "helm chart",
+			files: map[string]string{
+				"chart/Chart.yaml":  "apiVersion: v2\nname: web\n",
+				"chart/values.yaml": "replicas: 2\n",
+			},
+			want: map[string]int{"helm": 1},
+		},
+		{
+			name: "plain yaml is not kubernetes",
+			files: map[string]string{
+				".github/workflows/ci.yml": "on: push\n",
+				"config.yaml":              "kind: settings\nname: app\n",
+			},
+			want: map[string]int{},
+		},
+		{
+			name: "allscanignore excludes paths",
+			files: map[string]string{
+				".allscanignore":         "testdata/\n*.rb\n!keep.rb\n",
+				"main.go":                "package main\n",
+				"testdata/fixture.py":    "print('fixture')\n",
+				"scripts/release.rb":     "puts 'release'\n",
+				"scripts/keep.rb":        "puts 'keep'\n",
+				"internal/testdata/x.py": "print('x')\n",
+			},
+			want: map[string]int{"go": 1, "ruby": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			detected, err := detectLanguagesFromFilesystem(root)
+			if err != nil {
+				t.Fatalf("detectLanguagesFromFilesystem() error = %v", err)
+			}
+			if len(detected.FileCounts) != len(tt.want) {
+				t.Errorf("FileCounts = %v, want %v", detected.FileCounts, tt.want)
+			}
+			for lang, count := range tt.want {
+				if detected.FileCounts[lang] != count {
+					t.Errorf("FileCounts[%q] = %d, want %d", lang, detected.FileCounts[lang], count)
+				}
+			}
+		})
+	}
+}
+
+func TestScanIgnoreReadPerCheckout(t *testing.T) {
+	// all_tags/all_branches check out every ref into the same directory, so
+	// each checkout's .allscanignore must be read afresh
+	root := t.TempDir()
+	writeTestFiles(t, root, "main.go", "scripts/release.rb")
+	for _, tt := range []struct {
+		ignore   string
+		wantRuby bool
+	}{
+		{"*.rb\n", false},
+		{"testdata/\n", true},
+	} {
+		if err := os.WriteFile(filepath.Join(root, ".allscanignore"), []byte(tt.ignore), 0644); err != nil {
+			t.Fatal(err)
+		}
+		detected, err := detectLanguages(root, "", loadScanIgnore(root))
+		if err != nil {
+			t.Fatalf("detectLanguages() error = %v", err)
+		}
+		if detected.hasLanguage("ruby") != tt.wantRuby {
+			t.Errorf("with .allscanignore %q: languages = %v, want ruby %v", tt.ignore, detected.Languages, tt.wantRuby)
+		}
+	}
+}
+
+func TestDetectionIgnore(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":                    "package main\n",
+		"api/service.pb.go":          "package api\n",
+		"api/service_grpc.pb.go":     "package api\n",
+		"app/server.py":              "print('hi')\n",
+		"app/proto/service_pb2.py":   "# generated\n",
+		"web/app.js":                 "console.log('hi')\n",
+		"web/static/vendor.min.js":   "console.log('min')\n",
+		"web/static/jquery.min.js":   "console.log('min')\n",
+		"generated/client/client.ts": "export {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { setDetectionIgnore(defaultDetectionIgnore) })
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     map[string]int
+	}{
+		{name: "default patterns", patterns: defaultDetectionIgnore,
+			want: map[string]int{"go": 1, "python": 1, "javascript": 1, "typescript": 1}},
+		{name: "custom patterns", patterns: []string{"generated/", "*.min.js"},
+			want: map[string]int{"go": 3, "python": 2, "javascript": 1}},
+		{name: "empty list counts everything", patterns: []string{},
+			want: map[string]int{"go": 3, "python": 2, "javascript": 3, "typescript": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDetectionIgnore(tt.patterns)
+			sequential, _, _, err := walkLanguages(root, nil)
+			if err != nil {
+				t.Fatalf("walkLanguages() error = %v", err)
+			}
+			parallel, _, _ := walkLanguagesParallel(root, 4, nil)
+			if !reflect.DeepEqual(sequential, tt.want) || !reflect.DeepEqual(parallel, tt.want) {
+				t.Errorf("counts = %v (sequential), %v (parallel); want %v", sequential, parallel, tt.want)
+			}
+		})
+	}
+
+	if err := validateDetectionIgnore([]string{"*.pb.go", "!keep.min.js"}); err != nil {
+		t.Errorf("validateDetectionIgnore() = %v, want nil", err)
+	}
+	if err := validateDetectionIgnore([]string{"[*.js"}); err == nil || !strings.Contains(err.Error(), "detection_ignore") {
+		t.Errorf("validateDetectionIgnore([\"[*.js\"]) = %v, want a detection_ignore error", err)
+	}
+}
+
+func TestAddIaCLanguages(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"chart/Chart.yaml": "apiVersion: v2\nname: web\n",
+		"go.mod":           "module example\n",
+		"main.go":          "package main\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// As reported by the GitHub API: byte counts, no YAML-based languages
+	detected := &DetectedLanguages{Languages: []string{"go"}, FileCounts: map[string]int{"go": 5120}, Source: "github-api"}
+	addIaCLanguages(detected, root, nil)
+
+	if !detected.hasLanguage("helm") {
+		t.Errorf("Languages = %v, want helm added from the checkout", detected.Languages)
+	}
+	if detected.hasLanguage("kubernetes") {
+		t.Errorf("Languages = %v, kubernetes added without manifests", detected.Languages)
+	}
+	if !detected.ManifestFiles["go.mod"] {
+		t.Errorf("ManifestFiles = %v, want go.mod added from the checkout", detected.ManifestFiles)
+	}
+	if len(detected.FileCounts) != 1 || detected.Source != "github-api" {
+		t.Errorf("FileCounts = %v, Source = %q; want API data untouched", detected.FileCounts, detected.Source)
+	}
+
+	scanner := ScannerConfig{Name: "kubescape", Languages: []string{"kubernetes", "helm"}}
+	if !isScannerCompatible(scanner, detected) {
+		t.Error("scanner targeting helm not selected for a repo with a chart")
+	}
+}
+
+func TestRequiredManifestsSkipGrype(t *testing.T) {
+	grype := ScannerConfig{
+		Name:              "grype",
+		Enabled:           true,
+		Languages:         []string{"go", "python"},
+		RequiredManifests: []string{"go.mod", "requirements.txt", "requirements-*.txt", "poetry.lock", "Pipfile.lock"},
+	}
+	config := &Config{Scanners: []ScannerConfig{grype}}
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{
+			name:  "python sources without a lock file",
+			files: map[string]string{"app/main.py": "print('hi')\n"},
+			want:  false,
+		},
+		{
+			name:  "python with poetry.lock in a subdirectory",
+			files: map[string]string{"app/main.py": "print('hi')\n", "app/poetry.lock": "\n"},
+			want:  true,
+		},
+		{
+			name:  "go module",
+			files: map[string]string{"go.mod": "module example\n", "main.go": "package main\n"},
+			want:  true,
+		},
+		{
+			name:  "requirements file matching a pattern",
+			files: map[string]string{"main.py": "print('hi')\n", "requirements-dev.txt": "pytest\n"},
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			detected, err := detectLanguagesFromFilesystem(root)
+			if err != nil {
+				t.Fatalf("detectLanguagesFromFilesystem() error = %v", err)
+			}
+			got := len(getScannersForRepo(config, RepositoryConfig{URL: "https://github.com/org/repo"}, detected)) == 1
+			if got != tt.want {
+				t.Errorf("grype selected = %v, want %v (manifests %v)", got, tt.want, detected.ManifestFiles)
+			}
+		})
+	}
+}
+
+func TestShippedGrypeManifestsCoverLanguages(t *testing.T) {
+	config, err := loadConfigStrict("../scanners.yaml")
+	if err != nil {
+		t.Fatalf("loadConfigStrict(../scanners.yaml) error = %v", err)
+	}
+	// Languages whose manifests are recorded under a sibling language
+	sameEcosystem := map[string]string{"typescript": "javascript", "c": "cpp"}
+
+	for _, scanner := range config.Scanners {
+		if scanner.Name != "grype" {
+			continue
+		}
+		covered := make(map[string]bool)
+		for _, name := range scanner.RequiredManifests {
+			lang, ok := manifestLanguages[name]
+			if !ok {
+				lang = manifestPatterns[name]
+			}
+			covered[lang] = true
+		}
+		for _, lang := range append(append([]string{}, scanner.Languages...), scanner.LanguagesConditional...) {
+			if !covered[lang] && !covered[sameEcosystem[lang]] {
+				t.Errorf("grype supports %s but required_manifests lists none of its manifests", lang)
+			}
+		}
+		return
+	}
+	t.Fatal("no grype scanner in ../scanners.yaml")
+}
+
+// writeLanguageFixture builds a repo tree with sources in many nested
+// directories, manifests, Kubernetes YAML and directories detection skips
+func writeLanguageFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                        "module example.com/widget\n",
+		"main.go":                       "package main\n",
+		"web/package.json":              "{}\n",
+		"web/src/app.ts":                "",
+		"web/src/view.tsx":              "",
+		"deploy/app.yaml":               "apiVersion: v1\nkind: Service\n",
+		"deploy/values.yaml":            "replicas: 2\n",
+		"scripts/build.sh":              "",
+		"README":                        "",
+		"node_modules/lib/index.js":     "",
+		"vendor/dep/dep.go":             "",
+		".github/workflows/ci.yaml":     "apiVersion: v1\nkind: Pod\n",
+		"services/api/requirements.txt": "",
+	}
+	for d := 0; d < 20; d++ {
+		for f := 0; f < 5; f++ {
+			files[filepath.Join("pkg", string(rune('a'+d)), "sub", string(rune('a'+f))+".go")] = ""
+			files[filepath.Join("services", string(rune('a'+d)), string(rune('a'+f))+".py")] = ""
+		}
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestWalkLanguagesParallelMatchesSequential(t *testing.T) {
+	root := writeLanguageFixture(t)
+
+	wantCounts, wantManifests, _, err := walkLanguages(root, nil)
+	if err != nil {
+		t.Fatalf("walkLanguages: %v", err)
+	}
+	if wantCounts["go"] != 102 || wantCounts["python"] != 101 || wantCounts["kubernetes"] != 1 || !wantManifests["package.json"] {
+		t.Fatalf("unexpected sequential counts %v", wantCounts)
+	}
+
+	for _, workers := range []int{2, 4, 16} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			counts, manifests, _ := walkLanguagesParallel(root, workers, nil)
+			if !reflect.DeepEqual(counts, wantCounts) {
+				t.Errorf("counts = %v, want %v", counts, wantCounts)
+			}
+			if !reflect.DeepEqual(manifests, wantManifests) {
+				t.Errorf("manifests = %v, want %v", manifests, wantManifests)
+			}
+		})
+	}
+
+	t.Run("skipped root", func(t *testing.T) {
+		counts, _, _ := walkLanguagesParallel(filepath.Join(root, "vendor"), 4, nil)
+		seqCounts, _, _, _ := walkLanguages(filepath.Join(root, "vendor"), nil)
+		if len(counts) != 0 || len(seqCounts) != 0 {
+			t.Errorf("skipped root counted %v (sequential %v)", counts, seqCounts)
+		}
+	})
+}
+
+func TestWalkLanguagesSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	repo, outside := filepath.Join(dir, "repo"), filepath.Join(dir, "outside")
+	files := []string{"repo/main.go", "repo/pkg/util.go", "outside/app.py", "outside/lib/tool.py", "outside/deploy.yaml"}
+	for _, name := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("apiVersion: v1\nkind: Pod\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"repo/host":        outside,                               // directory outside the repo
+		"repo/deploy.yaml": filepath.Join(outside, "deploy.yaml"), // file outside the repo
+		"repo/shared":      "pkg",                                 // directory inside the repo
+		"repo/pkg/loop":    "..",                                  // cycle back to the root
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		follow bool
+		want   map[string]int
+	}{
+		{follow: false, want: map[string]int{"go": 2}},
+		{follow: true, want: map[string]int{"go": 3}}, // shared/util.go
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("follow_symlinks=%v", tt.follow), func(t *testing.T) {
+			followSymlinks = tt.follow
+			t.Cleanup(func() { followSymlinks = false })
+
+			counts, _, _, err := walkLanguages(repo, nil)
+			if err != nil {
+				t.Fatalf("walkLanguages: %v", err)
+			}
+			if !reflect.DeepEqual(counts, tt.want) {
+				t.Errorf("walkLanguages counts = %v, want %v (nothing outside the repo)", counts, tt.want)
+			}
+			if parallel, _, _ := walkLanguagesParallel(repo, 4, nil); !reflect.DeepEqual(parallel, tt.want) {
+				t.Errorf("walkLanguagesParallel counts = %v, want %v", parallel, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguagesFromGitHubBodyLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "normal response", body: `{"Go": 1000, "Shell": 20}`},
+		{name: "oversized response", body: `{"Go": 1000` + strings.Repeat(" ", githubLanguagesMaxBody) + `}`, wantErr: "response exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			origURL := githubAPIURL
+			githubAPIURL = server.URL
+			t.Cleanup(func() { githubAPIURL = origURL })
+			t.Setenv("GITHUB_TOKEN", "test-token")
+
+			detected, err := detectLanguagesFromGitHub("https://github.com/org/repo")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("detectLanguagesFromGitHub() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectLanguagesFromGitHub() error = %v", err)
+			}
+			if !detected.hasLanguage("go") {
+				t.Errorf("Languages = %v, want go", detected.Languages)
+			}
+		})
+	}
+}
+
+func TestHasMoreEntries(t *testing.T) {
+	root := writeLanguageFixture(t)
+
+	tests := []struct {
+		limit int
+		want  bool
+	}{
+		{10, true},
+		{200, true},
+		{100000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("limit %d", tt.limit), func(t *testing.T) {
+			if got := hasMoreEntries(root, tt.limit); got != tt.want {
+				t.Errorf("hasMoreEntries(%d) = %v, want %v", tt.limit, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+)
+
+// errorLogMarker is the prefix used by error log lines; these always pass the quiet filter.
+const errorLogMarker = "❌"
+
+// quietLogger is an io.Writer for the standard logger that drops every line
+// except errors. log.Logger issues one Write per message, so each call is
+// filtered as a whole.
+type quietLogger struct {
+	out io.Writer
+}
+
+// Write forwards p to the underlying writer only if it contains the error marker.
+// Suppressed lines still report success so the logger doesn't treat them as failures.
+func (q *quietLogger) Write(p []byte) (int, error) {
+	if !bytes.Contains(p, []byte(errorLogMarker)) {
+		return len(p), nil
+	}
+	return q.out.Write(p)
+}
+
+// setLogLevel configures the global logger. In quiet mode only error lines are
+// written; verbose takes precedence over quiet so that explicitly requesting
+// more detail is never silently ignored.
+func setLogLevel(quiet, verbose bool) {
+	if quiet && !verbose {
+		log.SetOutput(&quietLogger{out: os.Stderr})
+		return
+	}
+	log.SetOutput(os.Stderr)
+}
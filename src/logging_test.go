@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestQuietLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    bool // whether the line should be written
+	}{
+		{name: "progress line suppressed", message: "  🔎 Running gosec...", want: false},
+		{name: "success line suppressed", message: "    ✅ gosec completed in 1s", want: false},
+		{name: "warning line suppressed", message: "  ⚠️  SBOM generation failed: boom", want: false},
+		{name: "error line kept", message: "    ❌ gosec failed: exit status 2", want: true},
+		{name: "fatal error line kept", message: "❌ Failed to load config: missing file", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.New(&quietLogger{out: &buf}, "", 0)
+			logger.Print(tt.message)
+
+			written := buf.Len() > 0
+			if written != tt.want {
+				t.Errorf("quietLogger wrote %q, want written=%v", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	t.Cleanup(func() { setLogLevel(false, false) })
+
+	tests := []struct {
+		name      string
+		quiet     bool
+		verbose   bool
+		wantQuiet bool
+	}{
+		{name: "default", quiet: false, verbose: false, wantQuiet: false},
+		{name: "quiet", quiet: true, verbose: false, wantQuiet: true},
+		{name: "verbose overrides quiet", quiet: true, verbose: true, wantQuiet: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLogLevel(tt.quiet, tt.verbose)
+			_, isQuiet := log.Writer().(*quietLogger)
+			if isQuiet != tt.wantQuiet {
+				t.Errorf("setLogLevel(%v, %v): quiet writer = %v, want %v", tt.quiet, tt.verbose, isQuiet, tt.wantQuiet)
+			}
+		})
+	}
+}
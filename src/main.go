@@ -6,12 +6,19 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -84,10 +91,17 @@ func resolveFromLsRemote(url string, output []byte) RepositoryConfig {
 }
 
 // resolveRepoTarget resolves a repository URL to a RepositoryConfig by detecting
-// the latest tagged release via git ls-remote. Falls back to branch "main" if no tags exist.
+// the latest tagged release via git ls-remote. Falls back to branch "main" if no tags exist,
+// or if the remote can't be listed within lsRemoteTimeout.
 func resolveRepoTarget(url string) RepositoryConfig {
-	cmd := exec.Command("git", "ls-remote", "--tags", "--sort=-v:refname", url)
-	output, err := cmd.Output()
+	ctx, cancel := context.WithTimeout(context.Background(), lsRemoteTimeout)
+	defer cancel()
+
+	output, err := lsRemoteTags(ctx, url)
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("⚠️  Listing tags for %s timed out after %v, using branch main", url, lsRemoteTimeout)
+		return RepositoryConfig{URL: url, Branch: "main"}
+	}
 	if err != nil {
 		log.Printf("⚠️  Could not list tags for %s: %v, using branch main", url, err)
 		return RepositoryConfig{URL: url, Branch: "main"}
@@ -95,23 +109,186 @@ func resolveRepoTarget(url string) RepositoryConfig {
 	return resolveFromLsRemote(url, output)
 }
 
+// defaultLsRemoteTimeout bounds git ls-remote when ls_remote_timeout is unset
+const defaultLsRemoteTimeout = 30 * time.Second
+
+// lsRemoteTimeout bounds tag resolution in resolveRepoTarget; set from
+// global.ls_remote_timeout
+var lsRemoteTimeout = defaultLsRemoteTimeout
+
+// lsRemoteTags lists a remote's tags, newest version first. It is a variable
+// so tests can simulate slow or unreachable remotes.
+var lsRemoteTags = func(ctx context.Context, url string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--tags", "--sort=-v:refname", url)
+	setupGitAuth(cmd, url)
+	// git's transport helpers inherit stdout; don't wait on them after a kill
+	cmd.WaitDelay = time.Second
+	return cmd.Output()
+}
+
+// targetSources describes where scan targets come from: the repositories file,
+// inline YAML from --repos-yaml, and already-resolved --repo/--purl targets.
+type targetSources struct {
+	ReposPath       string
+	LoadReposFile   bool
+	ReposYAML       string
+	AdHoc           []RepositoryConfig
+	IncludeDisabled bool
+	MergeDuplicates bool // keep the first of duplicate entries instead of failing
+}
+
+// collectTargets merges targets from all sources, in order: repositories file,
+// --repos-yaml, then --repo/--purl (marked Explicit), and checks the result
+// for duplicates.
+func collectTargets(src targetSources) ([]RepositoryConfig, error) {
+	var targets []RepositoryConfig
+
+	if src.LoadReposFile {
+		repositories, err := loadRepositories(src.ReposPath, src.IncludeDisabled)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, repositories...)
+	}
+
+	if src.ReposYAML != "" {
+		repositories, err := parseRepositories([]byte(src.ReposYAML), src.IncludeDisabled)
+		if err != nil {
+			return nil, fmt.Errorf("--repos-yaml: %w", err)
+		}
+		targets = append(targets, repositories...)
+	}
+
+	for _, target := range src.AdHoc {
+		target.Explicit = true
+		targets = append(targets, target)
+	}
+	return checkDuplicateRepositories(targets, src.MergeDuplicates)
+}
+
+// limitTargets keeps the first max targets (all of them when max is 0), so
+// a large repository list can be tried out on a few entries first
+func limitTargets(targets []RepositoryConfig, max int) []RepositoryConfig {
+	if max <= 0 || len(targets) <= max {
+		return targets
+	}
+	return targets[:max]
+}
+
+// netrcCredentialHelper is an inline git credential helper that answers "get"
+// requests from environment variables, so netrc passwords never appear in args.
+const netrcCredentialHelper = `!f() { test "$1" = get && echo "username=$ALLSCAN_GIT_USERNAME" && echo "password=$ALLSCAN_GIT_PASSWORD"; }; f`
+
+// setupGitAuth configures authentication for a git command that talks to repoURL.
+// Only HTTP(S) remotes are affected (SSH uses the user's SSH agent/config).
+// Methods, in order of precedence:
+//   - GIT_CREDENTIAL_HELPER: used as git's credential.helper (replacing any configured helper)
+//   - NETRC_FILE: credentials for the repo host are read from this .netrc file
+//   - GIT_ASKPASS: inherited from the environment and used by git as-is
+//
+// Terminal prompts are always disabled so a missing credential fails fast
+// instead of hanging the run.
+func setupGitAuth(cmd *exec.Cmd, repoURL string) {
+	u, err := url.Parse(repoURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	env = append(env, "GIT_TERMINAL_PROMPT=0")
+
+	helper := os.Getenv("GIT_CREDENTIAL_HELPER")
+	if helper == "" {
+		if netrcPath := os.Getenv("NETRC_FILE"); netrcPath != "" {
+			data, err := os.ReadFile(filepath.Clean(netrcPath))
+			if err != nil {
+				log.Printf("⚠️  Could not read NETRC_FILE: %v", err)
+			} else if login, password, ok := parseNetrc(data, u.Hostname()); ok {
+				helper = netrcCredentialHelper
+				env = append(env, "ALLSCAN_GIT_USERNAME="+login, "ALLSCAN_GIT_PASSWORD="+password)
+			}
+		}
+	}
+
+	if helper != "" {
+		// Pass config through GIT_CONFIG_* so it applies to this command only.
+		// An empty credential.helper first clears helpers from git config files.
+		n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=credential.helper", n),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=", n),
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=credential.helper", n+1),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", n+1, helper),
+			fmt.Sprintf("GIT_CONFIG_COUNT=%d", n+2),
+		)
+	}
+
+	cmd.Env = env
+}
+
+// parseNetrc returns the login and password for host from .netrc data, falling
+// back to the "default" entry. ok is false when no entry matches.
+func parseNetrc(data []byte, host string) (login, password string, ok bool) {
+	fields := strings.Fields(string(data))
+	var inMatch, matched bool
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if matched {
+				return login, password, true
+			}
+			i++
+			inMatch = i < len(fields) && fields[i] == host
+			matched = inMatch
+		case "default":
+			if matched {
+				return login, password, true
+			}
+			inMatch = true
+			matched = true
+		case "login":
+			i++
+			if inMatch && i < len(fields) {
+				login = fields[i]
+			}
+		case "password":
+			i++
+			if inMatch && i < len(fields) {
+				password = fields[i]
+			}
+		case "account", "macdef":
+			i++
+		}
+	}
+	return login, password, matched
+}
+
 // checkAllRequiredEnv checks required environment variables for all enabled scanners
-// and for upload if configured. Returns a map of feature name -> missing env var name.
-func checkAllRequiredEnv(config *Config, localMode bool) map[string]string {
-	missing := make(map[string]string)
+// and for upload if configured. Returns a map of feature name -> every missing env var,
+// so all of them can be fixed in one go. A variable the scanner's env or any
+// repository's env supplies counts as set; repositories without it skip the
+// scanner at run time.
+func checkAllRequiredEnv(config *Config, localMode bool) map[string][]string {
+	missing := make(map[string][]string)
 	for _, scanner := range config.Scanners {
 		if !scanner.Enabled {
 			continue
 		}
-		for _, envVar := range scanner.RequiredEnv {
-			if os.Getenv(envVar) == "" {
-				missing[scanner.Name] = envVar
-				break // Only report first missing var per scanner
-			}
+		env := scanner.Env
+		for _, repo := range config.Repositories {
+			env = layerEnv(repo.Env, env)
+		}
+		if vars := missingRequiredEnv(scanner.RequiredEnv, env); len(vars) > 0 {
+			missing[scanner.Name] = vars
 		}
 	}
-	if !localMode && config.Global.UploadEndpoint != "" && os.Getenv("VULN_MGMT_API_TOKEN") == "" {
-		missing["DefectDojo upload"] = "VULN_MGMT_API_TOKEN"
+	if !localMode {
+		if vars := missingUploadTokens(config); len(vars) > 0 {
+			missing["DefectDojo upload"] = vars
+		}
 	}
 	return missing
 }
@@ -131,10 +308,16 @@ func promptYesNo(prompt string) bool {
 }
 
 // promptContinue asks the user if they want to continue and returns their choice.
-func promptContinue(missing map[string]string) bool {
+func promptContinue(missing map[string][]string) bool {
 	fmt.Println("\n⚠️  Missing required environment variables:")
-	for scanner, envVar := range missing {
-		fmt.Printf("   • %s%s%s%s requires %s%s%s\n", ColorBold, ColorCyan, titleCase(scanner), ColorReset, ColorYellow, envVar, ColorReset)
+	features := make([]string, 0, len(missing))
+	for feature := range missing {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		envVars := strings.Join(missing[feature], ", ")
+		fmt.Printf("   • %s%s%s%s requires %s%s%s\n", ColorBold, ColorCyan, titleCase(feature), ColorReset, ColorYellow, envVars, ColorReset)
 	}
 	return promptYesNo("\nContinue anyway? [y/N]: ")
 }
@@ -166,12 +349,34 @@ func isValidCachedRepo(repoPath, expectedURL string) bool {
 		return false
 	}
 
-	// Normalize URLs for comparison (trim whitespace, handle .git suffix)
-	actualURL := strings.TrimSpace(string(output))
-	actualURL = strings.TrimSuffix(actualURL, ".git")
-	expectedNormalized := strings.TrimSuffix(expectedURL, ".git")
+	// Normalize URLs for comparison, so an SSH clone matches an HTTPS URL
+	return normalizeGitURL(string(output)) == normalizeGitURL(expectedURL)
+}
+
+// Git remote URL forms understood by normalizeGitURL. Each captures the host
+// and the repository path.
+var (
+	// ssh://[user@]host[:port]/owner/repo (also git+ssh://)
+	sshGitURLPattern = regexp.MustCompile(`^(?:git\+)?ssh://(?:[^@/]+@)?([^/:]+)(?::\d+)?/(.+)$`)
+	// http(s)://[user[:token]@]host[:port]/owner/repo
+	httpGitURLPattern = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/:]+)(?::\d+)?/(.+)$`)
+	// scp-like [user@]host:owner/repo, as in git@github.com:org/repo.git
+	scpGitURLPattern = regexp.MustCompile(`^(?:[^@/]+@)?([\w.-]+):/?([^/].*)$`)
+)
 
-	return actualURL == expectedNormalized
+// normalizeGitURL converts a git remote URL to a canonical
+// https://host/owner/repo form for comparison: SSH and scp-like remotes
+// become HTTPS, credentials, ports and the .git suffix are dropped, and the
+// host is lowercased. URLs in other forms are only trimmed.
+func normalizeGitURL(rawURL string) string {
+	normalized := strings.TrimSuffix(strings.TrimSpace(rawURL), "/")
+	normalized = strings.TrimSuffix(normalized, ".git")
+	for _, pattern := range []*regexp.Regexp{sshGitURLPattern, httpGitURLPattern, scpGitURLPattern} {
+		if m := pattern.FindStringSubmatch(normalized); m != nil {
+			return "https://" + strings.ToLower(m[1]) + "/" + m[2]
+		}
+	}
+	return normalized
 }
 
 // getCommitHash returns the short commit hash of HEAD for a repository
@@ -205,12 +410,18 @@ func validateVersionCommit(repoPath, version, expectedCommit string) {
 	}
 }
 
+// workspaceRepoName returns the "owner/repo" directory a repository is
+// cloned into under the workspace
+func workspaceRepoName(repoURL string) string {
+	parts := strings.Split(repoURL, "/")
+	return parts[len(parts)-2] + "/" + strings.TrimSuffix(parts[len(parts)-1], ".git")
+}
+
 // cloneRepository performs a shallow clone of the target repository, or updates an existing cached clone
 // Returns: repoPath, commitHash (short), branchTag (branch or tag name), error
 func cloneRepository(config *Config, repo RepositoryConfig) (repoPath, commitHash, branchTag string, err error) {
 	// Extract repo name from URL
-	parts := strings.Split(repo.URL, "/")
-	repoName := parts[len(parts)-2] + "/" + strings.TrimSuffix(parts[len(parts)-1], ".git")
+	repoName := workspaceRepoName(repo.URL)
 
 	repoPath = filepath.Join(config.Global.Workspace, repoName)
 
@@ -239,7 +450,8 @@ func cloneRepository(config *Config, repo RepositoryConfig) (repoPath, commitHas
 		}
 
 		log.Printf("  📥 Cloning %s (tag: %s)...", repoName, repo.Version)
-		cmd := exec.Command("git", "clone", "--depth=1", "--branch", repo.Version, repo.URL, repoPath)
+		cmd := exec.Command("git", gitCloneArgs(repo.Version, repo.URL, repoPath)...)
+		setupGitAuth(cmd, repo.URL)
 		if output, err := cmd.CombinedOutput(); err != nil {
 			return "", "", "", fmt.Errorf("git clone failed: %w\n%s", err, output)
 		}
@@ -285,8 +497,9 @@ func cloneRepository(config *Config, repo RepositoryConfig) (repoPath, commitHas
 		}
 
 		// Fetch the specific commit
-		fetchCmd := exec.Command("git", "fetch", "--depth=1", "origin", repo.Commit)
+		fetchCmd := exec.Command("git", gitFetchArgs(repo.Commit)...)
 		fetchCmd.Dir = repoPath
+		setupGitAuth(fetchCmd, repo.URL)
 		if output, err := fetchCmd.CombinedOutput(); err != nil {
 			return "", "", "", fmt.Errorf("git fetch failed: %w\n%s", err, output)
 		}
@@ -313,8 +526,9 @@ func cloneRepository(config *Config, repo RepositoryConfig) (repoPath, commitHas
 		log.Printf("  📦 Updating cached repo: %s (branch: %s)...", repoName, ref)
 
 		// Fetch latest changes
-		fetchCmd := exec.Command("git", "fetch", "origin", ref, "--depth=1")
+		fetchCmd := exec.Command("git", gitFetchArgs(ref)...)
 		fetchCmd.Dir = repoPath
+		setupGitAuth(fetchCmd, repo.URL)
 		if _, err := fetchCmd.CombinedOutput(); err != nil {
 			log.Printf("    ⚠️  Fetch failed, will re-clone: %v", err)
 			// Fall through to fresh clone
@@ -343,7 +557,8 @@ func cloneRepository(config *Config, repo RepositoryConfig) (repoPath, commitHas
 
 	// Fresh clone
 	log.Printf("  📥 Cloning %s (branch: %s)...", repoName, ref)
-	cmd := exec.Command("git", "clone", "--depth=1", "--branch", ref, repo.URL, repoPath)
+	cmd := exec.Command("git", gitCloneArgs(ref, repo.URL, repoPath)...)
+	setupGitAuth(cmd, repo.URL)
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return "", "", "", fmt.Errorf("git clone failed: %w\n%s", err, output)
 	}
@@ -357,70 +572,195 @@ func cloneRepository(config *Config, repo RepositoryConfig) (repoPath, commitHas
 	return repoPath, commitHash, branchTag, nil
 }
 
+// gitCloneArgs builds the arguments of a shallow "git clone" of a branch or tag
+func gitCloneArgs(ref, repoURL, repoPath string) []string {
+	return []string{"clone", "--depth=1", "--branch", ref, repoURL, repoPath}
+}
+
+// gitFetchArgs builds the arguments of a shallow "git fetch" of a ref or
+// commit from origin
+func gitFetchArgs(ref string) []string {
+	return []string{"fetch", "--depth=1", "origin", ref}
+}
+
+// submoduleTimeoutFactor scales SBOM and scanner timeouts for repos scanned with
+// submodules, which can be considerably larger than the superproject alone
+const submoduleTimeoutFactor = 2
+
+// submoduleUpdateCommand builds the git command that checks out all submodules
+// (recursively, shallow) in a cloned repository.
+func submoduleUpdateCommand(repoPath, repoURL string) *exec.Cmd {
+	cmd := exec.Command("git", "submodule", "update", "--init", "--recursive", "--depth=1")
+	cmd.Dir = repoPath
+	setupGitAuth(cmd, repoURL)
+	return cmd
+}
+
+// updateSubmodules checks out the submodules of a cloned repository
+func updateSubmodules(repoPath, repoURL string) error {
+	log.Printf("  📥 Updating submodules...")
+	if output, err := submoduleUpdateCommand(repoPath, repoURL).CombinedOutput(); err != nil {
+		return fmt.Errorf("git submodule update failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
 // runScans clones/updates repositories and runs scanners against them
 func runScans(config *Config) []RepoScanContext {
-	var contexts []RepoScanContext
+	workers := repoScanWorkers(config.Global.ParallelRepos, config.Global.MaxConcurrent, len(config.Repositories))
+	if workers > 1 {
+		log.Printf("🚀 Scanning up to %d repositories in parallel", workers)
+	}
+	progress.start(len(config.Repositories))
+	defer progress.finish()
+	return scanReposConcurrently(config.Repositories, workers, config.Global.FailFast, func(repo RepositoryConfig) []RepoScanContext {
+		defer progress.repoDone()
+		events.emit(Event{Event: eventRepoStart, Repository: repo.URL})
+		contexts := scanRepository(config, repo)
+		if events != nil {
+			events.emit(repoDoneEvent(repo, contexts))
+		}
+		return contexts
+	})
+}
 
-	for _, repo := range config.Repositories {
-		log.Printf("\n📦 Processing repository: %s", repo.URL)
+// scanRepository validates, clones, and scans a single repository, returning
+// one context per scanned target (several for monorepo sub-projects). Invalid
+// or unclonable repos are logged and yield no contexts.
+func scanRepository(config *Config, repo RepositoryConfig) []RepoScanContext {
+	log.Printf("\n📦 Processing repository: %s", repo.URL)
 
-		// Validate repository config
-		if err := ValidateRepositoryConfig(repo); err != nil {
-			log.Printf("❌ Invalid repository config for %s: %v", repo.URL, err)
-			continue
-		}
+	// Validate repository config
+	if err := ValidateRepositoryConfig(repo); err != nil {
+		log.Printf("❌ Invalid repository config for %s: %v", repo.URL, err)
+		return nil
+	}
+	if err := validateRepoBundle(config.Global, repo); err != nil {
+		log.Printf("❌ Invalid repository config for %s: %v", repo.URL, err)
+		return nil
+	}
 
-		// Clone or update repository
-		repoPath, commitHash, branchTag, err := cloneRepository(config, repo)
-		if err != nil {
-			log.Printf("❌ Failed to clone %s: %v", repo.URL, err)
-			continue
-		}
+	// Entries sharing a clone directory (same repo at different refs) must
+	// not be checked out concurrently
+	unlock := lockWorkspace(workspaceRepoName(repo.URL))
+	defer unlock()
 
-		// Extract repo name for SBOM filename
-		parts := strings.Split(repo.URL, "/")
-		repoName := strings.TrimSuffix(parts[len(parts)-1], ".git")
+	// Clone or update repository
+	repoPath, commitHash, branchTag, err := cloneRepository(config, repo)
+	events.emit(cloneDoneEvent(repo, commitHash, branchTag, err))
+	if err != nil {
+		log.Printf("❌ Failed to clone %s: %v", repo.URL, err)
+		return []RepoScanContext{{RepoURL: repo.URL, CloneError: err}}
+	}
 
-		// Use the original pURL version in the SBOM filename when available,
-		// so that the user-provided version appears rather than the git tag name
-		sbomVersion := branchTag
-		if repo.PURLVersion != "" {
-			sbomVersion = repo.PURLVersion
+	// Check out submodules so scanners see vendored submodule code
+	if repo.Submodules {
+		if err := updateSubmodules(repoPath, repo.URL); err != nil {
+			log.Printf("  ⚠️  Submodule checkout failed, scanning without submodules: %v", err)
 		}
+	}
 
-		// Generate SBOM (reused by grype via {{sbom}} template)
-		sbomPath, sbomErr := generateSBOM(config.Global.ResultsDir, repoPath, repoName, commitHash, sbomVersion)
-		if sbomErr != nil {
-			log.Printf("  ⚠️  SBOM generation failed: %v", sbomErr)
+	// Scope the scan to the repo's path, which must exist in this checkout;
+	// without it the repo is reported like one that couldn't be cloned
+	var contexts []RepoScanContext
+	if err := validateScopePath(repoPath, repo); err != nil {
+		log.Printf("❌ Invalid path for %s: %v", repo.URL, err)
+		contexts = []RepoScanContext{{RepoURL: repo.URL, CloneError: err}}
+	} else {
+		// Generate SBOMs and run scanners (per sub-project when enabled)
+		contexts = scanRepoTargets(config, repo, repoPath, commitHash, branchTag)
+	}
+
+	// Scanner outputs live in the results dir, so the clone is no longer needed
+	if config.Global.WorkspaceCleanupAfterScan {
+		if err := removeClone(config.Global.Workspace, repo.URL); err != nil {
+			log.Printf("  ⚠️  Failed to remove clone %s: %v", repoPath, err)
 		}
+	}
+	return contexts
+}
 
-		// Run scanners on this repo
-		ctx := runScannersOnRepo(config, repo, repoPath, commitHash, branchTag, sbomPath)
-		contexts = append(contexts, ctx)
+// removeClone deletes a repository's clone from the workspace, along with its
+// owner directory once no other clones remain in it
+func removeClone(workspace, repoURL string) error {
+	repoPath := filepath.Join(workspace, workspaceRepoName(repoURL))
+	if err := os.RemoveAll(repoPath); err != nil {
+		return err
+	}
+	os.Remove(filepath.Dir(repoPath)) // fails harmlessly while the owner dir isn't empty
+	return nil
+}
 
-		// Check for fail-fast across all results
-		for _, result := range ctx.Results {
-			if !result.Success && config.Global.FailFast {
-				return contexts
-			}
+// cleanupWorkspace deletes the clones of every repository in this run.
+// Clones of repositories not in the run are left in place.
+func cleanupWorkspace(config *Config) {
+	removed := 0
+	for _, repo := range config.Repositories {
+		repoPath := filepath.Join(config.Global.Workspace, workspaceRepoName(repo.URL))
+		if _, err := os.Stat(repoPath); err != nil {
+			continue
+		}
+		if err := removeClone(config.Global.Workspace, repo.URL); err != nil {
+			log.Printf("⚠️  Failed to remove clone %s: %v", repoPath, err)
+			continue
 		}
+		removed++
+	}
+	if removed > 0 {
+		log.Printf("🧹 Removed %d clone(s) from %s", removed, config.Global.Workspace)
 	}
+}
 
-	return contexts
+// configFiles collects repeated --config flags in the order given
+type configFiles []string
+
+func (c *configFiles) String() string { return strings.Join(*c, ", ") }
+
+func (c *configFiles) Set(path string) error {
+	*c = append(*c, path)
+	return nil
 }
 
 func main() {
 	// Parse command line flags
-	configPath := flag.String("config", "scanners.yaml", "Path to config file")
+	var configPaths configFiles
+	flag.Var(&configPaths, "config", "Path to config file; repeat to merge overlays in order, later files win (default: scanners.yaml)")
 	reposPath := flag.String("repos", "repositories.yaml", "Path to repositories config file")
 	preflight := flag.Bool("preflight", false, "Validate configuration and check environment without running scans")
 	local := flag.Bool("local", false, "Scan current directory instead of cloning repos (skips upload)")
 	repo := flag.String("repo", "", "Scan a single repository by URL (uses latest tagged release if available)")
+	reposYAML := flag.String("repos-yaml", "", "Inline repositories YAML (same format as repositories.yaml); merged with --repo/--purl")
 	purlFlag := flag.String("purl", "", "Scan a package by its Package URL (pURL), e.g. pkg:github/owner/repo@v1.0.0")
 	product := flag.String("product", "", "Product name for DefectDojo uploads (overrides auto-detected name)")
 	productType := flag.String("product-type", "", "Product type name for DefectDojo uploads (e.g. \"Research and Development\")")
 	scan := flag.String("scan", "", "Run only the specified scanner(s), comma-separated by name (e.g., --scan=trufflehog,gosec)")
 	sarif := flag.Bool("sarif", false, "Output scan results in SARIF format (for scanners that support it)")
+	width := flag.Int("width", 0, "Terminal width for the summary (default $COLUMNS); a coverage matrix wider than this is printed as one line per language")
+	themeName := flag.String("theme", "emoji", "Summary symbol theme: emoji or plain (ASCII only)")
+	includeDisabled := flag.Bool("include-disabled", false, "Include repositories marked disabled: true in repositories.yaml")
+	includeArchived := flag.Bool("include-archived", false, "Scan repositories that are archived on GitHub (skipped by default when GITHUB_TOKEN is set)")
+	skipForks := flag.Bool("skip-forks", false, "Skip repositories that are forks on GitHub (needs GITHUB_TOKEN; same as global.skip_forks)")
+	quiet := flag.Bool("quiet", false, "Suppress progress output; only errors and the final summary are shown")
+	noProgress := flag.Bool("no-progress", false, "Don't show the live \"repo 3/20, scanner 2/5\" status line (it is only shown when stderr is a terminal)")
+	output := flag.String("output", "", "Write a JSON run report with per-scanner finding counts to this path")
+	outputFormat := flag.String("output-format", outputFormatJSON, "Format of the run report: json (native, readable by --previous-run) or kics (KICS results.json layout)")
+	strict := flag.Bool("strict", false, "Exit with an error when the post-run hook fails")
+	parallelRepos := flag.Bool("parallel-repos", false, "Scan repositories concurrently (up to max_concurrent at a time) instead of one by one")
+	explain := flag.Bool("explain", false, "Log why each scanner was selected or skipped for every repo (language, enabled, repo list/bundle, env)")
+	cleanupAfterRun := flag.Bool("cleanup-workspace-after-run", false, "Delete this run's repository clones from the workspace once the run completes")
+	topFindingsFlag := flag.Int("top-findings", 0, "List up to N Critical/High findings per repository in the summary (same as global.top_findings)")
+	requireCov := flag.Bool("require-coverage", false, "Fail the run when no scanner ran on a target (no compatible scanners, or all skipped for missing env vars)")
+	eventsPath := flag.String("events-jsonl", "", "Stream one JSON event per line (repo-start, clone-done, scanner-start, scanner-done, repo-done) to this path as the run progresses, or - for stdout")
+	listRepos := flag.Bool("list-repos", false, "List the repositories a run would scan (URL, ref, scanner counts) without cloning or scanning, then exit")
+	listScanners := flag.Bool("list-scanners", false, "List the scanners allscan can parse results for, with their type and supported languages, then exit")
+	strictConfig := flag.Bool("strict-config", false, "Fail on unknown keys in the config files instead of warning about them (same as global.strict_config)")
+	maxRepos := flag.Int("max-repos", 0, "Scan only the first N repositories after loading (0 = all); useful for trying out a large repositories.yaml")
+	includeArchivedResults := flag.Bool("include-archived-results", false, "With --parse-only, also read results that archive_results moved into the directory's archive/ subdirectory (plain or tar.gz)")
+	parseOnly := flag.String("parse-only", "", "Parse existing scanner result files in this directory and print the summary and run report, without scanning or uploading")
+	only := flag.String("only", "", "Upload and write the run report for only the success or failed scanner results (the summary still shows all)")
+	previousRun := flag.String("previous-run", "", "JSON run report from an earlier --output; shows critical-finding trends in the summary (with --list-repos, its scanner durations drive the run estimate)")
+	clean := flag.Bool("clean", false, "Delete the workspace clones and scan results/SBOMs older than 7 days, print the space freed, then exit")
+	cleanAll := flag.Bool("all", false, "With --clean, delete all scan results and SBOMs regardless of age")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: allscan [options]\n\nOptions:\n")
 		flag.VisitAll(func(f *flag.Flag) {
@@ -430,10 +770,54 @@ func main() {
 				fmt.Fprintf(os.Stderr, "  --%-12s %s\n", f.Name, f.Usage)
 			}
 		})
+		fmt.Fprintf(os.Stderr, "\nGit authentication (HTTPS remotes, in order of precedence):\n")
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", "GIT_CREDENTIAL_HELPER", "Credential helper used for clone/fetch (e.g. \"store\", \"/usr/local/bin/my-helper\")")
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", "NETRC_FILE", "Path to a .netrc file with machine/login/password entries")
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", "GIT_ASKPASS", "Askpass program, passed through to git unchanged")
+		fmt.Fprintf(os.Stderr, "\nScanners:\n")
+		for _, name := range parsers.Names() {
+			fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, parsers.Describe(name))
+		}
 		fmt.Fprintf(os.Stderr, "\n")
 	}
 	flag.Parse()
 
+	setLogLevel(*quiet, false)
+	enableProgress(*quiet, *noProgress)
+
+	selectedTheme, err := parsers.ThemeByName(*themeName)
+	if err != nil {
+		log.Fatalf("❌ Invalid --theme: %v", err)
+	}
+	theme = selectedTheme
+
+	if *width < 0 {
+		log.Fatalf("❌ Invalid --width %d (must be 0 or more)", *width)
+	}
+	summaryWidth = terminalWidth(*width, os.Getenv("COLUMNS"))
+
+	if *listScanners {
+		printScannerList()
+		return
+	}
+
+	if *outputFormat != outputFormatJSON && *outputFormat != outputFormatKICS {
+		log.Fatalf("❌ Invalid --output-format %q (want %s or %s)", *outputFormat, outputFormatJSON, outputFormatKICS)
+	}
+
+	// Load the previous run report for trend arrows (skipped if missing)
+	if *previousRun != "" {
+		summaries, err := loadPreviousRunSummaries(*previousRun)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Printf("⏭️  Previous run report %s not found, skipping trends", *previousRun)
+		case err != nil:
+			log.Printf("⚠️  Ignoring previous run report: %v", err)
+		default:
+			previousSummaries = summaries
+		}
+	}
+
 	// Parse --scan into a list of scanner names
 	var scanFilter []string
 	if *scan != "" {
@@ -445,25 +829,57 @@ func main() {
 		}
 	}
 
+	if *maxRepos < 0 {
+		log.Fatalf("❌ Invalid --max-repos %d (must be 0 or more)", *maxRepos)
+	}
+	if err := validateOnlyFilter(*only); err != nil {
+		log.Fatalf("❌ Invalid --only: %v", err)
+	}
+
 	// --local is incompatible with --repo and --purl
 	if *local && (*repo != "" || *purlFlag != "") {
-		log.Fatalf("Flag --local cannot be combined with --repo or --purl")
+		log.Fatalf("❌ Flag --local cannot be combined with --repo or --purl")
+	}
+	if *parseOnly != "" && (*local || *preflight || *repo != "" || *purlFlag != "") {
+		log.Fatalf("❌ Flag --parse-only cannot be combined with --local, --preflight, --repo or --purl")
+	}
+	if *includeArchivedResults && *parseOnly == "" {
+		log.Fatalf("❌ Flag --include-archived-results requires --parse-only")
 	}
 
 	// Load configuration
-	config, err := loadConfig(*configPath)
+	if len(configPaths) == 0 {
+		configPaths = configFiles{"scanners.yaml"}
+	}
+	load := loadConfig
+	if *strictConfig {
+		load = loadConfigStrict
+	}
+	config, err := load(configPaths...)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		log.Fatalf("❌ Failed to load config: %v", err)
 	}
 
 	// Store CLI-only overrides in config
 	config.Global.ProductOverride = *product
 	config.Global.ProductTypeOverride = *productType
 	config.Global.SarifMode = *sarif
+	coverageScanTypes = config.Global.CoverageScanTypes
+	severityLabels = withSeverityLabels(config.Global.SeverityLabels)
+	theme = theme.WithSeverityIcons(config.Global.SeverityIcons)
 
 	// Parse timeouts
 	if err := parseTimeouts(config); err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+	lsRemoteTimeout = config.Global.lsRemoteTimeout
+	if dir := config.Global.OfflineDBDir; dir != "" {
+		// Scanners and syft run in the repo dir, so relative paths won't do
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			log.Fatalf("❌ Invalid offline_db_dir: %v", err)
+		}
+		offlineDBDir = abs
 	}
 
 	// Validate --scan filter against configured scanner names
@@ -483,13 +899,74 @@ func main() {
 			for _, s := range config.Scanners {
 				names = append(names, s.Name)
 			}
-			log.Fatalf("Unknown scanner(s): %s\nAvailable scanners: %s",
+			log.Fatalf("❌ Unknown scanner(s): %s\nAvailable scanners: %s",
 				strings.Join(invalid, ", "), strings.Join(names, ", "))
 		}
 	}
 
 	// Store scan filter in config for use by scanner functions
 	config.Global.ScanFilter = scanFilter
+	config.Global.IncludeDisabled = *includeDisabled
+	config.Global.IncludeArchived = *includeArchived
+	config.Global.SkipForks = config.Global.SkipForks || *skipForks
+	config.Global.OutputPath = *output
+	config.Global.OutputFormat = *outputFormat
+	config.Global.Strict = *strict
+	config.Global.Explain = *explain
+	config.Global.OnlyResults = *only
+	config.Global.ParallelRepos = *parallelRepos
+	config.Global.CleanupWorkspaceAfterRun = *cleanupAfterRun
+	config.Global.RequireCoverage = config.Global.RequireCoverage || *requireCov
+	requireCoverage = config.Global.RequireCoverage
+	if *topFindingsFlag > 0 {
+		config.Global.TopFindings = *topFindingsFlag
+	}
+	topFindingsLimit = config.Global.TopFindings
+	languageDetectionWorkers = config.Global.LanguageDetectionWorkers
+	followSymlinks = config.Global.FollowSymlinks
+	setDetectionIgnore(config.Global.DetectionIgnore)
+	if len(config.Global.ScorecardExcludeChecks) > 0 {
+		parsers.RegisterOrReplace("scorecard", &parsers.ScorecardParser{ExcludedChecks: config.Global.ScorecardExcludeChecks})
+	}
+	if config.Global.GrypeExcludeDevDeps {
+		parsers.RegisterOrReplace("grype", &parsers.GrypeParser{ExcludeDevDeps: true})
+	}
+	app, err := newGitHubAppAuth(config.Global.GitHubApp)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	githubApp = app
+
+	// Open the live event stream (not for --preflight, which scans nothing)
+	if *eventsPath != "" && !*preflight {
+		stream, err := openEventStream(*eventsPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to open --events-jsonl: %v", err)
+		}
+		events = stream
+		defer events.close()
+		// Keep stdout for the events alone: the summary and other human
+		// output go to stderr, with the log
+		if *eventsPath == "-" {
+			os.Stdout = os.Stderr
+		}
+	}
+
+	// Maintenance: reclaim disk space without scanning
+	if *clean {
+		runClean(config, *cleanAll)
+		return
+	} else if *cleanAll {
+		log.Fatalf("❌ --all only applies to --clean")
+	}
+
+	// Parse-only mode: summarize results from an earlier run
+	if *parseOnly != "" {
+		if err := runParseOnly(config, *parseOnly, *includeArchivedResults); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
 
 	// Local mode: scan current directory
 	if *local {
@@ -499,48 +976,86 @@ func main() {
 		}
 		if missing := checkAllRequiredEnv(config, true); len(missing) > 0 {
 			if !promptContinue(missing) {
-				log.Fatalf("Aborted: missing required environment variables")
+				log.Fatalf("❌ Aborted: missing required environment variables")
 			}
 		}
 		runLocalMode(config)
 		return
 	}
 
-	// Accumulate targets from all sources: repositories.yaml, --repo, --purl
-	var targets []RepositoryConfig
-
-	// Load from repositories.yaml unless --repo or --purl were provided (to avoid
-	// scanning the default file's entries when the user only wants specific targets)
-	if *repo == "" && *purlFlag == "" {
-		repositories, err := loadRepositories(*reposPath)
-		if err != nil {
-			log.Fatalf("Failed to load repositories: %v", err)
-		}
-		targets = append(targets, repositories...)
-	}
+	// Accumulate ad-hoc targets from --repo and --purl
+	var adHoc []RepositoryConfig
 
 	// Resolve --repo flag
 	if *repo != "" {
 		target := resolveRepoTarget(*repo)
-		targets = append(targets, target)
+		adHoc = append(adHoc, target)
 	}
 
 	// Resolve --purl flag
 	if *purlFlag != "" {
 		target, err := resolvePURLToTarget(*purlFlag)
 		if err != nil {
-			log.Fatalf("%v", err)
+			log.Fatalf("❌ %v", err)
 		}
 		if target != nil {
-			targets = append(targets, *target)
+			adHoc = append(adHoc, *target)
+		}
+	}
+
+	// Load the repositories file unless other targets were given (to avoid scanning
+	// the default file's entries when the user only wants specific targets).
+	// An explicit --repos always loads, merged with the other sources.
+	reposFlagSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "repos" {
+			reposFlagSet = true
 		}
+	})
+	sources := targetSources{
+		ReposPath:       *reposPath,
+		LoadReposFile:   reposFlagSet || (*repo == "" && *purlFlag == "" && *reposYAML == ""),
+		ReposYAML:       *reposYAML,
+		AdHoc:           adHoc,
+		IncludeDisabled: *includeDisabled || *preflight || *listRepos, // preflight and --list-repos list disabled repos too (marked as skipped)
+		MergeDuplicates: config.Global.MergeDuplicateRepos,
+	}
+	targets, err := collectTargets(sources)
+	if err != nil {
+		log.Fatalf("❌ Failed to load repositories: %v", err)
 	}
 
-	// Resolve any pURL entries from repositories.yaml
+	// Resolve any pURL entries from repositories.yaml / --repos-yaml
 	targets = resolvePURLEntries(targets)
 
+	// Expand all_tags/all_branches entries into one target per ref
+	targets = expandAllRefs(targets)
+
+	// Drop archived (and optionally forked) GitHub repos before --max-repos
+	// picks from the list (preflight and --list-repos list every configured repo)
+	if !*preflight && !*listRepos {
+		targets = skipArchivedRepos(targets, config.Global)
+	}
+
+	if limited := limitTargets(targets, *maxRepos); len(limited) < len(targets) {
+		log.Printf("⏭️  --max-repos: selected the first %d of %d repositories", len(limited), len(targets))
+		targets = limited
+	}
+
 	config.Repositories = targets
 
+	if *listRepos {
+		// Scanner durations recorded in --previous-run drive the estimate
+		var durations map[string]time.Duration
+		if *previousRun != "" {
+			if durations, err = loadScannerDurations(*previousRun); err != nil {
+				log.Printf("⚠️  No scanner durations for the estimate: %v", err)
+			}
+		}
+		listRepositories(config.Repositories, config, durations, os.Stdout)
+		return
+	}
+
 	if *preflight {
 		runPreflight(config, false)
 		return
@@ -548,12 +1063,12 @@ func main() {
 
 	if missing := checkAllRequiredEnv(config, false); len(missing) > 0 {
 		if !promptContinue(missing) {
-			log.Fatalf("Aborted: missing required environment variables")
+			log.Fatalf("❌ Aborted: missing required environment variables")
 		}
 	}
 
 	log.Printf("🔍 Vulnerability Scanner Orchestrator")
-	log.Printf("Config: %s", *configPath)
+	log.Printf("Config: %s", configPaths)
 	if len(scanFilter) > 0 {
 		log.Printf("Selected scanners: %s", strings.Join(scanFilter, ", "))
 	} else {
@@ -563,47 +1078,51 @@ func main() {
 
 	// Create workspace and results dirs
 	if err := setupDirectories(config); err != nil {
-		log.Fatalf("Failed to setup directories: %v", err)
+		log.Fatalf("❌ Failed to setup directories: %v", err)
 	}
 
 	// Cleanup old scan results
-	cleanupOldResults(config.Global.ResultsDir)
+	cleanupOldResults(config.Global.ResultsDir, config.Global.ArchiveResults)
+
+	// Update the grype DB once, before scanners run concurrently (if configured)
+	prefetchGrypeDB(config)
 
 	// Run scans
 	contexts := runScans(config)
 
-	// Print summary
+	// Annotate SCA findings with their age, then print summary
+	trackFindingAges(config, contexts)
 	printSummary(contexts)
 
 	// Upload results (if configured)
-	if config.Global.UploadEndpoint != "" {
+	if len(uploadTargets(config.Global)) > 0 {
 		var results []ScanResult
-		// Build a combined reachability index from all govulncheck outputs
+		// Build a combined reachability index from all govulncheck and
+		// osv-scanner call-analysis outputs
 		var reachIdx parsers.ReachabilityIndex
 		for _, ctx := range contexts {
-			results = append(results, ctx.Results...)
-			if idx := buildReachabilityIndexFromResults(ctx.Results); idx != nil {
-				if reachIdx == nil {
-					reachIdx = idx
-				} else {
-					for k, v := range idx {
-						if existing, ok := reachIdx[k]; ok && existing {
-							continue // don't downgrade reachable
-						}
-						reachIdx[k] = v
-					}
-				}
-			}
+			results = append(results, filterResults(ctx.Results, config.Global.OnlyResults)...)
+			reachIdx = reachIdx.Merge(buildReachabilityIndexFromResults(ctx.Results))
 		}
 		uploadResults(config, results, reachIdx)
 	}
+
+	// Remove clones now that scans and uploads are done (if requested)
+	if config.Global.CleanupWorkspaceAfterRun {
+		cleanupWorkspace(config)
+	}
+
+	// Save the run report and run the post-run hook (if configured)
+	if err := finishRun(config, contexts); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
 }
 
 // runLocalMode scans the current directory without cloning or uploading
 func runLocalMode(config *Config) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		log.Fatalf("Failed to get current directory: %v", err)
+		log.Fatalf("❌ Failed to get current directory: %v", err)
 	}
 
 	// Get directory name for display
@@ -619,11 +1138,11 @@ func runLocalMode(config *Config) {
 
 	// Create results directory
 	if err := setupDirectories(config); err != nil {
-		log.Fatalf("Failed to setup directories: %v", err)
+		log.Fatalf("❌ Failed to setup directories: %v", err)
 	}
 
 	// Cleanup old scan results
-	cleanupOldResults(config.Global.ResultsDir)
+	cleanupOldResults(config.Global.ResultsDir, config.Global.ArchiveResults)
 
 	// Get commit hash for SBOM filename (if in a git repo)
 	commitHash, _ := getCommitHash(cwd)
@@ -631,8 +1150,10 @@ func runLocalMode(config *Config) {
 		commitHash = "unknown"
 	}
 
+	prefetchGrypeDB(config)
+
 	// Generate SBOM (reused by grype via {{sbom}} template)
-	sbomPath, sbomErr := generateSBOM(config.Global.ResultsDir, cwd, dirName, commitHash, "local")
+	sbomPath, sbomErr := generateSBOM(config.Global.ResultsDir, cwd, dirName, commitHash, "local", config.Global.SBOMDeterministicNames, sbomTimeout)
 	if sbomErr != nil {
 		log.Printf("  ⚠️  SBOM generation failed: %v", sbomErr)
 	}
@@ -646,10 +1167,21 @@ func runLocalMode(config *Config) {
 	log.Printf("\n📂 Scanning local directory: %s", cwd)
 
 	// Run scans on current directory
-	ctx := runScannersOnRepo(config, localRepo, cwd, "", "", sbomPath)
+	events.emit(Event{Event: eventRepoStart, Repository: localRepo.URL})
+	ctx := runScannersOnRepo(config, localRepo, cwd, commitHash, "", sbomPath)
+	ctx.SBOMError = sbomErr
+	if events != nil {
+		events.emit(repoDoneEvent(localRepo, []RepoScanContext{ctx}))
+	}
+	progress.finish()
 
-	// Print summary
-	printSummary([]RepoScanContext{ctx})
+	// Annotate SCA findings with their age, then print summary
+	contexts := []RepoScanContext{ctx}
+	trackFindingAges(config, contexts)
+	printSummary(contexts)
+	if err := finishRun(config, contexts); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
 
 	// Note: No upload in local mode
 	log.Printf("📝 Local mode: results saved to %s (upload skipped)", config.Global.ResultsDir)
@@ -671,16 +1203,37 @@ func runPreflight(config *Config, localMode bool) {
 	if config.Global.SarifMode {
 		fmt.Printf("  %-18s enabled\n", "SARIF Mode:")
 	}
-	if config.Global.UploadEndpoint != "" {
-		if os.Getenv("VULN_MGMT_API_TOKEN") != "" {
-			fmt.Printf("  %-18s %s %s(token: SET)%s\n", "Upload:", config.Global.UploadEndpoint, ColorGreen, ColorReset)
-		} else {
-			fmt.Printf("  %-18s %s %s(token: NOT SET)%s\n", "Upload:", config.Global.UploadEndpoint, ColorYellow, ColorReset)
-			issues++
+	if targets := uploadTargets(config.Global); len(targets) > 0 {
+		for _, target := range targets {
+			token := "token"
+			if target.EnvTokenVar != "" {
+				token = target.EnvTokenVar
+			}
+			var repos string
+			if len(target.RepoFilter) > 0 {
+				repos = " [" + strings.Join(target.RepoFilter, ", ") + "]"
+			}
+			if os.Getenv(target.tokenEnvVar()) != "" {
+				fmt.Printf("  %-18s %s%s %s(%s: SET)%s\n", "Upload:", target.Endpoint, repos, ColorGreen, token, ColorReset)
+			} else {
+				fmt.Printf("  %-18s %s%s %s(%s: NOT SET)%s\n", "Upload:", target.Endpoint, repos, ColorYellow, token, ColorReset)
+				issues++
+			}
+		}
+		if config.Global.Dojo.Reimport {
+			fmt.Printf("  %-18s enabled\n", "Reimport:")
 		}
 	} else {
 		fmt.Printf("  %-18s (not configured)\n", "Upload:")
 	}
+	if offlineDBDir != "" {
+		if info, err := os.Stat(offlineDBDir); err == nil && info.IsDir() {
+			fmt.Printf("  %-18s %s\n", "Offline DB:", offlineDBDir)
+		} else {
+			fmt.Printf("  %-18s %s %s(NOT FOUND)%s\n", "Offline DB:", offlineDBDir, ColorYellow, ColorReset)
+			issues++
+		}
+	}
 
 	// Binary / environment checks
 	fmt.Printf("\n%sEnvironment:%s\n", ColorBold, ColorReset)
@@ -756,8 +1309,16 @@ func runPreflight(config *Config, localMode bool) {
 				statusStr = ColorDim + "⛔ OFF" + ColorReset
 			}
 		} else {
-			binaryPath, err := exec.LookPath(scanner.Command)
+			// Image scanners need the container runtime rather than the tool
+			program := scanner.Command
+			if scanner.Image != "" {
+				program = containerRuntime(config.Global)
+			}
+			binaryPath, err := exec.LookPath(program)
 			pathStr = binaryPath
+			if scanner.Image != "" && err == nil {
+				pathStr += " " + scanner.Image
+			}
 			pathColor = ColorGreen
 			if !scanner.Enabled {
 				statusStr = ColorDim + "⛔ OFF" + ColorReset
@@ -789,6 +1350,13 @@ func runPreflight(config *Config, localMode bool) {
 
 		fmt.Printf("  %s  %-20s %-14s %s%-50s%s %-8s %s\n",
 			statusStr, scanner.Name, scanner.Command, pathColor, pathStr, ColorReset, timeout, format)
+		if desc := parsers.Describe(scanner.Name); desc != "" {
+			fmt.Printf("  %-7s  %s%s%s\n", "", ColorDim, desc, ColorReset)
+		}
+		if supported, mismatch := languageMismatch(scanner); mismatch {
+			fmt.Printf("  %-7s  %s⚠️  languages: %s, but the parser supports %s%s\n", "",
+				ColorYellow, formatLanguageList(configuredLanguages(scanner)), formatLanguageList(supported), ColorReset)
+		}
 	}
 
 	// Repositories table (non-local mode only)
@@ -806,6 +1374,16 @@ func runPreflight(config *Config, localMode bool) {
 			scanners := "all enabled"
 			if len(repo.Scanners) > 0 {
 				scanners = strings.Join(repo.Scanners, ", ")
+			} else if repo.Bundle != "" {
+				scanners = fmt.Sprintf("bundle %s: %s", repo.Bundle, strings.Join(config.Global.ScannerBundles[repo.Bundle], ", "))
+				if err := validateRepoBundle(config.Global, repo); err != nil {
+					scanners = fmt.Sprintf("%s✗ unknown bundle %s%s", ColorRed, repo.Bundle, ColorReset)
+					issues++
+				}
+			}
+			if repo.Disabled && !config.Global.IncludeDisabled {
+				fmt.Printf("  %s%-55s %-20s ⏭  disabled%s\n", ColorDim, repo.URL, ref, ColorReset)
+				continue
 			}
 			fmt.Printf("  %-55s %-20s %s\n", repo.URL, ref, scanners)
 		}
@@ -817,22 +1395,107 @@ func runPreflight(config *Config, localMode bool) {
 	}
 }
 
-// cleanupOldResults removes scan result files older than resultsMaxAge
-func cleanupOldResults(resultsDir string) {
-	entries, err := os.ReadDir(resultsDir)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			log.Printf("⚠️  Failed to cleanup old results: %v", err)
+// printScannerList prints every registered result parser with its scan type
+// and the languages it supports (--list-scanners)
+func printScannerList() {
+	fmt.Printf("%-26s %-13s %-22s %s\n", "NAME", "TYPE", "LANGUAGES", "DESCRIPTION")
+	for _, name := range parsers.Names() {
+		parser, _ := parsers.Get(name)
+		languages := "(see scanners.yaml)"
+		if supported, ok := parsers.SupportedLanguages(name); ok {
+			languages = formatLanguageList(supported)
+		}
+		fmt.Printf("%-26s %-13s %-22s %s\n", name, parser.Type(), languages, parsers.Describe(name))
+	}
+}
+
+// cleanupOldResults removes scan result files and SBOMs (in the sboms/
+// subdirectory) older than resultsMaxAge. With archive_results set they are
+// moved into the archive/ subdirectory (or a tarball there) instead.
+func cleanupOldResults(resultsDir, archive string) {
+	cleanupResultsBefore(resultsDir, archive, time.Now().Add(-resultsMaxAge))
+}
+
+// cleanupResultsBefore is cleanupOldResults for files last modified before
+// cutoff
+func cleanupResultsBefore(resultsDir, archive string, cutoff time.Time) {
+	var results []string
+	for _, sub := range resultSubdirs(resultsDir) {
+		for _, name := range oldFiles(filepath.Join(resultsDir, sub), cutoff, ".json", ".sarif") {
+			results = append(results, filepath.Join(sub, name))
+		}
+	}
+	sboms := oldFiles(filepath.Join(resultsDir, "sboms"), cutoff, ".cdx.json", ".spdx.json")
+
+	if archive != "" {
+		files := append([]string{}, results...)
+		for _, name := range sboms {
+			files = append(files, filepath.Join("sboms", name))
+		}
+		archived, err := archiveOldFiles(resultsDir, files, archive)
+		if err != nil {
+			log.Printf("⚠️  Failed to archive old results: %v", err)
+		}
+		if archived > 0 {
+			log.Printf("📦 Archived %d old scan result(s) and SBOM(s) to %s", archived, filepath.Join(resultsDir, archiveDirName))
 		}
 		return
 	}
 
-	cutoff := time.Now().Add(-resultsMaxAge)
-	removed := 0
+	removed := removeFiles(resultsDir, results)
+	removedSBOMs := removeFiles(filepath.Join(resultsDir, "sboms"), sboms)
+	switch {
+	case removed > 0 && removedSBOMs > 0:
+		log.Printf("🧹 Cleaned up %d old scan result(s) and %d old SBOM(s)", removed, removedSBOMs)
+	case removed > 0:
+		log.Printf("🧹 Cleaned up %d old scan result(s)", removed)
+	case removedSBOMs > 0:
+		log.Printf("🧹 Cleaned up %d old SBOM(s)", removedSBOMs)
+	}
+}
+
+// resultSubdirs returns the directories of a results directory that hold
+// scanner results, relative to it: "." for the flat layout plus every
+// <owner>/<repo> directory of the per-repo layout. Both are always returned,
+// so results written before results_layout changed are still found. The sboms
+// and archive directories are not result directories.
+func resultSubdirs(resultsDir string) []string {
+	subdirs := []string{"."}
+	owners, err := os.ReadDir(resultsDir)
+	if err != nil {
+		return subdirs
+	}
+	for _, owner := range owners {
+		if !owner.IsDir() || owner.Name() == "sboms" || owner.Name() == archiveDirName {
+			continue
+		}
+		repos, err := os.ReadDir(filepath.Join(resultsDir, owner.Name()))
+		if err != nil {
+			continue
+		}
+		for _, repo := range repos {
+			if repo.IsDir() {
+				subdirs = append(subdirs, filepath.Join(owner.Name(), repo.Name()))
+			}
+		}
+	}
+	return subdirs
+}
 
+// oldFiles returns the names of files directly inside dir that were last
+// modified before cutoff and have one of the given suffixes
+func oldFiles(dir string, cutoff time.Time, suffixes ...string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("⚠️  Failed to cleanup old files in %s: %v", dir, err)
+		}
+		return nil
+	}
+
+	var old []string
 	for _, entry := range entries {
-		name := entry.Name()
-		if entry.IsDir() || (!strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".sarif")) {
+		if entry.IsDir() || !hasAnySuffix(entry.Name(), suffixes) {
 			continue
 		}
 
@@ -842,13 +1505,30 @@ func cleanupOldResults(resultsDir string) {
 		}
 
 		if info.ModTime().Before(cutoff) {
-			if err := os.Remove(filepath.Join(resultsDir, entry.Name())); err == nil {
-				removed++
-			}
+			old = append(old, entry.Name())
 		}
 	}
+	return old
+}
 
-	if removed > 0 {
-		log.Printf("🧹 Cleaned up %d old scan result(s)", removed)
+// removeFiles removes the named files from dir and returns how many were
+// removed
+func removeFiles(dir string, names []string) int {
+	removed := 0
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(dir, name)); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// hasAnySuffix reports whether name ends with one of suffixes
+func hasAnySuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// offlineDBDir is an absolute path to a pre-downloaded grype vulnerability DB
+// for air-gapped runs; set from global.offline_db_dir in main
+var offlineDBDir string
+
+// offlineDBEnv returns the environment variables that point grype at the DB in
+// dir and keep grype and syft from going online for DB or release updates
+func offlineDBEnv(dir string) []string {
+	return []string{
+		"GRYPE_DB_CACHE_DIR=" + dir,
+		"GRYPE_DB_AUTO_UPDATE=false",
+		"GRYPE_DB_VALIDATE_AGE=false", // an offline DB soon exceeds grype's max age
+		"GRYPE_CHECK_FOR_APP_UPDATE=false",
+		"SYFT_CHECK_FOR_APP_UPDATE=false",
+	}
+}
+
+// setOfflineDBEnv adds the offline DB settings to a scanner or SBOM command
+// when offline_db_dir is configured. Other commands inherit the environment.
+func setOfflineDBEnv(cmd *exec.Cmd) {
+	if offlineDBDir == "" {
+		return
+	}
+	appendCmdEnv(cmd, offlineDBEnv(offlineDBDir)...)
+}
+
+// appendCmdEnv adds environment variables to a command, on top of the
+// inherited environment when it has none of its own yet
+func appendCmdEnv(cmd *exec.Cmd, vars ...string) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env, vars...)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOfflineDBEnv(t *testing.T) {
+	dbDir := t.TempDir()
+	wantEnv := offlineDBEnv(dbDir)
+
+	// envOf runs the scanner and SBOM commands with a fake binary that dumps
+	// its environment, and returns what each saw
+	envOf := func(t *testing.T) (scannerEnv, syftEnv string) {
+		t.Helper()
+		binDir := t.TempDir()
+		syft := "#!/bin/sh\nenv > \"${4#cyclonedx-json=}\"\n"
+		if err := os.WriteFile(filepath.Join(binDir, "syft"), []byte(syft), 0755); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		repoPath := t.TempDir()
+		scanner := ScannerConfig{Name: "grype", Command: "env", timeout: time.Minute}
+		output, _, _, err := execScanner(scanner, nil, repoPath, true)
+		if err != nil {
+			t.Fatalf("execScanner() error = %v", err)
+		}
+
+		sbomPath, err := generateSBOM(t.TempDir(), repoPath, "repo", "abc1234", "main", true, time.Minute)
+		if err != nil {
+			t.Fatalf("generateSBOM() error = %v", err)
+		}
+		sbom, err := os.ReadFile(sbomPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(output), string(sbom)
+	}
+
+	t.Run("offline_db_dir set", func(t *testing.T) {
+		offlineDBDir = dbDir
+		t.Cleanup(func() { offlineDBDir = "" })
+
+		scannerEnv, syftEnv := envOf(t)
+		for _, kv := range wantEnv {
+			if !strings.Contains(scannerEnv, kv+"\n") {
+				t.Errorf("scanner env missing %s", kv)
+			}
+			if !strings.Contains(syftEnv, kv+"\n") {
+				t.Errorf("syft env missing %s", kv)
+			}
+		}
+		if !strings.Contains(scannerEnv, "PATH=") {
+			t.Error("scanner env dropped the inherited environment")
+		}
+	})
+
+	t.Run("offline_db_dir unset", func(t *testing.T) {
+		scannerEnv, syftEnv := envOf(t)
+		if strings.Contains(scannerEnv, wantEnv[0]) || strings.Contains(syftEnv, wantEnv[0]) {
+			t.Error("offline DB env set without offline_db_dir")
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// repoScanWorkers returns how many repositories to scan at once. Repos are
+// scanned one by one unless --parallel-repos is set; then up to maxConcurrent
+// repos run at a time. Scanners within a repo always run sequentially, so the
+// whole max_concurrent budget goes to repos.
+func repoScanWorkers(parallel bool, maxConcurrent, repoCount int) int {
+	if !parallel || maxConcurrent < 1 {
+		return 1
+	}
+	if repoCount < maxConcurrent {
+		return max(repoCount, 1)
+	}
+	return maxConcurrent
+}
+
+// repoScanResult carries one repository's contexts back to the collector,
+// tagged with its position in the repository list
+type repoScanResult struct {
+	index    int
+	contexts []RepoScanContext
+}
+
+// scanReposConcurrently runs scan for each repository on a pool of workers and
+// returns all contexts in repository order, regardless of completion order.
+// With failFast, no new repositories are started once a scan has failed;
+// repos already in progress finish and their contexts are kept.
+func scanReposConcurrently(repos []RepositoryConfig, workers int, failFast bool, scan func(RepositoryConfig) []RepoScanContext) []RepoScanContext {
+	jobs := make(chan int)
+	results := make(chan repoScanResult)
+	var stopped atomic.Bool
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if stopped.Load() {
+					continue
+				}
+				contexts := scan(repos[i])
+				if failFast {
+					for _, ctx := range contexts {
+						if hasFailedResult(ctx) {
+							stopped.Store(true)
+							break
+						}
+					}
+				}
+				results <- repoScanResult{index: i, contexts: contexts}
+			}
+		}()
+	}
+
+	// Feed jobs, then close results once every worker has drained them
+	go func() {
+		for i := range repos {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	byRepo := make([][]RepoScanContext, len(repos))
+	for r := range results {
+		byRepo[r.index] = r.contexts
+	}
+
+	var contexts []RepoScanContext
+	for _, repoContexts := range byRepo {
+		contexts = append(contexts, repoContexts...)
+	}
+	return contexts
+}
+
+// workspaceLocks serializes scans of repositories that share a clone
+// directory, keyed by workspaceRepoName
+var workspaceLocks sync.Map
+
+// lockWorkspace locks the clone directory for name and returns its unlock func
+func lockWorkspace(name string) func() {
+	mu, _ := workspaceLocks.LoadOrStore(name, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
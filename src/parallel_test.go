@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRepoScanWorkers(t *testing.T) {
+	tests := []struct {
+		name          string
+		parallel      bool
+		maxConcurrent int
+		repoCount     int
+		want          int
+	}{
+		{"sequential by default", false, 3, 10, 1},
+		{"parallel bounded by max_concurrent", true, 3, 10, 3},
+		{"parallel bounded by repo count", true, 8, 2, 2},
+		{"parallel with no repos", true, 3, 0, 1},
+		{"parallel with invalid max_concurrent", true, 0, 5, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repoScanWorkers(tt.parallel, tt.maxConcurrent, tt.repoCount); got != tt.want {
+				t.Errorf("repoScanWorkers(%v, %d, %d) = %d, want %d",
+					tt.parallel, tt.maxConcurrent, tt.repoCount, got, tt.want)
+			}
+		})
+	}
+}
+
+// testRepos returns n repositories named repo0..repoN-1
+func testRepos(n int) []RepositoryConfig {
+	repos := make([]RepositoryConfig, n)
+	for i := range repos {
+		repos[i] = RepositoryConfig{URL: fmt.Sprintf("https://github.com/org/repo%d", i)}
+	}
+	return repos
+}
+
+func TestScanReposConcurrently(t *testing.T) {
+	const workers = 3
+	repos := testRepos(6)
+
+	// The first batch of repos waits until all workers are busy, proving the
+	// scans overlap; later repos finish in reverse order to shuffle completion
+	var started, running, peak atomic.Int32
+	allStarted := make(chan struct{})
+	scan := func(repo RepositoryConfig) []RepoScanContext {
+		if started.Add(1) == workers {
+			close(allStarted)
+		}
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+			t.Error("scans did not run concurrently")
+		}
+		var i int
+		fmt.Sscanf(repo.URL, "https://github.com/org/repo%d", &i)
+		time.Sleep(time.Duration(len(repos)-i) * time.Millisecond)
+
+		return []RepoScanContext{{RepoURL: repo.URL}, {RepoURL: repo.URL, Subproject: "sub"}}
+	}
+
+	contexts := scanReposConcurrently(repos, workers, false, scan)
+
+	if len(contexts) != 2*len(repos) {
+		t.Fatalf("got %d contexts, want %d", len(contexts), 2*len(repos))
+	}
+	for i, ctx := range contexts {
+		want := repos[i/2].URL
+		if ctx.RepoURL != want {
+			t.Errorf("contexts[%d].RepoURL = %q, want %q (results out of order)", i, ctx.RepoURL, want)
+		}
+	}
+	if p := peak.Load(); p > workers {
+		t.Errorf("peak concurrency = %d, want at most %d", p, workers)
+	}
+}
+
+func TestScanReposConcurrentlyFailFast(t *testing.T) {
+	repos := testRepos(4)
+	var scanned []string
+	var cloneFailure bool
+	var mu sync.Mutex
+	scan := func(repo RepositoryConfig) []RepoScanContext {
+		mu.Lock()
+		scanned = append(scanned, repo.URL)
+		mu.Unlock()
+		if repo.URL == repos[1].URL && cloneFailure {
+			return []RepoScanContext{{RepoURL: repo.URL, CloneError: errors.New("clone failed")}}
+		}
+		result := ScanResult{Scanner: "grype", Success: repo.URL != repos[1].URL}
+		if !result.Success {
+			result.Error = errors.New("scanner failed")
+		}
+		return []RepoScanContext{{RepoURL: repo.URL, Results: []ScanResult{result}}}
+	}
+
+	tests := []struct {
+		name         string
+		failFast     bool
+		cloneFailure bool
+		wantScanned  int
+	}{
+		{"fail-fast stops after the failing repo", true, false, 2},
+		{"fail-fast stops after a clone failure", true, true, 2},
+		{"without fail-fast every repo is scanned", false, false, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanned, cloneFailure = nil, tt.cloneFailure
+			contexts := scanReposConcurrently(repos, 1, tt.failFast, scan)
+			if len(scanned) != tt.wantScanned {
+				t.Errorf("scanned %v, want %d repos", scanned, tt.wantScanned)
+			}
+			if len(contexts) != tt.wantScanned {
+				t.Errorf("got %d contexts, want %d", len(contexts), tt.wantScanned)
+			}
+		})
+	}
+}
+
+func TestLockWorkspace(t *testing.T) {
+	var inside, overlaps atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := lockWorkspace("org/shared")
+			defer unlock()
+			if inside.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			time.Sleep(time.Millisecond)
+			inside.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if n := overlaps.Load(); n != 0 {
+		t.Errorf("%d scans held the same workspace concurrently", n)
+	}
+}
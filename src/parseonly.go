@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"allscan/parsers"
+)
+
+// parseResultFilename infers the repository, scanner, ref and date from a
+// result filename written with the default result_name_template
+// ({repo}_{ref}_{scanner}_{date}). The scanner is the last "_"-separated part
+// that names a known scanner, so repo names containing "_" still work; the
+// part before it is the ref, everything earlier the repo, and the rest the
+// date.
+func parseResultFilename(name string, knownScanners map[string]bool) (repo, scanner, ref, date string, ok bool) {
+	base := filepath.Base(name)
+	for _, ext := range []string{".json", ".sarif"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	parts := strings.Split(base, "_")
+	for i := len(parts) - 1; i >= 1; i-- {
+		if !knownScanners[parts[i]] {
+			continue
+		}
+		scanner, date = parts[i], strings.Join(parts[i+1:], "_")
+		if i >= 2 {
+			return strings.Join(parts[:i-1], "_"), scanner, parts[i-1], date, true
+		}
+		return parts[0], scanner, "", date, true
+	}
+	return "", "", "", "", false
+}
+
+// isResultFile reports whether a file in a results directory holds scanner
+// output (SBOMs and other files are skipped)
+func isResultFile(name string) bool {
+	if strings.HasSuffix(name, ".cdx.json") || strings.HasSuffix(name, ".spdx.json") {
+		return false
+	}
+	return strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".sarif")
+}
+
+// collectResultFiles reads existing scanner output files from dir (directly
+// inside it and in the <owner>/<repo> directories of the per-repo layout) and
+// any extraDirs, such as unpacked archives, and groups
+// them into one context per repository, ready for printSummary and the run
+// report. A per-repo directory is a repository of its own, named by its
+// <owner>/<repo>, so same-named repositories of different owners stay apart.
+// Only the newest file (by the date in its name) of each
+// repo+ref+scanner is kept, so results of several days aren't counted
+// together; on the same date, dir wins over extraDirs. Files whose scanner
+// can't be inferred from the name are skipped with a warning.
+func collectResultFiles(config *Config, dir string, extraDirs ...string) ([]RepoScanContext, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	type resultFile struct {
+		dir  string
+		sub  string // "." or the <owner>/<repo> of the per-repo layout
+		name string
+	}
+	var files []resultFile
+	for i, d := range append([]string{absDir}, extraDirs...) {
+		if _, err := os.ReadDir(d); err != nil {
+			if i == 0 {
+				return nil, fmt.Errorf("reading results directory: %w", err)
+			}
+			return nil, fmt.Errorf("reading %s: %w", d, err)
+		}
+		for _, sub := range resultSubdirs(d) {
+			entries, err := os.ReadDir(filepath.Join(d, sub))
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() && isResultFile(entry.Name()) {
+					files = append(files, resultFile{dir: filepath.Join(d, sub), sub: sub, name: entry.Name()})
+				}
+			}
+		}
+	}
+
+	scannerConfigs := make(map[string]ScannerConfig)
+	known := make(map[string]bool)
+	for _, s := range config.Scanners {
+		scannerConfigs[s.Name] = s
+		known[s.Name] = true
+	}
+	for _, name := range parsers.Names() {
+		known[name] = true
+	}
+
+	type parsedFile struct {
+		resultFile
+		repo, scanner, ref, date string
+	}
+	var parsed []parsedFile
+	newest := make(map[string]int) // repo+ref+scanner -> index in parsed
+	for _, file := range files {
+		repoName, scannerName, ref, date, ok := parseResultFilename(file.name, known)
+		if !ok {
+			log.Printf("⚠️  Skipping %s: can't tell which scanner wrote it", file.name)
+			continue
+		}
+		if file.sub != "." {
+			repoName = filepath.ToSlash(file.sub)
+		}
+		if _, ok := parsers.Get(scannerName); !ok {
+			log.Printf("⏭️  Skipping %s: no parser for %s", file.name, scannerName)
+			continue
+		}
+		key := repoName + "\x00" + ref + "\x00" + scannerName
+		if i, seen := newest[key]; seen {
+			if date > parsed[i].date {
+				parsed[i] = parsedFile{file, repoName, scannerName, ref, date}
+			}
+			continue
+		}
+		newest[key] = len(parsed)
+		parsed = append(parsed, parsedFile{file, repoName, scannerName, ref, date})
+	}
+
+	byRepo := make(map[string]*RepoScanContext)
+	var repoNames []string
+	for _, file := range parsed {
+		repoName, scannerName, ref := file.repo, file.scanner, file.ref
+		ctx, seen := byRepo[repoName]
+		if !seen {
+			ctx = &RepoScanContext{RepoURL: "local://" + filepath.Join(absDir, repoName)}
+			byRepo[repoName] = ctx
+			repoNames = append(repoNames, repoName)
+		}
+
+		scanner := scannerConfigs[scannerName]
+		result := ScanResult{
+			Scanner:      scannerName,
+			Repository:   ctx.RepoURL,
+			OutputPath:   filepath.Join(file.dir, file.name),
+			Success:      true,
+			DojoScanType: scanner.DojoScanType,
+			IsSarif:      strings.HasSuffix(file.name, ".sarif"),
+			NDJSON:       scanner.NDJSON,
+		}
+		if isVersionTag(ref) {
+			result.BranchTag = ref
+		} else {
+			result.CommitHash = ref
+		}
+		if !result.IsSarif {
+			checkScanOutput(&result)
+		}
+		ctx.Results = append(ctx.Results, result)
+		if scanner.Name != "" && !hasScanner(ctx.Scanners, scannerName) {
+			ctx.Scanners = append(ctx.Scanners, scanner)
+		}
+	}
+
+	sort.Strings(repoNames)
+	contexts := make([]RepoScanContext, 0, len(repoNames))
+	for _, name := range repoNames {
+		contexts = append(contexts, *byRepo[name])
+	}
+	return contexts, nil
+}
+
+// hasScanner reports whether scanners includes one with the given name
+func hasScanner(scanners []ScannerConfig, name string) bool {
+	for _, s := range scanners {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runParseOnly re-renders the summary and run report from result files left
+// by an earlier run, without cloning, scanning or uploading anything. With
+// includeArchived, results that cleanup moved into dir's archive/ (including
+// tarballs) are read too. Errors are returned rather than fatal, so the
+// archives unpacked into temporary directories are removed before exiting.
+func runParseOnly(config *Config, dir string, includeArchived bool) error {
+	var extraDirs []string
+	if includeArchived {
+		archived, cleanup, err := archivedResultDirs(dir)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		extraDirs = archived
+	}
+	contexts, err := collectResultFiles(config, dir, extraDirs...)
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		return fmt.Errorf("No scanner result files found in %s", dir)
+	}
+
+	files := 0
+	for _, ctx := range contexts {
+		files += len(ctx.Results)
+	}
+	log.Printf("📂 Parse-only mode: %d result file(s) for %d repo(s) in %s", files, len(contexts), dir)
+
+	trackFindingAges(config, contexts)
+	printSummary(contexts)
+	return finishRun(config, contexts)
+}
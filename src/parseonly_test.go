@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseResultFilename(t *testing.T) {
+	known := map[string]bool{"grype": true, "gosec": true, "osv-scanner": true}
+
+	tests := []struct {
+		name        string
+		file        string
+		wantRepo    string
+		wantScanner string
+		wantRef     string
+		wantDate    string
+		wantOK      bool
+	}{
+		{"commit ref", "widget_abc1234_grype_20240115.json", "widget", "grype", "abc1234", "20240115", true},
+		{"version tag", "widget_v1.2.0_gosec_20240115.json", "widget", "gosec", "v1.2.0", "20240115", true},
+		{"sarif output", "widget_abc1234_osv-scanner_20240115.sarif", "widget", "osv-scanner", "abc1234", "20240115", true},
+		{"repo name with underscores", "my_cool_repo_abc1234_grype_20240115.json", "my_cool_repo", "grype", "abc1234", "20240115", true},
+		{"repo named like a scanner", "gosec_abc1234_grype_20240115.json", "gosec", "grype", "abc1234", "20240115", true},
+		{"no ref", "widget_grype.json", "widget", "grype", "", "", true},
+		{"unknown scanner", "widget_abc1234_semgrep_20240115.json", "", "", "", "", false},
+		{"scanner name only", "grype.json", "", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, scanner, ref, date, ok := parseResultFilename(tt.file, known)
+			if ok != tt.wantOK || repo != tt.wantRepo || scanner != tt.wantScanner || ref != tt.wantRef || date != tt.wantDate {
+				t.Errorf("parseResultFilename(%q) = (%q, %q, %q, %q, %v), want (%q, %q, %q, %q, %v)",
+					tt.file, repo, scanner, ref, date, ok, tt.wantRepo, tt.wantScanner, tt.wantRef, tt.wantDate, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCollectResultFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"widget_abc1234_grype_20240115.json": `{"matches": [
+			{"vulnerability": {"id": "CVE-1", "severity": "Critical"}},
+			{"vulnerability": {"id": "CVE-2", "severity": "High"}},
+			{"vulnerability": {"id": "CVE-3", "severity": "Medium"}}
+		]}`,
+		// per-repo results_layout, mixed with flat results: a repository of
+		// its own, named by <owner>/<repo>
+		"acme/widget/widget_abc1234_gosec_20240115.json": `{"Issues": [{"severity": "HIGH"}, {"severity": "LOW"}]}`,
+		"gadget_v2.0.0_grype_20240115.json": `{"matches": [
+			{"vulnerability": {"id": "CVE-4", "severity": "Low"}}
+		]}`,
+		"gadget_v2.0.0_osv-scanner_20240115.sarif": `{"runs": []}`,
+		"widget_abc1234_20240115.cdx.json":         `{"bomFormat": "CycloneDX"}`,
+		"notes_abc1234_unknowntool_20240115.json":  `{}`,
+		"sboms/widget_abc1234_grype_20240115.json": `{"matches": []}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &Config{Scanners: []ScannerConfig{
+		{Name: "grype", DojoScanType: "Anchore Grype"},
+		{Name: "gosec", DojoScanType: "Gosec Scanner"},
+		{Name: "osv-scanner"},
+	}}
+	contexts, err := collectResultFiles(config, dir)
+	if err != nil {
+		t.Fatalf("collectResultFiles: %v", err)
+	}
+	if len(contexts) != 3 {
+		t.Fatalf("got %d contexts, want 3 (acme/widget, gadget, widget)", len(contexts))
+	}
+
+	acme, gadget, widget := contexts[0], contexts[1], contexts[2]
+	if want := "local://" + filepath.Join(dir, "acme", "widget"); acme.RepoURL != want {
+		t.Errorf("per-repo RepoURL = %q, want %q", acme.RepoURL, want)
+	}
+	if len(acme.Results) != 1 || len(gadget.Results) != 2 || len(widget.Results) != 1 {
+		t.Fatalf("got %d acme/widget, %d gadget and %d widget results, want 1, 2 and 1",
+			len(acme.Results), len(gadget.Results), len(widget.Results))
+	}
+	for _, result := range gadget.Results {
+		if result.BranchTag != "v2.0.0" || result.CommitHash != "" {
+			t.Errorf("gadget %s: BranchTag=%q CommitHash=%q, want tag v2.0.0", result.Scanner, result.BranchTag, result.CommitHash)
+		}
+		if result.IsSarif != (result.Scanner == "osv-scanner") {
+			t.Errorf("gadget %s: IsSarif = %v", result.Scanner, result.IsSarif)
+		}
+	}
+	for _, result := range append(acme.Results, widget.Results...) {
+		if result.CommitHash != "abc1234" {
+			t.Errorf("widget %s: CommitHash = %q, want abc1234", result.Scanner, result.CommitHash)
+		}
+		if result.Scanner == "grype" && result.DojoScanType != "Anchore Grype" {
+			t.Errorf("widget grype: DojoScanType = %q, want it from config", result.DojoScanType)
+		}
+	}
+
+	// The run report holds the same totals a scan would have produced
+	reportPath := filepath.Join(dir, "out", "report.json")
+	if err := writeRunReport(reportPath, contexts, RunMetadata{}); err != nil {
+		t.Fatalf("writeRunReport: %v", err)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report RunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	var critical, high, medium, low, total int
+	for _, entry := range report.Results {
+		critical += entry.Summary.Critical
+		high += entry.Summary.High
+		medium += entry.Summary.Medium
+		low += entry.Summary.Low
+		total += entry.Summary.Total
+	}
+	if critical != 1 || high != 2 || medium != 1 || low != 2 || total != 6 {
+		t.Errorf("totals = critical %d, high %d, medium %d, low %d, total %d; want 1, 2, 1, 2, 6",
+			critical, high, medium, low, total)
+	}
+
+	out := captureStdout(t, func() { printSummary(contexts) })
+	if out == "" {
+		t.Error("printSummary printed nothing for parsed results")
+	}
+}
+
+func TestCollectResultFilesMissingDir(t *testing.T) {
+	if _, err := collectResultFiles(&Config{}, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing results directory")
+	}
+}
+
+func TestCollectResultFilesKeepsNewest(t *testing.T) {
+	dir, archived := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(dir, "widget_abc1234_grype_20240115.json"):      `{"matches": [{"vulnerability": {"id": "CVE-1", "severity": "Critical"}}]}`,
+		filepath.Join(dir, "widget_abc1234_grype_20240116.json"):      `{"matches": [{"vulnerability": {"id": "CVE-2", "severity": "Low"}}]}`,
+		filepath.Join(dir, "widget_def5678_grype_20240116.json"):      `{"matches": []}`,
+		filepath.Join(archived, "widget_abc1234_grype_20240110.json"): `{"matches": [{"vulnerability": {"id": "CVE-3", "severity": "High"}}]}`,
+		filepath.Join(archived, "widget_abc1234_grype_20240116.json"): `{"matches": [{"vulnerability": {"id": "CVE-4", "severity": "High"}}]}`,
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &Config{Scanners: []ScannerConfig{{Name: "grype"}}}
+	contexts, err := collectResultFiles(config, dir, archived)
+	if err != nil {
+		t.Fatalf("collectResultFiles: %v", err)
+	}
+	if len(contexts) != 1 || len(contexts[0].Results) != 2 {
+		t.Fatalf("got %+v, want one widget context with one result per ref", contexts)
+	}
+	for _, result := range contexts[0].Results {
+		want := filepath.Join(dir, "widget_"+result.CommitHash+"_grype_20240116.json")
+		if result.OutputPath != want {
+			t.Errorf("%s result read from %s, want the newest file %s", result.CommitHash, result.OutputPath, want)
+		}
+	}
+}
+
+func TestCollectResultFilesPerRepoOwners(t *testing.T) {
+	dir := t.TempDir()
+	for _, owner := range []string{"org1", "org2"} {
+		repoDir := filepath.Join(dir, owner, "api")
+		if err := os.MkdirAll(repoDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := `{"matches": [{"vulnerability": {"id": "CVE-` + owner + `", "severity": "High"}}]}`
+		if err := os.WriteFile(filepath.Join(repoDir, "api_abc1234_grype_20240116.json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &Config{Scanners: []ScannerConfig{{Name: "grype"}}}
+	contexts, err := collectResultFiles(config, dir)
+	if err != nil {
+		t.Fatalf("collectResultFiles: %v", err)
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("got %d contexts, want one per owner: %+v", len(contexts), contexts)
+	}
+	for i, owner := range []string{"org1", "org2"} {
+		ctx := contexts[i]
+		if want := "local://" + filepath.Join(dir, owner, "api"); ctx.RepoURL != want {
+			t.Errorf("context %d RepoURL = %q, want %q", i, ctx.RepoURL, want)
+		}
+		if len(ctx.Results) != 1 || filepath.Dir(ctx.Results[0].OutputPath) != filepath.Join(dir, owner, "api") {
+			t.Errorf("context %d results = %+v, want the %s file only", i, ctx.Results, owner)
+		}
+	}
+}
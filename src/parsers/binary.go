@@ -32,7 +32,14 @@ type BinaryFile struct {
 
 func (p *BinaryParser) Name() string { return "binary-detector" }
 func (p *BinaryParser) Type() string { return "Binary" }
-func (p *BinaryParser) Icon() string { return "📀" }
+func (p *BinaryParser) Icon() string { return iconBinary }
+func (p *BinaryParser) Describe() string {
+	return "Detects committed binary files (executables, libraries, archives) that can hide unreviewed code."
+}
+
+func (p *BinaryParser) SupportedLanguages() []string {
+	return nil
+}
 
 func (p *BinaryParser) Parse(data []byte) (FindingSummary, error) {
 	var output BinaryOutput
@@ -112,32 +119,35 @@ type sarifArtifactLocation struct {
 	URIBaseID string `json:"uriBaseId"`
 }
 
-// RunBinaryDetector scans for binary files and writes JSON or SARIF output.
+// RunBinaryDetector scans for binary files and writes JSON or SARIF output,
+// skipping paths matched by ignore (which may be nil). Symlinks are handled
+// by WalkRepo: never followed outside repoPath, and into directories only
+// with followSymlinks.
 // Returns the count of binaries found.
-func RunBinaryDetector(repoPath string, outputPath string, sarifMode bool) (int, error) {
+func RunBinaryDetector(repoPath string, outputPath string, sarifMode bool, ignore *IgnorePatterns, followSymlinks bool) (int, error) {
 	var binaries []BinaryFile
 
-	err := filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+	err := WalkRepo(repoPath, followSymlinks, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil // Skip files we can't access
 		}
 
-		// Skip directories and hidden paths
+		// Get relative path for cleaner output
+		relPath, _ := filepath.Rel(repoPath, path)
+
+		// Skip directories, hidden paths and .allscanignore entries
 		if d.IsDir() {
-			if strings.HasPrefix(d.Name(), ".") {
+			if strings.HasPrefix(d.Name(), ".") || (path != repoPath && ignore.Match(relPath, true)) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		// Skip hidden files
-		if strings.HasPrefix(d.Name(), ".") {
+		if strings.HasPrefix(d.Name(), ".") || ignore.Match(relPath, false) {
 			return nil
 		}
 
-		// Get relative path for cleaner output
-		relPath, _ := filepath.Rel(repoPath, path)
-
 		// Check by extension first (fast path)
 		ext := strings.ToLower(filepath.Ext(path))
 		if binaryExtensions[ext] {
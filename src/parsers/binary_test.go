@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -120,7 +121,7 @@ func TestRunBinaryDetector(t *testing.T) {
 			}
 			outputPath := filepath.Join(outDir, "out"+ext)
 
-			count, err := RunBinaryDetector(repoDir, outputPath, tt.sarifMode)
+			count, err := RunBinaryDetector(repoDir, outputPath, tt.sarifMode, nil, false)
 			if err != nil {
 				t.Fatalf("RunBinaryDetector() error = %v", err)
 			}
@@ -185,3 +186,90 @@ func TestRunBinaryDetector(t *testing.T) {
 		})
 	}
 }
+
+func TestRunBinaryDetectorIgnore(t *testing.T) {
+	repoDir := t.TempDir()
+	for _, name := range []string{"tools/protoc.exe", "testdata/fixture.so", "lib/native.dll"} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("MZ"), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := ParseIgnorePatterns("testdata/\n/tools/*.exe\n")
+
+	outputPath := filepath.Join(t.TempDir(), "out.json")
+	count, err := RunBinaryDetector(repoDir, outputPath, false, ignore, false)
+	if err != nil {
+		t.Fatalf("RunBinaryDetector() error = %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("output file not written: %v", err)
+	}
+	var out BinaryOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("JSON output is not valid: %v", err)
+	}
+	if count != 1 || len(out.Binaries) != 1 || out.Binaries[0].Path != filepath.Join("lib", "native.dll") {
+		t.Errorf("got %d binaries %+v, want only lib/native.dll", count, out.Binaries)
+	}
+}
+
+func TestRunBinaryDetectorSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	repoDir, outside := filepath.Join(dir, "repo"), filepath.Join(dir, "outside")
+	for _, name := range []string{"repo/lib/native.so", "outside/tool.exe", "outside/blob"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("MZ\x00\x00"), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"repo/host":   outside,
+		"repo/blob":   filepath.Join(outside, "blob"),
+		"repo/vendor": "lib",
+		"repo/lib/up": "..",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		follow bool
+		want   []string
+	}{
+		{name: "default", want: []string{"lib/native.so"}},
+		{name: "follow_symlinks", follow: true, want: []string{"lib/native.so", "vendor/native.so"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "out.json")
+			if _, err := RunBinaryDetector(repoDir, outputPath, false, nil, tt.follow); err != nil {
+				t.Fatalf("RunBinaryDetector() error = %v", err)
+			}
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out BinaryOutput
+			if err := json.Unmarshal(data, &out); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, b := range out.Binaries {
+				got = append(got, filepath.ToSlash(b.Path))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("binaries = %v, want %v (nothing outside the repo)", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package parsers
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// CVSS - Severity derivation from CVSS scores and v3 vectors
+// ============================================================================
+
+// cvssV3Weights holds the CVSS v3.x base metric weights, keyed by metric then value.
+// Privileges Required is handled separately because it depends on Scope.
+var cvssV3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvssV3BaseScore computes the base score of a CVSS v3.0/v3.1 vector such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H". ok is false when the vector
+// is not v3 or is missing a base metric.
+func cvssV3BaseScore(vector string) (score float64, ok bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		if key, value, found := strings.Cut(part, ":"); found {
+			metrics[key] = value
+		}
+	}
+
+	weight := func(metric string) (float64, bool) {
+		w, found := cvssV3Weights[metric][metrics[metric]]
+		return w, found
+	}
+
+	scopeChanged := metrics["S"] == "C"
+	if !scopeChanged && metrics["S"] != "U" {
+		return 0, false
+	}
+
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if scopeChanged {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if scopeChanged {
+			pr = 0.5
+		}
+	default:
+		return 0, false
+	}
+
+	av, okAV := weight("AV")
+	ac, okAC := weight("AC")
+	ui, okUI := weight("UI")
+	c, okC := weight("C")
+	i, okI := weight("I")
+	a, okA := weight("A")
+	if !okAV || !okAC || !okUI || !okC || !okI || !okA {
+		return 0, false
+	}
+
+	iss := 1 - (1-c)*(1-i)*(1-a)
+	var impact float64
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * av * ac * pr * ui
+	if scopeChanged {
+		return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return cvssRoundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// cvssRoundUp implements the CVSS v3.1 Roundup function: the smallest number,
+// to one decimal place, that is equal to or higher than its input.
+func cvssRoundUp(x float64) float64 {
+	scaled := int(math.Round(x * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return float64(scaled/10000+1) / 10
+}
+
+// severityFromCVSS maps a CVSS numeric score or v3 vector to a normalized severity
+// using the CVSS v3 qualitative rating scale. ok is false when cvss is empty or
+// can't be interpreted.
+func severityFromCVSS(cvss string) (severity string, ok bool) {
+	cvss = strings.TrimSpace(cvss)
+	if cvss == "" {
+		return "", false
+	}
+
+	score, err := strconv.ParseFloat(cvss, 64)
+	if err != nil {
+		var valid bool
+		if score, valid = cvssV3BaseScore(cvss); !valid {
+			return "", false
+		}
+	}
+
+	switch {
+	case score >= 9.0:
+		return "critical", true
+	case score >= 7.0:
+		return "high", true
+	case score >= 4.0:
+		return "medium", true
+	case score > 0:
+		return "low", true
+	default:
+		return "info", true
+	}
+}
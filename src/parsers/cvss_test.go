@@ -0,0 +1,60 @@
+package parsers
+
+import "testing"
+
+func TestCVSSV3BaseScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector string
+		want   float64
+		wantOK bool
+	}{
+		{name: "critical network RCE", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", want: 9.8, wantOK: true},
+		{name: "scope changed", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", want: 10.0, wantOK: true},
+		{name: "medium XSS", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", want: 6.1, wantOK: true},
+		{name: "low local", vector: "CVSS:3.1/AV:L/AC:H/PR:L/UI:R/S:U/C:L/I:N/A:N", want: 2.2, wantOK: true},
+		{name: "v3.0 prefix", vector: "CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", want: 6.5, wantOK: true},
+		{name: "no impact", vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", want: 0, wantOK: true},
+		{name: "v2 vector", vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", wantOK: false},
+		{name: "missing metric", vector: "CVSS:3.1/AV:N/AC:L/PR:N/S:U/C:H/I:H/A:H", wantOK: false},
+		{name: "empty", vector: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cvssV3BaseScore(tt.vector)
+			if ok != tt.wantOK {
+				t.Fatalf("cvssV3BaseScore(%q) ok = %v, want %v", tt.vector, ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("cvssV3BaseScore(%q) = %v, want %v", tt.vector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityFromCVSS(t *testing.T) {
+	tests := []struct {
+		cvss   string
+		want   string
+		wantOK bool
+	}{
+		{cvss: "9.8", want: "critical", wantOK: true},
+		{cvss: "7.0", want: "high", wantOK: true},
+		{cvss: "4.0", want: "medium", wantOK: true},
+		{cvss: "0.1", want: "low", wantOK: true},
+		{cvss: "0", want: "info", wantOK: true},
+		{cvss: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", want: "medium", wantOK: true},
+		{cvss: "", wantOK: false},
+		{cvss: "garbage", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cvss, func(t *testing.T) {
+			got, ok := severityFromCVSS(tt.cvss)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("severityFromCVSS(%q) = (%q, %v), want (%q, %v)", tt.cvss, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// heuristicSeverityKeys are the keys whose value HeuristicParser takes as a
+// finding's severity
+var heuristicSeverityKeys = []string{"severity", "Severity", "level"}
+
+// heuristicSeverities maps the severity values HeuristicParser recognizes,
+// lowercased, to normalized severities. SARIF-style levels count too; other
+// values (e.g. a numeric level) don't mark a finding.
+var heuristicSeverities = map[string]string{
+	"critical":      "critical",
+	"high":          "high",
+	"error":         "high",
+	"medium":        "medium",
+	"moderate":      "medium",
+	"warning":       "medium",
+	"low":           "low",
+	"note":          "low",
+	"info":          "info",
+	"informational": "info",
+}
+
+// HeuristicParser is a best-effort fallback for scanners without a
+// registered parser. It is deliberately not registered: the summary uses it
+// for unknown scanners and labels its counts as heuristic.
+type HeuristicParser struct {
+	ScannerName string
+}
+
+// Parse counts every JSON object with a recognized severity under one of
+// heuristicSeverityKeys as one finding. Objects nested inside a counted
+// object aren't counted again. Accepts a single document or NDJSON.
+func (p HeuristicParser) Parse(data []byte) (FindingSummary, error) {
+	var summary FindingSummary
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return summary, nil
+		}
+		if err != nil {
+			return FindingSummary{}, fmt.Errorf("parsing JSON: %w", err)
+		}
+		countHeuristicFindings(doc, &summary)
+	}
+}
+
+// countHeuristicFindings walks a decoded JSON value and counts the objects
+// that carry a recognized severity
+func countHeuristicFindings(value any, summary *FindingSummary) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range heuristicSeverityKeys {
+			s, ok := v[key].(string)
+			if !ok {
+				continue
+			}
+			if severity, known := heuristicSeverities[strings.ToLower(strings.TrimSpace(s))]; known {
+				summary.count(severity)
+				return
+			}
+		}
+		for _, child := range v {
+			countHeuristicFindings(child, summary)
+		}
+	case []any:
+		for _, child := range v {
+			countHeuristicFindings(child, summary)
+		}
+	}
+}
+
+func (p HeuristicParser) Type() string { return "heuristic" }
+func (p HeuristicParser) Icon() string { return iconHeuristic }
+func (p HeuristicParser) Name() string { return p.ScannerName }
@@ -0,0 +1,72 @@
+package parsers
+
+import "testing"
+
+func TestHeuristicParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FindingSummary
+		wantErr bool
+	}{
+		{
+			name: "nested results list",
+			input: `{"version": "1.2", "results": [
+				{"id": "A1", "severity": "CRITICAL", "location": {"file": "main.go"}},
+				{"id": "A2", "severity": "high"},
+				{"id": "A3", "severity": "Moderate"}
+			]}`,
+			want: FindingSummary{Critical: 1, High: 1, Medium: 1, Total: 3},
+		},
+		{
+			name: "capitalized key deep in the document",
+			input: `{"Report": {"Targets": [{"Target": "go.sum", "Issues": [
+				{"Severity": "LOW"}, {"Severity": "Informational"}
+			]}]}}`,
+			want: FindingSummary{Low: 1, Info: 1, Total: 2},
+		},
+		{
+			name: "SARIF-style levels",
+			input: `{"runs": [{"results": [
+				{"ruleId": "R1", "level": "error"}, {"ruleId": "R2", "level": "warning"}, {"ruleId": "R3", "level": "note"}
+			]}]}`,
+			want: FindingSummary{High: 1, Medium: 1, Low: 1, Total: 3},
+		},
+		{
+			name:  "nested severities of a counted finding aren't counted again",
+			input: `[{"severity": "high", "related": [{"severity": "critical"}, {"severity": "medium"}]}]`,
+			want:  FindingSummary{High: 1, Total: 1},
+		},
+		{
+			name:  "NDJSON",
+			input: "{\"severity\": \"critical\"}\n{\"severity\": \"low\"}\n",
+			want:  FindingSummary{Critical: 1, Low: 1, Total: 2},
+		},
+		{
+			name:  "unrecognized severity values are skipped",
+			input: `{"logs": [{"level": 3}, {"level": "debug"}, {"severity": ""}], "findings": [{"severity": "unknown", "level": "warning"}]}`,
+			want:  FindingSummary{Medium: 1, Total: 1},
+		},
+		{
+			name:  "no severity fields",
+			input: `{"files_scanned": 12, "ok": true}`,
+		},
+		{
+			name:    "not JSON",
+			input:   `scan finished: 3 issues`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HeuristicParser{ScannerName: "newtool"}.Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package parsers
+
+// Parser icons are kept together in one file so that an encoding problem
+// (e.g., an emoji saved as mojibake) is easy to spot and fix in one place.
+// TestRegisteredParserIcons validates every registered parser's icon.
+const (
+	iconGrype       = "📦"
+	iconOSVScanner  = "🔎"
+	iconGosec       = "🔍"
+	iconTrufflehog  = "🔑"
+	iconBinary      = "📀"
+	iconScorecard   = "🛡️" // U+1F6E1 SHIELD + U+FE0F emoji presentation selector
+	iconGovulncheck = "🔬"
+	iconCargoAudit  = "🦀"
+	iconKubernetes  = "☸️" // U+2638 WHEEL OF DHARMA + U+FE0F emoji presentation selector
+	iconKubescape   = "🚢"
+	iconRuff        = "🐍"
+	iconTrivy       = "🧪"
+	iconSensitive   = "🔐"
+	iconHeuristic   = "❔" // U+2754, fallback for scanners without a parser
+)
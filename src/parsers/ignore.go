@@ -0,0 +1,145 @@
+package parsers
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the file at the root of a scanned directory listing paths
+// that language detection and the built-in scanners skip
+const IgnoreFileName = ".allscanignore"
+
+// IgnorePatterns is a parsed .allscanignore, in gitignore syntax: blank lines
+// and # comments are skipped, a leading ! re-includes a path, a trailing /
+// matches directories only, and a pattern containing a / (other than a
+// trailing one) is anchored to the root while one without matches at any
+// depth. *, ? and [...] match within one path segment; ** matches any number
+// of segments. A nil *IgnorePatterns matches nothing.
+type IgnorePatterns struct {
+	rules []ignoreRule
+	base  string // slash-separated prefix added to matched paths (see Within)
+}
+
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// LoadIgnoreFile reads the .allscanignore in dir. A missing file is not an
+// error and returns nil patterns.
+func LoadIgnoreFile(dir string) (*IgnorePatterns, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseIgnorePatterns(string(data)), nil
+}
+
+// ParseIgnorePatterns parses gitignore-syntax patterns, one per line
+func ParseIgnorePatterns(content string) *IgnorePatterns {
+	patterns := &IgnorePatterns{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// Without a slash the pattern matches a name at any depth
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		rule.segments = strings.Split(line, "/")
+		if !anchored {
+			rule.segments = append([]string{"**"}, rule.segments...)
+		}
+		patterns.rules = append(patterns.rules, rule)
+	}
+	return patterns
+}
+
+// Within returns the patterns for walking subdir (relative to the directory
+// the ignore file was read from), so paths relative to subdir still match
+// patterns written relative to the root
+func (p *IgnorePatterns) Within(subdir string) *IgnorePatterns {
+	if p == nil {
+		return nil
+	}
+	subdir = filepath.ToSlash(filepath.Clean(subdir))
+	if subdir == "." {
+		return p
+	}
+	return &IgnorePatterns{rules: p.rules, base: path.Join(p.base, subdir)}
+}
+
+// Match reports whether relPath (relative to the walked directory) is
+// ignored. The last matching pattern wins. Walkers skip ignored directories
+// entirely, so as in git a file can't be re-included when its directory is
+// ignored.
+func (p *IgnorePatterns) Match(relPath string, isDir bool) bool {
+	if p == nil || len(p.rules) == 0 {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	if p.base != "" {
+		relPath = path.Join(p.base, relPath)
+	}
+	segments := strings.Split(relPath, "/")
+
+	ignored := false
+	for _, rule := range p.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if matchIgnoreSegments(rule.segments, segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchIgnoreSegments matches path segments against pattern segments, where
+// a ** segment matches zero or more path segments (one or more when it ends
+// the pattern, so "build/**" matches what's inside build but not build)
+func matchIgnoreSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return len(segments) > 0
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchIgnoreSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
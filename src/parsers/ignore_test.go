@@ -0,0 +1,58 @@
+package parsers
+
+import "testing"
+
+func TestIgnorePatternsMatch(t *testing.T) {
+	patterns := ParseIgnorePatterns(`# generated code
+*.min.js
+build/
+/docs/*.md
+!docs/README.md
+vendor/**/testdata
+third_party/**
+\#notes
+`)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"app.min.js", false, true},
+		{"web/static/app.min.js", false, true},
+		{"app.js", false, false},
+		{"build", true, true},
+		{"src/build", true, true},
+		{"build", false, false}, // trailing slash matches directories only
+		{"docs/guide.md", false, true},
+		{"docs/README.md", false, false},    // re-included
+		{"src/docs/guide.md", false, false}, // anchored to the root
+		{"vendor/testdata", true, true},
+		{"vendor/a/b/testdata", true, true},
+		{"third_party/lib/x.go", false, true},
+		{"third_party", true, false},
+		{"#notes", false, true},
+		{"generated code", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := patterns.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestIgnorePatternsWithin(t *testing.T) {
+	patterns := ParseIgnorePatterns("/backend/fixtures/\n")
+	if !patterns.Within("backend").Match("fixtures", true) {
+		t.Error("Within(backend) should match fixtures against /backend/fixtures/")
+	}
+	if patterns.Within("frontend").Match("fixtures", true) {
+		t.Error("Within(frontend) should not match fixtures")
+	}
+
+	var none *IgnorePatterns
+	if none.Within("backend").Match("anything", false) {
+		t.Error("nil patterns should match nothing")
+	}
+}
@@ -0,0 +1,417 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// Kubernetes Policy Checker - Checks manifests and Helm charts for risky pods
+// ============================================================================
+
+// KubernetesPolicyParser parses kubernetes-policy-checker results.
+// The checker flags workloads that weaken cluster isolation, such as
+// privileged containers, hostPath mounts, and containers without limits.
+type KubernetesPolicyParser struct{}
+
+// KubernetesPolicyOutput represents the JSON output of the policy checker
+type KubernetesPolicyOutput struct {
+	Findings      []KubernetesPolicyFinding `json:"findings"`
+	Total         int                       `json:"total"`
+	SkippedCharts []SkippedChart            `json:"skipped_charts,omitempty"`
+}
+
+// KubernetesPolicyFinding is one policy violation in a manifest
+type KubernetesPolicyFinding struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	File      string `json:"file"` // Manifest or chart template, relative to the repo
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// SkippedChart is a Helm chart that could not be rendered and was not checked
+type SkippedChart struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+func (p *KubernetesPolicyParser) Name() string { return "kubernetes-policy-checker" }
+func (p *KubernetesPolicyParser) Type() string { return "IaC" }
+func (p *KubernetesPolicyParser) Icon() string { return iconKubernetes }
+func (p *KubernetesPolicyParser) Describe() string {
+	return "Checks Kubernetes manifests and Helm charts for privileged containers, hostPath mounts, and missing resource limits."
+}
+
+func (p *KubernetesPolicyParser) SupportedLanguages() []string {
+	return []string{"kubernetes", "helm"}
+}
+
+func (p *KubernetesPolicyParser) Parse(data []byte) (FindingSummary, error) {
+	var output KubernetesPolicyOutput
+	var summary FindingSummary
+
+	if err := json.Unmarshal(data, &output); err != nil {
+		return summary, err
+	}
+
+	for _, f := range output.Findings {
+		summary.Total++
+		switch normalizeSeverity(f.Severity) {
+		case "critical":
+			summary.Critical++
+		case "high":
+			summary.High++
+		case "medium":
+			summary.Medium++
+		case "low":
+			summary.Low++
+		default:
+			summary.Info++
+		}
+	}
+
+	return summary, nil
+}
+
+// Findings lists each policy violation with the manifest it was found in
+func (p *KubernetesPolicyParser) Findings(data []byte) ([]Finding, error) {
+	var output KubernetesPolicyOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(output.Findings))
+	for _, f := range output.Findings {
+		findings = append(findings, Finding{
+			ID:       f.Rule,
+			Title:    f.Message,
+			Severity: normalizeSeverity(f.Severity),
+			File:     f.File,
+		})
+	}
+	return findings, nil
+}
+
+// Verify KubernetesPolicyParser implements IaCParser and DetailedParser
+var (
+	_ IaCParser      = (*KubernetesPolicyParser)(nil)
+	_ DetailedParser = (*KubernetesPolicyParser)(nil)
+)
+
+// ============================================================================
+// Kubernetes Policy Checker Scanner Logic
+// ============================================================================
+
+// Built-in policy rules and their default severities
+const (
+	RulePrivilegedContainer = "K8S001"
+	RuleHostPathVolume      = "K8S002"
+	RuleMissingLimits       = "K8S003"
+)
+
+var defaultRuleSeverities = map[string]string{
+	RulePrivilegedContainer: "high",
+	RuleHostPathVolume:      "high",
+	RuleMissingLimits:       "medium",
+}
+
+// KubernetesPolicy customizes the built-in rules. Rules not listed keep their
+// default severity and stay enabled.
+//
+//	rules:
+//	  K8S003:
+//	    severity: low
+//	  K8S002:
+//	    enabled: false
+type KubernetesPolicy struct {
+	Rules map[string]KubernetesRulePolicy `yaml:"rules"`
+}
+
+// KubernetesRulePolicy overrides one built-in rule
+type KubernetesRulePolicy struct {
+	Enabled  *bool  `yaml:"enabled"`
+	Severity string `yaml:"severity"`
+}
+
+// LoadKubernetesPolicy reads a policy file, rejecting unknown rule IDs and
+// severities so a typo doesn't silently leave a rule at its default.
+func LoadKubernetesPolicy(path string) (*KubernetesPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy KubernetesPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for id, rule := range policy.Rules {
+		if _, ok := defaultRuleSeverities[id]; !ok {
+			return nil, fmt.Errorf("%s: unknown rule %q", path, id)
+		}
+		if rule.Severity != "" && normalizeSeverity(rule.Severity) != strings.ToLower(rule.Severity) {
+			return nil, fmt.Errorf("%s: rule %s has unknown severity %q", path, id, rule.Severity)
+		}
+	}
+	return &policy, nil
+}
+
+// severity returns the severity of a rule, or "" if the policy disables it
+func (p *KubernetesPolicy) severity(rule string) string {
+	if p != nil {
+		if override, ok := p.Rules[rule]; ok {
+			if override.Enabled != nil && !*override.Enabled {
+				return ""
+			}
+			if override.Severity != "" {
+				return strings.ToLower(override.Severity)
+			}
+		}
+	}
+	return defaultRuleSeverities[rule]
+}
+
+// KubernetesCheckOptions configures RunKubernetesPolicyChecker
+type KubernetesCheckOptions struct {
+	// Policy overrides the built-in rules; nil uses the defaults
+	Policy *KubernetesPolicy
+
+	// RenderChart renders the Helm chart in chartDir to manifests, as
+	// `helm template` does. Charts that fail to render are skipped and
+	// listed in the output; nil skips all charts.
+	RenderChart func(chartDir string) ([]byte, error)
+
+	// Ignore lists paths to skip (a parsed .allscanignore); nil skips none
+	Ignore *IgnorePatterns
+
+	// FollowSymlinks descends into symlinked directories inside the repo
+	// (see WalkRepo); symlinks resolving outside it are never read
+	FollowSymlinks bool
+}
+
+// RunKubernetesPolicyChecker checks every Kubernetes manifest and Helm chart
+// in the repository and writes JSON output. Directories containing a
+// Chart.yaml are rendered as a whole instead of being read file by file,
+// since chart templates are not valid YAML until rendered.
+func RunKubernetesPolicyChecker(repoPath, outputPath string, opts KubernetesCheckOptions) (*KubernetesPolicyOutput, error) {
+	output := &KubernetesPolicyOutput{Findings: []KubernetesPolicyFinding{}}
+
+	err := WalkRepo(repoPath, opts.FollowSymlinks, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+		relPath, _ := filepath.Rel(repoPath, path)
+
+		if d.IsDir() {
+			if path != repoPath && (strings.HasPrefix(d.Name(), ".") || opts.Ignore.Match(relPath, true)) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "Chart.yaml")); err == nil {
+				checkHelmChart(output, path, relPath, opts)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if (ext != ".yaml" && ext != ".yml") || opts.Ignore.Match(relPath, false) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		output.Findings = append(output.Findings, CheckKubernetesManifests(data, relPath, opts.Policy)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output.Total = len(output.Findings)
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(outputPath, data, 0640); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// checkHelmChart renders a chart and checks each rendered template, reporting
+// findings against the template's path in the repository
+func checkHelmChart(output *KubernetesPolicyOutput, chartDir, relPath string, opts KubernetesCheckOptions) {
+	if opts.RenderChart == nil {
+		output.SkippedCharts = append(output.SkippedCharts, SkippedChart{Path: relPath, Reason: "chart rendering disabled"})
+		return
+	}
+	rendered, err := opts.RenderChart(chartDir)
+	if err != nil {
+		output.SkippedCharts = append(output.SkippedCharts, SkippedChart{Path: relPath, Reason: err.Error()})
+		return
+	}
+
+	// helm template separates templates with "---" and a "# Source:
+	// <chart>/templates/<file>" comment; map that back into the repo
+	for _, doc := range splitYAMLDocuments(rendered) {
+		file := relPath
+		for _, line := range strings.Split(doc, "\n") {
+			if source, ok := strings.CutPrefix(line, "# Source: "); ok {
+				if _, inChart, found := strings.Cut(source, "/"); found {
+					file = filepath.Join(relPath, inChart)
+				}
+				break
+			}
+		}
+		output.Findings = append(output.Findings, CheckKubernetesManifests([]byte(doc), file, opts.Policy)...)
+	}
+}
+
+// splitYAMLDocuments splits a multi-document YAML stream on "---" lines
+func splitYAMLDocuments(data []byte) []string {
+	var docs []string
+	var current strings.Builder
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimRight(line, " \r") == "---" {
+			docs = append(docs, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	return append(docs, current.String())
+}
+
+// k8sObject holds the fields of a Kubernetes object the policy rules need.
+// Pod specs live at different paths depending on the workload kind.
+type k8sObject struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		k8sPodSpec `yaml:",inline"` // Pod
+		Template   struct {
+			Spec k8sPodSpec `yaml:"spec"`
+		} `yaml:"template"` // Deployment, StatefulSet, DaemonSet, ReplicaSet, Job
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec k8sPodSpec `yaml:"spec"`
+				} `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"` // CronJob
+	} `yaml:"spec"`
+}
+
+type k8sPodSpec struct {
+	Containers     []k8sContainer `yaml:"containers"`
+	InitContainers []k8sContainer `yaml:"initContainers"`
+	Volumes        []struct {
+		Name     string    `yaml:"name"`
+		HostPath *struct{} `yaml:"hostPath"`
+	} `yaml:"volumes"`
+}
+
+type k8sContainer struct {
+	Name            string `yaml:"name"`
+	SecurityContext struct {
+		Privileged bool `yaml:"privileged"`
+	} `yaml:"securityContext"`
+	Resources struct {
+		Limits map[string]interface{} `yaml:"limits"`
+	} `yaml:"resources"`
+}
+
+// podSpec returns the pod template of a workload, or nil for other kinds
+func (o *k8sObject) podSpec() *k8sPodSpec {
+	switch o.Kind {
+	case "Pod":
+		return &o.Spec.k8sPodSpec
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		return &o.Spec.Template.Spec
+	case "CronJob":
+		return &o.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return nil
+}
+
+// CheckKubernetesManifests applies the policy rules to every workload in a
+// (possibly multi-document) YAML file. Files that aren't valid YAML or don't
+// contain Kubernetes objects produce no findings.
+func CheckKubernetesManifests(data []byte, file string, policy *KubernetesPolicy) []KubernetesPolicyFinding {
+	var findings []KubernetesPolicyFinding
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var obj k8sObject
+		err := decoder.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var typeErr *yaml.TypeError
+		if err != nil && !errors.As(err, &typeErr) {
+			break // Syntax error: not a manifest we can read
+		}
+		if obj.APIVersion == "" || obj.Kind == "" {
+			continue
+		}
+		if spec := obj.podSpec(); spec != nil {
+			findings = append(findings, checkPodSpec(&obj, spec, file, policy)...)
+		}
+	}
+	return findings
+}
+
+// checkPodSpec applies each enabled rule to a pod spec
+func checkPodSpec(obj *k8sObject, spec *k8sPodSpec, file string, policy *KubernetesPolicy) []KubernetesPolicyFinding {
+	var findings []KubernetesPolicyFinding
+	add := func(rule, container, message string) {
+		if severity := policy.severity(rule); severity != "" {
+			findings = append(findings, KubernetesPolicyFinding{
+				Rule:      rule,
+				Severity:  severity,
+				File:      file,
+				Kind:      obj.Kind,
+				Name:      obj.Metadata.Name,
+				Container: container,
+				Message:   message,
+			})
+		}
+	}
+
+	containers := append(append([]k8sContainer{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		if c.SecurityContext.Privileged {
+			add(RulePrivilegedContainer, c.Name, "container runs privileged")
+		}
+		var missing []string
+		for _, resource := range []string{"cpu", "memory"} {
+			if _, ok := c.Resources.Limits[resource]; !ok {
+				missing = append(missing, resource)
+			}
+		}
+		if len(missing) > 0 {
+			add(RuleMissingLimits, c.Name, "container has no "+strings.Join(missing, " or ")+" limit")
+		}
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			add(RuleHostPathVolume, "", "volume "+v.Name+" mounts a hostPath")
+		}
+	}
+	return findings
+}
@@ -0,0 +1,315 @@
+package parsers
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestKubernetesPolicyParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FindingSummary
+		wantErr bool
+	}{
+		{
+			name:  "no findings",
+			input: `{"findings": [], "total": 0}`,
+			want:  FindingSummary{},
+		},
+		{
+			name: "mixed severities",
+			input: `{"findings": [
+				{"rule": "K8S001", "severity": "high"},
+				{"rule": "K8S002", "severity": "high"},
+				{"rule": "K8S003", "severity": "medium"},
+				{"rule": "K8S003", "severity": "low"}
+			], "total": 4}`,
+			want: FindingSummary{High: 2, Medium: 1, Low: 1, Total: 4},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `not json`,
+			wantErr: true,
+		},
+	}
+
+	parser := &KubernetesPolicyParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+const riskyDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          securityContext:
+            privileged: true
+          resources:
+            limits:
+              cpu: 500m
+        - name: sidecar
+          resources:
+            limits:
+              cpu: 100m
+              memory: 64Mi
+      volumes:
+        - name: docker
+          hostPath:
+            path: /var/run/docker.sock
+`
+
+func TestCheckKubernetesManifests(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name   string
+		input  string
+		policy *KubernetesPolicy
+		want   []string // "rule/container/severity"
+	}{
+		{
+			name:  "privileged, hostPath and missing limits",
+			input: riskyDeployment,
+			want:  []string{"K8S001/app/high", "K8S003/app/medium", "K8S002//high"},
+		},
+		{
+			name: "cronjob pod template with multiple documents",
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: nightly
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+            - name: init
+          containers:
+            - name: job
+              resources:
+                limits: {cpu: 1, memory: 1Gi}
+`,
+			want: []string{"K8S003/init/medium"},
+		},
+		{
+			name:  "policy disables and re-ranks rules",
+			input: riskyDeployment,
+			policy: &KubernetesPolicy{Rules: map[string]KubernetesRulePolicy{
+				RuleHostPathVolume: {Enabled: &disabled},
+				RuleMissingLimits:  {Severity: "Low"},
+			}},
+			want: []string{"K8S001/app/high", "K8S003/app/low"},
+		},
+		{
+			name:  "non-kubernetes yaml",
+			input: "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n",
+			want:  nil,
+		},
+		{
+			name:  "unrendered template",
+			input: "apiVersion: v1\nkind: Pod\n{{- if .Values.enabled }}\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := CheckKubernetesManifests([]byte(tt.input), "deploy.yaml", tt.policy)
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.Rule+"/"+f.Container+"/"+f.Severity)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("findings = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("findings[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunKubernetesPolicyChecker(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("deploy/web.yaml", riskyDeployment)
+	write("charts/api/Chart.yaml", "apiVersion: v2\nname: api\n")
+	write("charts/api/templates/pod.yaml", "{{ not valid yaml until rendered }}\n")
+	write("charts/broken/Chart.yaml", "apiVersion: v2\nname: broken\n")
+
+	// Stand in for helm template: render the api chart, fail the broken one
+	render := func(chartDir string) ([]byte, error) {
+		if filepath.Base(chartDir) == "broken" {
+			return nil, errors.New("missing dependencies")
+		}
+		return []byte(`---
+# Source: api/templates/pod.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: api
+spec:
+  containers:
+    - name: api
+      securityContext:
+        privileged: true
+      resources:
+        limits: {cpu: 1, memory: 1Gi}
+`), nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "k8s.json")
+	output, err := RunKubernetesPolicyChecker(dir, outputPath, KubernetesCheckOptions{RenderChart: render})
+	if err != nil {
+		t.Fatalf("RunKubernetesPolicyChecker() error = %v", err)
+	}
+
+	if output.Total != 4 {
+		t.Errorf("Total = %d, want 4: %+v", output.Total, output.Findings)
+	}
+	wantChartFile := filepath.Join("charts", "api", "templates", "pod.yaml")
+	var chartFinding bool
+	for _, f := range output.Findings {
+		if f.File == wantChartFile && f.Rule == RulePrivilegedContainer && f.Name == "api" {
+			chartFinding = true
+		}
+	}
+	if !chartFinding {
+		t.Errorf("no privileged finding reported against %s: %+v", wantChartFile, output.Findings)
+	}
+	if len(output.SkippedCharts) != 1 || output.SkippedCharts[0].Path != filepath.Join("charts", "broken") {
+		t.Errorf("SkippedCharts = %+v, want charts/broken", output.SkippedCharts)
+	}
+
+	// The written output parses back to the same totals
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written KubernetesPolicyOutput
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	summary, err := (&KubernetesPolicyParser{}).Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Total != 4 || summary.High != 3 || summary.Medium != 1 {
+		t.Errorf("Parse(output) = %+v, want 3 high and 1 medium", summary)
+	}
+}
+
+func TestRunKubernetesPolicyCheckerSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	repoDir, outside := filepath.Join(dir, "repo"), filepath.Join(dir, "outside")
+	for _, name := range []string{"repo/deploy/web.yaml", "outside/host.yaml"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(riskyDeployment), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"repo/host":      outside,
+		"repo/leak.yaml": filepath.Join(outside, "host.yaml"),
+		"repo/mirror":    "deploy",
+		"repo/deploy/up": "..",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		follow bool
+		want   []string
+	}{
+		{name: "default", want: []string{"deploy/web.yaml"}},
+		{name: "follow_symlinks", follow: true, want: []string{"deploy/web.yaml", "mirror/web.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := RunKubernetesPolicyChecker(repoDir, filepath.Join(t.TempDir(), "k8s.json"), KubernetesCheckOptions{FollowSymlinks: tt.follow})
+			if err != nil {
+				t.Fatalf("RunKubernetesPolicyChecker() error = %v", err)
+			}
+			files := make(map[string]bool)
+			for _, f := range output.Findings {
+				files[filepath.ToSlash(f.File)] = true
+			}
+			var got []string
+			for file := range files {
+				got = append(got, file)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("files with findings = %v, want %v (nothing outside the repo)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadKubernetesPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid overrides", "rules:\n  K8S003:\n    severity: low\n  K8S002:\n    enabled: false\n", false},
+		{"unknown rule", "rules:\n  K8S999:\n    severity: low\n", true},
+		{"unknown severity", "rules:\n  K8S001:\n    severity: urgent\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadKubernetesPolicy(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadKubernetesPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package parsers
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ============================================================================
+// Kubescape Parser - Kubernetes manifest posture checks
+// ============================================================================
+
+// KubescapeParser parses Kubescape JSON results (kubescape scan --format json).
+// Kubescape checks Kubernetes manifests and Helm charts against control
+// frameworks such as NSA and MITRE. Each failed control counts once, however
+// many resources fail it.
+type KubescapeParser struct{}
+
+type kubescapeOutput struct {
+	SummaryDetails *struct {
+		Controls map[string]struct {
+			ScoreFactor float64 `json:"scoreFactor"`
+		} `json:"controls"`
+		ControlsSeverityCounters *struct {
+			Critical int `json:"criticalSeverity"`
+			High     int `json:"highSeverity"`
+			Medium   int `json:"mediumSeverity"`
+			Low      int `json:"lowSeverity"`
+		} `json:"controlsSeverityCounters"`
+	} `json:"summaryDetails"`
+	Results []struct {
+		Controls []struct {
+			ControlID     string          `json:"controlID"`
+			SeverityScore float64         `json:"severityScore"`
+			Status        json.RawMessage `json:"status"`
+		} `json:"controls"`
+	} `json:"results"`
+}
+
+func (p *KubescapeParser) Name() string { return "kubescape" }
+func (p *KubescapeParser) Type() string { return "IaC" }
+func (p *KubescapeParser) Icon() string { return iconKubescape }
+func (p *KubescapeParser) Describe() string {
+	return "Checks Kubernetes manifests and Helm charts against security frameworks such as NSA and MITRE ATT&CK."
+}
+
+func (p *KubescapeParser) SupportedLanguages() []string {
+	return []string{"kubernetes", "helm"}
+}
+
+// Parse reads Kubescape JSON and counts failed controls by severity. When the
+// framework summary carries per-severity counters they are used directly;
+// otherwise each control's severity score is mapped: 9-10=Critical, 7-8=High,
+// 4-6=Medium, 1-3=Low, below 1=Info.
+func (p *KubescapeParser) Parse(data []byte) (FindingSummary, error) {
+	var output kubescapeOutput
+	var summary FindingSummary
+
+	if err := json.Unmarshal(data, &output); err != nil {
+		return summary, err
+	}
+
+	// Fast path: the framework summary already counts failed controls
+	if output.SummaryDetails != nil && output.SummaryDetails.ControlsSeverityCounters != nil {
+		c := output.SummaryDetails.ControlsSeverityCounters
+		summary.Critical = c.Critical
+		summary.High = c.High
+		summary.Medium = c.Medium
+		summary.Low = c.Low
+		summary.Total = c.Critical + c.High + c.Medium + c.Low
+		return summary, nil
+	}
+
+	failed := make(map[string]float64)
+	for _, result := range output.Results {
+		for _, control := range result.Controls {
+			if kubescapeStatus(control.Status) != "failed" {
+				continue
+			}
+			score := control.SeverityScore
+			if score == 0 && output.SummaryDetails != nil {
+				score = output.SummaryDetails.Controls[control.ControlID].ScoreFactor
+			}
+			if prev, ok := failed[control.ControlID]; !ok || score > prev {
+				failed[control.ControlID] = score
+			}
+		}
+	}
+
+	for _, score := range failed {
+		summary.Total++
+		switch {
+		case score >= 9:
+			summary.Critical++
+		case score >= 7:
+			summary.High++
+		case score >= 4:
+			summary.Medium++
+		case score >= 1:
+			summary.Low++
+		default:
+			summary.Info++
+		}
+	}
+
+	return summary, nil
+}
+
+// kubescapeStatus reads a control status, which newer Kubescape versions
+// report as {"status": "failed"} and older ones as a plain string
+func kubescapeStatus(raw json.RawMessage) string {
+	var status string
+	if err := json.Unmarshal(raw, &status); err == nil {
+		return strings.ToLower(status)
+	}
+	var obj struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return strings.ToLower(obj.Status)
+	}
+	return ""
+}
+
+// Verify KubescapeParser implements IaCParser
+var _ IaCParser = (*KubescapeParser)(nil)
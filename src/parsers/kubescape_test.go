@@ -0,0 +1,79 @@
+package parsers
+
+import "testing"
+
+func TestKubescapeParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FindingSummary
+		wantErr bool
+	}{
+		{
+			name: "failed controls counted once by severity score",
+			input: `{"results": [
+				{"resourceID": "apps/v1/default/Deployment/web", "controls": [
+					{"controlID": "C-0057", "severityScore": 8, "status": {"status": "failed"}},
+					{"controlID": "C-0009", "severityScore": 7, "status": {"status": "passed"}},
+					{"controlID": "C-0016", "severityScore": 5, "status": {"status": "failed"}}
+				]},
+				{"resourceID": "apps/v1/default/Deployment/api", "controls": [
+					{"controlID": "C-0057", "severityScore": 8, "status": {"status": "failed"}},
+					{"controlID": "C-0045", "severityScore": 9, "status": "failed"},
+					{"controlID": "C-0034", "severityScore": 2, "status": "failed"}
+				]}
+			]}`,
+			want: FindingSummary{Critical: 1, High: 1, Medium: 1, Low: 1, Total: 4},
+		},
+		{
+			name: "passed and skipped controls ignored",
+			input: `{"results": [{"controls": [
+				{"controlID": "C-0057", "severityScore": 8, "status": {"status": "passed"}},
+				{"controlID": "C-0016", "severityScore": 5, "status": {"status": "skipped"}}
+			]}]}`,
+			want: FindingSummary{},
+		},
+		{
+			name: "score taken from summary controls when missing",
+			input: `{
+				"summaryDetails": {"controls": {"C-0057": {"controlID": "C-0057", "scoreFactor": 8}}},
+				"results": [{"controls": [{"controlID": "C-0057", "status": {"status": "failed"}}]}]
+			}`,
+			want: FindingSummary{High: 1, Total: 1},
+		},
+		{
+			name: "framework summary counters fast path",
+			input: `{
+				"summaryDetails": {
+					"controlsSeverityCounters": {"criticalSeverity": 1, "highSeverity": 3, "mediumSeverity": 10, "lowSeverity": 2}
+				},
+				"results": [{"controls": [{"controlID": "C-0057", "severityScore": 8, "status": {"status": "failed"}}]}]
+			}`,
+			want: FindingSummary{Critical: 1, High: 3, Medium: 10, Low: 2, Total: 16},
+		},
+		{
+			name:  "empty output",
+			input: `{}`,
+			want:  FindingSummary{},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `not json`,
+			wantErr: true,
+		},
+	}
+
+	parser := &KubescapeParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
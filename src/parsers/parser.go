@@ -2,14 +2,71 @@
 // security scanner output files.
 package parsers
 
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // FindingSummary holds parsed findings counts by severity for display
 type FindingSummary struct {
-	Critical int
-	High     int
-	Medium   int
-	Low      int
-	Info     int
-	Total    int
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Info     int `json:"info"`
+	Total    int `json:"total"`
+}
+
+// IsEmpty reports whether the summary has no findings at all
+func (s FindingSummary) IsEmpty() bool {
+	return s == FindingSummary{}
+}
+
+// count adds one finding of the given scanner-reported severity to the summary
+func (s *FindingSummary) count(severity string) {
+	s.Total++
+	switch normalizeSeverity(severity) {
+	case "critical":
+		s.Critical++
+	case "high":
+		s.High++
+	case "medium":
+		s.Medium++
+	case "low":
+		s.Low++
+	default:
+		s.Info++
+	}
+}
+
+// MarshalJSON encodes the summary without its zero counts, so a clean scan
+// is just {} in the run report
+func (s FindingSummary) MarshalJSON() ([]byte, error) {
+	type compact struct {
+		Critical int `json:"critical,omitempty"`
+		High     int `json:"high,omitempty"`
+		Medium   int `json:"medium,omitempty"`
+		Low      int `json:"low,omitempty"`
+		Info     int `json:"info,omitempty"`
+		Total    int `json:"total,omitempty"`
+	}
+	return json.Marshal(compact(s))
+}
+
+// UnmarshalJSON decodes both the compact form written by MarshalJSON and
+// the full form with every count present; omitted counts are zero.
+func (s *FindingSummary) UnmarshalJSON(data []byte) error {
+	type plain FindingSummary // drops the methods, avoiding recursion
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*s = FindingSummary(p)
+	return nil
 }
 
 // ResultParser is the base interface for all scanner result parsers.
@@ -18,7 +75,7 @@ type ResultParser interface {
 	// Parse reads scanner output and returns a summary of findings
 	Parse(data []byte) (FindingSummary, error)
 
-	// Type returns the scanner category: "SCA", "SAST", "Secrets", or "Reachability"
+	// Type returns the scanner category: "SCA", "SAST", "Secrets", "Reachability", or "IaC"
 	Type() string
 
 	// Icon returns an emoji icon for display
@@ -28,6 +85,146 @@ type ResultParser interface {
 	Name() string
 }
 
+// DescribableParser is an optional interface for parsers that can explain what
+// their scanner does. Descriptions are shown in --help and --preflight output.
+type DescribableParser interface {
+	ResultParser
+
+	// Describe returns a one-sentence description of the scanner
+	Describe() string
+}
+
+// LanguageParser is an optional interface for parsers that know which
+// languages their scanner applies to. It is a second source of truth next to
+// the scanner's languages in scanners.yaml, used to catch misconfiguration.
+type LanguageParser interface {
+	ResultParser
+
+	// SupportedLanguages returns the detected language names the scanner
+	// covers (as in scanners.yaml, e.g. "go"); empty means every language
+	SupportedLanguages() []string
+}
+
+// Finding is a single finding reported by a DetailedParser
+type Finding struct {
+	ID       string // Rule or advisory ID (e.g. "GHSA-xxxx", "K8S001")
+	Title    string // Short human-readable description
+	Severity string // Normalized severity: critical, high, medium, low, or info
+	File     string // File the finding is in, relative to the repo (empty for dependency findings)
+	Line     int    // Line in File (0 when unknown)
+	CWE      string // Weakness category, e.g. "CWE-22" (empty when the scanner doesn't report one)
+}
+
+// DetailedParser is an optional interface for parsers that can list individual
+// findings, not just count them. Exporters such as the KICS report use it.
+type DetailedParser interface {
+	ResultParser
+
+	// Findings reads scanner output and returns every finding it reports
+	Findings(data []byte) ([]Finding, error)
+}
+
+// RootedParser is an optional interface for DetailedParsers whose scanner
+// reports absolute file paths. FindingsUnder makes the paths of files under
+// root, the directory the scanner ran on, relative to it, so findings don't
+// depend on where the repository was checked out.
+type RootedParser interface {
+	DetailedParser
+
+	// FindingsUnder is Findings with file paths made relative to root
+	FindingsUnder(data []byte, root string) ([]Finding, error)
+}
+
+// ListFindings returns the findings p reads from data, with file paths
+// relative to root for RootedParsers. An empty root (unknown, e.g. for
+// results loaded with --parse-only) leaves paths as the scanner reported them.
+func ListFindings(p DetailedParser, data []byte, root string) ([]Finding, error) {
+	if rooted, ok := p.(RootedParser); ok {
+		return rooted.FindingsUnder(data, root)
+	}
+	return p.Findings(data)
+}
+
+// relativeTo returns file relative to root, in slash form. Relative paths,
+// files outside root and an empty root are returned unchanged.
+func relativeTo(root, file string) string {
+	if root == "" || !filepath.IsAbs(file) {
+		return file
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// MultiTypeParser is an optional interface for scanners that report several
+// kinds of findings in one output, such as trivy's vulnerabilities, secrets
+// and misconfigurations. Type() stays the primary type; the summary and the
+// coverage matrix use the per-type breakdown.
+type MultiTypeParser interface {
+	ResultParser
+
+	// Types returns every scan type the scanner reports, primary type first
+	Types() []string
+
+	// ParseByType reads scanner output and returns a summary for each of
+	// Types(), including types without findings
+	ParseByType(data []byte) (map[string]FindingSummary, error)
+}
+
+// ScanTypes returns the scan types a parser reports: Types() for a
+// MultiTypeParser, otherwise just Type()
+func ScanTypes(parser ResultParser) []string {
+	if multi, ok := parser.(MultiTypeParser); ok {
+		return multi.Types()
+	}
+	return []string{parser.Type()}
+}
+
+// SecurityRuleFilter is an optional interface for parsers of general-purpose
+// linters that also report security issues. It backs the security_rules_only
+// scanner setting.
+type SecurityRuleFilter interface {
+	ResultParser
+
+	// FilterSecurityRules returns the scanner output with only its
+	// security findings, and how many other findings were dropped
+	FilterSecurityRules(data []byte) ([]byte, int, error)
+}
+
+// SchemaVersionParser is an optional interface for parsers whose scanner
+// output declares a schema or tool version. A new output format can parse
+// without errors yet report zero findings, so output from a version the
+// parser wasn't tested against is flagged.
+type SchemaVersionParser interface {
+	ResultParser
+
+	// SchemaVersion returns the version the output declares ("" when it
+	// declares none) and whether the parser was tested against it
+	SchemaVersion(data []byte) (version string, tested bool)
+
+	// TestedSchemaVersions describes the tested versions for warnings,
+	// e.g. "0.x"
+	TestedSchemaVersions() string
+}
+
+// CheckSchemaVersion returns a warning when the output of a
+// SchemaVersionParser declares a version outside its tested range, or ""
+// when the version is tested, missing, or the parser doesn't check it.
+func CheckSchemaVersion(parser ResultParser, data []byte) string {
+	versioned, ok := parser.(SchemaVersionParser)
+	if !ok {
+		return ""
+	}
+	version, tested := versioned.SchemaVersion(data)
+	if version == "" || tested {
+		return ""
+	}
+	return fmt.Sprintf("output schema version %s is outside the tested range (%s): findings may be missed or miscounted",
+		version, versioned.TestedSchemaVersions())
+}
+
 // SCAParser interface for Software Composition Analysis scanners.
 // These analyze dependencies for known vulnerabilities.
 type SCAParser interface {
@@ -46,26 +243,116 @@ type SecretsParser interface {
 	ResultParser
 }
 
-// Registry maps scanner names to their parser implementations
-var registry = map[string]ResultParser{
-	"grype":           &GrypeParser{},
-	"osv-scanner":     &OSVScannerParser{},
-	"gosec":           &GosecParser{},
-	"trufflehog":      &TrufflehogParser{},
-	"binary-detector": &BinaryParser{},
-	"scorecard":       &ScorecardParser{},
-	"govulncheck":     &GovulncheckParser{},
+// IaCParser interface for infrastructure-as-code scanners.
+// These check deployment configuration such as Kubernetes manifests.
+type IaCParser interface {
+	ResultParser
+}
+
+// registry maps scanner names to their parser implementations.
+// Access is guarded by registryMu so parsers can be registered at runtime.
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ResultParser)
+)
+
+func init() {
+	MustRegister("grype", &GrypeParser{})
+	MustRegister("osv-scanner", &OSVScannerParser{})
+	MustRegister("gosec", &GosecParser{})
+	MustRegister("trufflehog", &TrufflehogParser{})
+	MustRegister("binary-detector", &BinaryParser{})
+	MustRegister("scorecard", &ScorecardParser{})
+	MustRegister("govulncheck", &GovulncheckParser{})
+	MustRegister("cargo-audit", &CargoAuditParser{})
+	MustRegister("kubernetes-policy-checker", &KubernetesPolicyParser{})
+	MustRegister("kubescape", &KubescapeParser{})
+	MustRegister("ruff", &RuffParser{})
+	MustRegister("trivy", &TrivyParser{})
+	MustRegister("sensitive-files", &SensitiveFilesParser{})
 }
 
 // Get returns the appropriate parser for a scanner name.
 // Returns nil and false if no parser is registered for that scanner.
 func Get(scannerName string) (ResultParser, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	parser, ok := registry[scannerName]
 	return parser, ok
 }
 
+// Names returns the names of all registered parsers in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe returns a one-sentence description of the named scanner, or ""
+// if it has no registered parser or the parser doesn't implement DescribableParser.
+func Describe(name string) string {
+	parser, ok := Get(name)
+	if !ok {
+		return ""
+	}
+	if d, ok := parser.(DescribableParser); ok {
+		return d.Describe()
+	}
+	return ""
+}
+
+// SupportedLanguages returns the languages the named scanner supports and
+// true, or false if it has no registered parser or the parser doesn't
+// implement LanguageParser. An empty list with true means every language.
+func SupportedLanguages(name string) ([]string, bool) {
+	parser, ok := Get(name)
+	if !ok {
+		return nil, false
+	}
+	if l, ok := parser.(LanguageParser); ok {
+		return l.SupportedLanguages(), true
+	}
+	return nil, false
+}
+
 // Register adds a new parser to the registry.
-// Use this to register custom parsers at runtime.
-func Register(name string, parser ResultParser) {
+// Returns an error if a parser is already registered under that name;
+// use RegisterOrReplace when overriding an existing parser is intentional.
+func Register(name string, parser ResultParser) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("parser %q already registered", name)
+	}
+	registry[name] = parser
+	return nil
+}
+
+// MustRegister is like Register but panics if the name is already taken.
+// Intended for init() where a collision is a programming error.
+func MustRegister(name string, parser ResultParser) {
+	if err := Register(name, parser); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterOrReplace adds a parser to the registry, overwriting any existing
+// parser registered under the same name.
+func RegisterOrReplace(name string, parser ResultParser) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	registry[name] = parser
 }
+
+// Unregister removes a parser from the registry. It is a no-op if no parser
+// is registered under that name. Mainly useful for test cleanup.
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, name)
+}
@@ -1,6 +1,11 @@
 package parsers
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
 
 func TestGet(t *testing.T) {
 	registered := []struct {
@@ -16,6 +21,11 @@ func TestGet(t *testing.T) {
 		{name: "binary-detector", wantName: "binary-detector", wantType: "Binary", wantIconNE: true},
 		{name: "scorecard", wantName: "scorecard", wantType: "Scorecard", wantIconNE: true},
 		{name: "govulncheck", wantName: "govulncheck", wantType: "Reachability", wantIconNE: true},
+		{name: "kubernetes-policy-checker", wantName: "kubernetes-policy-checker", wantType: "IaC", wantIconNE: true},
+		{name: "kubescape", wantName: "kubescape", wantType: "IaC", wantIconNE: true},
+		{name: "ruff", wantName: "ruff", wantType: "SAST", wantIconNE: true},
+		{name: "trivy", wantName: "trivy", wantType: "SCA", wantIconNE: true},
+		{name: "sensitive-files", wantName: "sensitive-files", wantType: "Secrets", wantIconNE: true},
 	}
 
 	for _, tt := range registered {
@@ -46,3 +56,282 @@ func TestGet(t *testing.T) {
 		}
 	})
 }
+
+// stubParser is a minimal ResultParser for registry tests
+type stubParser struct{ name string }
+
+func (p *stubParser) Parse(data []byte) (FindingSummary, error) { return FindingSummary{}, nil }
+func (p *stubParser) Type() string                              { return "SAST" }
+func (p *stubParser) Icon() string                              { return "🔧" }
+func (p *stubParser) Name() string                              { return p.name }
+
+func TestRegister(t *testing.T) {
+	t.Run("new name succeeds", func(t *testing.T) {
+		t.Cleanup(func() { Unregister("test-register-new") })
+		if err := Register("test-register-new", &stubParser{name: "test-register-new"}); err != nil {
+			t.Fatalf("Register() error = %v, want nil", err)
+		}
+		if _, ok := Get("test-register-new"); !ok {
+			t.Error("Get() after Register returned ok=false, want true")
+		}
+	})
+
+	t.Run("duplicate name returns error", func(t *testing.T) {
+		if err := Register("grype", &stubParser{name: "grype"}); err == nil {
+			t.Fatal("Register(grype) error = nil, want duplicate error")
+		}
+		parser, _ := Get("grype")
+		if _, isStub := parser.(*stubParser); isStub {
+			t.Error("Register(grype) replaced the built-in parser")
+		}
+	})
+
+	t.Run("MustRegister panics on duplicate", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("MustRegister(grype) did not panic")
+			}
+		}()
+		MustRegister("grype", &stubParser{name: "grype"})
+	})
+
+	t.Run("RegisterOrReplace overrides", func(t *testing.T) {
+		t.Cleanup(func() { Unregister("test-register-replace") })
+		RegisterOrReplace("test-register-replace", &stubParser{name: "first"})
+		RegisterOrReplace("test-register-replace", &stubParser{name: "second"})
+		parser, ok := Get("test-register-replace")
+		if !ok || parser.Name() != "second" {
+			t.Errorf("Get() after RegisterOrReplace = %v, want parser named second", parser)
+		}
+	})
+
+	t.Run("Unregister removes parser", func(t *testing.T) {
+		MustRegister("test-register-remove", &stubParser{name: "test-register-remove"})
+		Unregister("test-register-remove")
+		if _, ok := Get("test-register-remove"); ok {
+			t.Error("Get() after Unregister returned ok=true, want false")
+		}
+		// Unregistering an unknown name is a no-op
+		Unregister("test-register-remove")
+	})
+}
+
+func TestRegisteredParserIcons(t *testing.T) {
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("Names() returned no registered parsers")
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			parser, ok := Get(name)
+			if !ok {
+				t.Fatalf("Get(%q) returned ok=false for a name from Names()", name)
+			}
+			icon := parser.Icon()
+			if icon == "" {
+				t.Fatal("Icon() is empty")
+			}
+			if !utf8.ValidString(icon) {
+				t.Errorf("Icon() = %q is not valid UTF-8", icon)
+			}
+			if strings.ContainsRune(icon, utf8.RuneError) {
+				t.Errorf("Icon() = %q contains the Unicode replacement character", icon)
+			}
+			// Mojibake (UTF-8 bytes decoded as Latin-1/CP1252 and re-encoded) shows up
+			// as runs of Latin-1 Supplement characters such as "ğŸ›¡ï¸"
+			for _, r := range icon {
+				if r >= 0x80 && r <= 0x24F {
+					t.Errorf("Icon() = %q contains %U, which looks like mis-encoded UTF-8", icon, r)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Errorf("Names() not sorted: %q before %q", names[i-1], names[i])
+		}
+	}
+	for _, want := range []string{"grype", "scorecard", "trufflehog"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Names() missing %q", want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			desc := Describe(name)
+			if desc == "" {
+				t.Fatalf("Describe(%q) is empty; implement DescribableParser", name)
+			}
+			if !strings.HasSuffix(desc, ".") || strings.Count(desc, ". ") > 0 {
+				t.Errorf("Describe(%q) = %q, want a single sentence ending in a period", name, desc)
+			}
+		})
+	}
+
+	t.Run("unknown scanner", func(t *testing.T) {
+		if got := Describe("nonexistent"); got != "" {
+			t.Errorf("Describe(nonexistent) = %q, want empty", got)
+		}
+	})
+
+	t.Run("parser without description", func(t *testing.T) {
+		if err := Register("undescribed", &stubParser{name: "undescribed"}); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { Unregister("undescribed") })
+		if got := Describe("undescribed"); got != "" {
+			t.Errorf("Describe(undescribed) = %q, want empty", got)
+		}
+	})
+}
+
+func TestSupportedLanguages(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   []string
+		wantOK bool
+	}{
+		{name: "gosec", want: []string{"go"}, wantOK: true},
+		{name: "cargo-audit", want: []string{"rust"}, wantOK: true},
+		{name: "ruff", want: []string{"python"}, wantOK: true},
+		{name: "kubescape", want: []string{"kubernetes", "helm"}, wantOK: true},
+		{name: "trufflehog", want: nil, wantOK: true}, // universal
+		{name: "grype", wantOK: false},                // languages left to scanners.yaml
+		{name: "nonexistent", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SupportedLanguages(tt.name)
+			if ok != tt.wantOK {
+				t.Fatalf("SupportedLanguages(%q) ok = %v, want %v", tt.name, ok, tt.wantOK)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SupportedLanguages(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindingSummaryMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary FindingSummary
+		want    string
+	}{
+		{"zero", FindingSummary{}, `{}`},
+		{"partial", FindingSummary{High: 2, Low: 1, Total: 3}, `{"high":2,"low":1,"total":3}`},
+		{
+			"full",
+			FindingSummary{Critical: 1, High: 2, Medium: 3, Low: 4, Info: 5, Total: 15},
+			`{"critical":1,"high":2,"medium":3,"low":4,"info":5,"total":15}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.summary)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+
+			// Round trip through a containing struct, as in the run report
+			var back struct{ Summary FindingSummary }
+			if err := json.Unmarshal([]byte(`{"Summary":`+string(got)+`}`), &back); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if back.Summary != tt.summary {
+				t.Errorf("round trip = %+v, want %+v", back.Summary, tt.summary)
+			}
+		})
+	}
+}
+
+func TestFindingSummaryUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FindingSummary
+		wantErr bool
+	}{
+		{"zero", `{}`, FindingSummary{}, false},
+		{"partial", `{"critical":1,"total":1}`, FindingSummary{Critical: 1, Total: 1}, false},
+		{
+			"full with zero counts",
+			`{"critical":0,"high":2,"medium":0,"low":0,"info":1,"total":3}`,
+			FindingSummary{High: 2, Info: 1, Total: 3},
+			false,
+		},
+		{"invalid", `{"critical":"many"}`, FindingSummary{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindingSummary{Critical: 99} // must be overwritten, not merged
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindingSummaryIsEmpty(t *testing.T) {
+	if !(FindingSummary{}).IsEmpty() {
+		t.Error("zero summary IsEmpty() = false, want true")
+	}
+	if (FindingSummary{Info: 1, Total: 1}).IsEmpty() {
+		t.Error("summary with findings IsEmpty() = true, want false")
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		parser      ResultParser
+		data        string
+		wantWarning bool
+	}{
+		{name: "grype tested version", parser: &GrypeParser{}, data: `{"matches": [], "descriptor": {"name": "grype", "version": "0.108.0"}}`},
+		{name: "grype untested major version", parser: &GrypeParser{}, data: `{"matches": [], "descriptor": {"name": "grype", "version": "1.0.0"}}`, wantWarning: true},
+		{name: "grype without descriptor", parser: &GrypeParser{}, data: `{"matches": []}`},
+		{name: "trivy tested schema", parser: &TrivyParser{}, data: `{"SchemaVersion": 2, "Results": []}`},
+		{name: "trivy untested schema", parser: &TrivyParser{}, data: `{"SchemaVersion": 3, "Results": []}`, wantWarning: true},
+		{name: "trivy without schema", parser: &TrivyParser{}, data: `{"Results": []}`},
+		{name: "parser without schema check", parser: &GosecParser{}, data: `{"Issues": [], "SchemaVersion": 9}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := CheckSchemaVersion(tt.parser, []byte(tt.data))
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("CheckSchemaVersion() = %q, want warning %v", warning, tt.wantWarning)
+			}
+			if tt.wantWarning && !strings.Contains(warning, "tested range") {
+				t.Errorf("warning %q doesn't name the tested range", warning)
+			}
+		})
+	}
+}
@@ -33,7 +33,14 @@ type govulncheckMessage struct {
 
 func (p *GovulncheckParser) Name() string { return "govulncheck" }
 func (p *GovulncheckParser) Type() string { return "Reachability" }
-func (p *GovulncheckParser) Icon() string { return "🔬" }
+func (p *GovulncheckParser) Icon() string { return iconGovulncheck }
+func (p *GovulncheckParser) Describe() string {
+	return "Reports Go vulnerabilities whose affected functions are actually reachable from your code."
+}
+
+func (p *GovulncheckParser) SupportedLanguages() []string {
+	return []string{"go"}
+}
 
 func (p *GovulncheckParser) Parse(data []byte) (FindingSummary, error) {
 	var summary FindingSummary
@@ -194,6 +201,21 @@ func (idx ReachabilityIndex) ExpandWithAliasGroups(groups [][]string) {
 	}
 }
 
+// Merge adds the entries of other to the index and returns the result, which
+// is other itself when idx is nil. Reachable status wins on conflicts.
+func (idx ReachabilityIndex) Merge(other ReachabilityIndex) ReachabilityIndex {
+	if idx == nil {
+		return other
+	}
+	for id, reachable := range other {
+		if existing, ok := idx[id]; ok && existing {
+			continue
+		}
+		idx[id] = reachable
+	}
+	return idx
+}
+
 // Lookup returns the reachability status for a vulnerability ID.
 // Returns (reachable, known): known=false if the ID is not in the index.
 func (idx ReachabilityIndex) Lookup(vulnID string) (reachable, known bool) {
@@ -374,3 +374,36 @@ func TestReachabilityIndex_Lookup(t *testing.T) {
 		}
 	})
 }
+
+func TestReachabilityIndex_Merge(t *testing.T) {
+	tests := []struct {
+		name  string
+		idx   ReachabilityIndex
+		other ReachabilityIndex
+		want  ReachabilityIndex
+	}{
+		{"both nil", nil, nil, nil},
+		{"nil receiver returns other", nil, ReachabilityIndex{"A": true}, ReachabilityIndex{"A": true}},
+		{"nil other keeps index", ReachabilityIndex{"A": false}, nil, ReachabilityIndex{"A": false}},
+		{
+			name:  "reachable wins on conflict",
+			idx:   ReachabilityIndex{"A": true, "B": false},
+			other: ReachabilityIndex{"A": false, "B": true, "C": false},
+			want:  ReachabilityIndex{"A": true, "B": true, "C": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.idx.Merge(tt.other)
+			if len(got) != len(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Fatalf("Merge() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("Merge()[%q] = %v, want %v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
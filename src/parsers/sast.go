@@ -2,6 +2,7 @@ package parsers
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 )
 
@@ -24,7 +25,14 @@ type gosecOutput struct {
 
 func (p *GosecParser) Name() string { return "gosec" }
 func (p *GosecParser) Type() string { return "SAST" }
-func (p *GosecParser) Icon() string { return "🔍" }
+func (p *GosecParser) Icon() string { return iconGosec }
+func (p *GosecParser) Describe() string {
+	return "Inspects Go source code for security problems such as injection, weak crypto, and unsafe file handling."
+}
+
+func (p *GosecParser) SupportedLanguages() []string {
+	return []string{"go"}
+}
 
 func (p *GosecParser) Parse(data []byte) (FindingSummary, error) {
 	var output gosecOutput
@@ -49,5 +57,155 @@ func (p *GosecParser) Parse(data []byte) (FindingSummary, error) {
 	return summary, nil
 }
 
-// Verify GosecParser implements SASTParser
-var _ SASTParser = (*GosecParser)(nil)
+// gosecOutputFull holds the gosec issue fields needed to list findings
+type gosecOutputFull struct {
+	Issues []struct {
+		Severity string `json:"severity"`
+		RuleID   string `json:"rule_id"`
+		Details  string `json:"details"`
+		File     string `json:"file"`
+		Line     string `json:"line"` // "42", or a range like "42-44"
+		CWE      struct {
+			ID string `json:"id"`
+		} `json:"cwe"`
+	} `json:"Issues"`
+}
+
+// Findings lists each gosec issue with its rule, location and CWE, with the
+// absolute file paths gosec reports
+func (p *GosecParser) Findings(data []byte) ([]Finding, error) {
+	return p.FindingsUnder(data, "")
+}
+
+// FindingsUnder lists each gosec issue like Findings, with the paths of files
+// under root (the directory gosec ran on) made relative to it
+func (p *GosecParser) FindingsUnder(data []byte, root string) ([]Finding, error) {
+	var output gosecOutputFull
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(output.Issues))
+	for _, issue := range output.Issues {
+		line, _ := strconv.Atoi(strings.SplitN(issue.Line, "-", 2)[0])
+		findings = append(findings, Finding{
+			ID:       issue.RuleID,
+			Title:    issue.Details,
+			Severity: normalizeSeverity(issue.Severity),
+			File:     relativeTo(root, issue.File),
+			Line:     line,
+			CWE:      normalizeCWE(issue.CWE.ID),
+		})
+	}
+	return findings, nil
+}
+
+// Verify GosecParser implements SASTParser and RootedParser
+var (
+	_ SASTParser   = (*GosecParser)(nil)
+	_ RootedParser = (*GosecParser)(nil)
+)
+
+// ============================================================================
+// Ruff Parser - Python Linter
+// ============================================================================
+
+// RuffParser parses Ruff lint results (--output-format=json).
+// Ruff is a general Python linter; its S rules (from flake8-bandit) report
+// security issues and are rated High. pycodestyle errors and warnings (E, W)
+// are Low, and every other rule, including syntax errors without a code, is
+// Info.
+type RuffParser struct{}
+
+type ruffFinding struct {
+	Code *string `json:"code"` // null for syntax errors
+}
+
+func (p *RuffParser) Name() string { return "ruff" }
+func (p *RuffParser) Type() string { return "SAST" }
+func (p *RuffParser) Icon() string { return iconRuff }
+func (p *RuffParser) Describe() string {
+	return "Lints Python code, including flake8-bandit security rules such as hardcoded passwords, shell injection, and unsafe deserialization."
+}
+
+func (p *RuffParser) SupportedLanguages() []string {
+	return []string{"python"}
+}
+
+func (p *RuffParser) Parse(data []byte) (FindingSummary, error) {
+	var findings []ruffFinding
+	var summary FindingSummary
+
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return summary, err
+	}
+
+	for _, finding := range findings {
+		summary.Total++
+		switch ruffSeverity(finding.Code) {
+		case "high":
+			summary.High++
+		case "low":
+			summary.Low++
+		default:
+			summary.Info++
+		}
+	}
+
+	return summary, nil
+}
+
+// FilterSecurityRules drops every finding that isn't from an S (security)
+// rule and returns the remaining output and how many findings were dropped
+func (p *RuffParser) FilterSecurityRules(data []byte) ([]byte, int, error) {
+	var findings []json.RawMessage
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, 0, err
+	}
+
+	kept := make([]json.RawMessage, 0, len(findings))
+	for _, raw := range findings {
+		var finding ruffFinding
+		if err := json.Unmarshal(raw, &finding); err != nil {
+			return nil, 0, err
+		}
+		if ruffRuleHasPrefix(finding.Code, 'S') {
+			kept = append(kept, raw)
+		}
+	}
+
+	filtered, err := json.Marshal(kept)
+	if err != nil {
+		return nil, 0, err
+	}
+	return filtered, len(findings) - len(kept), nil
+}
+
+// ruffSeverity rates a Ruff rule code: high for S (security) rules, low for
+// E and W (pycodestyle), info for everything else
+func ruffSeverity(code *string) string {
+	switch {
+	case ruffRuleHasPrefix(code, 'S'):
+		return "high"
+	case ruffRuleHasPrefix(code, 'E'), ruffRuleHasPrefix(code, 'W'):
+		return "low"
+	default:
+		return "info"
+	}
+}
+
+// ruffRuleHasPrefix reports whether code is a rule of the single-letter
+// linter prefix: the letter followed by digits, so "S101" matches 'S' but
+// flake8-simplify's "SIM102" and eradicate's "ERA001" don't
+func ruffRuleHasPrefix(code *string, prefix byte) bool {
+	if code == nil || len(*code) < 2 || (*code)[0] != prefix {
+		return false
+	}
+	return (*code)[1] >= '0' && (*code)[1] <= '9'
+}
+
+// Verify RuffParser implements SASTParser and SecurityRuleFilter
+var (
+	_ SASTParser         = (*RuffParser)(nil)
+	_ SecurityRuleFilter = (*RuffParser)(nil)
+)
@@ -1,6 +1,9 @@
 package parsers
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestGosecParser_Parse(t *testing.T) {
 	tests := []struct {
@@ -73,3 +76,194 @@ func TestGosecParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestGosecParser_Findings(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		root    string
+		want    []Finding
+		wantErr bool
+	}{
+		{
+			name: "issue with CWE",
+			input: `{"Issues": [{"severity": "HIGH", "confidence": "HIGH", "cwe": {"id": "22", "url": "https://cwe.mitre.org/data/definitions/22.html"},
+				"rule_id": "G304", "details": "Potential file inclusion via variable", "file": "/work/org/app/main.go", "line": "42"}]}`,
+			want: []Finding{{ID: "G304", Title: "Potential file inclusion via variable", Severity: "high", File: "/work/org/app/main.go", Line: 42, CWE: "CWE-22"}},
+		},
+		{
+			name:  "path relative to scan root",
+			input: `{"Issues": [{"severity": "HIGH", "rule_id": "G304", "details": "Potential file inclusion via variable", "file": "/work/org/app/cmd/main.go", "line": "42"}]}`,
+			root:  "/work/org/app",
+			want:  []Finding{{ID: "G304", Title: "Potential file inclusion via variable", Severity: "high", File: "cmd/main.go", Line: 42}},
+		},
+		{
+			name:  "path outside scan root",
+			input: `{"Issues": [{"severity": "HIGH", "rule_id": "G304", "details": "Potential file inclusion via variable", "file": "/work/org/other/main.go", "line": "42"}]}`,
+			root:  "/work/org/app",
+			want:  []Finding{{ID: "G304", Title: "Potential file inclusion via variable", Severity: "high", File: "/work/org/other/main.go", Line: 42}},
+		},
+		{
+			name:  "line range and no CWE",
+			input: `{"Issues": [{"severity": "LOW", "rule_id": "G104", "details": "Errors unhandled.", "file": "util.go", "line": "10-12"}]}`,
+			want:  []Finding{{ID: "G104", Title: "Errors unhandled.", Severity: "low", File: "util.go", Line: 10}},
+		},
+		{
+			name:  "no issues",
+			input: `{"Issues": [], "Stats": {"found": 0}}`,
+			want:  []Finding{},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `not json`,
+			wantErr: true,
+		},
+	}
+
+	parser := &GosecParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListFindings(parser, []byte(tt.input), tt.root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Findings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Findings() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Findings()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRuffParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FindingSummary
+		wantErr bool
+	}{
+		{
+			name:  "no findings",
+			input: `[]`,
+			want:  FindingSummary{},
+		},
+		{
+			name: "security rules are high",
+			input: `[
+				{"code": "S101", "filename": "/repo/app.py", "location": {"row": 3, "column": 1}, "message": "Use of assert detected"},
+				{"code": "S602", "filename": "/repo/app.py", "location": {"row": 9, "column": 5}, "message": "subprocess call with shell=True"}
+			]`,
+			want: FindingSummary{High: 2, Total: 2},
+		},
+		{
+			name: "pycodestyle errors and warnings are low",
+			input: `[
+				{"code": "E501", "message": "Line too long"},
+				{"code": "W291", "message": "Trailing whitespace"}
+			]`,
+			want: FindingSummary{Low: 2, Total: 2},
+		},
+		{
+			name: "other rules and syntax errors are info",
+			input: `[
+				{"code": "F401", "message": "os imported but unused"},
+				{"code": "SIM102", "message": "Use a single if statement"},
+				{"code": "ERA001", "message": "Found commented-out code"},
+				{"code": null, "message": "SyntaxError: Expected an expression"}
+			]`,
+			want: FindingSummary{Info: 4, Total: 4},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `not json`,
+			wantErr: true,
+		},
+		{
+			name:    "object instead of array",
+			input:   `{"code": "S101"}`,
+			wantErr: true,
+		},
+	}
+
+	parser := &RuffParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuffParser_FilterSecurityRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantCodes   []string
+		wantDropped int
+		wantErr     bool
+	}{
+		{
+			name:      "no findings",
+			input:     `[]`,
+			wantCodes: []string{},
+		},
+		{
+			name: "keeps only S rules",
+			input: `[
+				{"code": "S105", "message": "Possible hardcoded password"},
+				{"code": "E501", "message": "Line too long"},
+				{"code": "SIM102", "message": "Use a single if statement"},
+				{"code": null, "message": "SyntaxError"},
+				{"code": "S301", "message": "pickle deserialization"}
+			]`,
+			wantCodes:   []string{"S105", "S301"},
+			wantDropped: 3,
+		},
+		{
+			name:    "invalid JSON",
+			input:   `[{"code": `,
+			wantErr: true,
+		},
+	}
+
+	parser := &RuffParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, dropped, err := parser.FilterSecurityRules([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FilterSecurityRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if dropped != tt.wantDropped {
+				t.Errorf("dropped = %d, want %d", dropped, tt.wantDropped)
+			}
+			var kept []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(filtered, &kept); err != nil {
+				t.Fatalf("filtered output is not a JSON array: %v\n%s", err, filtered)
+			}
+			if len(kept) != len(tt.wantCodes) {
+				t.Fatalf("kept %d findings, want %v", len(kept), tt.wantCodes)
+			}
+			for i, code := range tt.wantCodes {
+				if kept[i].Code != code || kept[i].Message == "" {
+					t.Errorf("kept[%d] = %+v, want code %s with its message", i, kept[i], code)
+				}
+			}
+		})
+	}
+}
@@ -11,19 +11,76 @@ import (
 
 // GrypeParser parses Anchore Grype SCA scan results.
 // Grype analyzes container images and filesystems for vulnerabilities.
-type GrypeParser struct{}
+type GrypeParser struct {
+	// ExcludeDevDeps leaves matches in dev-scoped dependencies (see
+	// isDevDependency) out of the counts and findings
+	ExcludeDevDeps bool
+}
 
 type grypeOutput struct {
 	Matches []struct {
 		Vulnerability struct {
 			Severity string `json:"severity"`
 		} `json:"vulnerability"`
+		Artifact struct {
+			Metadata map[string]any `json:"metadata"`
+		} `json:"artifact"`
 	} `json:"matches"`
 }
 
+// devScopes are the dependency scope values, lowercased, that mark a
+// dev-only dependency
+var devScopes = map[string]bool{"dev": true, "development": true, "test": true}
+
+// isDevDependency reports whether a grype artifact's package metadata marks
+// it as a dev-only dependency: "dev": true (npm lockfiles), or a "scope" or
+// "category" of dev, development or test (Maven, Poetry). Grype only carries
+// this metadata for some ecosystems and often not at all, so a package
+// without it counts as runtime; the split and ExcludeDevDeps are unsupported
+// there. The keys are matched against hand-written fixtures, not captured
+// grype output.
+func isDevDependency(metadata map[string]any) bool {
+	if dev, ok := metadata["dev"].(bool); ok && dev {
+		return true
+	}
+	for _, key := range []string{"scope", "category"} {
+		if scope, ok := metadata[key].(string); ok && devScopes[strings.ToLower(scope)] {
+			return true
+		}
+	}
+	return false
+}
+
+// DependencyScopes counts grype matches in runtime and dev-scoped
+// dependencies, whether or not ExcludeDevDeps is set
+type DependencyScopes struct {
+	Runtime int
+	Dev     int
+}
+
+// DependencyScopes splits the matches in grype output by dependency scope
+func (p *GrypeParser) DependencyScopes(data []byte) (DependencyScopes, error) {
+	var output grypeOutput
+	var scopes DependencyScopes
+	if err := json.Unmarshal(data, &output); err != nil {
+		return scopes, err
+	}
+	for _, match := range output.Matches {
+		if isDevDependency(match.Artifact.Metadata) {
+			scopes.Dev++
+		} else {
+			scopes.Runtime++
+		}
+	}
+	return scopes, nil
+}
+
 func (p *GrypeParser) Name() string { return "grype" }
 func (p *GrypeParser) Type() string { return "SCA" }
-func (p *GrypeParser) Icon() string { return "📦" }
+func (p *GrypeParser) Icon() string { return iconGrype }
+func (p *GrypeParser) Describe() string {
+	return "Matches packages in the generated SBOM against known vulnerability databases."
+}
 
 func (p *GrypeParser) Parse(data []byte) (FindingSummary, error) {
 	var output grypeOutput
@@ -34,6 +91,9 @@ func (p *GrypeParser) Parse(data []byte) (FindingSummary, error) {
 	}
 
 	for _, match := range output.Matches {
+		if p.ExcludeDevDeps && isDevDependency(match.Artifact.Metadata) {
+			continue
+		}
 		summary.Total++
 		switch strings.ToLower(match.Vulnerability.Severity) {
 		case "critical":
@@ -52,8 +112,69 @@ func (p *GrypeParser) Parse(data []byte) (FindingSummary, error) {
 	return summary, nil
 }
 
-// Verify GrypeParser implements SCAParser
-var _ SCAParser = (*GrypeParser)(nil)
+// Findings lists each grype match with the vulnerable package
+func (p *GrypeParser) Findings(data []byte) ([]Finding, error) {
+	var output grypeOutputFull
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(output.Matches))
+	for _, match := range output.Matches {
+		if p.ExcludeDevDeps && isDevDependency(match.Artifact.Metadata) {
+			continue
+		}
+		finding := Finding{
+			ID:       match.Vulnerability.ID,
+			Title:    packageFindingTitle(match.Vulnerability.ID, match.Artifact.Name, match.Artifact.Version),
+			Severity: normalizeSeverity(match.Vulnerability.Severity),
+		}
+		if len(match.Artifact.Locations) > 0 {
+			finding.File = strings.TrimPrefix(match.Artifact.Locations[0].Path, "/")
+		}
+		for _, related := range match.RelatedVulnerabilities {
+			if strings.HasPrefix(strings.ToUpper(related.ID), "CWE-") {
+				finding.CWE = normalizeCWE(related.ID)
+				break
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// grypeTestedMajor is the grype major version whose JSON output the parser
+// was tested against
+const grypeTestedMajor = "0"
+
+// SchemaVersion returns the grype version in the output's descriptor. Grype
+// doesn't version its JSON schema separately, so the tool version stands in;
+// it is tested when its major version is grypeTestedMajor.
+func (p *GrypeParser) SchemaVersion(data []byte) (string, bool) {
+	var output struct {
+		Descriptor struct {
+			Version string `json:"version"`
+		} `json:"descriptor"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return "", true
+	}
+	version := strings.TrimPrefix(output.Descriptor.Version, "v")
+	if version == "" {
+		return "", true
+	}
+	major, _, _ := strings.Cut(version, ".")
+	return version, major == grypeTestedMajor
+}
+
+func (p *GrypeParser) TestedSchemaVersions() string { return grypeTestedMajor + ".x" }
+
+// Verify GrypeParser implements SCAParser, DetailedParser and SchemaVersionParser
+var (
+	_ SCAParser           = (*GrypeParser)(nil)
+	_ DetailedParser      = (*GrypeParser)(nil)
+	_ SchemaVersionParser = (*GrypeParser)(nil)
+)
 
 // ============================================================================
 // OSV-Scanner Parser - Google OSV Scanner
@@ -65,7 +186,10 @@ type OSVScannerParser struct{}
 
 func (p *OSVScannerParser) Name() string { return "osv-scanner" }
 func (p *OSVScannerParser) Type() string { return "SCA" }
-func (p *OSVScannerParser) Icon() string { return "🔎" }
+func (p *OSVScannerParser) Icon() string { return iconOSVScanner }
+func (p *OSVScannerParser) Describe() string {
+	return "Checks dependency lockfiles against the Open Source Vulnerabilities (OSV) database."
+}
 
 func (p *OSVScannerParser) Parse(data []byte) (FindingSummary, error) {
 	var output osvOutputFull
@@ -99,8 +223,128 @@ func (p *OSVScannerParser) Parse(data []byte) (FindingSummary, error) {
 	return summary, nil
 }
 
-// Verify OSVScannerParser implements SCAParser
-var _ SCAParser = (*OSVScannerParser)(nil)
+// Findings lists each osv-scanner vulnerability group with the vulnerable package
+func (p *OSVScannerParser) Findings(data []byte) ([]Finding, error) {
+	var output osvOutputFull
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, result := range output.Results {
+		for _, pkg := range result.Packages {
+			vulnMap := buildVulnSeverityMap(pkg.Vulnerabilities)
+			for _, group := range pkg.Groups {
+				if len(group.IDs) == 0 {
+					continue
+				}
+				findings = append(findings, Finding{
+					ID:       group.IDs[0],
+					Title:    packageFindingTitle(group.IDs[0], pkg.Package.Name, pkg.Package.Version),
+					Severity: resolveGroupSeverity(group.MaxSeverity, group.Aliases, vulnMap),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// packageFindingTitle describes a vulnerable dependency, e.g. "GHSA-xxxx in lodash@4.17.20"
+func packageFindingTitle(id, name, version string) string {
+	if name == "" {
+		return id
+	}
+	if version != "" {
+		name += "@" + version
+	}
+	return id + " in " + name
+}
+
+// Verify OSVScannerParser implements SCAParser and DetailedParser
+var (
+	_ SCAParser      = (*OSVScannerParser)(nil)
+	_ DetailedParser = (*OSVScannerParser)(nil)
+)
+
+// ============================================================================
+// Cargo Audit Parser - RustSec Advisory Scanner
+// ============================================================================
+
+// CargoAuditParser parses `cargo audit --json` results for Rust projects.
+// Vulnerabilities are rated by their explicit severity, else by CVSS, else Medium.
+// Warnings (unmaintained, yanked, unsound crates) are counted as Info.
+type CargoAuditParser struct{}
+
+type cargoAuditOutput struct {
+	Vulnerabilities struct {
+		List []struct {
+			Advisory struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+				CVSS     string `json:"cvss"`
+			} `json:"advisory"`
+		} `json:"list"`
+	} `json:"vulnerabilities"`
+	Warnings map[string][]json.RawMessage `json:"warnings"` // keyed by kind: unmaintained, yanked, unsound, ...
+}
+
+func (p *CargoAuditParser) Name() string { return "cargo-audit" }
+func (p *CargoAuditParser) Type() string { return "SCA" }
+func (p *CargoAuditParser) Icon() string { return iconCargoAudit }
+func (p *CargoAuditParser) Describe() string {
+	return "Audits Rust Cargo.lock dependencies against the RustSec advisory database."
+}
+
+func (p *CargoAuditParser) SupportedLanguages() []string {
+	return []string{"rust"}
+}
+
+func (p *CargoAuditParser) Parse(data []byte) (FindingSummary, error) {
+	var output cargoAuditOutput
+	var summary FindingSummary
+
+	if err := json.Unmarshal(data, &output); err != nil {
+		return summary, err
+	}
+
+	for _, vuln := range output.Vulnerabilities.List {
+		summary.Total++
+		switch cargoAuditSeverity(vuln.Advisory.Severity, vuln.Advisory.CVSS) {
+		case "critical":
+			summary.Critical++
+		case "high":
+			summary.High++
+		case "medium":
+			summary.Medium++
+		case "low":
+			summary.Low++
+		default:
+			summary.Info++
+		}
+	}
+
+	for _, warnings := range output.Warnings {
+		summary.Total += len(warnings)
+		summary.Info += len(warnings)
+	}
+
+	return summary, nil
+}
+
+// cargoAuditSeverity resolves an advisory's severity: the explicit severity when
+// set, otherwise derived from its CVSS score or vector, otherwise medium.
+func cargoAuditSeverity(severity, cvss string) string {
+	if norm := normalizeSeverity(severity); norm != "info" {
+		return norm
+	}
+	if sev, ok := severityFromCVSS(cvss); ok {
+		return sev
+	}
+	return "medium"
+}
+
+// Verify CargoAuditParser implements SCAParser
+var _ SCAParser = (*CargoAuditParser)(nil)
 
 // ============================================================================
 // SCA Finding Extraction & Reachability Cross-Reference
@@ -110,6 +354,7 @@ var _ SCAParser = (*OSVScannerParser)(nil)
 type SCAFinding struct {
 	IDs      []string // All vulnerability IDs (CVE, GHSA, etc.)
 	Severity string   // Normalized severity: critical, high, medium, low, or info
+	Dev      bool     // In a dev-scoped dependency (grype only)
 }
 
 // EnrichedSummary extends FindingSummary with per-severity reachable counts
@@ -131,6 +376,17 @@ type grypeOutputFull struct {
 			ID       string `json:"id"`
 			Severity string `json:"severity"`
 		} `json:"vulnerability"`
+		RelatedVulnerabilities []struct {
+			ID string `json:"id"`
+		} `json:"relatedVulnerabilities"`
+		Artifact struct {
+			Name      string `json:"name"`
+			Version   string `json:"version"`
+			Locations []struct {
+				Path string `json:"path"`
+			} `json:"locations"`
+			Metadata map[string]any `json:"metadata"`
+		} `json:"artifact"`
 	} `json:"matches"`
 }
 
@@ -146,6 +402,7 @@ func ExtractGrypeFindings(data []byte) ([]SCAFinding, error) {
 		findings = append(findings, SCAFinding{
 			IDs:      []string{match.Vulnerability.ID},
 			Severity: normalizeSeverity(match.Vulnerability.Severity),
+			Dev:      isDevDependency(match.Artifact.Metadata),
 		})
 	}
 	return findings, nil
@@ -153,10 +410,17 @@ func ExtractGrypeFindings(data []byte) ([]SCAFinding, error) {
 
 // osvGroup represents a vulnerability group in osv-scanner output.
 // ids contains only the primary advisory ID(s); aliases contains all IDs including CVE/GHSA aliases.
+// experimentalAnalysis is only present with --experimental-call-analysis (keyed by vulnerability ID).
 type osvGroup struct {
-	IDs         []string `json:"ids"`
-	Aliases     []string `json:"aliases"`
-	MaxSeverity string   `json:"max_severity"`
+	IDs                  []string                   `json:"ids"`
+	Aliases              []string                   `json:"aliases"`
+	MaxSeverity          string                     `json:"max_severity"`
+	ExperimentalAnalysis map[string]osvCallAnalysis `json:"experimentalAnalysis"`
+}
+
+// osvCallAnalysis is osv-scanner's call-analysis verdict for one vulnerability
+type osvCallAnalysis struct {
+	Called bool `json:"called"`
 }
 
 // osvVulnerability represents a single vulnerability record embedded in osv-scanner output.
@@ -172,6 +436,10 @@ type osvVulnerability struct {
 type osvOutputFull struct {
 	Results []struct {
 		Packages []struct {
+			Package struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"package"`
 			Groups          []osvGroup         `json:"groups"`
 			Vulnerabilities []osvVulnerability `json:"vulnerabilities"`
 		} `json:"packages"`
@@ -341,6 +609,66 @@ func ExtractOSVScannerAliasGroups(data []byte) [][]string {
 	return groups
 }
 
+// BuildOSVCallAnalysisIndex builds a reachability index from osv-scanner's own
+// call analysis (groups[].experimentalAnalysis). A group is reachable when any
+// of its vulnerabilities is called, and the verdict applies to all its IDs and
+// aliases. Returns nil when the output has no call-analysis data, so findings
+// keep their plain counts.
+func BuildOSVCallAnalysisIndex(data []byte) ReachabilityIndex {
+	var output osvOutputFull
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil
+	}
+
+	var idx ReachabilityIndex
+	for _, result := range output.Results {
+		for _, pkg := range result.Packages {
+			for _, group := range pkg.Groups {
+				if len(group.ExperimentalAnalysis) == 0 {
+					continue
+				}
+				called := false
+				for _, analysis := range group.ExperimentalAnalysis {
+					if analysis.Called {
+						called = true
+						break
+					}
+				}
+
+				if idx == nil {
+					idx = make(ReachabilityIndex)
+				}
+				ids := append(append([]string{}, group.IDs...), group.Aliases...)
+				for id := range group.ExperimentalAnalysis {
+					ids = append(ids, id)
+				}
+				for _, id := range ids {
+					// Reachable wins when an ID appears in several groups
+					if existing, ok := idx[id]; ok && existing {
+						continue
+					}
+					idx[id] = called
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// normalizeCWE formats a CWE identifier as "CWE-<n>", accepting a bare
+// number ("22", as gosec reports it) or any casing of the prefix. Returns ""
+// for an empty id.
+func normalizeCWE(id string) string {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ""
+	}
+	if len(id) > 4 && strings.EqualFold(id[:4], "CWE-") {
+		id = id[4:]
+	}
+	return "CWE-" + id
+}
+
 // normalizeSeverity converts a severity string to lowercase canonical form.
 func normalizeSeverity(s string) string {
 	switch strings.ToLower(s) {
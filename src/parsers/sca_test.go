@@ -1,6 +1,9 @@
 package parsers
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestGrypeParser_Parse(t *testing.T) {
 	tests := []struct {
@@ -129,6 +132,84 @@ func TestExtractGrypeFindings(t *testing.T) {
 	}
 }
 
+func TestGrypeDevDependencies(t *testing.T) {
+	// Two runtime matches (one without metadata), three dev-scoped ones
+	input := []byte(`{"matches": [
+		{"vulnerability": {"id": "CVE-1", "severity": "Critical"}, "artifact": {"name": "express", "metadata": {"dev": false}}},
+		{"vulnerability": {"id": "CVE-2", "severity": "High"}, "artifact": {"name": "openssl"}},
+		{"vulnerability": {"id": "CVE-3", "severity": "High"}, "artifact": {"name": "jest", "metadata": {"dev": true}}},
+		{"vulnerability": {"id": "CVE-4", "severity": "Medium"}, "artifact": {"name": "junit", "metadata": {"scope": "Test"}}},
+		{"vulnerability": {"id": "CVE-5", "severity": "Low"}, "artifact": {"name": "pytest", "metadata": {"category": "dev"}}}
+	]}`)
+
+	tests := []struct {
+		name         string
+		exclude      bool
+		wantSummary  FindingSummary
+		wantFindings []string
+	}{
+		{
+			name:         "dev dependencies counted",
+			wantSummary:  FindingSummary{Critical: 1, High: 2, Medium: 1, Low: 1, Total: 5},
+			wantFindings: []string{"CVE-1", "CVE-2", "CVE-3", "CVE-4", "CVE-5"},
+		},
+		{
+			name:         "dev dependencies excluded",
+			exclude:      true,
+			wantSummary:  FindingSummary{Critical: 1, High: 1, Total: 2},
+			wantFindings: []string{"CVE-1", "CVE-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &GrypeParser{ExcludeDevDeps: tt.exclude}
+			summary, err := parser.Parse(input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if summary != tt.wantSummary {
+				t.Errorf("Parse() = %+v, want %+v", summary, tt.wantSummary)
+			}
+
+			findings, err := parser.Findings(input)
+			if err != nil {
+				t.Fatalf("Findings() error = %v", err)
+			}
+			var ids []string
+			for _, f := range findings {
+				ids = append(ids, f.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantFindings, ",") {
+				t.Errorf("Findings() IDs = %v, want %v", ids, tt.wantFindings)
+			}
+
+			// The scope split doesn't depend on ExcludeDevDeps
+			scopes, err := parser.DependencyScopes(input)
+			if err != nil {
+				t.Fatalf("DependencyScopes() error = %v", err)
+			}
+			if want := (DependencyScopes{Runtime: 2, Dev: 3}); scopes != want {
+				t.Errorf("DependencyScopes() = %+v, want %+v", scopes, want)
+			}
+		})
+	}
+
+	findings, err := ExtractGrypeFindings(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dev []string
+	for _, f := range findings {
+		if f.Dev {
+			dev = append(dev, f.IDs[0])
+		}
+	}
+	if got := strings.Join(dev, ","); got != "CVE-3,CVE-4,CVE-5" {
+		t.Errorf("ExtractGrypeFindings() dev findings = %s, want CVE-3,CVE-4,CVE-5", got)
+	}
+}
+
 func TestExtractOSVScannerFindings(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -198,6 +279,58 @@ func TestExtractOSVScannerFindings(t *testing.T) {
 	}
 }
 
+func TestSCAParserFindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser DetailedParser
+		input  string
+		want   []Finding
+	}{
+		{
+			name:   "grype match with location",
+			parser: &GrypeParser{},
+			input: `{"matches": [{"vulnerability": {"id": "GHSA-1", "severity": "Medium"},
+				"artifact": {"name": "lodash", "version": "4.17.20", "locations": [{"path": "/package-lock.json"}]}}]}`,
+			want: []Finding{{ID: "GHSA-1", Title: "GHSA-1 in lodash@4.17.20", Severity: "medium", File: "package-lock.json"}},
+		},
+		{
+			name:   "grype CWE from related vulnerabilities",
+			parser: &GrypeParser{},
+			input: `{"matches": [{"vulnerability": {"id": "GHSA-2", "severity": "High"},
+				"relatedVulnerabilities": [{"id": "CVE-2020-8203"}, {"id": "cwe-1321"}],
+				"artifact": {"name": "lodash", "version": "4.17.15"}}]}`,
+			want: []Finding{{ID: "GHSA-2", Title: "GHSA-2 in lodash@4.17.15", Severity: "high", CWE: "CWE-1321"}},
+		},
+		{
+			name:   "osv group resolves severity from aliases",
+			parser: &OSVScannerParser{},
+			input: `{"results": [{"packages": [{
+				"package": {"name": "golang.org/x/net", "version": "0.1.0"},
+				"groups": [{"ids": ["GO-2023-0001"], "aliases": ["GO-2023-0001", "GHSA-2"], "max_severity": ""}, {"ids": []}],
+				"vulnerabilities": [{"id": "GHSA-2", "database_specific": {"severity": "HIGH"}}]
+			}]}]}`,
+			want: []Finding{{ID: "GO-2023-0001", Title: "GO-2023-0001 in golang.org/x/net@0.1.0", Severity: "high"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parser.Findings([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Findings() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Findings() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Findings()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func TestCrossReferenceReachability(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -355,6 +488,67 @@ func TestExtractOSVScannerAliasGroups(t *testing.T) {
 	}
 }
 
+func TestBuildOSVCallAnalysisIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  ReachabilityIndex
+	}{
+		{
+			name: "no call analysis returns nil",
+			input: `{"results": [{"packages": [{"groups": [
+				{"ids": ["GO-2024-0001"], "aliases": ["CVE-2024-1111"], "max_severity": "HIGH"}
+			]}]}]}`,
+			want: nil,
+		},
+		{
+			name: "called and uncalled groups",
+			input: `{"results": [{"packages": [{"groups": [
+				{"ids": ["GO-2024-0001"], "aliases": ["GO-2024-0001", "CVE-2024-1111"],
+				 "experimentalAnalysis": {"GO-2024-0001": {"called": true}}},
+				{"ids": ["GO-2024-0002"], "aliases": ["GO-2024-0002", "GHSA-aaaa-bbbb-cccc"],
+				 "experimentalAnalysis": {"GO-2024-0002": {"called": false}}},
+				{"ids": ["GHSA-dddd-eeee-ffff"], "max_severity": "LOW"}
+			]}]}]}`,
+			want: ReachabilityIndex{
+				"GO-2024-0001":        true,
+				"CVE-2024-1111":       true,
+				"GO-2024-0002":        false,
+				"GHSA-aaaa-bbbb-cccc": false,
+			},
+		},
+		{
+			name: "any called vulnerability makes the group reachable",
+			input: `{"results": [{"packages": [{"groups": [
+				{"ids": ["GO-2024-0003"], "experimentalAnalysis": {
+					"GO-2024-0003": {"called": false},
+					"GHSA-gggg-hhhh-iiii": {"called": true}
+				}}
+			]}]}]}`,
+			want: ReachabilityIndex{"GO-2024-0003": true, "GHSA-gggg-hhhh-iiii": true},
+		},
+		{
+			name:  "invalid JSON returns nil",
+			input: `{invalid`,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildOSVCallAnalysisIndex([]byte(tt.input))
+			if (got == nil) != (tt.want == nil) || len(got) != len(tt.want) {
+				t.Fatalf("BuildOSVCallAnalysisIndex() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if reachable, known := got.Lookup(id); !known || reachable != want {
+					t.Errorf("Lookup(%q) = (%v, %v), want (%v, true)", id, reachable, known, want)
+				}
+			}
+		})
+	}
+}
+
 func TestOSVScannerParser_Parse(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -407,6 +601,14 @@ func TestOSVScannerParser_Parse(t *testing.T) {
 			]}]}]}`,
 			want: FindingSummary{Info: 1, Total: 1},
 		},
+		{
+			name: "call analysis does not change counts",
+			input: `{"results": [{"packages": [{"groups": [
+				{"ids": ["GO-2024-0001"], "max_severity": "HIGH", "experimentalAnalysis": {"GO-2024-0001": {"called": false}}},
+				{"ids": ["GO-2024-0002"], "max_severity": "CRITICAL", "experimentalAnalysis": {"GO-2024-0002": {"called": true}}}
+			]}]}]}`,
+			want: FindingSummary{Critical: 1, High: 1, Total: 2},
+		},
 		{
 			name: "moderate severity maps to medium",
 			input: `{"results": [{"packages": [{"groups": [
@@ -467,3 +669,86 @@ func TestOSVScannerParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestCargoAuditParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FindingSummary
+		wantErr bool
+	}{
+		{
+			name: "empty report",
+			input: `{
+				"database": {"advisory-count": 600},
+				"vulnerabilities": {"found": false, "count": 0, "list": []},
+				"warnings": {}
+			}`,
+			want: FindingSummary{},
+		},
+		{
+			name: "vulnerabilities with explicit severity, CVSS, and neither",
+			input: `{
+				"vulnerabilities": {"found": true, "count": 5, "list": [
+					{"advisory": {"id": "RUSTSEC-2024-0001", "severity": "high", "cvss": null}},
+					{"advisory": {"id": "RUSTSEC-2024-0002", "cvss": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+					{"advisory": {"id": "RUSTSEC-2024-0003", "cvss": "CVSS:3.1/AV:L/AC:H/PR:L/UI:R/S:U/C:L/I:N/A:N"}},
+					{"advisory": {"id": "RUSTSEC-2024-0004", "cvss": "5.3"}},
+					{"advisory": {"id": "RUSTSEC-2024-0005"}}
+				]},
+				"warnings": {}
+			}`,
+			want: FindingSummary{Critical: 1, High: 1, Medium: 2, Low: 1, Total: 5},
+		},
+		{
+			name: "warnings only",
+			input: `{
+				"vulnerabilities": {"found": false, "count": 0, "list": []},
+				"warnings": {
+					"unmaintained": [{"kind": "unmaintained", "package": {"name": "ansi_term"}}],
+					"yanked": [{"kind": "yanked", "package": {"name": "foo"}}, {"kind": "yanked", "package": {"name": "bar"}}]
+				}
+			}`,
+			want: FindingSummary{Info: 3, Total: 3},
+		},
+		{
+			name: "vulnerabilities and warnings",
+			input: `{
+				"vulnerabilities": {"list": [{"advisory": {"id": "RUSTSEC-2023-0001", "severity": "critical"}}]},
+				"warnings": {"unsound": [{"kind": "unsound"}]}
+			}`,
+			want: FindingSummary{Critical: 1, Info: 1, Total: 2},
+		},
+		{
+			name:    "invalid JSON",
+			input:   `not json`,
+			wantErr: true,
+		},
+	}
+
+	parser := &CargoAuditParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCargoAuditParser_Metadata(t *testing.T) {
+	p := &CargoAuditParser{}
+	if p.Name() != "cargo-audit" {
+		t.Errorf("Name() = %q, want %q", p.Name(), "cargo-audit")
+	}
+	if p.Type() != "SCA" {
+		t.Errorf("Type() = %q, want %q", p.Type(), "SCA")
+	}
+	if _, ok := Get("cargo-audit"); !ok {
+		t.Error("cargo-audit parser is not registered")
+	}
+}
@@ -13,7 +13,21 @@ import (
 
 // ScorecardParser parses OpenSSF Scorecard results.
 // Scorecard assesses open source project security practices.
-type ScorecardParser struct{}
+type ScorecardParser struct {
+	// ExcludedChecks are check names (e.g. "CII-Best-Practices") left out of
+	// the finding counts and the detailed report, matched case-insensitively
+	ExcludedChecks []string
+}
+
+// excluded reports whether the named check is in ExcludedChecks
+func (p *ScorecardParser) excluded(name string) bool {
+	for _, check := range p.ExcludedChecks {
+		if strings.EqualFold(check, name) {
+			return true
+		}
+	}
+	return false
+}
 
 type scorecardOutput struct {
 	Date      string  `json:"date"`
@@ -30,10 +44,18 @@ type scorecardOutput struct {
 
 func (p *ScorecardParser) Name() string { return "scorecard" }
 func (p *ScorecardParser) Type() string { return "Scorecard" }
-func (p *ScorecardParser) Icon() string { return "🛡️" }
+func (p *ScorecardParser) Icon() string { return iconScorecard }
+func (p *ScorecardParser) Describe() string {
+	return "Rates the repository's security practices (branch protection, CI, maintenance) with OpenSSF Scorecard."
+}
+
+func (p *ScorecardParser) SupportedLanguages() []string {
+	return nil
+}
 
 // Parse reads scorecard JSON and returns a summary.
-// Scores are mapped: 0-3=Critical, 4-5=High, 6-7=Medium, 8-9=Low, 10=pass (Info)
+// Scores are mapped: 0-3=Critical, 4-5=High, 6-7=Medium, 8-9=Low, 10=pass (Info).
+// Excluded checks aren't counted.
 func (p *ScorecardParser) Parse(data []byte) (FindingSummary, error) {
 	var output scorecardOutput
 	var summary FindingSummary
@@ -44,7 +66,7 @@ func (p *ScorecardParser) Parse(data []byte) (FindingSummary, error) {
 
 	for _, check := range output.Checks {
 		// Skip checks that returned -1 (inconclusive/not applicable)
-		if check.Score < 0 {
+		if check.Score < 0 || p.excluded(check.Name) {
 			continue
 		}
 		summary.Total++
@@ -68,9 +90,25 @@ func (p *ScorecardParser) Parse(data []byte) (FindingSummary, error) {
 // Verify ScorecardParser implements ResultParser
 var _ ResultParser = (*ScorecardParser)(nil)
 
-// PrintScorecardReport prints a detailed scorecard report to stdout.
-// This provides human-readable output beyond the standard summary.
-func PrintScorecardReport(outputPath string) error {
+// ScorecardScore returns the overall score (0-10) from scorecard JSON output.
+// Scorecard reports -1 when no check was conclusive.
+func ScorecardScore(data []byte) (float64, error) {
+	var output scorecardOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return 0, err
+	}
+	return output.Score, nil
+}
+
+// PrintScorecardReport prints a detailed scorecard report to stdout using the
+// given theme's symbols. This provides human-readable output beyond the standard summary.
+func PrintScorecardReport(outputPath string, theme Theme) error {
+	return (&ScorecardParser{}).PrintReport(outputPath, theme)
+}
+
+// PrintReport is PrintScorecardReport without the parser's excluded checks.
+// The overall score is scorecard's own and still includes them.
+func (p *ScorecardParser) PrintReport(outputPath string, theme Theme) error {
 	data, err := os.ReadFile(outputPath)
 	if err != nil {
 		return err
@@ -92,9 +130,12 @@ func PrintScorecardReport(outputPath string) error {
 		cyan   = "\033[36m"
 	)
 
-	fmt.Printf("\n%s%s════════════════════════════════════════════════════════════════%s\n", cyan, bold, reset)
-	fmt.Printf("%s%s 🛡️  OpenSSF Scorecard Report %s\n", bold, cyan, reset)
-	fmt.Printf("%s%s════════════════════════════════════════════════════════════════%s\n", cyan, bold, reset)
+	separator := strings.Repeat(theme.Separator, 64)
+	thinSeparator := strings.Repeat(theme.ThinSeparator, 60)
+
+	fmt.Printf("\n%s%s%s%s\n", cyan, bold, separator, reset)
+	fmt.Printf("%s%s %s  OpenSSF Scorecard Report %s\n", bold, cyan, theme.ScorecardIcon, reset)
+	fmt.Printf("%s%s%s%s\n", cyan, bold, separator, reset)
 
 	// Overall score with color
 	scoreColor := red
@@ -107,24 +148,30 @@ func PrintScorecardReport(outputPath string) error {
 	fmt.Printf("  %sScorecard Version:%s %s\n", dim, reset, output.Scorecard.Version)
 
 	fmt.Printf("\n  %s%sIndividual Checks:%s\n", bold, cyan, reset)
-	fmt.Printf("  %s────────────────────────────────────────────────────────────%s\n", dim, reset)
+	fmt.Printf("  %s%s%s\n", dim, thinSeparator, reset)
 
+	var excluded []string
 	for _, check := range output.Checks {
+		if p.excluded(check.Name) {
+			excluded = append(excluded, check.Name)
+			continue
+		}
+
 		// Color based on score
 		color := red
-		icon := "🔴"
+		icon := theme.Critical
 		if check.Score < 0 {
 			color = dim
-			icon = "⚪"
+			icon = theme.Info
 		} else if check.Score >= 8 {
 			color = green
-			icon = "🟢"
+			icon = theme.Low
 		} else if check.Score >= 5 {
 			color = yellow
-			icon = "🟡"
+			icon = theme.Medium
 		} else if check.Score >= 3 {
 			color = yellow
-			icon = "🟠"
+			icon = theme.High
 		}
 
 		scoreStr := fmt.Sprintf("%2d", check.Score)
@@ -139,7 +186,10 @@ func PrintScorecardReport(outputPath string) error {
 			dim, truncateReason(check.Reason, 40), reset)
 	}
 
-	fmt.Printf("  %s────────────────────────────────────────────────────────────%s\n\n", dim, reset)
+	if len(excluded) > 0 {
+		fmt.Printf("  %sExcluded: %s%s\n", dim, strings.Join(excluded, ", "), reset)
+	}
+	fmt.Printf("  %s%s%s\n\n", dim, thinSeparator, reset)
 
 	return nil
 }
@@ -1,6 +1,11 @@
 package parsers
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestScorecardParser_Parse(t *testing.T) {
 	tests := []struct {
@@ -101,6 +106,55 @@ func TestScorecardParser_Parse(t *testing.T) {
 	}
 }
 
+func TestScorecardExcludedChecks(t *testing.T) {
+	data := `{"score": 5.5, "checks": [
+		{"name": "Maintained", "score": 10, "reason": "active"},
+		{"name": "CII-Best-Practices", "score": 0, "reason": "no badge detected"},
+		{"name": "Fuzzing", "score": 0, "reason": "project is not fuzzed"},
+		{"name": "Code-Review", "score": 4, "reason": "some changesets reviewed"}
+	]}`
+
+	tests := []struct {
+		name     string
+		excluded []string
+		want     FindingSummary
+	}{
+		{name: "nothing excluded", want: FindingSummary{Critical: 2, High: 1, Info: 1, Total: 4}},
+		{name: "excluded check not counted", excluded: []string{"CII-Best-Practices"}, want: FindingSummary{Critical: 1, High: 1, Info: 1, Total: 3}},
+		{name: "names match case-insensitively", excluded: []string{"cii-best-practices", "fuzzing"}, want: FindingSummary{High: 1, Info: 1, Total: 2}},
+		{name: "unknown check ignored", excluded: []string{"Not-A-Check"}, want: FindingSummary{Critical: 2, High: 1, Info: 1, Total: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &ScorecardParser{ExcludedChecks: tt.excluded}
+			got, err := parser.Parse([]byte(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "scorecard.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	parser := &ScorecardParser{ExcludedChecks: []string{"CII-Best-Practices"}}
+	out := captureStdout(t, func() {
+		if err := parser.PrintReport(path, PlainTheme); err != nil {
+			t.Errorf("PrintReport() error = %v", err)
+		}
+	})
+	if strings.Contains(out, "no badge detected") || !strings.Contains(out, "Excluded: CII-Best-Practices") {
+		t.Errorf("report should list CII-Best-Practices only as excluded:\n%s", out)
+	}
+	if !strings.Contains(out, "Fuzzing") {
+		t.Errorf("report is missing the remaining checks:\n%s", out)
+	}
+}
+
 func TestTruncateReason(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -21,7 +21,14 @@ type trufflehogFinding struct {
 
 func (p *TrufflehogParser) Name() string { return "trufflehog" }
 func (p *TrufflehogParser) Type() string { return "Secrets" }
-func (p *TrufflehogParser) Icon() string { return "🔑" }
+func (p *TrufflehogParser) Icon() string { return iconTrufflehog }
+func (p *TrufflehogParser) Describe() string {
+	return "Finds hardcoded secrets and credentials, verifying whether they are still live."
+}
+
+func (p *TrufflehogParser) SupportedLanguages() []string {
+	return nil
+}
 
 func (p *TrufflehogParser) Parse(data []byte) (FindingSummary, error) {
 	var summary FindingSummary
@@ -0,0 +1,237 @@
+package parsers
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ============================================================================
+// Sensitive Files Detector - Flags committed credential and key files
+// ============================================================================
+
+// SensitiveFilesParser parses sensitive-files detector results.
+// The detector flags files whose names suggest committed credentials, such
+// as .env files and private keys, without looking at their contents.
+type SensitiveFilesParser struct{}
+
+// SensitiveFilesOutput represents the JSON output of the sensitive-files detector
+type SensitiveFilesOutput struct {
+	Files []SensitiveFile `json:"files"`
+	Total int             `json:"total"`
+}
+
+// SensitiveFile is one committed file matching a sensitive filename pattern
+type SensitiveFile struct {
+	Path    string `json:"path"`    // Relative to the repo
+	Pattern string `json:"pattern"` // The pattern the file matched
+	Size    int64  `json:"size"`
+}
+
+func (p *SensitiveFilesParser) Name() string { return "sensitive-files" }
+func (p *SensitiveFilesParser) Type() string { return "Secrets" }
+func (p *SensitiveFilesParser) Icon() string { return iconSensitive }
+func (p *SensitiveFilesParser) Describe() string {
+	return "Flags committed files whose names suggest credentials, such as .env files, private keys and credentials.json."
+}
+
+func (p *SensitiveFilesParser) SupportedLanguages() []string {
+	return nil
+}
+
+// Parse counts sensitive files. They are unverified, since only the name
+// is checked, so each counts as Medium (shown as "Unverified" like
+// trufflehog's unverified secrets).
+func (p *SensitiveFilesParser) Parse(data []byte) (FindingSummary, error) {
+	var output SensitiveFilesOutput
+	var summary FindingSummary
+
+	if err := json.Unmarshal(data, &output); err != nil {
+		return summary, err
+	}
+
+	summary.Total = output.Total
+	summary.Medium = output.Total
+	return summary, nil
+}
+
+// Findings lists each sensitive file
+func (p *SensitiveFilesParser) Findings(data []byte) ([]Finding, error) {
+	var output SensitiveFilesOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(output.Files))
+	for _, f := range output.Files {
+		findings = append(findings, Finding{
+			ID:       sensitiveFileRule,
+			Title:    "Sensitive file committed (matches " + f.Pattern + ")",
+			Severity: "medium",
+			File:     f.Path,
+			CWE:      "CWE-538",
+		})
+	}
+	return findings, nil
+}
+
+// Verify SensitiveFilesParser implements SecretsParser
+var _ SecretsParser = (*SensitiveFilesParser)(nil)
+
+// ============================================================================
+// Sensitive Files Detector Scanner Logic
+// ============================================================================
+
+// sensitiveFileRule is the rule ID of sensitive-files findings in SARIF and
+// detailed output
+const sensitiveFileRule = "SENSITIVE001"
+
+// DefaultSensitivePatterns are the filename patterns the detector flags
+// unless replaced. Patterns without a / match the file name at any depth;
+// patterns with one match the path relative to the repo. Matching is
+// case-insensitive.
+var DefaultSensitivePatterns = []string{
+	".env", ".env.*", "*.env",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519",
+	"*.pem", "*.key", "*.p12", "*.pfx", "*.jks", "*.keystore",
+	"credentials.json", "service-account*.json",
+	".npmrc", ".pypirc", ".netrc", ".git-credentials", ".htpasswd",
+	"*.kdbx", "terraform.tfstate",
+}
+
+// DefaultSensitiveExcludes are patterns for templates and examples that
+// match a sensitive pattern but hold no real secrets
+var DefaultSensitiveExcludes = []string{
+	"*.example", "*.sample", "*.template", "*.dist",
+}
+
+// SensitiveFilesOptions configures RunSensitiveFilesDetector
+type SensitiveFilesOptions struct {
+	// Patterns are the sensitive filename patterns (DefaultSensitivePatterns
+	// unless the scanner args replace them)
+	Patterns []string
+
+	// Excludes are patterns for files never flagged, checked first
+	Excludes []string
+
+	// SarifMode writes SARIF instead of JSON
+	SarifMode bool
+
+	// Ignore lists paths to skip (a parsed .allscanignore); nil skips none
+	Ignore *IgnorePatterns
+
+	// FollowSymlinks descends into symlinked directories inside the repo
+	// (see WalkRepo); symlinks resolving outside it are skipped
+	FollowSymlinks bool
+}
+
+// RunSensitiveFilesDetector walks the repository, including hidden
+// directories other than .git, flags files matching a sensitive pattern and
+// writes JSON or SARIF output. Returns the count of files found.
+func RunSensitiveFilesDetector(repoPath, outputPath string, opts SensitiveFilesOptions) (int, error) {
+	files := []SensitiveFile{}
+
+	err := WalkRepo(repoPath, opts.FollowSymlinks, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+		relPath, _ := filepath.Rel(repoPath, p)
+
+		if d.IsDir() {
+			if p != repoPath && (d.Name() == ".git" || opts.Ignore.Match(relPath, true)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || opts.Ignore.Match(relPath, false) {
+			return nil
+		}
+
+		if matchSensitivePattern(opts.Excludes, relPath) != "" {
+			return nil
+		}
+		pattern := matchSensitivePattern(opts.Patterns, relPath)
+		if pattern == "" {
+			return nil
+		}
+		size := int64(0)
+		if info, err := d.Info(); err == nil {
+			size = info.Size()
+		}
+		files = append(files, SensitiveFile{Path: relPath, Pattern: pattern, Size: size})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var data []byte
+	if opts.SarifMode {
+		data, err = json.MarshalIndent(sensitiveFilesSarif(files), "", "  ")
+	} else {
+		data, err = json.MarshalIndent(SensitiveFilesOutput{Files: files, Total: len(files)}, "", "  ")
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(outputPath, data, 0640); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// matchSensitivePattern returns the first pattern matching relPath: its file
+// name for patterns without a /, otherwise the whole path. Returns "" when
+// none matches.
+func matchSensitivePattern(patterns []string, relPath string) string {
+	relPath = strings.ToLower(filepath.ToSlash(relPath))
+	name := path.Base(relPath)
+	for _, pattern := range patterns {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = relPath
+		}
+		if ok, _ := path.Match(strings.ToLower(pattern), target); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// sensitiveFilesSarif builds the SARIF log for the detected files
+func sensitiveFilesSarif(files []SensitiveFile) sarifLog {
+	results := make([]sarifResult, 0, len(files))
+	for _, f := range files {
+		results = append(results, sarifResult{
+			RuleID:  sensitiveFileRule,
+			Level:   "warning",
+			Message: sarifMessage{Text: "Sensitive file committed (matches " + f.Pattern + ")"},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Path), URIBaseID: "%SRCROOT%"},
+				},
+			}},
+		})
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name: "sensitive-files",
+					Rules: []sarifRule{{
+						ID:               sensitiveFileRule,
+						Name:             "SensitiveFileCommitted",
+						ShortDescription: sarifMessage{Text: "File name suggests committed credentials"},
+						DefaultConfig:    sarifDefaultConfig{Level: "warning"},
+					}},
+				},
+			},
+			Results: results,
+		}},
+	}
+}
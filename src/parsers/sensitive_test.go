@@ -0,0 +1,200 @@
+package parsers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveFilesParser_Parse(t *testing.T) {
+	p := &SensitiveFilesParser{}
+	got, err := p.Parse([]byte(`{"files": [{"path": ".env", "pattern": ".env", "size": 12}, {"path": "deploy/id_rsa", "pattern": "id_rsa", "size": 1679}], "total": 2}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := (FindingSummary{Medium: 2, Total: 2}); got != want {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+	if _, err := p.Parse([]byte(`not json`)); err == nil {
+		t.Error("Parse() on invalid JSON returned no error")
+	}
+}
+
+func TestMatchSensitivePattern(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{".env", ".env"},
+		{"services/api/.env.production", ".env.*"},
+		{"config/prod.env", "*.env"},
+		{"keys/ID_RSA", "id_rsa"},
+		{"certs/server.key", "*.key"},
+		{"app/credentials.json", "credentials.json"},
+		{"README.md", ""},
+		{"src/environment.go", ""},
+		{"id_rsa.pub", ""},
+	}
+
+	for _, tt := range tests {
+		if got := matchSensitivePattern(DefaultSensitivePatterns, tt.path); got != tt.want {
+			t.Errorf("matchSensitivePattern(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	if got := matchSensitivePattern([]string{"config/secrets.yml"}, "config/secrets.yml"); got != "config/secrets.yml" {
+		t.Errorf("path pattern did not match: %q", got)
+	}
+	if got := matchSensitivePattern([]string{"config/secrets.yml"}, "other/config/secrets.yml"); got != "" {
+		t.Errorf("path pattern matched below the root: %q", got)
+	}
+}
+
+func TestRunSensitiveFilesDetector(t *testing.T) {
+	repoDir := t.TempDir()
+	for _, name := range []string{
+		".env",
+		".env.example",
+		"main.go",
+		"deploy/.ssh/id_rsa",
+		"deploy/.ssh/id_rsa.pub",
+		"certs/tls.pem",
+		"fixtures/test.pem",
+		".git/config",
+		".git/credentials.json",
+	} {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("secret"), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := SensitiveFilesOptions{
+		Patterns: DefaultSensitivePatterns,
+		Excludes: DefaultSensitiveExcludes,
+		Ignore:   ParseIgnorePatterns("fixtures/\n"),
+	}
+
+	t.Run("json", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "out.json")
+		count, err := RunSensitiveFilesDetector(repoDir, outputPath, opts)
+		if err != nil {
+			t.Fatalf("RunSensitiveFilesDetector() error = %v", err)
+		}
+
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("output file not written: %v", err)
+		}
+		var out SensitiveFilesOutput
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatalf("JSON output is not valid: %v", err)
+		}
+		var paths []string
+		for _, f := range out.Files {
+			paths = append(paths, filepath.ToSlash(f.Path))
+			if f.Pattern == "" || f.Size != int64(len("secret")) {
+				t.Errorf("file %+v missing pattern or size", f)
+			}
+		}
+		sort.Strings(paths)
+		want := []string{".env", "certs/tls.pem", "deploy/.ssh/id_rsa"}
+		if count != len(want) || out.Total != len(want) || strings.Join(paths, ",") != strings.Join(want, ",") {
+			t.Errorf("got count %d, total %d, files %v; want %v", count, out.Total, paths, want)
+		}
+
+		summary, err := (&SensitiveFilesParser{}).Parse(data)
+		if err != nil || summary.Medium != len(want) {
+			t.Errorf("Parse() of detector output = %+v, %v", summary, err)
+		}
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		sarifOpts := opts
+		sarifOpts.SarifMode = true
+		outputPath := filepath.Join(t.TempDir(), "out.sarif")
+		if _, err := RunSensitiveFilesDetector(repoDir, outputPath, sarifOpts); err != nil {
+			t.Fatalf("RunSensitiveFilesDetector() error = %v", err)
+		}
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("output file not written: %v", err)
+		}
+		var log sarifLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			t.Fatalf("SARIF output is not valid: %v", err)
+		}
+		if len(log.Runs) != 1 || len(log.Runs[0].Results) != 3 {
+			t.Fatalf("SARIF runs = %+v, want one run with 3 results", log.Runs)
+		}
+		for _, r := range log.Runs[0].Results {
+			if r.RuleID != sensitiveFileRule || len(r.Locations) == 0 {
+				t.Errorf("SARIF result %+v missing rule or location", r)
+			}
+		}
+	})
+}
+
+func TestRunSensitiveFilesDetectorSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	repoDir, outside := filepath.Join(dir, "repo"), filepath.Join(dir, "outside")
+	for _, name := range []string{"repo/certs/tls.pem", "outside/id_rsa"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("secret"), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"repo/host":       outside,
+		"repo/id_rsa":     filepath.Join(outside, "id_rsa"),
+		"repo/server.key": filepath.Join("certs", "tls.pem"),
+		"repo/keys":       "certs",
+		"repo/certs/up":   "..",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		follow bool
+		want   []string
+	}{
+		{name: "default", want: []string{"certs/tls.pem", "server.key"}},
+		{name: "follow_symlinks", follow: true, want: []string{"certs/tls.pem", "keys/tls.pem", "server.key"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "out.json")
+			opts := SensitiveFilesOptions{Patterns: DefaultSensitivePatterns, FollowSymlinks: tt.follow}
+			if _, err := RunSensitiveFilesDetector(repoDir, outputPath, opts); err != nil {
+				t.Fatalf("RunSensitiveFilesDetector() error = %v", err)
+			}
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out SensitiveFilesOutput
+			if err := json.Unmarshal(data, &out); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range out.Files {
+				got = append(got, filepath.ToSlash(f.Path))
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("files = %v, want %v (nothing outside the repo)", got, tt.want)
+			}
+		})
+	}
+}
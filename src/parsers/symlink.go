@@ -0,0 +1,116 @@
+package parsers
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PathWithin reports whether path is dir or below it
+func PathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// SymlinkTarget resolves the symlink at path. ok is false when the link is
+// dangling or resolves outside realRoot (the repo root with its own symlinks
+// resolved), so a repo can't point a walk at files elsewhere on the host.
+func SymlinkTarget(realRoot, path string) (target string, info fs.FileInfo, ok bool) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil || !PathWithin(target, realRoot) {
+		return "", nil, false
+	}
+	info, err = os.Stat(target)
+	if err != nil {
+		return "", nil, false
+	}
+	return target, info, true
+}
+
+// SymlinkCycle reports whether a symlink to the directory target, found in
+// the real directory dir, leads back into a directory being walked: dir or
+// one of its ancestors, or one of linkDirs, the directories holding the
+// symlinks followed to reach dir, or their ancestors
+func SymlinkCycle(target, dir string, linkDirs []string) bool {
+	if PathWithin(dir, target) {
+		return true
+	}
+	for _, linkDir := range linkDirs {
+		if PathWithin(linkDir, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// symlinkEntry is the fs.DirEntry of a followed symlink: the link's name
+// with its target's type and info
+type symlinkEntry struct {
+	name string
+	info fs.FileInfo
+}
+
+func (e symlinkEntry) Name() string               { return e.name }
+func (e symlinkEntry) IsDir() bool                { return e.info.IsDir() }
+func (e symlinkEntry) Type() fs.FileMode          { return e.info.Mode().Type() }
+func (e symlinkEntry) Info() (fs.FileInfo, error) { return e.info, nil }
+
+// WalkRepo walks the tree under root like filepath.WalkDir, but with explicit
+// symlink handling. Symlinks that are dangling or resolve outside root are
+// skipped. Symlinks to files inside root are reported with their target's
+// type. Symlinks to directories inside root are only descended with
+// followSymlinks, and never when they lead back into a directory being walked
+// (see SymlinkCycle). Paths under a followed link are reported below the
+// link's path.
+func WalkRepo(root string, followSymlinks bool, fn fs.WalkDirFunc) error {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return filepath.WalkDir(root, fn) // let fn see the error
+	}
+	w := &repoWalker{realRoot: realRoot, follow: followSymlinks, fn: fn}
+	return w.walk(root, realRoot, nil, nil)
+}
+
+// repoWalker holds the state of one WalkRepo
+type repoWalker struct {
+	realRoot string
+	follow   bool
+	fn       fs.WalkDirFunc
+}
+
+// walk walks the real directory realDir, reporting its paths below path.
+// rootEntry, when set, replaces realDir's own entry (a followed symlink), and
+// linkDirs are the real directories of the symlinks followed to get here.
+func (w *repoWalker) walk(path, realDir string, rootEntry fs.DirEntry, linkDirs []string) error {
+	return filepath.WalkDir(realDir, func(p string, d fs.DirEntry, err error) error {
+		shown := path
+		if p != realDir {
+			shown = filepath.Join(path, strings.TrimPrefix(p, realDir+string(filepath.Separator)))
+		} else if rootEntry != nil {
+			d = rootEntry
+		}
+		if err == nil && d.Type()&fs.ModeSymlink != 0 {
+			return w.symlink(shown, p, d.Name(), linkDirs)
+		}
+		return w.fn(shown, d, err)
+	})
+}
+
+// symlink handles the symlink at the real path p, shown as shown
+func (w *repoWalker) symlink(shown, p, name string, linkDirs []string) error {
+	target, info, ok := SymlinkTarget(w.realRoot, p)
+	if !ok {
+		return nil
+	}
+	entry := symlinkEntry{name: name, info: info}
+	if !info.IsDir() {
+		return w.fn(shown, entry, nil)
+	}
+	dir := filepath.Dir(p)
+	if !w.follow || SymlinkCycle(target, dir, linkDirs) {
+		return nil
+	}
+	return w.walk(shown, target, entry, append(slices.Clip(linkDirs), dir))
+}
@@ -0,0 +1,105 @@
+package parsers
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeSymlinkFixture builds a repo with symlinks into itself, back to its
+// root, outside it and to nowhere, next to a directory outside the repo
+func writeSymlinkFixture(t *testing.T) (repo string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, outside := filepath.Join(dir, "repo"), filepath.Join(dir, "outside")
+	for _, name := range []string{"repo/main.go", "repo/pkg/util.go", "outside/secret.go", "outside/id_rsa"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"repo/host":     outside,                          // directory outside the repo
+		"repo/key":      filepath.Join(outside, "id_rsa"), // file outside the repo
+		"repo/escape":   filepath.Join("..", "outside"),   // relative, outside the repo
+		"repo/alias.go": "main.go",                        // file inside the repo
+		"repo/shared":   "pkg",                            // directory inside the repo
+		"repo/pkg/loop": "..",                             // back to the root
+		"repo/pkg/self": ".",                              // to itself
+		"repo/dangling": "missing",                        // to nothing
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+	return repo
+}
+
+func TestWalkRepo(t *testing.T) {
+	repo := writeSymlinkFixture(t)
+
+	tests := []struct {
+		name   string
+		follow bool
+		want   []string
+	}{
+		{
+			name: "symlinked directories not followed",
+			want: []string{".", "alias.go", "main.go", "pkg", "pkg/util.go"},
+		},
+		{
+			name:   "symlinked directories inside the repo followed, cycles skipped",
+			follow: true,
+			want:   []string{".", "alias.go", "main.go", "pkg", "pkg/util.go", "shared", "shared/util.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := WalkRepo(repo, tt.follow, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					t.Errorf("walk error at %s: %v", path, err)
+					return nil
+				}
+				rel, _ := filepath.Rel(repo, path)
+				got = append(got, filepath.ToSlash(rel))
+				if rel == "shared" && !d.IsDir() {
+					t.Errorf("shared reported as %v, want the target's directory type", d.Type())
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WalkRepo() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WalkRepo() visited %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("SkipDir on a followed symlink", func(t *testing.T) {
+		var got []string
+		WalkRepo(repo, true, func(path string, d fs.DirEntry, err error) error {
+			rel, _ := filepath.Rel(repo, path)
+			if rel == "shared" {
+				return filepath.SkipDir
+			}
+			got = append(got, filepath.ToSlash(rel))
+			return nil
+		})
+		for _, rel := range got {
+			if rel == "shared/util.go" {
+				t.Errorf("walked %s below a skipped symlink", rel)
+			}
+		}
+	})
+}
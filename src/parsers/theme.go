@@ -0,0 +1,142 @@
+package parsers
+
+import "fmt"
+
+// ============================================================================
+// Display Themes - Symbol sets for terminal output
+// ============================================================================
+
+// Theme holds the symbols used when rendering summaries and reports.
+// The emoji theme is the default; the plain theme uses ASCII only, for
+// terminals and log aggregators that mangle multibyte characters.
+type Theme struct {
+	Name  string
+	Plain bool // When true, parser icons are replaced by ScannerIcon
+
+	// Section decoration
+	Separator     string // Heavy horizontal rule character
+	ThinSeparator string // Light horizontal rule character
+	SummaryIcon   string
+	StatsIcon     string
+	RepoIcon      string
+	ScannerIcon   string // Fallback icon for unknown scanners (and all scanners in plain mode)
+	ScorecardIcon string
+
+	// Status markers
+	OK          string
+	Failed      string
+	Warning     string
+	InfoOnly    string
+	Conditional string
+	None        string
+	NoFindings  string
+
+	// Severity markers
+	Critical string
+	High     string
+	Medium   string
+	Low      string
+	Info     string
+
+	// Trend markers (critical count compared to a previous run)
+	TrendUp   string
+	TrendDown string
+	TrendSame string
+}
+
+// EmojiTheme is the default, colorful theme.
+var EmojiTheme = Theme{
+	Name:          "emoji",
+	Separator:     "═",
+	ThinSeparator: "─",
+	SummaryIcon:   "📊",
+	StatsIcon:     "📈",
+	RepoIcon:      "📦",
+	ScannerIcon:   "🔧",
+	ScorecardIcon: "🛡️",
+	OK:            "✔",
+	Failed:        "❌",
+	Warning:       "⚠",
+	InfoOnly:      "◎",
+	Conditional:   "◐",
+	None:          "✘",
+	NoFindings:    "✨",
+	Critical:      "🔴",
+	High:          "🟠",
+	Medium:        "🟡",
+	Low:           "🟢",
+	Info:          "⚪",
+	TrendUp:       "↑",
+	TrendDown:     "↓",
+	TrendSame:     "=",
+}
+
+// PlainTheme uses ASCII markers only.
+var PlainTheme = Theme{
+	Name:          "plain",
+	Plain:         true,
+	Separator:     "=",
+	ThinSeparator: "-",
+	SummaryIcon:   "#",
+	StatsIcon:     "#",
+	RepoIcon:      ">",
+	ScannerIcon:   "*",
+	ScorecardIcon: "#",
+	OK:            "[OK]",
+	Failed:        "[x]",
+	Warning:       "[!!]",
+	InfoOnly:      "[i]",
+	Conditional:   "[~]",
+	None:          "[-]",
+	NoFindings:    "[OK]",
+	Critical:      "[C]",
+	High:          "[H]",
+	Medium:        "[M]",
+	Low:           "[L]",
+	Info:          "[I]",
+	TrendUp:       "+",
+	TrendDown:     "-",
+	TrendSame:     "=",
+}
+
+// ThemeByName returns the theme with the given name ("emoji" or "plain").
+func ThemeByName(name string) (Theme, error) {
+	switch name {
+	case "", EmojiTheme.Name:
+		return EmojiTheme, nil
+	case PlainTheme.Name:
+		return PlainTheme, nil
+	default:
+		return Theme{}, fmt.Errorf("unknown theme %q (available: emoji, plain)", name)
+	}
+}
+
+// Icon returns the icon to display for a parser: the parser's own icon,
+// or the generic scanner icon in plain mode.
+func (t Theme) Icon(parser ResultParser) string {
+	if t.Plain || parser == nil {
+		return t.ScannerIcon
+	}
+	return parser.Icon()
+}
+
+// WithSeverityIcons returns the theme with the severity markers replaced by
+// icons, keyed by severity ("critical", "high", "medium", "low", "info").
+// Severities missing from icons keep the theme's markers.
+func (t Theme) WithSeverityIcons(icons map[string]string) Theme {
+	for severity, icon := range icons {
+		switch severity {
+		case "critical":
+			t.Critical = icon
+		case "high":
+			t.High = icon
+		case "medium":
+			t.Medium = icon
+		case "low":
+			t.Low = icon
+		case "info":
+			t.Info = icon
+		}
+	}
+	return t
+}
@@ -0,0 +1,110 @@
+package parsers
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// captureStdout runs fn and returns everything it wrote to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// firstNonASCII returns the index of the first byte >= 0x80, or -1.
+func firstNonASCII(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestThemeByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: "emoji"},
+		{name: "emoji", want: "emoji"},
+		{name: "plain", want: "plain"},
+		{name: "fancy", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ThemeByName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ThemeByName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Name != tt.want {
+				t.Errorf("ThemeByName(%q).Name = %q, want %q", tt.name, got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainThemeIsASCII(t *testing.T) {
+	v := reflect.ValueOf(PlainTheme)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.String {
+			continue
+		}
+		if idx := firstNonASCII(field.String()); idx >= 0 {
+			t.Errorf("PlainTheme.%s = %q contains non-ASCII byte at %d", v.Type().Field(i).Name, field.String(), idx)
+		}
+	}
+}
+
+func TestThemeIcon(t *testing.T) {
+	parser := &GrypeParser{}
+	if got := EmojiTheme.Icon(parser); got != parser.Icon() {
+		t.Errorf("EmojiTheme.Icon() = %q, want parser icon %q", got, parser.Icon())
+	}
+	if got := PlainTheme.Icon(parser); got != PlainTheme.ScannerIcon {
+		t.Errorf("PlainTheme.Icon() = %q, want %q", got, PlainTheme.ScannerIcon)
+	}
+}
+
+func TestPrintScorecardReport_PlainTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scorecard.json")
+	data := `{"score": 5.5, "scorecard": {"version": "v5"}, "checks": [
+		{"name": "Maintained", "score": 10, "reason": "active"},
+		{"name": "Pinned-Dependencies", "score": 6, "reason": "some pinned"},
+		{"name": "Fuzzing", "score": 4, "reason": "partial"},
+		{"name": "SAST", "score": 0, "reason": "none"},
+		{"name": "Packaging", "score": -1, "reason": "unknown"}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := PrintScorecardReport(path, PlainTheme); err != nil {
+			t.Errorf("PrintScorecardReport() error = %v", err)
+		}
+	})
+	if idx := firstNonASCII(out); idx >= 0 {
+		t.Errorf("plain scorecard report contains non-ASCII byte at %d: %q", idx, out)
+	}
+}
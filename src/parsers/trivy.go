@@ -0,0 +1,132 @@
+package parsers
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// ============================================================================
+// Trivy Parser - Vulnerabilities, secrets, misconfigurations and licenses
+// ============================================================================
+
+// TrivyParser parses Trivy JSON results (trivy fs --format json). One trivy
+// run can report vulnerable dependencies, hardcoded secrets, IaC
+// misconfigurations and license issues, so the parser is a MultiTypeParser
+// that splits its findings into SCA, Secrets, IaC and License summaries.
+type TrivyParser struct{}
+
+// Scan types a trivy report is split into
+const (
+	trivyTypeVulnerabilities = "SCA"
+	trivyTypeSecrets         = "Secrets"
+	trivyTypeMisconfigs      = "IaC"
+	trivyTypeLicenses        = "License"
+)
+
+// trivySeverity is the part of every trivy finding the parser reads
+type trivySeverity struct {
+	Severity string `json:"Severity"`
+}
+
+type trivyOutput struct {
+	Results []struct {
+		Vulnerabilities   []trivySeverity `json:"Vulnerabilities"`
+		Secrets           []trivySeverity `json:"Secrets"`
+		Misconfigurations []struct {
+			Severity string `json:"Severity"`
+			Status   string `json:"Status"`
+		} `json:"Misconfigurations"`
+		Licenses []trivySeverity `json:"Licenses"`
+	} `json:"Results"`
+}
+
+func (p *TrivyParser) Name() string { return "trivy" }
+func (p *TrivyParser) Type() string { return trivyTypeVulnerabilities }
+func (p *TrivyParser) Icon() string { return iconTrivy }
+func (p *TrivyParser) Describe() string {
+	return "Finds vulnerable dependencies, hardcoded secrets, IaC misconfigurations and license issues in one filesystem scan."
+}
+
+// Types returns the scan types trivy findings are split into
+func (p *TrivyParser) Types() []string {
+	return []string{trivyTypeVulnerabilities, trivyTypeSecrets, trivyTypeMisconfigs, trivyTypeLicenses}
+}
+
+// Parse reads Trivy JSON and counts every finding by severity, whatever its
+// type. UNKNOWN severities count as Info.
+func (p *TrivyParser) Parse(data []byte) (FindingSummary, error) {
+	var summary FindingSummary
+	byType, err := p.ParseByType(data)
+	if err != nil {
+		return summary, err
+	}
+	for _, s := range byType {
+		summary.Critical += s.Critical
+		summary.High += s.High
+		summary.Medium += s.Medium
+		summary.Low += s.Low
+		summary.Info += s.Info
+		summary.Total += s.Total
+	}
+	return summary, nil
+}
+
+// ParseByType reads Trivy JSON and counts vulnerabilities as SCA, secrets as
+// Secrets, failed misconfiguration checks as IaC and license findings as
+// License. Passed misconfiguration checks (--include-non-failures) are not
+// findings.
+func (p *TrivyParser) ParseByType(data []byte) (map[string]FindingSummary, error) {
+	var output trivyOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	var vulns, secrets, misconfigs, licenses FindingSummary
+	for _, result := range output.Results {
+		for _, v := range result.Vulnerabilities {
+			vulns.count(v.Severity)
+		}
+		for _, s := range result.Secrets {
+			secrets.count(s.Severity)
+		}
+		for _, m := range result.Misconfigurations {
+			if m.Status == "" || m.Status == "FAIL" {
+				misconfigs.count(m.Severity)
+			}
+		}
+		for _, l := range result.Licenses {
+			licenses.count(l.Severity)
+		}
+	}
+
+	return map[string]FindingSummary{
+		trivyTypeVulnerabilities: vulns,
+		trivyTypeSecrets:         secrets,
+		trivyTypeMisconfigs:      misconfigs,
+		trivyTypeLicenses:        licenses,
+	}, nil
+}
+
+// trivyTestedSchema is the trivy report SchemaVersion the parser was tested
+// against
+const trivyTestedSchema = 2
+
+// SchemaVersion returns the report's SchemaVersion, tested when it is
+// trivyTestedSchema
+func (p *TrivyParser) SchemaVersion(data []byte) (string, bool) {
+	var output struct {
+		SchemaVersion int `json:"SchemaVersion"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil || output.SchemaVersion == 0 {
+		return "", true
+	}
+	return strconv.Itoa(output.SchemaVersion), output.SchemaVersion == trivyTestedSchema
+}
+
+func (p *TrivyParser) TestedSchemaVersions() string { return strconv.Itoa(trivyTestedSchema) }
+
+// Verify TrivyParser implements MultiTypeParser and SchemaVersionParser
+var (
+	_ MultiTypeParser     = (*TrivyParser)(nil)
+	_ SchemaVersionParser = (*TrivyParser)(nil)
+)
@@ -0,0 +1,79 @@
+package parsers
+
+import "testing"
+
+func TestTrivyParser_ParseByType(t *testing.T) {
+	input := `{"SchemaVersion": 2, "Results": [
+		{"Target": "go.mod", "Class": "lang-pkgs", "Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2024-0001", "Severity": "CRITICAL"},
+			{"VulnerabilityID": "CVE-2024-0002", "Severity": "HIGH"},
+			{"VulnerabilityID": "CVE-2024-0003", "Severity": "UNKNOWN"}
+		]},
+		{"Target": "config/.env", "Class": "secret", "Secrets": [
+			{"RuleID": "aws-access-key-id", "Severity": "CRITICAL"}
+		]},
+		{"Target": "deploy/web.yaml", "Class": "config", "Misconfigurations": [
+			{"ID": "KSV001", "Severity": "MEDIUM", "Status": "FAIL"},
+			{"ID": "KSV003", "Severity": "LOW", "Status": "FAIL"},
+			{"ID": "KSV012", "Severity": "HIGH", "Status": "PASS"}
+		]},
+		{"Target": "go.mod", "Class": "license", "Licenses": [
+			{"Name": "AGPL-3.0", "Severity": "HIGH"}
+		]}
+	]}`
+
+	p := &TrivyParser{}
+	got, err := p.ParseByType([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseByType() error = %v", err)
+	}
+
+	want := map[string]FindingSummary{
+		"SCA":     {Critical: 1, High: 1, Info: 1, Total: 3},
+		"Secrets": {Critical: 1, Total: 1},
+		"IaC":     {Medium: 1, Low: 1, Total: 2},
+		"License": {High: 1, Total: 1},
+	}
+	if len(got) != len(want) {
+		t.Errorf("ParseByType() returned types %v, want %v", got, want)
+	}
+	for scanType, summary := range want {
+		if got[scanType] != summary {
+			t.Errorf("ParseByType()[%q] = %+v, want %+v", scanType, got[scanType], summary)
+		}
+	}
+
+	total, err := p.Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if wantTotal := (FindingSummary{Critical: 2, High: 2, Medium: 1, Low: 1, Info: 1, Total: 7}); total != wantTotal {
+		t.Errorf("Parse() = %+v, want %+v", total, wantTotal)
+	}
+}
+
+func TestTrivyParser_Empty(t *testing.T) {
+	p := &TrivyParser{}
+	got, err := p.ParseByType([]byte(`{"SchemaVersion": 2, "Results": [{"Target": "go.mod", "Class": "lang-pkgs"}]}`))
+	if err != nil {
+		t.Fatalf("ParseByType() error = %v", err)
+	}
+	for _, scanType := range p.Types() {
+		if !got[scanType].IsEmpty() {
+			t.Errorf("ParseByType()[%q] = %+v, want empty", scanType, got[scanType])
+		}
+	}
+
+	if _, err := p.ParseByType([]byte(`not json`)); err == nil {
+		t.Error("ParseByType() on invalid JSON returned no error")
+	}
+}
+
+func TestScanTypes(t *testing.T) {
+	if got := ScanTypes(&TrivyParser{}); len(got) != 4 || got[0] != "SCA" {
+		t.Errorf("ScanTypes(trivy) = %v, want SCA first of 4 types", got)
+	}
+	if got := ScanTypes(&GosecParser{}); len(got) != 1 || got[0] != "SAST" {
+		t.Errorf("ScanTypes(gosec) = %v, want [SAST]", got)
+	}
+}
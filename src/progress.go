@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progress is the run's status line; nil (the default, e.g. in tests) tracks
+// nothing. Set in main unless --quiet, --no-progress or stderr isn't a terminal.
+var progress *progressTracker
+
+// progressState is a snapshot of how far a run has got: repositories
+// finished out of the total, and the targets whose scanners are running
+type progressState struct {
+	ReposDone  int
+	ReposTotal int
+	Active     []targetProgress // in the order the targets started
+}
+
+// targetProgress tracks the scanners of one repository or sub-project
+type targetProgress struct {
+	Name          string
+	ScannersDone  int
+	ScannersTotal int
+	Scanner       string // scanner currently running, "" between scanners
+}
+
+// String renders the state as a status line, e.g.
+// "repo 3/20, scanner 2/5 (grype)". With several targets scanning at once
+// (--parallel-repos) each gets its own "name: scanner i/n" part.
+func (s progressState) String() string {
+	var parts []string
+	if s.ReposTotal > 0 {
+		current := s.ReposDone + len(s.Active)
+		if current == s.ReposDone && current < s.ReposTotal {
+			current++ // between targets, the next repo is being cloned
+		}
+		if current > s.ReposTotal {
+			current = s.ReposTotal
+		}
+		parts = append(parts, fmt.Sprintf("repo %d/%d", current, s.ReposTotal))
+	}
+
+	for _, target := range s.Active {
+		part := target.scannerPosition()
+		if len(s.Active) > 1 {
+			part = target.Name + ": " + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// scannerPosition renders "scanner i/n (name)" for the scanner running now
+func (t targetProgress) scannerPosition() string {
+	current := t.ScannersDone
+	if t.Scanner != "" {
+		current++
+	}
+	position := fmt.Sprintf("scanner %d/%d", current, t.ScannersTotal)
+	if t.Scanner != "" {
+		position += " (" + t.Scanner + ")"
+	}
+	return position
+}
+
+// progressTracker keeps the progress state and, when enabled, draws it as a
+// status line at the bottom of the terminal. Log lines and status updates go
+// through the same lock, so concurrent repo scans never garble the output:
+// the status line is cleared before each log line and redrawn after it.
+type progressTracker struct {
+	mu      sync.Mutex
+	out     io.Writer
+	enabled bool
+	shown   bool // a status line is on screen
+	state   progressState
+}
+
+// newProgressTracker returns a tracker that draws to out when enabled
+func newProgressTracker(out io.Writer, enabled bool) *progressTracker {
+	return &progressTracker{out: out, enabled: enabled}
+}
+
+// stderrIsTerminal reports whether stderr is an interactive terminal, where
+// a status line can be redrawn in place
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// logWriter wraps the logger's output so log lines don't mix with the
+// status line
+func (p *progressTracker) logWriter(w io.Writer) io.Writer {
+	return progressLogWriter{p: p, out: w}
+}
+
+// progressLogWriter writes log lines above the status line
+type progressLogWriter struct {
+	p   *progressTracker
+	out io.Writer
+}
+
+func (w progressLogWriter) Write(b []byte) (int, error) {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+	w.p.clear()
+	n, err := w.out.Write(b)
+	w.p.draw()
+	return n, err
+}
+
+// start begins tracking a run over total repositories
+func (p *progressTracker) start(total int) {
+	p.update(func(s *progressState) {
+		*s = progressState{ReposTotal: total}
+	})
+}
+
+// repoDone counts a repository as finished, however its scan ended
+func (p *progressTracker) repoDone() {
+	p.update(func(s *progressState) { s.ReposDone++ })
+}
+
+// targetStarted begins tracking the scanners of a repository or sub-project
+func (p *progressTracker) targetStarted(name string, scanners int) {
+	p.update(func(s *progressState) {
+		s.Active = append(s.Active, targetProgress{Name: name, ScannersTotal: scanners})
+	})
+}
+
+// scannerStarted records which scanner is running on a target
+func (p *progressTracker) scannerStarted(name, scanner string) {
+	p.update(func(s *progressState) {
+		if t := s.target(name); t != nil {
+			t.Scanner = scanner
+		}
+	})
+}
+
+// scannerDone counts a target's running scanner as finished
+func (p *progressTracker) scannerDone(name string) {
+	p.update(func(s *progressState) {
+		if t := s.target(name); t != nil {
+			t.ScannersDone++
+			t.Scanner = ""
+		}
+	})
+}
+
+// targetDone stops tracking a target once its scanners have finished
+func (p *progressTracker) targetDone(name string) {
+	p.update(func(s *progressState) {
+		for i := range s.Active {
+			if s.Active[i].Name == name {
+				s.Active = append(s.Active[:i], s.Active[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// finish removes the status line at the end of the run
+func (p *progressTracker) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	p.enabled = false
+}
+
+// snapshot returns a copy of the current state
+func (p *progressTracker) snapshot() progressState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.state
+	s.Active = append([]targetProgress(nil), p.state.Active...)
+	return s
+}
+
+// target returns the active target with the given name, or nil
+func (s *progressState) target(name string) *targetProgress {
+	for i := range s.Active {
+		if s.Active[i].Name == name {
+			return &s.Active[i]
+		}
+	}
+	return nil
+}
+
+// update changes the state and redraws the status line. Methods are no-ops
+// on a nil tracker so callers don't need to check whether progress is on.
+func (p *progressTracker) update(change func(*progressState)) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	change(&p.state)
+	p.clear()
+	p.draw()
+}
+
+// clear erases the status line; the caller holds p.mu
+func (p *progressTracker) clear() {
+	if p.shown {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.shown = false
+	}
+}
+
+// draw writes the status line without a newline; the caller holds p.mu
+func (p *progressTracker) draw() {
+	if !p.enabled {
+		return
+	}
+	if line := p.state.String(); line != "" {
+		fmt.Fprintf(p.out, "⏳ %s", line)
+		p.shown = true
+	}
+}
+
+// enableProgress installs the status line for interactive runs and routes
+// log output through it
+func enableProgress(quiet, disabled bool) {
+	if quiet || disabled || !stderrIsTerminal() {
+		return
+	}
+	progress = newProgressTracker(os.Stderr, true)
+	log.SetOutput(progress.logWriter(log.Writer()))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestProgressStateString(t *testing.T) {
+	tests := []struct {
+		name  string
+		state progressState
+		want  string
+	}{
+		{
+			name:  "cloning the first repo",
+			state: progressState{ReposTotal: 20},
+			want:  "repo 1/20",
+		},
+		{
+			name: "scanner running",
+			state: progressState{ReposDone: 2, ReposTotal: 20, Active: []targetProgress{
+				{Name: "org/api", ScannersDone: 1, ScannersTotal: 5, Scanner: "grype"},
+			}},
+			want: "repo 3/20, scanner 2/5 (grype)",
+		},
+		{
+			name: "between scanners",
+			state: progressState{ReposDone: 2, ReposTotal: 20, Active: []targetProgress{
+				{Name: "org/api", ScannersDone: 2, ScannersTotal: 5},
+			}},
+			want: "repo 3/20, scanner 2/5",
+		},
+		{
+			name: "parallel repos",
+			state: progressState{ReposDone: 4, ReposTotal: 20, Active: []targetProgress{
+				{Name: "org/api", ScannersDone: 0, ScannersTotal: 5, Scanner: "gosec"},
+				{Name: "org/web", ScannersDone: 3, ScannersTotal: 3},
+			}},
+			want: "repo 6/20, org/api: scanner 1/5 (gosec), org/web: scanner 3/3",
+		},
+		{
+			name:  "all repos done",
+			state: progressState{ReposDone: 20, ReposTotal: 20},
+			want:  "repo 20/20",
+		},
+		{
+			name: "local mode has no repo count",
+			state: progressState{Active: []targetProgress{
+				{Name: "module", ScannersDone: 3, ScannersTotal: 4, Scanner: "trufflehog"},
+			}},
+			want: "scanner 4/4 (trufflehog)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressTracker(t *testing.T) {
+	p := newProgressTracker(&bytes.Buffer{}, false)
+	p.start(3)
+	p.targetStarted("org/api", 2)
+	p.scannerStarted("org/api", "grype")
+	if got := p.snapshot().String(); got != "repo 1/3, scanner 1/2 (grype)" {
+		t.Errorf("after first scanner started: %q", got)
+	}
+	p.scannerDone("org/api")
+	p.scannerStarted("org/api", "gosec")
+	p.scannerDone("org/api")
+	p.targetDone("org/api")
+	p.repoDone()
+	if got := p.snapshot(); got.ReposDone != 1 || len(got.Active) != 0 {
+		t.Errorf("after first repo = %+v, want 1 repo done and no active targets", got)
+	}
+	if got := p.snapshot().String(); got != "repo 2/3" {
+		t.Errorf("after first repo: %q", got)
+	}
+
+	// A nil tracker (progress disabled) ignores every update
+	var disabled *progressTracker
+	disabled.start(1)
+	disabled.targetStarted("org/api", 1)
+	disabled.scannerStarted("org/api", "grype")
+	disabled.finish()
+}
+
+func TestProgressLogWriter(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgressTracker(&out, true)
+	w := p.logWriter(&out)
+
+	p.start(2)
+	fmt.Fprint(w, "log line\n")
+	p.finish()
+
+	// The status line is cleared before the log line and redrawn after it
+	want := "⏳ repo 1/2" + "\r\033[K" + "log line\n" + "⏳ repo 1/2" + "\r\033[K"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	// Concurrent log lines and updates never split a log line
+	out.Reset()
+	p = newProgressTracker(&out, true)
+	w = p.logWriter(&out)
+	p.start(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("org/repo%d", i)
+			p.targetStarted(name, 1)
+			fmt.Fprintf(w, "scanning %s\n", name)
+			p.targetDone(name)
+			p.repoDone()
+		}(i)
+	}
+	wg.Wait()
+	p.finish()
+	for i := 0; i < 10; i++ {
+		if line := fmt.Sprintf("\r\033[Kscanning org/repo%d\n", i); !strings.Contains(out.String(), line) {
+			t.Errorf("log line for org/repo%d was not written whole after clearing the status line", i)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultVersionArgs print a scanner's version when version_args is not set
+var defaultVersionArgs = []string{"--version"}
+
+// versionTimeout bounds a scanner's version command
+const versionTimeout = 30 * time.Second
+
+// secretFlagWords mark command-line flags whose value is a secret, e.g.
+// --token, --api-key or --password=...
+var secretFlagWords = []string{"token", "password", "passwd", "secret", "apikey", "api-key", "api_key", "auth"}
+
+// scannerVersions caches each tool's version for the run, so a scanner that
+// runs on many repositories is asked for its version only once
+var scannerVersions = struct {
+	sync.Mutex
+	byCommand map[string]*cachedVersion
+}{byCommand: make(map[string]*cachedVersion)}
+
+// cachedVersion is a scanner version looked up at most once
+type cachedVersion struct {
+	once    sync.Once
+	version string
+}
+
+// recordScannerCommand stores the command a scanner ran (program and args),
+// with secrets redacted, and the scanner's version on its result
+// (record_commands). Image scanners record their image as the version.
+func recordScannerCommand(result *ScanResult, scanner ScannerConfig, program string, args []string) {
+	result.Command = append([]string{program}, redactCommandArgs(args, requiredEnvValues(scanner.RequiredEnv, scanner.Env))...)
+	switch {
+	case scanner.Image != "":
+		result.Version = scanner.Image
+	case strings.HasPrefix(scanner.Command, "builtin:"):
+		// built into allscan, no separate tool version
+	default:
+		result.Version = scannerVersion(scanner)
+	}
+}
+
+// scannerVersion returns the first line a scanner prints for its
+// version_args, or "" when the version command fails. The lookup is cached
+// per command and version args.
+func scannerVersion(scanner ScannerConfig) string {
+	versionArgs := scanner.VersionArgs
+	if len(versionArgs) == 0 {
+		versionArgs = defaultVersionArgs
+	}
+	key := scanner.Command + "\x00" + strings.Join(versionArgs, "\x00")
+
+	scannerVersions.Lock()
+	entry, ok := scannerVersions.byCommand[key]
+	if !ok {
+		entry = &cachedVersion{}
+		scannerVersions.byCommand[key] = entry
+	}
+	scannerVersions.Unlock()
+
+	entry.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+		defer cancel()
+		output, err := scannerCommand(ctx, scanner, versionArgs, "").CombinedOutput()
+		if err != nil {
+			log.Printf("    ⚠️  Could not get %s version (%s %s): %v", scanner.Name, scanner.Command, strings.Join(versionArgs, " "), err)
+			return
+		}
+		entry.version = firstLine(output)
+	})
+	return entry.version
+}
+
+// firstLine returns the first non-empty line of a command's output, such as
+// the version a tool prints or the error it crashed with
+func firstLine(output []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// requiredEnvValues returns the values of a scanner's required env vars, the
+// usual place for its credentials, from allscan's environment and from env
+// (the scanner's layered env: entries, which can supply them)
+func requiredEnvValues(names []string, env map[string]string) []string {
+	var values []string
+	for _, name := range names {
+		for _, value := range []string{os.Getenv(name), os.ExpandEnv(env[name])} {
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// redactCommandArgs returns a copy of args that is safe to store in a report:
+// values of secret flags (--token x, --password=x), credentials in URLs and
+// any of the given secret values are replaced with redactedValue
+func redactCommandArgs(args, secrets []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case i > 0 && isSecretFlag(args[i-1]) && !strings.Contains(args[i-1], "="):
+			arg = redactedValue
+		case isSecretFlag(arg) && strings.Contains(arg, "="):
+			arg = arg[:strings.Index(arg, "=")+1] + redactedValue
+		default:
+			arg = redactURLCredentials(arg)
+		}
+		for _, secret := range secrets {
+			arg = strings.ReplaceAll(arg, secret, redactedValue)
+		}
+		redacted[i] = arg
+	}
+	return redacted
+}
+
+// isSecretFlag reports whether arg is a flag (with or without "=value")
+// whose name suggests it carries a secret
+func isSecretFlag(arg string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	name = strings.ToLower(name)
+	for _, word := range secretFlagWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURLCredentials replaces the user info of a URL argument, such as a
+// token in https://x-access-token:<token>@github.com/org/repo
+func redactURLCredentials(arg string) string {
+	if !strings.Contains(arg, "://") {
+		return arg
+	}
+	u, err := url.Parse(arg)
+	if err != nil || u.User == nil {
+		return arg
+	}
+	u.User = nil // url.User would escape the "*"s
+	return strings.Replace(u.String(), "://", "://"+redactedValue+"@", 1)
+}
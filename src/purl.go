@@ -257,6 +257,7 @@ func resolveVersionTag(repoURL, version string) (tagName, commitHash string) {
 func resolveVersionTagFromOutput(repoURL, version string, lsRemoteOutput []byte) (tagName, commitHash string) {
 	if lsRemoteOutput == nil {
 		cmd := exec.Command("git", "ls-remote", "--tags", repoURL)
+		setupGitAuth(cmd, repoURL)
 		output, err := cmd.Output()
 		if err != nil {
 			log.Printf("⚠️  Could not list tags for %s: %v", repoURL, err)
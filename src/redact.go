@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"allscan/parsers"
+)
+
+// redactedValue replaces secret values in redacted scanner output
+const redactedValue = "****"
+
+// secretFields are the JSON keys that hold secret values in secrets scanner
+// output: Secret, Match and Line (gitleaks) and Raw, RawV2 (trufflehog).
+// Only string values are replaced, so numeric fields like trufflehog's
+// lowercase "line" number are kept.
+var secretFields = map[string]bool{
+	"Secret": true,
+	"Match":  true,
+	"Line":   true,
+	"Raw":    true,
+	"RawV2":  true,
+}
+
+// isSecretsScanner reports whether a scanner's parser is of type Secrets
+func isSecretsScanner(scannerName string) bool {
+	parser, ok := parsers.Get(scannerName)
+	return ok && parser.Type() == "Secrets"
+}
+
+// redactSecretFields replaces secret values in JSON scanner output with
+// redactedValue and returns the rewritten output and how many values were
+// replaced. The input may be a single document or NDJSON; each value is
+// written back on its own line. SARIF snippet text is redacted too.
+func redactSecretFields(data []byte) ([]byte, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // keep large integers exact
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+
+	redacted := 0
+	for {
+		var value any
+		if err := dec.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, 0, err
+		}
+		redacted += redactValue(value)
+		if err := enc.Encode(value); err != nil {
+			return nil, 0, err
+		}
+	}
+	return out.Bytes(), redacted, nil
+}
+
+// redactValue redacts secret fields in a decoded JSON value in place and
+// returns how many values were replaced
+func redactValue(value any) int {
+	redacted := 0
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if s, ok := field.(string); ok && secretFields[key] {
+				if s != "" && s != redactedValue {
+					v[key] = redactedValue
+					redacted++
+				}
+				continue
+			}
+			if snippet, ok := field.(map[string]any); ok && key == "snippet" {
+				if s, ok := snippet["text"].(string); ok && s != "" && s != redactedValue {
+					snippet["text"] = redactedValue
+					redacted++
+				}
+			}
+			redacted += redactValue(field)
+		}
+	case []any:
+		for _, item := range v {
+			redacted += redactValue(item)
+		}
+	}
+	return redacted
+}
+
+// redactScanResult redacts a secrets scanner's output file before it is
+// parsed or uploaded. If that fails the result is marked failed, so output
+// that may still hold secrets is never uploaded.
+func redactScanResult(result *ScanResult) {
+	redacted, err := redactSecretsInFile(result.OutputPath)
+	if err != nil {
+		log.Printf("    ❌ Failed to redact secrets in %s output: %v", result.Scanner, err)
+		result.Success = false
+		result.Error = fmt.Errorf("redacting secrets: %w", err)
+		return
+	}
+	if redacted > 0 {
+		log.Printf("    🔒 Redacted %d secret value(s) in %s output", redacted, result.Scanner)
+	}
+}
+
+// redactSecretsInFile redacts secret values in a scanner output file in place
+// and returns how many values were replaced. A missing file (a stdout-only
+// scanner that printed nothing) has nothing to redact.
+func redactSecretsInFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	redactedData, redacted, err := redactSecretFields(data)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if redacted == 0 {
+		return 0, nil
+	}
+	if err := os.WriteFile(path, redactedData, 0644); err != nil {
+		return 0, err
+	}
+	return redacted, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import "fmt"
+
+// --only values: restrict uploads and the run report to the successful or the
+// failed scanner results of a run
+const (
+	onlySuccess = "success"
+	onlyFailed  = "failed"
+)
+
+// validateOnlyFilter rejects unknown --only values ("" keeps every result)
+func validateOnlyFilter(only string) error {
+	switch only {
+	case "", onlySuccess, onlyFailed:
+		return nil
+	}
+	return fmt.Errorf("--only must be %q or %q, got %q", onlySuccess, onlyFailed, only)
+}
+
+// filterResults returns the results matching the --only filter, all of them
+// when only is ""
+func filterResults(results []ScanResult, only string) []ScanResult {
+	if only == "" {
+		return results
+	}
+	var kept []ScanResult
+	for _, result := range results {
+		if result.Success == (only == onlySuccess) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// filterContextResults applies the --only filter to each context's results.
+// Contexts left without results are dropped, so a filtered run report lists
+// only the targets with matching results. Repositories that failed to clone
+// count as failed and are kept by "failed". The contexts passed in are not
+// modified.
+func filterContextResults(contexts []RepoScanContext, only string) []RepoScanContext {
+	if only == "" {
+		return contexts
+	}
+	var kept []RepoScanContext
+	for _, ctx := range contexts {
+		if ctx.CloneError != nil {
+			if only == onlyFailed {
+				kept = append(kept, ctx)
+			}
+			continue
+		}
+		if results := filterResults(ctx.Results, only); len(results) > 0 {
+			ctx.Results = results
+			kept = append(kept, ctx)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFilterResults(t *testing.T) {
+	results := []ScanResult{
+		{Scanner: "grype", Success: true},
+		{Scanner: "gosec"},
+		{Scanner: "trufflehog", Success: true},
+		{Scanner: "semgrep"},
+	}
+
+	tests := []struct {
+		only string
+		want []string
+	}{
+		{"", []string{"grype", "gosec", "trufflehog", "semgrep"}},
+		{onlySuccess, []string{"grype", "trufflehog"}},
+		{onlyFailed, []string{"gosec", "semgrep"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.only, func(t *testing.T) {
+			var got []string
+			for _, result := range filterResults(results, tt.only) {
+				got = append(got, result.Scanner)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterResults(%q) = %v, want %v", tt.only, got, tt.want)
+			}
+		})
+	}
+
+	// Every result lands in exactly one of the two partitions
+	if n := len(filterResults(results, onlySuccess)) + len(filterResults(results, onlyFailed)); n != len(results) {
+		t.Errorf("success and failed partitions hold %d results, want %d", n, len(results))
+	}
+}
+
+func TestFilterContextResults(t *testing.T) {
+	contexts := []RepoScanContext{
+		{RepoURL: "https://github.com/org/api", Results: []ScanResult{{Scanner: "grype", Success: true}, {Scanner: "gosec"}}},
+		{RepoURL: "https://github.com/org/docs", Results: []ScanResult{{Scanner: "grype", Success: true}}},
+		{RepoURL: "https://github.com/org/gone", CloneError: errors.New("repository not found")},
+	}
+
+	got := filterContextResults(contexts, onlyFailed)
+	if len(got) != 2 || got[0].RepoURL != "https://github.com/org/api" || len(got[0].Results) != 1 || got[0].Results[0].Scanner != "gosec" {
+		t.Errorf("filterContextResults(failed) = %+v, want org/api with only gosec", got)
+	}
+	if len(got) == 2 && got[1].CloneError == nil {
+		t.Errorf("filterContextResults(failed) = %+v, want the clone failure kept", got)
+	}
+	if got := filterContextResults(contexts, onlySuccess); len(got) != 2 {
+		t.Errorf("filterContextResults(success) kept %d contexts, want 2 without the clone failure", len(got))
+	}
+	if len(contexts[0].Results) != 2 {
+		t.Error("filterContextResults modified the contexts passed in")
+	}
+	if got := filterContextResults(contexts, ""); len(got) != 3 {
+		t.Errorf("filterContextResults(\"\") kept %d contexts, want 3", len(got))
+	}
+}
+
+func TestValidateOnlyFilter(t *testing.T) {
+	for _, only := range []string{"", "success", "failed"} {
+		if err := validateOnlyFilter(only); err != nil {
+			t.Errorf("validateOnlyFilter(%q) = %v, want nil", only, err)
+		}
+	}
+	if err := validateOnlyFilter("failures"); err == nil {
+		t.Error("validateOnlyFilter(\"failures\") = nil, want error")
+	}
+}
+
+func TestUploadSkipsFilteredResults(t *testing.T) {
+	t.Setenv("VULN_MGMT_API_TOKEN", "test-token")
+
+	var mu sync.Mutex
+	var uploaded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm() error = %v", err)
+		}
+		mu.Lock()
+		uploaded = append(uploaded, r.FormValue("engagement_name"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	var results []ScanResult
+	for _, scanner := range []string{"grype", "gosec"} {
+		path := filepath.Join(dir, scanner+".json")
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, ScanResult{Scanner: scanner, Repository: "https://github.com/org/repo",
+			OutputPath: path, Success: scanner == "grype", DojoScanType: "Generic Findings Import"})
+	}
+	config := &Config{Global: GlobalConfig{UploadEndpoint: server.URL}}
+
+	tests := []struct {
+		only string
+		want []string
+	}{
+		{onlySuccess, []string{"org/repo-grype"}},
+		{onlyFailed, nil}, // failed results have nothing to upload
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.only, func(t *testing.T) {
+			uploaded = nil
+			uploadResults(config, filterResults(results, tt.only), nil)
+			if !reflect.DeepEqual(uploaded, tt.want) {
+				t.Errorf("uploaded %v, want %v", uploaded, tt.want)
+			}
+		})
+	}
+}
@@ -23,18 +23,28 @@ func isVersionTag(branchTag string) bool {
 // buildSBOMFilename constructs a filename for the SBOM based on repo metadata.
 // Pattern: {repoName}_{version}_{commitHash}_{date}.cdx.json for version tags
 //          {repoName}_{commitHash}_{date}.cdx.json for branch-only targets
-func buildSBOMFilename(repoName, commitHash, branchTag string) string {
-	date := time.Now().Format("2006-01-02")
+// With deterministic names (global.sbom_deterministic_names) the date is
+// dropped, so the same commit always produces the same filename.
+func buildSBOMFilename(repoName, commitHash, branchTag string, deterministic bool) string {
+	base := sbomBaseName(repoName, commitHash, branchTag)
+	if deterministic {
+		return base + ".cdx.json"
+	}
+	return base + "_" + time.Now().Format("2006-01-02") + ".cdx.json"
+}
 
+// sbomBaseName returns the date-free part of an SBOM filename
+func sbomBaseName(repoName, commitHash, branchTag string) string {
 	if isVersionTag(branchTag) {
-		return fmt.Sprintf("%s_%s_%s_%s.cdx.json", repoName, branchTag, commitHash, date)
+		return fmt.Sprintf("%s_%s_%s", repoName, branchTag, commitHash)
 	}
-	return fmt.Sprintf("%s_%s_%s.cdx.json", repoName, commitHash, date)
+	return fmt.Sprintf("%s_%s", repoName, commitHash)
 }
 
 // findExistingSBOM looks for an existing SBOM in sbomDir that matches the given
-// repo name, commit hash, and version tag. It ignores the date portion so that
-// re-running against the same commit reuses the existing SBOM.
+// repo name, commit hash, and version tag. It matches both dated and
+// deterministic filenames, ignoring the date portion so that re-running
+// against the same commit reuses the existing SBOM whichever layout made it.
 // Returns the full path if found, empty string otherwise.
 func findExistingSBOM(sbomDir, repoName, commitHash, branchTag string) string {
 	entries, err := os.ReadDir(sbomDir)
@@ -42,20 +52,16 @@ func findExistingSBOM(sbomDir, repoName, commitHash, branchTag string) string {
 		return ""
 	}
 
-	// Build the prefix to match (everything before the date)
-	var prefix string
-	if isVersionTag(branchTag) {
-		prefix = fmt.Sprintf("%s_%s_%s_", repoName, branchTag, commitHash)
-	} else {
-		prefix = fmt.Sprintf("%s_%s_", repoName, commitHash)
-	}
-
+	base := sbomBaseName(repoName, commitHash, branchTag)
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 		name := entry.Name()
-		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, ".cdx.json") {
+		if name == base+".cdx.json" {
+			return filepath.Join(sbomDir, name)
+		}
+		if strings.HasPrefix(name, base+"_") && strings.HasSuffix(name, ".cdx.json") {
 			return filepath.Join(sbomDir, name)
 		}
 	}
@@ -63,10 +69,20 @@ func findExistingSBOM(sbomDir, repoName, commitHash, branchTag string) string {
 	return ""
 }
 
+// sbomFailureReason returns the first line of an SBOM generation error for
+// display; syft's full output follows on later lines
+func sbomFailureReason(err error) string {
+	reason, _, _ := strings.Cut(err.Error(), "\n")
+	return reason
+}
+
+// sbomTimeout bounds a single Syft run
+const sbomTimeout = 5 * time.Minute
+
 // generateSBOM generates a CycloneDX SBOM for a repository using Syft.
 // It first checks for an existing SBOM matching the same repo+version+commit
 // and reuses it if found. Returns the path to the SBOM file.
-func generateSBOM(resultsDir, repoPath, repoName, commitHash, branchTag string) (string, error) {
+func generateSBOM(resultsDir, repoPath, repoName, commitHash, branchTag string, deterministic bool, timeout time.Duration) (string, error) {
 	sbomDir := filepath.Join(resultsDir, "sboms")
 
 	// Convert to absolute path
@@ -87,17 +103,18 @@ func generateSBOM(resultsDir, repoPath, repoName, commitHash, branchTag string)
 	}
 
 	// Build output filename and path
-	filename := buildSBOMFilename(repoName, commitHash, branchTag)
+	filename := buildSBOMFilename(repoName, commitHash, branchTag, deterministic)
 	outputPath := filepath.Join(absDir, filename)
 
 	log.Printf("  📋 Generating SBOM with Syft...")
 
 	// Run syft scan
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "syft", "scan", "dir:.", "-o", "cyclonedx-json="+outputPath)
 	cmd.Dir = repoPath
+	setOfflineDBEnv(cmd)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -87,7 +87,7 @@ func TestBuildSBOMFilename(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildSBOMFilename(tt.repoName, tt.commitHash, tt.branchTag)
+			got := buildSBOMFilename(tt.repoName, tt.commitHash, tt.branchTag, false)
 			if len(got) < len(tt.wantPrefix)+len(tt.wantSuffix) {
 				t.Fatalf("buildSBOMFilename() = %q, too short", got)
 			}
@@ -101,6 +101,28 @@ func TestBuildSBOMFilename(t *testing.T) {
 	}
 }
 
+func TestBuildSBOMFilenameDeterministic(t *testing.T) {
+	tests := []struct {
+		name       string
+		repoName   string
+		commitHash string
+		branchTag  string
+		want       string
+	}{
+		{"version tag", "grype", "abc1234", "v0.87.0", "grype_v0.87.0_abc1234.cdx.json"},
+		{"branch target", "allscan", "def5678", "main", "allscan_def5678.cdx.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSBOMFilename(tt.repoName, tt.commitHash, tt.branchTag, true)
+			if got != tt.want {
+				t.Errorf("buildSBOMFilename() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFindExistingSBOM(t *testing.T) {
 	t.Run("finds matching SBOM by repo+version+commit", func(t *testing.T) {
 		dir := t.TempDir()
@@ -162,4 +184,29 @@ func TestFindExistingSBOM(t *testing.T) {
 			t.Errorf("findExistingSBOM() = %q, want %q", filepath.Base(got), existing)
 		}
 	})
+
+	t.Run("finds deterministic SBOM", func(t *testing.T) {
+		dir := t.TempDir()
+		existing := buildSBOMFilename("grype", "abc1234", "v0.87.0", true)
+		if err := os.WriteFile(filepath.Join(dir, existing), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got := findExistingSBOM(dir, "grype", "abc1234", "v0.87.0")
+		if filepath.Base(got) != existing {
+			t.Errorf("findExistingSBOM() = %q, want %q", filepath.Base(got), existing)
+		}
+	})
+
+	t.Run("deterministic name does not match another commit", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "allscan_def5678.cdx.json"), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got := findExistingSBOM(dir, "allscan", "def567", "main")
+		if got != "" {
+			t.Errorf("findExistingSBOM() = %q, want empty string", got)
+		}
+	})
 }
@@ -3,13 +3,18 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"allscan/parsers"
 )
@@ -47,15 +52,64 @@ func selectArgs(scanner ScannerConfig, sarifMode, localMode bool) ([]string, boo
 	return scanner.Args, false
 }
 
-// checkRequiredEnv verifies that all required environment variables are set.
+// checkRequiredEnv verifies that all required environment variables are set,
+// in allscan's environment or in env (the scanner's layered env: entries).
 // Returns the name of the first missing variable, or empty string if all are set.
-func checkRequiredEnv(required []string) string {
+func checkRequiredEnv(required []string, env map[string]string) string {
+	if missing := missingRequiredEnv(required, env); len(missing) > 0 {
+		return missing[0]
+	}
+	return ""
+}
+
+// missingRequiredEnv returns every required environment variable that is
+// neither set in allscan's environment nor supplied (non-empty after
+// expansion) by env, in the order they are listed
+func missingRequiredEnv(required []string, env map[string]string) []string {
+	var missing []string
 	for _, envVar := range required {
-		if os.Getenv(envVar) == "" {
-			return envVar
+		if os.Getenv(envVar) == "" && os.ExpandEnv(env[envVar]) == "" {
+			missing = append(missing, envVar)
 		}
 	}
-	return ""
+	return missing
+}
+
+// missingEnvError marks a scanner that was skipped because a required
+// environment variable is not set
+type missingEnvError struct {
+	name string
+}
+
+func (e *missingEnvError) Error() string {
+	return fmt.Sprintf("required environment variable %s not set", e.name)
+}
+
+// Reasons a target ends up with no scanner actually run (RepoScanContext.CoverageGap)
+const (
+	gapNoCompatibleScanners = "no compatible scanners for the detected languages"
+	gapAllSkippedByEnv      = "every selected scanner was skipped (required env vars not set)"
+	gapFailFastAfterEnvSkip = "fail_fast stopped the scan after a scanner was skipped (required env var not set)"
+)
+
+// coverageGap reports why no scanner ran on a target: none were selected, or
+// every result is a skip for a missing env var, including when fail_fast
+// stopped the loop after such a skip. Returns "" when at least one scanner
+// ran, even if it failed.
+func coverageGap(scanners []ScannerConfig, results []ScanResult) string {
+	if len(scanners) == 0 {
+		return gapNoCompatibleScanners
+	}
+	for _, result := range results {
+		var envErr *missingEnvError
+		if !errors.As(result.Error, &envErr) {
+			return ""
+		}
+	}
+	if len(results) < len(scanners) {
+		return gapFailFastAfterEnvSkip
+	}
+	return gapAllSkippedByEnv
 }
 
 // isLocalRepo returns true if the repository uses the local:// URL scheme.
@@ -65,33 +119,77 @@ func isLocalRepo(repo RepositoryConfig) bool {
 
 // repoName extracts a short name from the repository config.
 // For local repos it returns the directory base name; for remote URLs the last path segment.
+// Sub-projects append their path so output filenames don't collide.
 func repoName(repo RepositoryConfig) string {
+	var name string
 	if isLocalRepo(repo) {
-		return filepath.Base(strings.TrimPrefix(repo.URL, "local://"))
+		name = filepath.Base(strings.TrimPrefix(repo.URL, "local://"))
+	} else {
+		parts := strings.Split(repo.URL, "/")
+		name = strings.TrimSuffix(parts[len(parts)-1], ".git")
 	}
-	parts := strings.Split(repo.URL, "/")
-	return strings.TrimSuffix(parts[len(parts)-1], ".git")
+	if suffix := subprojectSuffix(repo.Subproject); suffix != "" {
+		name += "-" + suffix
+	}
+	return name
 }
 
 // runScannersOnRepo executes all applicable scanners against a single repository
 func runScannersOnRepo(config *Config, repo RepositoryConfig, repoPath, commitHash, branchTag, sbomPath string) RepoScanContext {
 	var results []ScanResult
 
-	// Detect languages in the repository (tries GitHub API first, then filesystem)
-	detected, err := detectLanguages(repoPath, repo.URL)
+	// Detect languages in the repository (tries GitHub API first, then filesystem).
+	// Sub-projects always use the filesystem since the API reports whole-repo languages,
+	// and so do repos with submodules since the API ignores submodule contents.
+	languageURL := repo.URL
+	if repo.Subproject != "" || repo.Submodules {
+		languageURL = ""
+	}
+	ignore := loadScanIgnore(repoPath)
+	detected, err := detectLanguages(repoPath, languageURL, ignore)
 	if err != nil {
 		log.Printf("  ⚠️  Failed to detect languages: %v", err)
 		detected = &DetectedLanguages{Languages: []string{}, FileCounts: map[string]int{}}
-	} else {
-		logDetectedLanguages(detected)
+	}
+	if dropped := detected.dropMinorLanguages(config.Global.MinLanguagePercent); len(dropped) > 0 {
+		log.Printf("  ⏭️  Ignoring languages below %g%% (min_language_percent): %s",
+			config.Global.MinLanguagePercent, strings.Join(dropped, ", "))
 	}
 
 	// Determine which scanners to run based on repo config and detected languages
+	if config.Global.Explain {
+		logScannerDecisions(config, repo, detected)
+	}
 	scannersToRun := getScannersForRepo(config, repo, detected)
 
 	// Run each scanner
+	target := repoName(repo)
+	progress.targetStarted(target, len(scannersToRun))
+	defer progress.targetDone(target)
 	for _, scanner := range scannersToRun {
-		result := runScanner(config, scanner, repo, repoPath, commitHash, branchTag, sbomPath)
+		if repo.Submodules {
+			scanner.timeout *= submoduleTimeoutFactor
+		}
+		progress.scannerStarted(target, scanner.Name)
+		events.emit(Event{Event: eventScannerStart, Repository: repo.URL, Subproject: repo.Subproject, Scanner: scanner.Name})
+		result := runScanner(config, scanner, repo, repoPath, commitHash, branchTag, sbomPath, ignore, detected.HelmCharts)
+		if result.Success && !result.IsSarif {
+			checkScanOutput(&result)
+		}
+		if config.Global.RedactSecrets && result.Success && isSecretsScanner(scanner.Name) {
+			redactScanResult(&result)
+		}
+		if scanner.SecurityRulesOnly && result.Success && !result.IsSarif {
+			filterSecurityRules(&result)
+		}
+		if result.Success && !result.IsSarif {
+			enforceFindingLimit(&result, maxFindings(config.Global, scanner))
+		}
+		if events != nil {
+			// Only parse the output for the event when a stream is open
+			events.emit(scannerDoneEvent(repo, result))
+		}
+		progress.scannerDone(target)
 		results = append(results, result)
 
 		if !result.Success && config.Global.FailFast {
@@ -100,17 +198,201 @@ func runScannersOnRepo(config *Config, repo RepositoryConfig, repoPath, commitHa
 		}
 	}
 
+	// Tag results with the sub-project and SBOM so uploads and reports can tell
+	// them apart and upload the SBOM alongside
+	for i := range results {
+		results[i].Subproject = repo.Subproject
+		results[i].SBOMPath = sbomPath
+	}
+
+	gap := coverageGap(scannersToRun, results)
+	if gap != "" {
+		log.Printf("  ⚠️  No scanners ran on %s: %s", repoName(repo), gap)
+	}
+
 	return RepoScanContext{
-		RepoURL:   repo.URL,
-		Results:   results,
-		Languages: detected,
-		Scanners:  scannersToRun,
-		SBOMPath:  sbomPath,
+		RepoURL:     repo.URL,
+		Subproject:  repo.Subproject,
+		Results:     results,
+		Languages:   detected,
+		Scanners:    scannersToRun,
+		SBOMPath:    sbomPath,
+		CoverageGap: gap,
+	}
+}
+
+// filterSecurityRules rewrites a linter's output file to keep only its
+// security findings (security_rules_only). If that fails the result is
+// marked failed rather than reporting the unfiltered findings.
+func filterSecurityRules(result *ScanResult) {
+	parser, _ := parsers.Get(result.Scanner)
+	filter, ok := parser.(parsers.SecurityRuleFilter)
+	if !ok {
+		return
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if os.IsNotExist(err) {
+		return
+	}
+	var filtered []byte
+	var dropped int
+	if err == nil {
+		filtered, dropped, err = filter.FilterSecurityRules(data)
+	}
+	if err == nil && dropped > 0 {
+		err = os.WriteFile(result.OutputPath, filtered, 0644)
+	}
+	if err != nil {
+		log.Printf("    ❌ Failed to filter %s output to security rules: %v", result.Scanner, err)
+		result.Success = false
+		result.Error = fmt.Errorf("filtering security rules: %w", err)
+		return
+	}
+	if dropped > 0 {
+		log.Printf("    🔒 Dropped %d non-security finding(s) from %s output", dropped, result.Scanner)
 	}
 }
 
+// unlimitedFindings is the scanner-level max_findings_per_scanner that lifts
+// the global limit for that scanner; 0 there means "use the global limit"
+const unlimitedFindings = -1
+
+// maxFindings returns a scanner's finding limit: its own
+// max_findings_per_scanner, else the global one (0 = unlimited)
+func maxFindings(global GlobalConfig, scanner ScannerConfig) int {
+	if scanner.MaxFindingsPerScanner == unlimitedFindings {
+		return 0
+	}
+	if scanner.MaxFindingsPerScanner > 0 {
+		return scanner.MaxFindingsPerScanner
+	}
+	return global.MaxFindingsPerScanner
+}
+
+// validateFindingCount returns an error when a result reports more findings
+// than max (0 = unlimited). This is a sanity check for misconfigured
+// scanners, not a severity filter.
+func validateFindingCount(summary parsers.FindingSummary, max int) error {
+	if max > 0 && summary.Total > max {
+		return fmt.Errorf("too many findings: possible misconfiguration (%d, limit %d)", summary.Total, max)
+	}
+	return nil
+}
+
+// enforceFindingLimit marks a result failed and deletes its output file when
+// it has more than max findings, so the noise is neither summarized nor
+// uploaded. Results without a parser can't be counted and are kept.
+func enforceFindingLimit(result *ScanResult, max int) {
+	if max <= 0 {
+		return
+	}
+	summary, parser := parseScanOutput(*result)
+	if parser == nil {
+		return
+	}
+	err := validateFindingCount(summary, max)
+	if err == nil {
+		return
+	}
+	log.Printf("    ❌ %s: %v", result.Scanner, err)
+	result.Success = false
+	result.Error = err
+	if removeErr := os.Remove(result.OutputPath); removeErr != nil && !os.IsNotExist(removeErr) {
+		log.Printf("    ⚠️  Failed to delete %s output: %v", result.Scanner, removeErr)
+	}
+}
+
+// scannerTimeoutError marks a result whose scanner was killed when it ran
+// past its timeout, as opposed to crashing or exiting with an error
+type scannerTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *scannerTimeoutError) Error() string {
+	return "timed out after " + formatTimeout(e.timeout)
+}
+
+// formatTimeout formats a timeout without zero trailing units, so 5m0s
+// reads as 5m and 1h30m0s as 1h30m
+func formatTimeout(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// invalidOutputError marks a result whose output file can't be parsed at all,
+// typically because the scanner crashed mid-write: binary data, invalid UTF-8
+// or malformed JSON
+type invalidOutputError struct {
+	reason string
+	stderr string // first stderr line, when the scanner printed one
+}
+
+func (e *invalidOutputError) Error() string {
+	return "invalid output: " + e.detail()
+}
+
+// detail describes the problem and the scanner's stderr, for the summary
+func (e *invalidOutputError) detail() string {
+	if e.stderr == "" {
+		return e.reason
+	}
+	return fmt.Sprintf("%s (stderr: %s)", e.reason, e.stderr)
+}
+
+// invalidOutputReason returns why scanner output can't be parsed, or "" for
+// well-formed JSON or NDJSON (empty output included)
+func invalidOutputReason(data []byte) string {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "binary data (null bytes)"
+	}
+	if !utf8.Valid(data) {
+		return "not valid UTF-8"
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value json.RawMessage
+		if err := dec.Decode(&value); err == io.EOF {
+			return ""
+		} else if err != nil {
+			return "not valid JSON: " + err.Error()
+		}
+	}
+}
+
+// checkScanOutput marks a result failed with an invalidOutputError when its
+// output file isn't well-formed JSON, so garbage from a crashed scanner is
+// reported instead of silently showing no findings. Results without a parser
+// aren't checked, and the file is kept for inspection. Well-formed output
+// from an untested schema version only logs a warning.
+func checkScanOutput(result *ScanResult) {
+	parser, ok := parsers.Get(result.Scanner)
+	if !ok {
+		return
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		return // no output file: nothing was found
+	}
+	reason := invalidOutputReason(data)
+	if reason == "" {
+		if warning := parsers.CheckSchemaVersion(parser, data); warning != "" {
+			log.Printf("    ⚠️  %s: %s", result.Scanner, warning)
+		}
+		return
+	}
+	result.Success = false
+	result.Error = &invalidOutputError{reason: reason, stderr: result.StderrLine}
+	log.Printf("    ❌ %s: %v", result.Scanner, result.Error)
+}
+
 // getScannersForRepo determines which scanners to run on a repository
-// It filters based on repo-specific scanner list, enabled status, language compatibility,
+// It filters based on repo-specific scanner list or bundle, enabled status, language compatibility,
 // and the global --scan filter (which overrides enabled status).
 func getScannersForRepo(config *Config, repo RepositoryConfig, detected *DetectedLanguages) []ScannerConfig {
 	var scanners []ScannerConfig
@@ -129,21 +411,22 @@ func getScannersForRepo(config *Config, repo RepositoryConfig, detected *Detecte
 			if isScannerCompatible(scanner, detected) {
 				scanners = append(scanners, scanner)
 			} else {
-				log.Printf("    ⏭️  Skipping %s: no compatible languages detected", scanner.Name)
+				log.Printf("    ⏭️  Skipping %s: %s", scanner.Name, incompatibleReason(scanner, detected))
 			}
 		}
 		return scanners
 	}
 
-	// If repo specifies scanners, use only those (still filtered by language)
-	if len(repo.Scanners) > 0 {
-		for _, name := range repo.Scanners {
+	// If repo specifies scanners (directly or via a bundle), use only those
+	// (still filtered by language)
+	if names := repoScannerNames(config.Global, repo); len(names) > 0 {
+		for _, name := range names {
 			for _, scanner := range config.Scanners {
 				if scanner.Name == name && scanner.Enabled {
 					if isScannerCompatible(scanner, detected) {
 						scanners = append(scanners, scanner)
 					} else {
-						log.Printf("    ⏭️  Skipping %s: no compatible languages detected", scanner.Name)
+						log.Printf("    ⏭️  Skipping %s: %s", scanner.Name, incompatibleReason(scanner, detected))
 					}
 					break
 				}
@@ -158,7 +441,7 @@ func getScannersForRepo(config *Config, repo RepositoryConfig, detected *Detecte
 			if isScannerCompatible(scanner, detected) {
 				scanners = append(scanners, scanner)
 			} else {
-				log.Printf("    ⏭️  Skipping %s: no compatible languages detected", scanner.Name)
+				log.Printf("    ⏭️  Skipping %s: %s", scanner.Name, incompatibleReason(scanner, detected))
 			}
 		}
 	}
@@ -169,7 +452,36 @@ func getScannersForRepo(config *Config, repo RepositoryConfig, detected *Detecte
 // isScannerCompatible checks if a scanner should run based on detected languages
 // Scanners with empty Languages list are considered universal and always run.
 // Scanners also run if a detected language matches LanguagesConditional.
+// Scanners with RequiredManifests also need one of those files in the checkout.
 func isScannerCompatible(scanner ScannerConfig, detected *DetectedLanguages) bool {
+	return hasCompatibleLanguage(scanner, detected) && hasRequiredManifest(scanner, detected)
+}
+
+// incompatibleReason explains why isScannerCompatible rejected a scanner
+func incompatibleReason(scanner ScannerConfig, detected *DetectedLanguages) string {
+	if !hasCompatibleLanguage(scanner, detected) {
+		return "no compatible languages detected"
+	}
+	return "none of " + strings.Join(scanner.RequiredManifests, ", ") + " found"
+}
+
+// hasRequiredManifest reports whether the checkout has at least one of the
+// scanner's RequiredManifests (always true when it lists none)
+func hasRequiredManifest(scanner ScannerConfig, detected *DetectedLanguages) bool {
+	if len(scanner.RequiredManifests) == 0 {
+		return true
+	}
+	for _, name := range scanner.RequiredManifests {
+		if detected.hasManifest(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCompatibleLanguage checks the scanner's Languages and LanguagesConditional
+// against the detected languages
+func hasCompatibleLanguage(scanner ScannerConfig, detected *DetectedLanguages) bool {
 	// If scanner has no language restrictions, it's compatible with everything
 	if len(scanner.Languages) == 0 {
 		return true
@@ -189,20 +501,220 @@ func isScannerCompatible(scanner ScannerConfig, detected *DetectedLanguages) boo
 	return detected.hasAnyLanguage(scanner.LanguagesConditional)
 }
 
-// buildScanResultFilename constructs a filename for a scanner's output file.
-// Pattern: {repoName}_{version}_{scannerName}_{timestamp}{ext} for version tags
-//          {repoName}_{commitHash}_{scannerName}_{timestamp}{ext} for branch-only targets
-func buildScanResultFilename(repoName, scannerName, branchTag, commitHash, timestamp, ext string) string {
+// defaultResultNameTemplate is the result filename used without
+// result_name_template: {ref} is the version for version tags and the commit
+// hash for branch-only targets
+const defaultResultNameTemplate = "{repo}_{ref}_{scanner}_{date}"
+
+// resultNameTokenPattern matches the {token} placeholders of a result name template
+var resultNameTokenPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
+// resultNameTokens are the placeholders a result name template may use
+var resultNameTokens = map[string]bool{
+	"{repo}": true, "{scanner}": true, "{commit}": true, "{branch}": true, "{ref}": true, "{date}": true,
+}
+
+// validateResultNameTemplate checks that a result_name_template only uses
+// known tokens, names each repo's and scanner's results apart ({repo} and
+// {scanner}), and can't escape results_dir: path separators are rejected in
+// the template, and token values have theirs replaced when rendered.
+func validateResultNameTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	for _, token := range resultNameTokenPattern.FindAllString(template, -1) {
+		if !resultNameTokens[token] {
+			return fmt.Errorf("result_name_template: unknown token %s", token)
+		}
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return fmt.Errorf("result_name_template %q must not contain path separators", template)
+	}
+	if !strings.Contains(template, "{repo}") || !strings.Contains(template, "{scanner}") {
+		return fmt.Errorf("result_name_template %q must contain {repo} and {scanner}", template)
+	}
+	return nil
+}
+
+// results_layout values: every result directly in results_dir, or each repo's
+// results under results_dir/<owner>/<repo>/
+const (
+	resultsLayoutFlat    = "flat"
+	resultsLayoutPerRepo = "per-repo"
+)
+
+// validateResultsLayout rejects unknown results_layout values
+func validateResultsLayout(layout string) error {
+	switch layout {
+	case "", resultsLayoutFlat, resultsLayoutPerRepo:
+		return nil
+	}
+	return fmt.Errorf("results_layout must be %q or %q, got %q", resultsLayoutFlat, resultsLayoutPerRepo, layout)
+}
+
+// repoResultsDir returns the directory a repo's scanner results are written
+// to: resultsDir itself, or resultsDir/<owner>/<repo> with the per-repo
+// layout. Local targets use "local" as the owner. Sub-projects share their
+// repo's directory; their filenames already tell them apart.
+func repoResultsDir(resultsDir, layout string, repo RepositoryConfig) string {
+	if layout != resultsLayoutPerRepo {
+		return resultsDir
+	}
+	owner, name := "local", filepath.Base(strings.TrimPrefix(repo.URL, "local://"))
+	if !isLocalRepo(repo) {
+		parts := strings.Split(strings.TrimSuffix(normalizeGitURL(repo.URL), ".git"), "/")
+		if len(parts) >= 2 {
+			owner = parts[len(parts)-2]
+		}
+		name = parts[len(parts)-1]
+	}
+	// URL segments must not add or leave directories
+	safe := func(segment string) string {
+		if segment == "" || segment == "." || segment == ".." {
+			return "_"
+		}
+		return strings.NewReplacer("/", "-", `\`, "-", ":", "-").Replace(segment)
+	}
+	return filepath.Join(resultsDir, safe(owner), safe(name))
+}
+
+// buildScanResultFilename constructs a filename for a scanner's output file
+// from template (defaultResultNameTemplate when empty) and ext, e.g.
+// grype_v0.87.0_gosec_20260304.json for a version tag and
+// allscan_def5678_grype_20260304.json for a branch
+func buildScanResultFilename(template, repoName, scannerName, branchTag, commitHash, timestamp, ext string) string {
+	if template == "" {
+		template = defaultResultNameTemplate
+	}
+	ref := commitHash
 	if isVersionTag(branchTag) {
-		return fmt.Sprintf("%s_%s_%s_%s%s", repoName, branchTag, scannerName, timestamp, ext)
+		ref = branchTag
+	}
+	// Branch names like feature/login must not add directories
+	safe := strings.NewReplacer("/", "-", `\`, "-")
+	replacer := strings.NewReplacer(
+		"{repo}", safe.Replace(repoName),
+		"{scanner}", safe.Replace(scannerName),
+		"{commit}", safe.Replace(commitHash),
+		"{branch}", safe.Replace(branchTag),
+		"{ref}", safe.Replace(ref),
+		"{date}", timestamp,
+	)
+	return replacer.Replace(template) + ext
+}
+
+// scannerRetryDelay is the pause between retries of a failed scanner
+var scannerRetryDelay = 2 * time.Second
+
+// scannerCommand builds a scanner's command with the environment set up for
+// offline DBs and a prefetched grype DB, plus the scanner's env
+func scannerCommand(ctx context.Context, scanner ScannerConfig, args []string, dir string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, scanner.Command, args...)
+	cmd.Dir = dir
+	setOfflineDBEnv(cmd)
+	setGrypeDBEnv(cmd)
+	if len(scanner.Env) > 0 {
+		appendCmdEnv(cmd, envVars(scanner.Env)...)
 	}
-	return fmt.Sprintf("%s_%s_%s_%s%s", repoName, commitHash, scannerName, timestamp, ext)
+	return cmd
 }
 
-// runScanner executes a single scanner against a repository
-func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, repoPath, commitHash, branchTag, sbomPath string) ScanResult {
+// execScanner runs a scanner command once with its timeout. For stdout-only
+// scanners, stdout is kept separate from stderr so that progress messages on
+// stderr don't corrupt the JSON output; otherwise combined output is returned.
+// diagnostics is what the scanner printed besides its results: stderr, or the
+// combined output of scanners that write their own output file.
+func execScanner(scanner ScannerConfig, args []string, dir string, stdoutOnly bool) (output, diagnostics []byte, timedOut bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), scanner.timeout)
+	defer cancel()
+
+	cmd := scannerCommand(ctx, scanner, args, dir)
+
+	if stdoutOnly {
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err = cmd.Run()
+		output, diagnostics = stdout.Bytes(), stderr.Bytes()
+	} else {
+		output, err = cmd.CombinedOutput()
+		diagnostics = output
+	}
+	return output, diagnostics, errors.Is(ctx.Err(), context.DeadlineExceeded), err
+}
+
+// hasScanOutput reports whether a failed scanner still produced usable output:
+// either it wrote the output file, or (stdout-only) it printed something.
+func hasScanOutput(outputPath string, output []byte, stdoutOnly bool) bool {
+	if _, err := os.Stat(outputPath); err == nil {
+		return true
+	}
+	return stdoutOnly && len(output) > 0
+}
+
+// expandArgTemplates substitutes the template variables in scanner args:
+//
+//	{{output}}   - result file path
+//	{{repo}}     - repository URL (local://<path> in --local mode)
+//	{{reponame}} - repository name (directory basename in --local mode)
+//	{{commit}}   - short commit hash, or "unknown" when not in a git repo
+//	{{sbom}}     - CycloneDX SBOM path
+//	{{rendered}} - rendered Helm chart manifests (scanners with render_helm)
+func expandArgTemplates(args []string, repo RepositoryConfig, outputPath, sbomPath, commitHash, renderedDir string) []string {
+	if commitHash == "" {
+		commitHash = "unknown"
+	}
+	replacer := strings.NewReplacer(
+		"{{output}}", outputPath,
+		"{{repo}}", repo.URL,
+		"{{reponame}}", repoName(repo),
+		"{{commit}}", commitHash,
+		"{{sbom}}", sbomPath,
+		"{{rendered}}", renderedDir,
+	)
+
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = replacer.Replace(arg)
+	}
+	return expanded
+}
+
+// resolveWorkingDir returns the directory a scanner runs in: repoPath, or its
+// working_dir with {{repo}} replaced by repoPath. A relative working_dir is
+// taken relative to repoPath. It must stay inside the repo and exist.
+func resolveWorkingDir(workingDir, repoPath string) (string, error) {
+	if workingDir == "" {
+		return repoPath, nil
+	}
+	dir := strings.ReplaceAll(workingDir, "{{repo}}", repoPath)
+	if !filepath.IsAbs(dir) && !strings.HasPrefix(workingDir, "{{repo}}") {
+		dir = filepath.Join(repoPath, dir)
+	}
+	dir = filepath.Clean(dir)
+
+	rel, err := filepath.Rel(repoPath, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working_dir %q is outside the repository", workingDir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("working_dir %q not found in the repository", workingDir)
+	}
+	return dir, nil
+}
+
+// runScanner executes a single scanner against a repository. Built-in
+// scanners skip the paths matched by ignore, the checkout's .allscanignore,
+// and render_helm renders helmCharts, the checkout's detected chart
+// directories.
+// With record_commands, a scanner that ran gets its command and version
+// recorded.
+func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, repoPath, commitHash, branchTag, sbomPath string, ignore *parsers.IgnorePatterns, helmCharts []string) (result ScanResult) {
 	start := time.Now()
 
+	// Results carry the repo's scan type for this scanner, if it sets one
+	scanner.DojoScanType = dojoScanType(scanner, repo)
+
 	// Select args based on SARIF and local mode
 	localMode := isLocalRepo(repo)
 	selectedArgs, isSarif := selectArgs(scanner, config.Global.SarifMode, localMode)
@@ -219,14 +731,18 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 		}
 	}
 
+	// The repo's env applies to every scanner run on it, under the scanner's
+	// own, and can supply the scanner's required env
+	scanner.Env = layerEnv(repo.Env, scanner.Env)
+
 	// Check required environment variables before doing any work
-	if missing := checkRequiredEnv(scanner.RequiredEnv); missing != "" {
+	if missing := checkRequiredEnv(scanner.RequiredEnv, scanner.Env); missing != "" {
 		log.Printf("    ⏭️  Skipping %s: required env var %s not set", scanner.Name, missing)
 		return ScanResult{
 			Scanner:      scanner.Name,
 			Repository:   repo.URL,
 			Success:      false,
-			Error:        fmt.Errorf("required environment variable %s not set", missing),
+			Error:        &missingEnvError{name: missing},
 			Duration:     time.Since(start),
 			DojoScanType: scanner.DojoScanType,
 			CommitHash:   commitHash,
@@ -243,13 +759,14 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 	if isSarif {
 		ext = ".sarif"
 	}
-	outputFilename := buildScanResultFilename(name, scanner.Name, branchTag, commitHash, timestamp, ext)
+	outputFilename := buildScanResultFilename(config.Global.ResultNameTemplate, name, scanner.Name, branchTag, commitHash, timestamp, ext)
 
 	// Convert to absolute path
 	resultsDir, err := filepath.Abs(config.Global.ResultsDir)
 	if err != nil {
 		resultsDir = config.Global.ResultsDir
 	}
+	resultsDir = repoResultsDir(resultsDir, config.Global.ResultsLayout, repo)
 	outputPath := filepath.Join(resultsDir, outputFilename)
 
 	// Ensure output directory exists (create if needed)
@@ -268,16 +785,40 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 		}
 	}
 
+	// Resolve where the scanner runs (the repo root unless working_dir is set)
+	workDir, err := resolveWorkingDir(scanner.WorkingDir, repoPath)
+	if err != nil {
+		log.Printf("    ❌ %s: %v", scanner.Name, err)
+		return ScanResult{
+			Scanner:      scanner.Name,
+			Repository:   repo.URL,
+			OutputPath:   outputPath,
+			Success:      false,
+			Error:        err,
+			Duration:     time.Since(start),
+			DojoScanType: scanner.DojoScanType,
+			CommitHash:   commitHash,
+			BranchTag:    branchTag,
+		}
+	}
+
 	log.Printf("  🔎 Running %s...", scanner.Name)
 
 	// Handle built-in scanners
-	if scanner.Command == "builtin:binary-detector" {
+	if strings.HasPrefix(scanner.Command, "builtin:") {
+		if config.Global.RecordCommands {
+			defer func() { recordScannerCommand(&result, scanner, scanner.Command, selectedArgs) }()
+		}
 		builtinSarif := config.Global.SarifMode
 		actualOutputPath := outputPath
 		if builtinSarif {
 			actualOutputPath = strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".sarif"
 		}
-		count, err := parsers.RunBinaryDetector(repoPath, actualOutputPath, builtinSarif)
+		// .allscanignore patterns are relative to the repo root, not working_dir
+		if rel, err := filepath.Rel(repoPath, workDir); err == nil {
+			ignore = ignore.Within(rel)
+		}
+		found, err := runBuiltinScanner(scanner, selectedArgs, workDir, actualOutputPath, builtinSarif, ignore)
 		duration := time.Since(start)
 		if err != nil {
 			log.Printf("    ❌ %s failed: %v", scanner.Name, err)
@@ -293,8 +834,8 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 				BranchTag:    branchTag,
 			}
 		}
-		if count > 0 {
-			log.Printf("    ✅ %s completed in %v (found %d binaries)", scanner.Name, duration, count)
+		if found != "" {
+			log.Printf("    ✅ %s completed in %v (%s)", scanner.Name, duration, found)
 		} else {
 			log.Printf("    ✅ %s completed in %v", scanner.Name, duration)
 		}
@@ -311,9 +852,13 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 		}
 	}
 
-	// Check if scanner binary exists
-	if _, err := exec.LookPath(scanner.Command); err != nil {
-		log.Printf("    ❌ Scanner %s not found in PATH", scanner.Command)
+	// Check if scanner binary (or the container runtime for image scanners) exists
+	program := scanner.Command
+	if scanner.Image != "" {
+		program = containerRuntime(config.Global)
+	}
+	if _, err := exec.LookPath(program); err != nil {
+		log.Printf("    ❌ Scanner %s not found in PATH", program)
 		return ScanResult{
 			Scanner:      scanner.Name,
 			Repository:   repo.URL,
@@ -336,40 +881,90 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 		}
 	}
 
-	// Prepare arguments with template substitution
-	args := make([]string, len(selectedArgs))
-	for i, arg := range selectedArgs {
-		arg = strings.ReplaceAll(arg, "{{output}}", outputPath)
-		arg = strings.ReplaceAll(arg, "{{repo}}", repo.URL)
-		arg = strings.ReplaceAll(arg, "{{sbom}}", sbomPath)
-		args[i] = arg
+	// Render Helm charts for scanners that only understand plain manifests
+	var renderedDir string
+	if scanner.RenderHelm {
+		renderedDir, err = renderHelmCharts(repoPath, workDir, helmCharts, scanner.timeout)
+		if err != nil {
+			log.Printf("    ❌ %s: %v", scanner.Name, err)
+			return ScanResult{
+				Scanner:      scanner.Name,
+				Repository:   repo.URL,
+				OutputPath:   outputPath,
+				Success:      false,
+				Error:        err,
+				Duration:     time.Since(start),
+				DojoScanType: scanner.DojoScanType,
+				CommitHash:   commitHash,
+				BranchTag:    branchTag,
+			}
+		}
+		defer os.RemoveAll(renderedDir)
 	}
 
-	// Create command with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), scanner.timeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, scanner.Command, args...)
-	cmd.Dir = repoPath
+	// Prepare arguments with template substitution
+	args := expandArgTemplates(selectedArgs, repo, outputPath, sbomPath, commitHash, renderedDir)
+	run := scanner
+	var container string
+	scanRoot, _ := filepath.Abs(repoPath)
+	defer func() { result.ScanRoot = scanRoot }()
+	if scanner.Image != "" {
+		// Run the image with the repo and results mounted; args see container paths
+		run.Command = program
+		container = containerName(scanner.Name)
+		scanRoot = containerRepoDir
+		args, err = containerRunArgs(program, container, scanner, selectedArgs, repo, repoPath, workDir, resultsDir, outputPath, sbomPath, commitHash, renderedDir)
+		if err != nil {
+			log.Printf("    ❌ %s: %v", scanner.Name, err)
+			return ScanResult{
+				Scanner:      scanner.Name,
+				Repository:   repo.URL,
+				OutputPath:   outputPath,
+				Success:      false,
+				Error:        err,
+				Duration:     time.Since(start),
+				DojoScanType: scanner.DojoScanType,
+				CommitHash:   commitHash,
+				BranchTag:    branchTag,
+			}
+		}
+	}
+	if config.Global.RecordCommands {
+		defer func() { recordScannerCommand(&result, scanner, program, args) }()
+	}
 
-	// Capture output — for stdout-only scanners, keep stdout separate from stderr
-	// so that progress messages on stderr don't corrupt the JSON output.
-	var output []byte
-	if stdoutOnly {
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-		err = cmd.Run()
-		output = stdout.Bytes()
-	} else {
-		output, err = cmd.CombinedOutput()
+	// Run the scanner, retrying transient failures (non-zero exit with no output)
+	var output, diagnostics []byte
+	var timedOut bool
+	for attempt := 0; ; attempt++ {
+		output, diagnostics, timedOut, err = execScanner(run, args, workDir, stdoutOnly)
+		if err == nil || timedOut || attempt >= scanner.Retries || hasScanOutput(outputPath, output, stdoutOnly) {
+			break
+		}
+		log.Printf("    🔁 %s failed (%v), retrying in %v (%d/%d)", scanner.Name, err, scannerRetryDelay, attempt+1, scanner.Retries)
+		time.Sleep(scannerRetryDelay)
 	}
 
 	duration := time.Since(start)
+	defer func() { result.StderrLine = firstLine(diagnostics) }()
+
+	// A killed scanner's "signal: killed" reads like a crash; report the
+	// timeout instead, and remove output it may have left half-written so
+	// --parse-only doesn't read it later. Killing the runtime client doesn't
+	// stop an image scanner's container, so it goes first.
+	if timedOut {
+		err = &scannerTimeoutError{timeout: scanner.timeout}
+		if container != "" {
+			removeContainer(program, container)
+		}
+		if rmErr := os.Remove(outputPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			log.Printf("    ⚠️  Failed to remove partial output of %s: %v", scanner.Name, rmErr)
+		}
+	}
 
 	if err != nil {
 		// Some scanners return non-zero on findings, check if output file was created
-		if _, statErr := os.Stat(outputPath); statErr == nil {
+		if _, statErr := os.Stat(outputPath); statErr == nil && !timedOut {
 			log.Printf("    ✅ %s completed in %v (with findings)", scanner.Name, duration)
 			return ScanResult{
 				Scanner:      scanner.Name,
@@ -386,7 +981,7 @@ func runScanner(config *Config, scanner ScannerConfig, repo RepositoryConfig, re
 		}
 
 		// Stdout-only scanners that exit non-zero may still have valid output
-		if stdoutOnly && len(output) > 0 {
+		if stdoutOnly && len(output) > 0 && !timedOut {
 			if writeErr := os.WriteFile(outputPath, output, 0644); writeErr == nil {
 				log.Printf("    ✅ %s completed in %v (with findings)", scanner.Name, duration)
 				return ScanResult{
@@ -1,6 +1,17 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"allscan/parsers"
+)
 
 func TestIsScannerCompatible(t *testing.T) {
 	tests := []struct {
@@ -57,6 +68,24 @@ func TestIsScannerCompatible(t *testing.T) {
 			detected: &DetectedLanguages{Languages: []string{"java"}},
 			want:     false,
 		},
+		{
+			name:     "required manifest present",
+			scanner:  ScannerConfig{Languages: []string{"python"}, RequiredManifests: []string{"requirements.txt", "poetry.lock"}},
+			detected: &DetectedLanguages{Languages: []string{"python"}, ManifestFiles: map[string]bool{"poetry.lock": true}},
+			want:     true,
+		},
+		{
+			name:     "required manifest missing",
+			scanner:  ScannerConfig{Languages: []string{"python"}, RequiredManifests: []string{"requirements.txt", "poetry.lock"}},
+			detected: &DetectedLanguages{Languages: []string{"python"}, ManifestFiles: map[string]bool{"pyproject.toml": true}},
+			want:     false,
+		},
+		{
+			name:     "required manifest applies to universal scanners",
+			scanner:  ScannerConfig{RequiredManifests: []string{"go.mod"}},
+			detected: &DetectedLanguages{Languages: []string{"go"}},
+			want:     false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,11 +148,34 @@ func TestGetScannersForRepo(t *testing.T) {
 			detected:  &DetectedLanguages{Languages: []string{}},
 			wantNames: []string{"grype"},
 		},
+		{
+			name:      "bundle selects its scanners",
+			repo:      RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "light"},
+			detected:  &DetectedLanguages{Languages: []string{"go"}},
+			wantNames: []string{"grype"},
+		},
+		{
+			name:      "bundle scanners still filtered by language",
+			repo:      RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "full"},
+			detected:  &DetectedLanguages{Languages: []string{"java"}},
+			wantNames: []string{"grype", "java-scanner"},
+		},
+		{
+			name:      "explicit scanners override bundle",
+			repo:      RepositoryConfig{URL: "https://github.com/org/repo", Bundle: "light", Scanners: []string{"gosec"}},
+			detected:  &DetectedLanguages{Languages: []string{"go"}},
+			wantNames: []string{"gosec"},
+		},
+	}
+
+	bundles := map[string][]string{
+		"light": {"grype"},
+		"full":  {"grype", "gosec", "java-scanner"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := &Config{Scanners: allScanners}
+			config := &Config{Scanners: allScanners, Global: GlobalConfig{ScannerBundles: bundles}}
 			got := getScannersForRepo(config, tt.repo, tt.detected)
 
 			gotNames := make([]string, len(got))
@@ -327,6 +379,7 @@ func TestSelectArgs(t *testing.T) {
 func TestBuildScanResultFilename(t *testing.T) {
 	tests := []struct {
 		name        string
+		template    string
 		repoName    string
 		scannerName string
 		branchTag   string
@@ -375,11 +428,44 @@ func TestBuildScanResultFilename(t *testing.T) {
 			ext:         ".json",
 			want:        "myrepo_aaa1111_trivy_20260304.json",
 		},
+		{
+			name:        "template with every token",
+			template:    "{date}-{repo}-{branch}-{commit}-{ref}-{scanner}",
+			repoName:    "allscan",
+			scannerName: "grype",
+			branchTag:   "main",
+			commitHash:  "def5678",
+			timestamp:   "20260304",
+			ext:         ".json",
+			want:        "20260304-allscan-main-def5678-def5678-grype.json",
+		},
+		{
+			name:        "template ref is the version for tags",
+			template:    "{repo}-{ref}-{scanner}",
+			repoName:    "myrepo",
+			scannerName: "semgrep",
+			branchTag:   "v1.2.3",
+			commitHash:  "abc1234",
+			timestamp:   "20260304",
+			ext:         ".sarif",
+			want:        "myrepo-v1.2.3-semgrep.sarif",
+		},
+		{
+			name:        "path separators in branch names are replaced",
+			template:    "{repo}_{branch}_{scanner}",
+			repoName:    "myrepo",
+			scannerName: "gosec",
+			branchTag:   "feature/../../etc",
+			commitHash:  "abc1234",
+			timestamp:   "20260304",
+			ext:         ".json",
+			want:        "myrepo_feature-..-..-etc_gosec.json",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildScanResultFilename(tt.repoName, tt.scannerName, tt.branchTag, tt.commitHash, tt.timestamp, tt.ext)
+			got := buildScanResultFilename(tt.template, tt.repoName, tt.scannerName, tt.branchTag, tt.commitHash, tt.timestamp, tt.ext)
 			if got != tt.want {
 				t.Errorf("buildScanResultFilename() = %q, want %q", got, tt.want)
 			}
@@ -387,12 +473,70 @@ func TestBuildScanResultFilename(t *testing.T) {
 	}
 }
 
+func TestRepoResultsDir(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		repo   RepositoryConfig
+		want   string
+	}{
+		{"flat by default", "", RepositoryConfig{URL: "https://github.com/org/api"}, "/results"},
+		{"flat", resultsLayoutFlat, RepositoryConfig{URL: "https://github.com/org/api"}, "/results"},
+		{"per-repo", resultsLayoutPerRepo, RepositoryConfig{URL: "https://github.com/org/api"}, "/results/org/api"},
+		{"per-repo .git suffix", resultsLayoutPerRepo, RepositoryConfig{URL: "https://github.com/org/api.git"}, "/results/org/api"},
+		{"per-repo scp URL", resultsLayoutPerRepo, RepositoryConfig{URL: "git@github.com:org/api.git"}, "/results/org/api"},
+		{"per-repo nested group uses the last two segments", resultsLayoutPerRepo, RepositoryConfig{URL: "https://gitlab.com/team/platform/api"}, "/results/platform/api"},
+		{"per-repo sub-project shares the repo dir", resultsLayoutPerRepo, RepositoryConfig{URL: "https://github.com/org/mono", Subproject: "services/api"}, "/results/org/mono"},
+		{"per-repo local target", resultsLayoutPerRepo, RepositoryConfig{URL: "local:///home/me/project"}, "/results/local/project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repoResultsDir("/results", tt.layout, tt.repo); got != filepath.FromSlash(tt.want) {
+				t.Errorf("repoResultsDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := validateResultsLayout("nested"); err == nil {
+		t.Error("validateResultsLayout(\"nested\") = nil, want error")
+	}
+}
+
+func TestValidateResultNameTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "empty uses the default", template: ""},
+		{name: "default pattern", template: defaultResultNameTemplate},
+		{name: "every token", template: "{date}-{repo}-{branch}-{commit}-{ref}-{scanner}"},
+		{name: "unknown token", template: "{repo}_{scanner}_{time}", wantErr: true},
+		{name: "path traversal", template: "../{repo}_{scanner}", wantErr: true},
+		{name: "subdirectory", template: "{repo}/{scanner}", wantErr: true},
+		{name: "windows separator", template: `..\{repo}_{scanner}`, wantErr: true},
+		{name: "missing scanner", template: "{repo}_{date}", wantErr: true},
+		{name: "missing repo", template: "{scanner}_{date}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateResultNameTemplate(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("validateResultNameTemplate(%q) error = %v, wantErr %v", tt.template, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestCheckRequiredEnv(t *testing.T) {
 	tests := []struct {
 		name     string
 		required []string
 		envVars  map[string]string
+		env      map[string]string // the scanner's layered env: entries
 		want     string
+		wantAll  []string
 	}{
 		{
 			name:     "no required vars",
@@ -410,12 +554,28 @@ func TestCheckRequiredEnv(t *testing.T) {
 			required: []string{"MISSING_VAR", "TEST_VAR_C"},
 			envVars:  map[string]string{"TEST_VAR_C": "val"},
 			want:     "MISSING_VAR",
+			wantAll:  []string{"MISSING_VAR"},
 		},
 		{
 			name:     "second var missing",
 			required: []string{"TEST_VAR_D", "ALSO_MISSING"},
 			envVars:  map[string]string{"TEST_VAR_D": "val"},
 			want:     "ALSO_MISSING",
+			wantAll:  []string{"ALSO_MISSING"},
+		},
+		{
+			name:     "several vars missing",
+			required: []string{"MISSING_ONE", "TEST_VAR_E", "MISSING_TWO", "MISSING_THREE"},
+			envVars:  map[string]string{"TEST_VAR_E": "val"},
+			want:     "MISSING_ONE",
+			wantAll:  []string{"MISSING_ONE", "MISSING_TWO", "MISSING_THREE"},
+		},
+		{
+			name:     "supplied by env entries",
+			required: []string{"ENV_ENTRY_VAR", "STILL_MISSING"},
+			env:      map[string]string{"ENV_ENTRY_VAR": "val", "STILL_MISSING": "${UNSET_ALLSCAN_VAR}"},
+			want:     "STILL_MISSING",
+			wantAll:  []string{"STILL_MISSING"},
 		},
 		{
 			name:     "nil required list",
@@ -429,10 +589,608 @@ func TestCheckRequiredEnv(t *testing.T) {
 			for k, v := range tt.envVars {
 				t.Setenv(k, v)
 			}
-			got := checkRequiredEnv(tt.required)
+			got := checkRequiredEnv(tt.required, tt.env)
 			if got != tt.want {
 				t.Errorf("checkRequiredEnv() = %q, want %q", got, tt.want)
 			}
+			if all := missingRequiredEnv(tt.required, tt.env); !reflect.DeepEqual(all, tt.wantAll) {
+				t.Errorf("missingRequiredEnv() = %v, want %v", all, tt.wantAll)
+			}
+
+			// checkAllRequiredEnv reports every missing var of an enabled scanner
+			config := &Config{Scanners: []ScannerConfig{
+				{Name: "scanner", Enabled: true, RequiredEnv: tt.required, Env: tt.env},
+				{Name: "disabled", Enabled: false, RequiredEnv: tt.required},
+			}}
+			missing := checkAllRequiredEnv(config, true)
+			if !reflect.DeepEqual(missing["scanner"], tt.wantAll) {
+				t.Errorf("checkAllRequiredEnv()[scanner] = %v, want %v", missing["scanner"], tt.wantAll)
+			}
+			if _, ok := missing["disabled"]; ok {
+				t.Error("checkAllRequiredEnv() reported a disabled scanner")
+			}
+		})
+	}
+}
+
+func TestCoverageGap(t *testing.T) {
+	envSkip := ScanResult{Scanner: "snyk", Error: &missingEnvError{name: "SNYK_TOKEN"}}
+	failed := ScanResult{Scanner: "gosec", Error: errors.New("exit status 2")}
+	scanners := []ScannerConfig{{Name: "snyk"}, {Name: "gosec"}}
+
+	tests := []struct {
+		name     string
+		scanners []ScannerConfig
+		results  []ScanResult
+		want     string
+	}{
+		{"no compatible scanners", nil, nil, gapNoCompatibleScanners},
+		{"all skipped by env", scanners, []ScanResult{envSkip, envSkip}, gapAllSkippedByEnv},
+		{"one scanner ran and failed", scanners, []ScanResult{envSkip, failed}, ""},
+		{"one scanner succeeded", scanners, []ScanResult{envSkip, {Scanner: "gosec", Success: true}}, ""},
+		{"fail-fast stopped after a scanner ran", scanners, []ScanResult{failed}, ""},
+		{"fail-fast stopped after an env skip", scanners, []ScanResult{envSkip}, gapFailFastAfterEnvSkip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coverageGap(tt.scanners, tt.results); got != tt.want {
+				t.Errorf("coverageGap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("runScannersOnRepo records the gap", func(t *testing.T) {
+		dir := t.TempDir()
+		repo := RepositoryConfig{URL: "local://" + dir}
+		t.Setenv("ALLSCAN_TEST_UNSET_TOKEN", "")
+
+		config := &Config{Scanners: []ScannerConfig{{Name: "gosec", Enabled: true, Languages: []string{"go"}}}}
+		if ctx := runScannersOnRepo(config, repo, dir, "abc123", "", ""); ctx.CoverageGap != gapNoCompatibleScanners {
+			t.Errorf("CoverageGap = %q, want %q", ctx.CoverageGap, gapNoCompatibleScanners)
+		}
+
+		config.Scanners = []ScannerConfig{{Name: "snyk", Enabled: true, RequiredEnv: []string{"ALLSCAN_TEST_UNSET_TOKEN"}}}
+		if ctx := runScannersOnRepo(config, repo, dir, "abc123", "", ""); ctx.CoverageGap != gapAllSkippedByEnv {
+			t.Errorf("CoverageGap = %q, want %q", ctx.CoverageGap, gapAllSkippedByEnv)
+		}
+
+		// With fail_fast the env skip stops the loop before the second scanner
+		config.Global.FailFast = true
+		config.Scanners = append(config.Scanners, ScannerConfig{Name: "binary-detector", Enabled: true, Command: "builtin:binary-detector"})
+		ctx := runScannersOnRepo(config, repo, dir, "abc123", "", "")
+		if len(ctx.Results) != 1 || ctx.CoverageGap != gapFailFastAfterEnvSkip {
+			t.Errorf("results = %d, CoverageGap = %q; want 1 result and %q", len(ctx.Results), ctx.CoverageGap, gapFailFastAfterEnvSkip)
+		}
+		if got := uncoveredTargets([]RepoScanContext{ctx}); len(got) != 1 {
+			t.Errorf("uncoveredTargets() = %v, want the target for require_coverage", got)
+		}
+	})
+}
+
+func TestExpandArgTemplates(t *testing.T) {
+	args := []string{
+		"--output={{output}}",
+		"sbom:{{sbom}}",
+		"{{repo}}",
+		"--report-name={{reponame}}-{{commit}}",
+		"--plain",
+	}
+
+	tests := []struct {
+		name       string
+		repo       RepositoryConfig
+		commitHash string
+		want       []string
+	}{
+		{
+			name:       "remote repository",
+			repo:       RepositoryConfig{URL: "https://github.com/org/widget.git"},
+			commitHash: "abc1234",
+			want: []string{
+				"--output=/results/out.json",
+				"sbom:/results/sbom.json",
+				"https://github.com/org/widget.git",
+				"--report-name=widget-abc1234",
+				"--plain",
+			},
+		},
+		{
+			name:       "local repository uses directory basename",
+			repo:       RepositoryConfig{URL: "local:///home/dev/widget"},
+			commitHash: "def5678",
+			want: []string{
+				"--output=/results/out.json",
+				"sbom:/results/sbom.json",
+				"local:///home/dev/widget",
+				"--report-name=widget-def5678",
+				"--plain",
+			},
+		},
+		{
+			name: "missing commit becomes unknown",
+			repo: RepositoryConfig{URL: "local:///home/dev/widget", Subproject: "services/api"},
+			want: []string{
+				"--output=/results/out.json",
+				"sbom:/results/sbom.json",
+				"local:///home/dev/widget",
+				"--report-name=widget-services-api-unknown",
+				"--plain",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expandArgTemplates(args, tt.repo, "/results/out.json", "/results/sbom.json", tt.commitHash, "")
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("expandArgTemplates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeFakeScanner writes a shell script that appends to an attempts file on
+// every run and behaves according to body. $1 is the output path, $2 the attempts file.
+func writeFakeScanner(t *testing.T, dir, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, "fake-scanner.sh")
+	script := "#!/bin/sh\necho run >> \"$2\"\n" + body + "\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunScannerRetries(t *testing.T) {
+	origDelay := scannerRetryDelay
+	scannerRetryDelay = 0
+	t.Cleanup(func() { scannerRetryDelay = origDelay })
+
+	// Fails on the first run, then writes its output file
+	flaky := `if [ "$(wc -l < "$2")" -lt 2 ]; then exit 1; fi
+echo '{"matches": []}' > "$1"`
+
+	tests := []struct {
+		name         string
+		body         string
+		retries      int
+		timeout      time.Duration
+		wantSuccess  bool
+		wantAttempts int
+	}{
+		{name: "single retry recovers from transient failure", body: flaky, retries: 1, timeout: 5 * time.Second, wantSuccess: true, wantAttempts: 2},
+		{name: "no retries records failure", body: flaky, retries: 0, timeout: 5 * time.Second, wantSuccess: false, wantAttempts: 1},
+		{name: "persistent failure exhausts retries", body: "exit 1", retries: 2, timeout: 5 * time.Second, wantSuccess: false, wantAttempts: 3},
+		{name: "timeout is not retried", body: "exec sleep 5", retries: 2, timeout: 100 * time.Millisecond, wantSuccess: false, wantAttempts: 1},
+		{name: "non-zero exit with output is not retried", body: `echo '{}' > "$1"; exit 1`, retries: 2, timeout: 5 * time.Second, wantSuccess: true, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			attemptsFile := filepath.Join(dir, "attempts")
+			scanner := ScannerConfig{
+				Name:    "fake",
+				Enabled: true,
+				Command: writeFakeScanner(t, dir, tt.body),
+				Args:    []string{"{{output}}", attemptsFile},
+				Retries: tt.retries,
+				timeout: tt.timeout,
+			}
+			config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+			repo := RepositoryConfig{URL: "https://github.com/org/repo"}
+
+			result := runScanner(config, scanner, repo, dir, "abc1234", "main", "", nil, nil)
+
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (error: %v)", result.Success, tt.wantSuccess, result.Error)
+			}
+			data, err := os.ReadFile(attemptsFile)
+			if err != nil {
+				t.Fatalf("reading attempts file: %v", err)
+			}
+			if got := strings.Count(string(data), "run"); got != tt.wantAttempts {
+				t.Errorf("scanner ran %d times, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRunScannerTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantTimeout bool
+	}{
+		{name: "killed at the timeout", body: "exec sleep 5", wantTimeout: true},
+		{name: "partial output is not trusted", body: `echo '{"matches": [' > "$1"; exec sleep 5`, wantTimeout: true},
+		{name: "crash is not a timeout", body: "exit 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			scanner := ScannerConfig{
+				Name:    "fake",
+				Enabled: true,
+				Command: writeFakeScanner(t, dir, tt.body),
+				Args:    []string{"{{output}}", filepath.Join(dir, "attempts")},
+				timeout: 100 * time.Millisecond,
+			}
+			config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+			repo := RepositoryConfig{URL: "https://github.com/org/repo"}
+
+			result := runScanner(config, scanner, repo, dir, "abc1234", "main", "", nil, nil)
+
+			if result.Success {
+				t.Fatalf("Success = true, want a failed result")
+			}
+			var timeout *scannerTimeoutError
+			if errors.As(result.Error, &timeout) != tt.wantTimeout {
+				t.Fatalf("Error = %v, timeout classification = %v, want %v", result.Error, !tt.wantTimeout, tt.wantTimeout)
+			}
+
+			out := captureStdout(t, func() { printSummary([]RepoScanContext{{RepoURL: repo.URL, Results: []ScanResult{result}}}) })
+			if got := strings.Contains(out, "TIMEOUT"+ColorReset+" (100ms)"); got != tt.wantTimeout {
+				t.Errorf("summary reports a timeout = %v, want %v:\n%s", got, tt.wantTimeout, out)
+			}
+			report := buildRunReport([]RepoScanContext{{Results: []ScanResult{result}}})
+			if got := report.Results[0].TimedOut; got != tt.wantTimeout {
+				t.Errorf("report timed_out = %v, want %v", got, tt.wantTimeout)
+			}
+			if _, err := os.Stat(result.OutputPath); tt.wantTimeout && !os.IsNotExist(err) {
+				t.Errorf("partial output %s left behind for --parse-only (stat error %v)", result.OutputPath, err)
+			}
+		})
+	}
+}
+
+func TestFormatTimeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    string
+	}{
+		{5 * time.Minute, "5m"},
+		{90 * time.Second, "1m30s"},
+		{2 * time.Hour, "2h"},
+		{time.Hour + 30*time.Minute, "1h30m"},
+		{100 * time.Millisecond, "100ms"},
+	}
+	for _, tt := range tests {
+		if got := formatTimeout(tt.timeout); got != tt.want {
+			t.Errorf("formatTimeout(%v) = %q, want %q", tt.timeout, got, tt.want)
+		}
+	}
+}
+
+func TestRunScannerLocalSBOM(t *testing.T) {
+	dir := t.TempDir()
+	sbomPath := filepath.Join(dir, "results", "sboms", "widget_local_abc1234.cdx.json")
+	scanner := ScannerConfig{
+		Name:      "fake",
+		Enabled:   true,
+		Command:   writeFakeScanner(t, dir, `echo "$3" > "$1"`),
+		Args:      []string{"{{output}}", filepath.Join(dir, "attempts"), "remote"},
+		ArgsLocal: []string{"{{output}}", filepath.Join(dir, "attempts"), "sbom:{{sbom}}"},
+		timeout:   10 * time.Second,
+	}
+	config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+	repo := RepositoryConfig{URL: "local://" + dir, Branch: "local"}
+
+	result := runScanner(config, scanner, repo, dir, "abc1234", "", sbomPath, nil, nil)
+	if !result.Success {
+		t.Fatalf("runScanner() failed: %v", result.Error)
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		t.Fatalf("reading scanner output: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "sbom:"+sbomPath; got != want {
+		t.Errorf("scanner got arg %q, want %q", got, want)
+	}
+}
+
+func TestResolveWorkingDir(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoPath, "backend", "api"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		workingDir string
+		want       string
+		wantErr    bool
+	}{
+		{name: "default is the repo root", workingDir: "", want: repoPath},
+		{name: "repo template", workingDir: "{{repo}}/backend", want: filepath.Join(repoPath, "backend")},
+		{name: "relative to the repo", workingDir: "backend/api", want: filepath.Join(repoPath, "backend", "api")},
+		{name: "dot-dot that stays inside", workingDir: "{{repo}}/backend/api/..", want: filepath.Join(repoPath, "backend")},
+		{name: "escapes with dot-dot", workingDir: "{{repo}}/../other", wantErr: true},
+		{name: "relative escape", workingDir: "../", wantErr: true},
+		{name: "absolute path outside the repo", workingDir: "/etc", wantErr: true},
+		{name: "missing directory", workingDir: "{{repo}}/frontend", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWorkingDir(tt.workingDir, repoPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveWorkingDir(%q) error = %v, wantErr %v", tt.workingDir, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveWorkingDir(%q) = %q, want %q", tt.workingDir, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("runScanner runs in the working dir", func(t *testing.T) {
+		dir := t.TempDir()
+		scanner := ScannerConfig{
+			Name:       "fake",
+			Command:    writeFakeScanner(t, dir, `pwd > "$1"`),
+			Args:       []string{"{{output}}", filepath.Join(dir, "attempts")},
+			WorkingDir: "{{repo}}/backend",
+			timeout:    5 * time.Second,
+		}
+		config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+		repo := RepositoryConfig{URL: "https://github.com/org/repo"}
+
+		result := runScanner(config, scanner, repo, repoPath, "abc1234", "main", "", nil, nil)
+		if !result.Success {
+			t.Fatalf("runScanner() failed: %v", result.Error)
+		}
+		if !filepath.IsAbs(result.OutputPath) {
+			t.Errorf("OutputPath = %q, want an absolute path", result.OutputPath)
+		}
+		data, err := os.ReadFile(result.OutputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantDir, _ := filepath.EvalSymlinks(filepath.Join(repoPath, "backend"))
+		if gotDir, _ := filepath.EvalSymlinks(strings.TrimSpace(string(data))); gotDir != wantDir {
+			t.Errorf("scanner ran in %q, want %q", gotDir, wantDir)
+		}
+
+		scanner.WorkingDir = "{{repo}}/../elsewhere"
+		if result := runScanner(config, scanner, repo, repoPath, "abc1234", "main", "", nil, nil); result.Success {
+			t.Error("runScanner() succeeded with a working_dir outside the repo")
+		}
+	})
+}
+
+func TestFilterSecurityRules(t *testing.T) {
+	dir := t.TempDir()
+	output := `[{"code": "S105", "message": "Possible hardcoded password"}, {"code": "E501", "message": "Line too long"}]`
+
+	tests := []struct {
+		name        string
+		content     string
+		wantSuccess bool
+		wantTotal   int
+	}{
+		{name: "drops non-security findings", content: output, wantSuccess: true, wantTotal: 1},
+		{name: "unparseable output fails the result", content: "not json", wantSuccess: false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("ruff-%d.json", i))
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			result := ScanResult{Scanner: "ruff", OutputPath: path, Success: true}
+
+			filterSecurityRules(&result)
+
+			if result.Success != tt.wantSuccess {
+				t.Fatalf("Success = %v, want %v (error: %v)", result.Success, tt.wantSuccess, result.Error)
+			}
+			if !tt.wantSuccess {
+				return
+			}
+			summary, _ := parseScanOutput(result)
+			if summary.Total != tt.wantTotal || summary.High != tt.wantTotal {
+				t.Errorf("summary after filtering = %+v, want %d high", summary, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestValidateFindingCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		max     int
+		wantErr bool
+	}{
+		{name: "unlimited", total: 100000, max: 0, wantErr: false},
+		{name: "below limit", total: 10, max: 500, wantErr: false},
+		{name: "at limit", total: 500, max: 500, wantErr: false},
+		{name: "over limit", total: 501, max: 500, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFindingCount(parsers.FindingSummary{Total: tt.total}, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateFindingCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "too many findings: possible misconfiguration") {
+				t.Errorf("error = %q, want it to mention a possible misconfiguration", err)
+			}
+		})
+	}
+}
+
+func TestMaxFindings(t *testing.T) {
+	global := GlobalConfig{MaxFindingsPerScanner: 1000}
+	if got := maxFindings(global, ScannerConfig{Name: "gosec"}); got != 1000 {
+		t.Errorf("maxFindings() without override = %d, want the global 1000", got)
+	}
+	if got := maxFindings(global, ScannerConfig{Name: "semgrep", MaxFindingsPerScanner: 50}); got != 50 {
+		t.Errorf("maxFindings() with override = %d, want 50", got)
+	}
+	if got := maxFindings(global, ScannerConfig{Name: "semgrep", MaxFindingsPerScanner: unlimitedFindings}); got != 0 {
+		t.Errorf("maxFindings() with -1 = %d, want 0 (unlimited despite the global limit)", got)
+	}
+	if got := maxFindings(GlobalConfig{}, ScannerConfig{Name: "gosec"}); got != 0 {
+		t.Errorf("maxFindings() unset = %d, want 0 (unlimited)", got)
+	}
+}
+
+func TestEnforceFindingLimit(t *testing.T) {
+	output := `{"matches": [
+		{"vulnerability": {"id": "CVE-1", "severity": "High"}},
+		{"vulnerability": {"id": "CVE-2", "severity": "Low"}},
+		{"vulnerability": {"id": "CVE-3", "severity": "Low"}}
+	]}`
+
+	tests := []struct {
+		name        string
+		scanner     string
+		max         int
+		wantSuccess bool
+	}{
+		{name: "within limit", scanner: "grype", max: 3, wantSuccess: true},
+		{name: "over limit", scanner: "grype", max: 2, wantSuccess: false},
+		{name: "no limit", scanner: "grype", max: 0, wantSuccess: true},
+		{name: "scanner without parser is not counted", scanner: "custom-tool", max: 1, wantSuccess: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "result.json")
+			if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+				t.Fatal(err)
+			}
+			result := ScanResult{Scanner: tt.scanner, OutputPath: path, Success: true}
+
+			enforceFindingLimit(&result, tt.max)
+
+			if result.Success != tt.wantSuccess {
+				t.Fatalf("Success = %v, want %v (error: %v)", result.Success, tt.wantSuccess, result.Error)
+			}
+			_, statErr := os.Stat(path)
+			if tt.wantSuccess && statErr != nil {
+				t.Errorf("result file removed for a result within the limit: %v", statErr)
+			}
+			if !tt.wantSuccess {
+				if !os.IsNotExist(statErr) {
+					t.Error("result file kept for a result over the limit")
+				}
+				if result.Error == nil {
+					t.Error("Error not set for a result over the limit")
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidOutputReason(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string // prefix of the reason, "" for valid output
+	}{
+		{"json object", []byte(`{"matches": []}`), ""},
+		{"ndjson", []byte("{\"a\": 1}\n{\"b\": 2}\n"), ""},
+		{"empty", nil, ""},
+		{"whitespace", []byte("\n  \n"), ""},
+		{"null bytes", []byte("{\"matches\": [\x00\x00\x7fELF"), "binary data"},
+		{"invalid utf-8", []byte("{\"id\": \"\xff\xfe\"}"), "not valid UTF-8"},
+		{"truncated json", []byte(`{"matches": [{"vulnerability":`), "not valid JSON"},
+		{"text", []byte("panic: runtime error\n"), "not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := invalidOutputReason(tt.data)
+			if (tt.want == "") != (got == "") || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("invalidOutputReason() = %q, want prefix %q", got, tt.want)
+			}
 		})
 	}
 }
+
+func TestCheckScanOutput(t *testing.T) {
+	binary := []byte("\x7fELF\x02\x01\x01\x00\x00\x00\xff\xfe garbage")
+
+	tests := []struct {
+		name        string
+		scanner     string
+		data        []byte
+		stderr      string
+		wantSuccess bool
+		wantDetail  string
+	}{
+		{"valid output", "grype", []byte(`{"matches": []}`), "", true, ""},
+		{"binary output", "grype", binary, "", false, "binary data (null bytes)"},
+		{"binary output with stderr", "grype", binary, "panic: out of memory", false, "binary data (null bytes) (stderr: panic: out of memory)"},
+		{"scanner without parser", "custom-tool", binary, "", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "result.json")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			result := ScanResult{Scanner: tt.scanner, OutputPath: path, Success: true, StderrLine: tt.stderr}
+
+			checkScanOutput(&result)
+
+			if result.Success != tt.wantSuccess {
+				t.Fatalf("Success = %v, want %v (error: %v)", result.Success, tt.wantSuccess, result.Error)
+			}
+			if tt.wantSuccess {
+				return
+			}
+			var invalid *invalidOutputError
+			if !errors.As(result.Error, &invalid) {
+				t.Fatalf("Error = %v, want an invalidOutputError", result.Error)
+			}
+			if invalid.detail() != tt.wantDetail {
+				t.Errorf("detail() = %q, want %q", invalid.detail(), tt.wantDetail)
+			}
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("invalid output file should be kept for inspection: %v", err)
+			}
+
+			out := captureStdout(t, func() { printSummary([]RepoScanContext{{RepoURL: "https://github.com/org/repo", Results: []ScanResult{result}}}) })
+			if !strings.Contains(out, "INVALID OUTPUT") || !strings.Contains(out, tt.wantDetail) {
+				t.Errorf("summary doesn't report the invalid output:\n%s", out)
+			}
+			report := buildRunReport([]RepoScanContext{{Results: []ScanResult{result}}})
+			if entry := report.Results[0]; !entry.InvalidOutput || entry.Success || entry.Error == "" {
+				t.Errorf("report entry = %+v, want a failed invalid_output entry with an error", entry)
+			}
+		})
+	}
+}
+
+func TestRunScannerCapturesStderr(t *testing.T) {
+	dir := t.TempDir()
+	scanner := ScannerConfig{
+		Name:    "grype",
+		Enabled: true,
+		Command: writeFakeScanner(t, dir, `printf '\177ELF\000\000' > "$1"; echo "fatal: segmentation fault" >&2; exit 2`),
+		Args:    []string{"{{output}}", filepath.Join(dir, "attempts")},
+		timeout: 10 * time.Second,
+	}
+	config := &Config{Global: GlobalConfig{ResultsDir: filepath.Join(dir, "results")}}
+
+	result := runScanner(config, scanner, RepositoryConfig{URL: "https://github.com/org/repo"}, dir, "abc1234", "main", "", nil, nil)
+	if !result.Success {
+		t.Fatalf("runScanner() failed: %v", result.Error)
+	}
+	if result.StderrLine != "fatal: segmentation fault" {
+		t.Errorf("StderrLine = %q, want the scanner's stderr", result.StderrLine)
+	}
+
+	checkScanOutput(&result)
+	if result.Success || !strings.Contains(errorString(result.Error), "stderr: fatal: segmentation fault") {
+		t.Errorf("checkScanOutput() left Success = %v, Error = %v", result.Success, result.Error)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"allscan/parsers"
+)
+
+// detectSubprojects walks a repository and returns the relative paths of
+// directories that contain a manifest file (go.mod, package.json, pom.xml, ...)
+// from manifestLanguages. The repository root is returned as "." when it has a
+// manifest of its own and no sub-project is found below it: scanning the root
+// would report the sub-projects' findings a second time (see
+// unscannedRemainder for the files left out then). Directories skipped
+// by language detection (hidden, vendor, node_modules, build output) are not
+// searched.
+func detectSubprojects(repoPath string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	err := filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+
+		if info.IsDir() {
+			if path != repoPath && isSkippedDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if _, ok := manifestLanguage(info.Name()); !ok {
+			return nil
+		}
+
+		rel, err := filepath.Rel(repoPath, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		seen[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(seen) > 1 {
+		delete(seen, ".")
+	}
+	subprojects := make([]string, 0, len(seen))
+	for dir := range seen {
+		subprojects = append(subprojects, dir)
+	}
+	sort.Strings(subprojects)
+	return subprojects, nil
+}
+
+// gapOutsideSubprojects is the CoverageGap of the part of a repository a
+// sub-project scan leaves out: the source files in none of the sub-projects
+const gapOutsideSubprojects = "%d source file(s) outside the sub-projects are not scanned, e.g. %s"
+
+// unscannedRemainder classifies the files under root that lie outside every
+// sub-project directory, which a sub-project scan never looks at. It returns
+// their languages and one of the files for the warning, or nil when every
+// source file is inside a sub-project (or the root is one itself). Skipped
+// directories and paths matched by ignore don't count.
+func unscannedRemainder(root string, subprojects []string, ignore *parsers.IgnorePatterns) (*DetectedLanguages, string) {
+	inSubproject := make(map[string]bool, len(subprojects))
+	for _, sub := range subprojects {
+		if sub == "." {
+			return nil, ""
+		}
+		inSubproject[sub] = true
+	}
+
+	counts := make(map[string]int)
+	var example string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			if path != root && (isSkippedDir(d.Name()) || inSubproject[filepath.ToSlash(rel)] || ignore.Match(rel, true)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignore.Match(rel, false) {
+			return nil
+		}
+		if lang, _ := classifyFile(path, d.Name()); lang != "" {
+			counts[lang]++
+			if example == "" {
+				example = filepath.ToSlash(rel)
+			}
+		}
+		return nil
+	})
+	if len(counts) == 0 {
+		return nil, ""
+	}
+
+	languages := make([]string, 0, len(counts))
+	for lang := range counts {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return &DetectedLanguages{Languages: languages, FileCounts: counts, Source: "filesystem"}, example
+}
+
+// scopePath returns a repository's path setting as a clean slash-separated
+// subdirectory, or "" when the whole repository is scanned
+func scopePath(repo RepositoryConfig) string {
+	if repo.Path == "" {
+		return ""
+	}
+	if cleaned := path.Clean(filepath.ToSlash(repo.Path)); cleaned != "." {
+		return cleaned
+	}
+	return ""
+}
+
+// validateScopePath checks after cloning that a repository's path setting
+// names a directory inside the clone, also after resolving symlinks
+func validateScopePath(repoPath string, repo RepositoryConfig) error {
+	scope := scopePath(repo)
+	if scope == "" {
+		return nil
+	}
+	dir := filepath.Join(repoPath, filepath.FromSlash(scope))
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("path %q is not a directory in the repository", repo.Path)
+	}
+
+	root, err := filepath.EvalSymlinks(repoPath)
+	if err != nil {
+		return err
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %q resolves outside the repository", repo.Path)
+	}
+	return nil
+}
+
+// subprojectSuffix converts a sub-project path into a filename-safe suffix
+// (e.g., "services/api" → "services-api"). The root project has no suffix.
+func subprojectSuffix(subproject string) string {
+	if subproject == "" || subproject == "." {
+		return ""
+	}
+	return strings.ReplaceAll(subproject, "/", "-")
+}
+
+// scanRepoTargets generates an SBOM and runs scanners for a cloned repository.
+// When global.subprojects is enabled and sub-projects are detected, each one is
+// scanned independently and produces its own RepoScanContext. Source files
+// outside all of them get a context of their own with a CoverageGap, so they
+// show up as unscanned rather than silently. A repository with a path is
+// scanned as that sub-project (and the sub-projects below it).
+func scanRepoTargets(config *Config, repo RepositoryConfig, repoPath, commitHash, branchTag string) []RepoScanContext {
+	scope := scopePath(repo)
+	scanRoot := repoPath
+	if scope != "" {
+		scanRoot = filepath.Join(repoPath, filepath.FromSlash(scope))
+		repo.Subproject = scope
+	}
+	targets := []RepositoryConfig{repo}
+	var remainder *RepoScanContext
+
+	if config.Global.Subprojects {
+		subprojects, err := detectSubprojects(scanRoot)
+		if err != nil {
+			log.Printf("  ⚠️  Failed to detect sub-projects: %v", err)
+		} else if len(subprojects) > 0 {
+			log.Printf("  🗂️  Detected %d sub-project(s): %s", len(subprojects), strings.Join(subprojects, ", "))
+			targets = targets[:0]
+			for _, sub := range subprojects {
+				target := repo
+				target.Subproject = path.Join(scope, sub)
+				if target.Subproject == "." {
+					target.Subproject = sub // the repo root, as without a path
+				}
+				targets = append(targets, target)
+			}
+			if languages, example := unscannedRemainder(scanRoot, subprojects, loadScanIgnore(scanRoot)); languages != nil {
+				files := 0
+				for _, n := range languages.FileCounts {
+					files += n
+				}
+				gap := fmt.Sprintf(gapOutsideSubprojects, files, example)
+				log.Printf("  ⚠️  %s", gap)
+				remainder = &RepoScanContext{RepoURL: repo.URL, Subproject: scope, Languages: languages, CoverageGap: gap}
+			}
+		}
+	}
+
+	// Use the original pURL version in the SBOM filename when available,
+	// so that the user-provided version appears rather than the git tag name
+	sbomVersion := branchTag
+	if repo.PURLVersion != "" {
+		sbomVersion = repo.PURLVersion
+	}
+
+	timeout := sbomTimeout
+	if repo.Submodules {
+		timeout *= submoduleTimeoutFactor
+	}
+
+	var contexts []RepoScanContext
+	for _, target := range targets {
+		targetPath := repoPath
+		if target.Subproject != "" {
+			targetPath = filepath.Join(repoPath, filepath.FromSlash(target.Subproject))
+			log.Printf("\n  📁 Sub-project: %s", target.Subproject)
+		}
+
+		// Generate SBOM (reused by grype via {{sbom}} template)
+		sbomPath, sbomErr := generateSBOM(config.Global.ResultsDir, targetPath, repoName(target), commitHash, sbomVersion, config.Global.SBOMDeterministicNames, timeout)
+		if sbomErr != nil {
+			log.Printf("  ⚠️  SBOM generation failed: %v", sbomErr)
+		}
+
+		ctx := runScannersOnRepo(config, target, targetPath, commitHash, branchTag, sbomPath)
+		ctx.SBOMError = sbomErr
+		contexts = append(contexts, ctx)
+
+		// Stop scanning further sub-projects on failure when fail-fast is enabled
+		if config.Global.FailFast && hasFailedResult(ctx) {
+			break
+		}
+	}
+	if remainder != nil {
+		contexts = append(contexts, *remainder)
+	}
+
+	return contexts
+}
+
+// hasFailedResult reports whether the repository failed to clone or any
+// scanner in the context failed.
+func hasFailedResult(ctx RepoScanContext) bool {
+	if ctx.CloneError != nil {
+		return true
+	}
+	for _, result := range ctx.Results {
+		if !result.Success {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"allscan/parsers"
+)
+
+// writeTestFiles creates the given relative file paths (with empty content) under root.
+func writeTestFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectSubprojects(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "no manifests",
+			files: []string{"README.md", "docs/index.md"},
+			want:  []string{},
+		},
+		{
+			name:  "root manifest only",
+			files: []string{"go.mod", "main.go"},
+			want:  []string{"."},
+		},
+		{
+			name:  "two go.mod sub-projects",
+			files: []string{"services/api/go.mod", "services/worker/go.mod", "README.md"},
+			want:  []string{"services/api", "services/worker"},
+		},
+		{
+			name:  "root with nested sub-projects is not scanned twice",
+			files: []string{"go.mod", "web/package.json", "web/package-lock.json", "java/pom.xml"},
+			want:  []string{"java", "web"},
+		},
+		{
+			name:  "skipped directories are ignored",
+			files: []string{"app/go.mod", "node_modules/dep/package.json", "vendor/lib/go.mod", ".github/package.json"},
+			want:  []string{"app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, tt.files...)
+
+			got, err := detectSubprojects(root)
+			if err != nil {
+				t.Fatalf("detectSubprojects() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectSubprojects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnscannedRemainder(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []string
+		subprojects []string
+		ignore      string
+		wantCounts  map[string]int
+	}{
+		{
+			name:        "root code outside the sub-projects",
+			files:       []string{"go.mod", "main.go", "tools/gen.py", "web/package.json", "web/index.js", "java/pom.xml", "README.md"},
+			subprojects: []string{"java", "web"},
+			wantCounts:  map[string]int{"go": 2, "python": 1},
+		},
+		{
+			name:        "everything inside a sub-project",
+			files:       []string{"services/api/go.mod", "services/api/main.go", "README.md"},
+			subprojects: []string{"services/api"},
+		},
+		{
+			name:        "root is a sub-project",
+			files:       []string{"go.mod", "main.go"},
+			subprojects: []string{"."},
+		},
+		{
+			name:        "ignored remainder",
+			files:       []string{"scripts/run.py", "app/go.mod"},
+			subprojects: []string{"app"},
+			ignore:      "scripts/\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, tt.files...)
+
+			got, example := unscannedRemainder(root, tt.subprojects, parsers.ParseIgnorePatterns(tt.ignore))
+			if tt.wantCounts == nil {
+				if got != nil {
+					t.Errorf("unscannedRemainder() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || !reflect.DeepEqual(got.FileCounts, tt.wantCounts) {
+				t.Fatalf("unscannedRemainder() = %+v, want counts %v", got, tt.wantCounts)
+			}
+			if example == "" || strings.HasPrefix(example, "web/") || strings.HasPrefix(example, "java/") {
+				t.Errorf("example = %q, want a file outside the sub-projects", example)
+			}
+		})
+	}
+}
+
+func TestSubprojectSuffix(t *testing.T) {
+	tests := []struct {
+		subproject string
+		want       string
+	}{
+		{"", ""},
+		{".", ""},
+		{"api", "api"},
+		{"services/api", "services-api"},
+	}
+	for _, tt := range tests {
+		if got := subprojectSuffix(tt.subproject); got != tt.want {
+			t.Errorf("subprojectSuffix(%q) = %q, want %q", tt.subproject, got, tt.want)
+		}
+	}
+}
+
+func TestScanRepoTargets(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, "services/api/go.mod", "services/api/main.go", "services/worker/go.mod", "services/worker/main.go")
+
+	repo := RepositoryConfig{URL: "https://example.com/acme/monorepo", Branch: "main"}
+
+	t.Run("sub-projects disabled yields one context", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{ResultsDir: t.TempDir()}}
+		contexts := scanRepoTargets(config, repo, root, "abc1234", "main")
+		if len(contexts) != 1 {
+			t.Fatalf("got %d contexts, want 1", len(contexts))
+		}
+		if contexts[0].Subproject != "" {
+			t.Errorf("Subproject = %q, want empty", contexts[0].Subproject)
+		}
+	})
+
+	t.Run("failed SBOM is recorded in the context", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir()) // no syft
+		config := &Config{Global: GlobalConfig{ResultsDir: t.TempDir()}}
+		contexts := scanRepoTargets(config, repo, root, "abc1234", "main")
+		if len(contexts) != 1 {
+			t.Fatalf("got %d contexts, want 1", len(contexts))
+		}
+		if contexts[0].SBOMError == nil || contexts[0].SBOMPath != "" {
+			t.Errorf("SBOMError = %v, SBOMPath = %q; want an error and no path", contexts[0].SBOMError, contexts[0].SBOMPath)
+		}
+	})
+
+	t.Run("two go.mod sub-projects yield two contexts", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{ResultsDir: t.TempDir(), Subprojects: true}}
+		contexts := scanRepoTargets(config, repo, root, "abc1234", "main")
+		if len(contexts) != 2 {
+			t.Fatalf("got %d contexts, want 2", len(contexts))
+		}
+		for i, want := range []string{"services/api", "services/worker"} {
+			if contexts[i].Subproject != want {
+				t.Errorf("contexts[%d].Subproject = %q, want %q", i, contexts[i].Subproject, want)
+			}
+			if contexts[i].RepoURL != repo.URL {
+				t.Errorf("contexts[%d].RepoURL = %q, want %q", i, contexts[i].RepoURL, repo.URL)
+			}
+			if !contexts[i].Languages.hasLanguage("go") {
+				t.Errorf("contexts[%d] languages = %v, want go", i, contexts[i].Languages.Languages)
+			}
+		}
+	})
+}
+
+func TestScanRepoTargetsPath(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, "main.go", "services/api/app.py", "services/api/requirements.txt", "services/worker/go.mod", "services/worker/main.go")
+
+	repo := RepositoryConfig{URL: "https://example.com/acme/monorepo", Branch: "main", Path: "services/api/"}
+
+	t.Run("scans only the path", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{ResultsDir: t.TempDir()}}
+		contexts := scanRepoTargets(config, repo, root, "abc1234", "main")
+		if len(contexts) != 1 {
+			t.Fatalf("got %d contexts, want 1", len(contexts))
+		}
+		if contexts[0].Subproject != "services/api" {
+			t.Errorf("Subproject = %q, want %q", contexts[0].Subproject, "services/api")
+		}
+		if !contexts[0].Languages.hasLanguage("python") || contexts[0].Languages.hasLanguage("go") {
+			t.Errorf("languages = %v, want python only", contexts[0].Languages.Languages)
+		}
+	})
+
+	t.Run("sub-projects are detected below the path", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{ResultsDir: t.TempDir(), Subprojects: true}}
+		services := repo
+		services.Path = "services"
+		contexts := scanRepoTargets(config, services, root, "abc1234", "main")
+		if len(contexts) != 2 {
+			t.Fatalf("got %d contexts, want 2", len(contexts))
+		}
+		for i, want := range []string{"services/api", "services/worker"} {
+			if contexts[i].Subproject != want {
+				t.Errorf("contexts[%d].Subproject = %q, want %q", i, contexts[i].Subproject, want)
+			}
+		}
+	})
+}
+
+func TestValidateScopePath(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, "services/api/main.go", "README.md")
+	if err := os.Symlink(t.TempDir(), filepath.Join(root, "outside")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"", false},
+		{".", false},
+		{"services/api", false},
+		{"services/missing", true},
+		{"README.md", true},
+		{"outside", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := validateScopePath(root, RepositoryConfig{Path: tt.path})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateScopePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"allscan/parsers"
 )
@@ -32,16 +37,92 @@ const (
 	CoverageNone        CoverageState = iota // No scanner of this type covers this language
 	CoverageConditional                      // A scanner conditionally covers this language (requires specific package manager files)
 	CoverageFailed                           // A scanner covers this language but failed
+	CoverageInfo                             // A scanner covers this language and succeeded, but reported only Info/Low findings
 	CoverageOK                               // A scanner covers this language and succeeded
 )
 
+// theme holds the symbols used by the summary output; set from --theme in main
+var theme = parsers.EmojiTheme
+
+// severityLabels are the names the summary shows for each severity; set from
+// global.severity_labels in main. Findings are still counted by the internal
+// severities.
+var severityLabels = defaultSeverityLabels
+
+// previousSummaries holds finding summaries from the --previous-run report,
+// keyed by trendKey. When nil, trend arrows are not shown.
+var previousSummaries map[string]parsers.FindingSummary
+
+// coverageScanTypes lists the scan types shown as coverage matrix columns;
+// set from global.coverage_scan_types in main
+var coverageScanTypes = defaultCoverageScanTypes
+
+// requireCoverage shows targets where no scanner ran as failures rather than
+// warnings; set from global.require_coverage or --require-coverage in main
+var requireCoverage bool
+
+// withSeverityLabels returns the default severity labels with overrides applied
+func withSeverityLabels(overrides map[string]string) map[string]string {
+	labels := make(map[string]string, len(defaultSeverityLabels))
+	for severity, label := range defaultSeverityLabels {
+		labels[severity] = label
+	}
+	for severity, label := range overrides {
+		labels[severity] = label
+	}
+	return labels
+}
+
+// summaryCategories orders the category groups of a repository's scanner
+// results in the summary. Other parser types follow alphabetically, then
+// scanners without a parser.
+var summaryCategories = []string{"SCA", "SAST", "Secrets", "IaC", "Scorecard", "Reachability", "Binary"}
+
+// otherCategory groups the results of scanners without a parser
+const otherCategory = "Other"
+
+// resultCategory returns the summary group of a result: its parser's Type()
+// (the primary type for a MultiTypeParser), or otherCategory
+func resultCategory(result ScanResult) string {
+	if parser, ok := parsers.Get(result.Scanner); ok {
+		return parser.Type()
+	}
+	return otherCategory
+}
+
+// groupResultsByCategory returns results ordered by category (see
+// summaryCategories), keeping the run order within each category
+func groupResultsByCategory(results []ScanResult) []ScanResult {
+	rank := func(category string) (int, string) {
+		for i, c := range summaryCategories {
+			if c == category {
+				return i, ""
+			}
+		}
+		if category == otherCategory {
+			return len(summaryCategories) + 1, ""
+		}
+		return len(summaryCategories), category
+	}
+	grouped := append([]ScanResult(nil), results...)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		ri, ni := rank(resultCategory(grouped[i]))
+		rj, nj := rank(resultCategory(grouped[j]))
+		if ri != rj {
+			return ri < rj
+		}
+		return ni < nj
+	})
+	return grouped
+}
+
 // printSummary displays a colorful summary of all scan results
 func printSummary(contexts []RepoScanContext) {
-	separator := strings.Repeat("═", 70)
-	thinSeparator := strings.Repeat("─", 70)
+	separator := strings.Repeat(theme.Separator, 70)
+	thinSeparator := strings.Repeat(theme.ThinSeparator, 70)
 
 	fmt.Printf("\n%s%s%s\n", ColorCyan, separator, ColorReset)
-	fmt.Printf("%s%s 📊 SCAN RESULTS SUMMARY %s%s\n", ColorBold, ColorCyan, ColorReset, ColorReset)
+	fmt.Printf("%s%s %s SCAN RESULTS SUMMARY %s%s\n", ColorBold, ColorCyan, theme.SummaryIcon, ColorReset, ColorReset)
 	fmt.Printf("%s%s%s\n\n", ColorCyan, separator, ColorReset)
 
 	successful := 0
@@ -49,19 +130,42 @@ func printSummary(contexts []RepoScanContext) {
 	totalResults := 0
 	totalDuration := time.Duration(0)
 
-	// Process each repository context
+	// Process each repository context; repos that failed to clone are
+	// listed after the others
+	var cloneFailures []RepoScanContext
 	for _, ctx := range contexts {
+		if ctx.CloneError != nil {
+			cloneFailures = append(cloneFailures, ctx)
+			continue
+		}
+
 		// Extract repo name for cleaner display
 		parts := strings.Split(ctx.RepoURL, "/")
 		repoName := parts[len(parts)-2] + "/" + strings.TrimSuffix(parts[len(parts)-1], ".git")
+		if ctx.Subproject != "" {
+			repoName += " (" + ctx.Subproject + ")"
+		}
 
-		fmt.Printf("%s%s 📦 %s%s\n", ColorBold, ColorMagenta, repoName, ColorReset)
+		fmt.Printf("%s%s %s %s%s\n", ColorBold, ColorMagenta, theme.RepoIcon, repoName, ColorReset)
+		if languages := formatLanguagesLine(ctx.Languages); languages != "" {
+			fmt.Printf("%s  Languages: %s%s\n", ColorDim, languages, ColorReset)
+		}
 		fmt.Printf("%s%s%s\n", ColorDim, thinSeparator, ColorReset)
 
-		// Build reachability index once per repo (from govulncheck output)
+		// Build reachability index once per repo (from govulncheck and osv-scanner call analysis)
 		reachIdx := buildReachabilityIndexFromResults(ctx.Results)
 
-		for _, result := range ctx.Results {
+		category := ""
+		for _, result := range groupResultsByCategory(ctx.Results) {
+			// Subheader for each category, e.g. SCA, SAST, Secrets
+			if c := resultCategory(result); c != category {
+				if category != "" {
+					fmt.Println()
+				}
+				category = c
+				fmt.Printf("  %s%s%s%s\n", ColorBold, ColorCyan, category, ColorReset)
+			}
+
 			totalResults++
 			totalDuration += result.Duration
 			if result.Success {
@@ -71,8 +175,20 @@ func printSummary(contexts []RepoScanContext) {
 			}
 
 			if !result.Success {
-				fmt.Printf("  %s❌ %s%s: %sFAILED%s - %v\n",
-					ColorRed, result.Scanner, ColorReset, ColorRed, ColorReset, result.Error)
+				var invalid *invalidOutputError
+				if errors.As(result.Error, &invalid) {
+					fmt.Printf("  %s%s %s%s: %sINVALID OUTPUT%s - %s\n",
+						ColorRed, theme.Failed, result.Scanner, ColorReset, ColorRed, ColorReset, invalid.detail())
+					continue
+				}
+				var timeout *scannerTimeoutError
+				if errors.As(result.Error, &timeout) {
+					fmt.Printf("  %s%s %s%s: %sTIMEOUT%s (%s)\n",
+						ColorRed, theme.Failed, result.Scanner, ColorReset, ColorRed, ColorReset, formatTimeout(timeout.timeout))
+					continue
+				}
+				fmt.Printf("  %s%s %s%s: %sFAILED%s - %v\n",
+					ColorRed, theme.Failed, result.Scanner, ColorReset, ColorRed, ColorReset, result.Error)
 				continue
 			}
 
@@ -80,9 +196,9 @@ func printSummary(contexts []RepoScanContext) {
 			if result.IsSarif {
 				parser, ok := parsers.Get(result.Scanner)
 				if ok {
-					fmt.Printf("  %s %s%s%s (%s%s%s)\n", parser.Icon(), ColorBold, parser.Name(), ColorReset, ColorDim, parser.Type(), ColorReset)
+					fmt.Printf("  %s %s%s%s (%s%s%s)\n", theme.Icon(parser), ColorBold, parser.Name(), ColorReset, ColorDim, parser.Type(), ColorReset)
 				} else {
-					fmt.Printf("  🔧 %s%s%s\n", ColorBold, result.Scanner, ColorReset)
+					fmt.Printf("  %s %s%s%s\n", theme.ScannerIcon, ColorBold, result.Scanner, ColorReset)
 				}
 				fmt.Printf("     %sSARIF output saved: %s%s\n", ColorDim, result.OutputPath, ColorReset)
 				continue
@@ -90,46 +206,94 @@ func printSummary(contexts []RepoScanContext) {
 
 			// Parse the scan output using the appropriate parser
 			summary, parser := parseScanOutput(result)
+			trend := formatTrend(summary, result)
 			if parser != nil {
-				// Scorecard gets detailed stdout output
-				if parser.Type() == "Scorecard" {
-					if err := parsers.PrintScorecardReport(result.OutputPath); err != nil {
-						fmt.Printf("  %s❌ %s%s: %sFailed to print report%s - %v\n",
-							ColorRed, result.Scanner, ColorReset, ColorRed, ColorReset, err)
+				if _, ok := parser.(parsers.MultiTypeParser); ok {
+					// One line per scan type (e.g. trivy's SCA, Secrets and IaC findings)
+					summaries, _ := parseScanOutputByType(result)
+					printMultiTypeScannerSummary(parser, summaries, summary, trend)
+				} else if parser.Type() == "Scorecard" {
+					// Scorecard gets detailed stdout output
+					printReport := parsers.PrintScorecardReport
+					if scorecard, ok := parser.(*parsers.ScorecardParser); ok {
+						printReport = scorecard.PrintReport // without global.scorecard_exclude_checks
+					}
+					if err := printReport(result.OutputPath, theme); err != nil {
+						fmt.Printf("  %s%s %s%s: %sFailed to print report%s - %v\n",
+							ColorRed, theme.Failed, result.Scanner, ColorReset, ColorRed, ColorReset, err)
 					}
 				} else if parser.Type() == "Reachability" {
 					printReachabilitySummary(parser, summary)
 				} else if parser.Type() == "SCA" {
 					// Try enriched display with reachability data
 					if enriched := enrichSCAResult(result, reachIdx); enriched != nil {
-						printEnrichedScannerSummary(parser, enriched)
+						printEnrichedScannerSummary(parser, enriched, trend)
 					} else {
-						printScannerSummary(parser, summary)
+						printScannerSummary(parser, summary, trend)
 					}
+					printDependencyScopes(parser, result)
 				} else {
-					printScannerSummary(parser, summary)
+					printScannerSummary(parser, summary, trend)
 				}
+			} else if heuristic, ok := heuristicScanOutput(result); ok {
+				// Unknown scanner - best-effort counts from severity fields
+				printScannerSummary(parsers.HeuristicParser{ScannerName: result.Scanner}, heuristic, "")
+				fmt.Printf("     %sNo parser available, counted from severity fields%s\n", ColorDim, ColorReset)
 			} else {
 				// Unknown scanner - show basic info
-				fmt.Printf("  🔧 %s%s%s (%sUnknown%s)\n", ColorBold, result.Scanner, ColorReset, ColorDim, ColorReset)
+				fmt.Printf("  %s %s%s%s (%sUnknown%s)\n", theme.ScannerIcon, ColorBold, result.Scanner, ColorReset, ColorDim, ColorReset)
 				fmt.Printf("     %sNo parser available%s\n", ColorDim, ColorReset)
 			}
 		}
 
+		// Flag targets that no scanner actually covered
+		if ctx.CoverageGap != "" {
+			if requireCoverage {
+				fmt.Printf("  %s%s No scanners ran%s: %sFAILED%s - %s\n",
+					ColorRed, theme.Failed, ColorReset, ColorRed, ColorReset, ctx.CoverageGap)
+			} else {
+				fmt.Printf("  %s%s No scanners ran%s - %s\n", ColorYellow, theme.Warning, ColorReset, ctx.CoverageGap)
+			}
+		}
+
 		// Print coverage matrix for this repo
-		printCoverageMatrix(ctx)
+		printCoverageMatrix(ctx, coverageScanTypes)
+
+		// List the worst individual findings (needs global.top_findings)
+		printTopFindings(ctx, topFindingsLimit)
 
-		// Print SBOM path if generated
-		if ctx.SBOMPath != "" {
+		// Print the age of the oldest open critical (needs global.finding_history)
+		if oldest, ok := oldestOpenFinding(ctx, "critical"); ok {
+			fmt.Printf("\n  %s%s Oldest open %s: %s%s (%s)\n",
+				ColorRed, theme.Critical, strings.ToLower(severityLabels["critical"]), formatAgeDays(oldest.AgeDays), ColorReset, oldest.ID)
+		}
+
+		// Print SBOM path if generated, or why generation failed
+		if ctx.SBOMError != nil {
+			fmt.Printf("\n  %s%sSBOM%s: %sfailed (%s)%s\n", ColorBold, ColorCyan, ColorReset, ColorRed, sbomFailureReason(ctx.SBOMError), ColorReset)
+		} else if ctx.SBOMPath != "" {
 			fmt.Printf("\n  %s%sSBOM%s: %s\n", ColorBold, ColorCyan, ColorReset, ctx.SBOMPath)
 		}
 
 		fmt.Println()
 	}
 
+	// Repos that were never scanned count as failed scans
+	if len(cloneFailures) > 0 {
+		fmt.Printf("%s%s%s FAILED TO SCAN (%d)%s\n", ColorBold, ColorRed, theme.Warning, len(cloneFailures), ColorReset)
+		fmt.Printf("%s%s%s\n", ColorDim, thinSeparator, ColorReset)
+		for _, ctx := range cloneFailures {
+			fmt.Printf("  %s%s %s%s: %sclone failed%s - %v\n",
+				ColorRed, theme.Failed, ctx.RepoURL, ColorReset, ColorRed, ColorReset, ctx.CloneError)
+		}
+		fmt.Println()
+		failed += len(cloneFailures)
+		totalResults += len(cloneFailures)
+	}
+
 	// Overall totals
 	fmt.Printf("%s%s%s\n", ColorCyan, separator, ColorReset)
-	fmt.Printf("%s%s 📈 OVERALL STATISTICS %s%s\n", ColorBold, ColorCyan, ColorReset, ColorReset)
+	fmt.Printf("%s%s %s OVERALL STATISTICS %s%s\n", ColorBold, ColorCyan, theme.StatsIcon, ColorReset, ColorReset)
 	fmt.Printf("%s%s%s\n", ColorCyan, separator, ColorReset)
 
 	fmt.Printf("  Total scans:    %s%d%s\n", ColorBold, totalResults, ColorReset)
@@ -144,15 +308,14 @@ func printSummary(contexts []RepoScanContext) {
 }
 
 // computeCoverage builds a coverage map: language → scanType → CoverageState.
-// Scanners with Type() == "Scorecard" are excluded (repo-level, not language-specific).
-func computeCoverage(ctx RepoScanContext) map[string]map[string]CoverageState {
+// Only scanners whose Type() is in scanTypes contribute; the rest are reported
+// by printRepoLevelScanners instead. A MultiTypeParser's scanner contributes to
+// each of its types that is a column.
+func computeCoverage(ctx RepoScanContext, scanTypes []string) map[string]map[string]CoverageState {
 	if ctx.Languages == nil || len(ctx.Languages.Languages) == 0 {
 		return nil
 	}
 
-	// Collect the scan types we care about (from parsers, excluding Scorecard)
-	scanTypes := []string{"SCA", "SAST", "Reachability"}
-
 	// Initialize the matrix: every (language, scanType) starts as CoverageNone
 	coverage := make(map[string]map[string]CoverageState)
 	for _, lang := range ctx.Languages.Languages {
@@ -164,83 +327,171 @@ func computeCoverage(ctx RepoScanContext) map[string]map[string]CoverageState {
 
 	// For each scanner that was selected to run, determine which languages it covers
 	for _, scanner := range ctx.Scanners {
-		// Look up the parser to get the scan type
+		// Look up the parser to get the scan types
 		parser, ok := parsers.Get(scanner.Name)
 		if !ok {
 			continue
 		}
-		scanType := parser.Type()
-
-		// Skip repo-level scanners that aren't language-specific
-		if scanType == "Scorecard" || scanType == "Binary" || scanType == "Secrets" {
-			continue
-		}
-
-		// Skip types we don't track
-		tracked := false
-		for _, st := range scanTypes {
-			if st == scanType {
-				tracked = true
-				break
-			}
-		}
-		if !tracked {
-			continue
-		}
 
-		// Determine if this scanner succeeded or failed
+		// Determine if this scanner succeeded or failed, and its findings per type
 		scannerSuccess := false
+		var summaries map[string]parsers.FindingSummary
 		for _, result := range ctx.Results {
 			if result.Scanner == scanner.Name {
 				scannerSuccess = result.Success
+				if result.Success && !result.IsSarif {
+					summaries, _ = parseScanOutputByType(result)
+				}
 				break
 			}
 		}
 
-		// Determine which languages this scanner covers
-		isUniversal := len(scanner.Languages) == 0
-		for _, lang := range ctx.Languages.Languages {
-			covers := isUniversal
-			if !covers {
-				for _, sl := range scanner.Languages {
-					if strings.EqualFold(sl, lang) {
-						covers = true
-						break
-					}
+		for _, scanType := range parsers.ScanTypes(parser) {
+			// Skip types we don't track (shown as repo-level scanners instead)
+			if !containsScanType(scanTypes, scanType) {
+				continue
+			}
+			markCoverage(coverage, ctx.Languages.Languages, scanner, scanType, scannerSuccess, hasOnlyInfoFindings(summaries[scanType]))
+		}
+	}
+
+	return coverage
+}
+
+// markCoverage records one scanner's result in the scanType column of the
+// coverage matrix for each language it covers, fully or conditionally
+func markCoverage(coverage map[string]map[string]CoverageState, languages []string, scanner ScannerConfig, scanType string, scannerSuccess, infoOnly bool) {
+	// Determine which languages this scanner covers
+	isUniversal := len(scanner.Languages) == 0
+	for _, lang := range languages {
+		covers := isUniversal
+		if !covers {
+			for _, sl := range scanner.Languages {
+				if strings.EqualFold(sl, lang) {
+					covers = true
+					break
 				}
 			}
+		}
 
-			if covers {
-				current := coverage[lang][scanType]
-				if scannerSuccess {
-					// Success always upgrades to OK
-					coverage[lang][scanType] = CoverageOK
-				} else if current < CoverageFailed {
-					// Failure upgrades from None/Conditional to Failed (doesn't downgrade OK)
-					coverage[lang][scanType] = CoverageFailed
+		if covers {
+			current := coverage[lang][scanType]
+			if scannerSuccess && !infoOnly {
+				// Success always upgrades to OK
+				coverage[lang][scanType] = CoverageOK
+			} else if scannerSuccess {
+				// Info-only success upgrades anything below it but doesn't downgrade OK
+				if current < CoverageInfo {
+					coverage[lang][scanType] = CoverageInfo
 				}
-				continue
+			} else if current < CoverageFailed {
+				// Failure upgrades from None/Conditional to Failed (doesn't downgrade OK)
+				coverage[lang][scanType] = CoverageFailed
 			}
+			continue
+		}
 
-			// Check conditional language support
-			for _, sl := range scanner.LanguagesConditional {
-				if strings.EqualFold(sl, lang) {
-					// Only upgrade from None to Conditional; don't override Failed or OK
-					if coverage[lang][scanType] == CoverageNone {
-						coverage[lang][scanType] = CoverageConditional
-					}
-					break
+		// Check conditional language support
+		for _, sl := range scanner.LanguagesConditional {
+			if strings.EqualFold(sl, lang) {
+				// Only upgrade from None to Conditional; don't override Failed or OK
+				if coverage[lang][scanType] == CoverageNone {
+					coverage[lang][scanType] = CoverageConditional
 				}
+				break
 			}
 		}
 	}
+}
 
-	return coverage
+// containsScanType reports whether scanType is one of scanTypes
+func containsScanType(scanTypes []string, scanType string) bool {
+	for _, st := range scanTypes {
+		if st == scanType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOnlyInfoFindings reports whether a scanner's summary has findings but
+// none of them Critical, High, or Medium. Such output often points at a
+// misconfigured scanner rather than a clean codebase.
+func hasOnlyInfoFindings(summary parsers.FindingSummary) bool {
+	return summary.Total > 0 && summary.Critical+summary.High+summary.Medium == 0
+}
+
+// languageMismatch compares the languages a scanner's parser supports with
+// the languages configured for it (full and conditional). It returns the
+// parser's languages and true when the two differ. Parsers that don't
+// implement LanguageParser are never reported.
+func languageMismatch(scanner ScannerConfig) ([]string, bool) {
+	supported, ok := parsers.SupportedLanguages(scanner.Name)
+	if !ok {
+		return nil, false
+	}
+	return supported, !sameLanguages(supported, configuredLanguages(scanner))
+}
+
+// configuredLanguages returns a scanner's full and conditional languages
+func configuredLanguages(scanner ScannerConfig) []string {
+	return append(append([]string{}, scanner.Languages...), scanner.LanguagesConditional...)
+}
+
+// sameLanguages reports whether two language lists hold the same languages,
+// ignoring order, case and duplicates
+func sameLanguages(a, b []string) bool {
+	set := func(langs []string) map[string]bool {
+		m := make(map[string]bool, len(langs))
+		for _, lang := range langs {
+			m[strings.ToLower(lang)] = true
+		}
+		return m
+	}
+	setA, setB := set(a), set(b)
+	if len(setA) != len(setB) {
+		return false
+	}
+	for lang := range setA {
+		if !setB[lang] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLanguageList renders a scanner language list, where empty means all
+func formatLanguageList(langs []string) string {
+	if len(langs) == 0 {
+		return "all languages"
+	}
+	return strings.Join(langs, ", ")
+}
+
+// warnedLanguageMismatch holds the scanners whose language mismatch has been
+// logged, so each is reported once per run rather than once per repo
+var warnedLanguageMismatch = make(map[string]bool)
+
+// warnLanguageMismatches logs a warning for each scanner whose configured
+// languages differ from the languages its parser supports, which usually
+// means scanners.yaml is misconfigured and the coverage matrix is off
+func warnLanguageMismatches(scanners []ScannerConfig) {
+	for _, scanner := range scanners {
+		if warnedLanguageMismatch[scanner.Name] {
+			continue
+		}
+		if supported, mismatch := languageMismatch(scanner); mismatch {
+			warnedLanguageMismatch[scanner.Name] = true
+			log.Printf("⚠️  %s is configured for %s, but its parser supports %s; check languages in scanners.yaml",
+				scanner.Name, formatLanguageList(configuredLanguages(scanner)), formatLanguageList(supported))
+		}
+	}
 }
 
 // printCoverageMatrix renders the language coverage table for a repo context
-func printCoverageMatrix(ctx RepoScanContext) {
-	coverage := computeCoverage(ctx)
+func printCoverageMatrix(ctx RepoScanContext, scanTypes []string) {
+	warnLanguageMismatches(ctx.Scanners)
+	coverage := computeCoverage(ctx, scanTypes)
 	if coverage == nil {
 		return
 	}
@@ -248,21 +499,12 @@ func printCoverageMatrix(ctx RepoScanContext) {
 	// Get percentages for labelling
 	pcts := ctx.Languages.Percentages()
 
-	// Sort languages by percentage descending (most prevalent first),
-	// with alphabetical as tiebreaker
+	// Sort languages by percentage descending (most prevalent first)
 	languages := make([]string, 0, len(coverage))
 	for lang := range coverage {
 		languages = append(languages, lang)
 	}
-	sort.Slice(languages, func(i, j int) bool {
-		pi, pj := pcts[languages[i]], pcts[languages[j]]
-		if pi != pj {
-			return pi > pj
-		}
-		return languages[i] < languages[j]
-	})
-
-	scanTypes := []string{"SCA", "SAST", "Reachability"}
+	sortLanguagesByShare(languages, pcts)
 
 	// Display labels for column headers (short names for narrow columns)
 	scanTypeLabels := map[string]string{"SCA": "SCA", "SAST": "SAST", "Reachability": "Reach"}
@@ -276,12 +518,7 @@ func printCoverageMatrix(ctx RepoScanContext) {
 			maxLangNameWidth = len(lang)
 		}
 		if pct, ok := pcts[lang]; ok {
-			var s string
-			if pct < 1.0 {
-				s = "(<1%)"
-			} else {
-				s = fmt.Sprintf("(%d%%)", int(pct+0.5))
-			}
+			s := "(" + formatLanguagePercent(pct) + ")"
 			pctStrs[lang] = s
 			if len(s) > maxPctWidth {
 				maxPctWidth = len(s)
@@ -307,52 +544,169 @@ func printCoverageMatrix(ctx RepoScanContext) {
 		}
 	}
 	colWidth := 10 // width for each scan type column
-
-	// Print header
-	fmt.Printf("\n  %s%sLanguage Coverage%s\n", ColorBold, ColorCyan, ColorReset)
-	fmt.Printf("  %-*s", langWidth, "Language")
 	for _, st := range scanTypes {
-		label := st
-		if l, ok := scanTypeLabels[st]; ok {
-			label = l
+		if label := scanTypeLabel(scanTypeLabels, st); len(label) > colWidth {
+			colWidth = len(label)
 		}
-		fmt.Printf("  %-*s", colWidth, label)
 	}
-	fmt.Println()
 
-	// Separator
+	fmt.Printf("\n  %s%sLanguage Coverage%s\n", ColorBold, ColorCyan, ColorReset)
 	totalWidth := langWidth + len(scanTypes)*(colWidth+2)
-	fmt.Printf("  %s%s%s\n", ColorDim, strings.Repeat("─", totalWidth), ColorReset)
-
-	// Rows
-	for _, lang := range languages {
-		fmt.Printf("  %-*s", langWidth, labels[lang])
-		for _, st := range scanTypes {
-			state := coverage[lang][st]
-			var cell string
-			switch state {
-			case CoverageOK:
-				cell = fmt.Sprintf("%s✔%s", ColorBrightGreen, ColorReset)
-			case CoverageFailed:
-				cell = fmt.Sprintf("%s⚠%s", ColorYellow, ColorReset)
-			case CoverageConditional:
-				cell = fmt.Sprintf("%s◐%s", ColorYellow, ColorReset)
-			default:
-				cell = fmt.Sprintf("%s✘%s", ColorRed, ColorReset)
+	if useCompactMatrix(totalWidth+2, summaryWidth) {
+		// Too wide for the terminal: one line per language instead of a table
+		for _, lang := range languages {
+			label := lang
+			if s, ok := pctStrs[lang]; ok {
+				label += " " + s
+			}
+			cells := make([]string, 0, len(scanTypes))
+			for _, st := range scanTypes {
+				color, symbol := coverageSymbol(coverage[lang][st])
+				cells = append(cells, fmt.Sprintf("%s %s%s%s", scanTypeLabel(scanTypeLabels, st), color, symbol, ColorReset))
 			}
-			// Pad to colWidth (symbol is 1 visible char + color codes)
-			fmt.Printf("  %s%*s", cell, colWidth-1, "")
+			fmt.Printf("  %s: %s\n", label, strings.Join(cells, ", "))
+		}
+	} else {
+		// Header
+		fmt.Printf("  %-*s", langWidth, "Language")
+		for _, st := range scanTypes {
+			fmt.Printf("  %-*s", colWidth, scanTypeLabel(scanTypeLabels, st))
 		}
 		fmt.Println()
+
+		// Separator
+		fmt.Printf("  %s%s%s\n", ColorDim, strings.Repeat(theme.ThinSeparator, totalWidth), ColorReset)
+
+		// Rows
+		for _, lang := range languages {
+			fmt.Printf("  %-*s", langWidth, labels[lang])
+			for _, st := range scanTypes {
+				color, symbol := coverageSymbol(coverage[lang][st])
+				// Pad to colWidth (visible symbol width + color codes)
+				fmt.Printf("  %s%s%s%*s", color, symbol, ColorReset, colWidth-utf8.RuneCountInString(symbol), "")
+			}
+			fmt.Println()
+		}
+	}
+
+	// Legend
+	legend := []string{
+		fmt.Sprintf("%s%s%s covered", ColorBrightGreen, theme.OK, ColorReset),
+		fmt.Sprintf("%s%s%s%s info only", ColorDim, ColorGreen, theme.InfoOnly, ColorReset),
+		fmt.Sprintf("%s%s%s conditional", ColorYellow, theme.Conditional, ColorReset),
+		fmt.Sprintf("%s%s%s failed", ColorYellow, theme.Warning, ColorReset),
+		fmt.Sprintf("%s%s%s none", ColorRed, theme.None, ColorReset),
 	}
+	fmt.Printf("  %s\n", strings.Join(legend, "  "))
 
 	// Print repo-level scanners below the table
-	printRepoLevelScanners(ctx)
+	printRepoLevelScanners(ctx, scanTypes)
+}
+
+// summaryWidth is the terminal width the summary must fit in, from --width
+// or $COLUMNS; 0 (unknown) always renders the coverage matrix as a table
+var summaryWidth int
+
+// terminalWidth returns the --width flag if set, otherwise the width in
+// $COLUMNS, or 0 when neither gives a usable width
+func terminalWidth(flagWidth int, columns string) int {
+	if flagWidth > 0 {
+		return flagWidth
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(columns)); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
+
+// useCompactMatrix reports whether a coverage table of tableWidth columns
+// would wrap on a terminal termWidth columns wide, so the compact one line per
+// language listing should be used instead. An unknown width (0) never wraps.
+func useCompactMatrix(tableWidth, termWidth int) bool {
+	return termWidth > 0 && tableWidth > termWidth
+}
+
+// scanTypeLabel returns the display label of a coverage matrix column
+func scanTypeLabel(labels map[string]string, scanType string) string {
+	if label, ok := labels[scanType]; ok {
+		return label
+	}
+	return scanType
+}
+
+// coverageSymbol returns the color and theme symbol for a coverage state
+func coverageSymbol(state CoverageState) (color, symbol string) {
+	switch state {
+	case CoverageOK:
+		return ColorBrightGreen, theme.OK
+	case CoverageInfo:
+		return ColorDim + ColorGreen, theme.InfoOnly
+	case CoverageFailed:
+		return ColorYellow, theme.Warning
+	case CoverageConditional:
+		return ColorYellow, theme.Conditional
+	default:
+		return ColorRed, theme.None
+	}
+}
+
+// sortLanguagesByShare sorts languages by percentage descending, with
+// alphabetical order as tiebreaker
+func sortLanguagesByShare(languages []string, pcts map[string]float64) {
+	sort.Slice(languages, func(i, j int) bool {
+		pi, pj := pcts[languages[i]], pcts[languages[j]]
+		if pi != pj {
+			return pi > pj
+		}
+		return languages[i] < languages[j]
+	})
+}
+
+// formatLanguagePercent renders a language share as a whole percentage,
+// or "<1%" for tiny fractions
+func formatLanguagePercent(pct float64) string {
+	if pct < 1.0 {
+		return "<1%"
+	}
+	return fmt.Sprintf("%d%%", int(pct+0.5))
+}
+
+// formatLanguagesLine renders the detected languages for the repo header, most
+// prevalent first, e.g. "go (72%), python (28%) [via GitHub API]".
+// Returns "" when no languages were detected.
+func formatLanguagesLine(detected *DetectedLanguages) string {
+	if detected == nil || len(detected.Languages) == 0 {
+		return ""
+	}
+
+	pcts := detected.Percentages()
+	languages := append([]string(nil), detected.Languages...)
+	sortLanguagesByShare(languages, pcts)
+
+	parts := make([]string, 0, len(languages))
+	for _, lang := range languages {
+		if pct, ok := pcts[lang]; ok {
+			parts = append(parts, fmt.Sprintf("%s (%s)", lang, formatLanguagePercent(pct)))
+		} else {
+			parts = append(parts, lang)
+		}
+	}
+	if len(detected.Ignored) > 0 {
+		ignored := append([]string(nil), detected.Ignored...)
+		sortLanguagesByShare(ignored, pcts)
+		for i, lang := range ignored {
+			ignored[i] = fmt.Sprintf("%s (%s)", lang, formatLanguagePercent(pcts[lang]))
+		}
+		parts = append(parts, "ignored: "+strings.Join(ignored, ", "))
+	}
+	return fmt.Sprintf("%s [via %s]", strings.Join(parts, ", "), detected.sourceLabel())
 }
 
-// printRepoLevelScanners lists language-agnostic scanners (Secrets, Binary, Scorecard)
-// separately from the per-language coverage matrix.
-func printRepoLevelScanners(ctx RepoScanContext) {
+// printRepoLevelScanners lists scanners whose type is not a coverage matrix
+// column (by default Secrets and Scorecard) separately from the
+// per-language coverage matrix. A MultiTypeParser's scanner is listed with
+// its types that aren't columns, if any.
+func printRepoLevelScanners(ctx RepoScanContext, scanTypes []string) {
 	type repoScanner struct {
 		name     string
 		scanType string
@@ -365,8 +719,13 @@ func printRepoLevelScanners(ctx RepoScanContext) {
 		if !ok {
 			continue
 		}
-		scanType := parser.Type()
-		if scanType != "Secrets" && scanType != "Binary" && scanType != "Scorecard" {
+		var repoTypes []string
+		for _, scanType := range parsers.ScanTypes(parser) {
+			if !containsScanType(scanTypes, scanType) {
+				repoTypes = append(repoTypes, scanType)
+			}
+		}
+		if len(repoTypes) == 0 {
 			continue
 		}
 
@@ -386,7 +745,7 @@ func printRepoLevelScanners(ctx RepoScanContext) {
 
 		scanners = append(scanners, repoScanner{
 			name:     scanner.Name,
-			scanType: scanType,
+			scanType: strings.Join(repoTypes, ", "),
 			success:  success,
 		})
 	}
@@ -399,9 +758,9 @@ func printRepoLevelScanners(ctx RepoScanContext) {
 	for _, s := range scanners {
 		var icon string
 		if s.success {
-			icon = fmt.Sprintf("%s✔%s", ColorBrightGreen, ColorReset)
+			icon = fmt.Sprintf("%s%s%s", ColorBrightGreen, theme.OK, ColorReset)
 		} else {
-			icon = fmt.Sprintf("%s⚠%s", ColorYellow, ColorReset)
+			icon = fmt.Sprintf("%s%s%s", ColorYellow, theme.Warning, ColorReset)
 		}
 		fmt.Printf("  %s %s (%s%s%s)\n", icon, s.name, ColorDim, s.scanType, ColorReset)
 	}
@@ -425,18 +784,99 @@ func parseScanOutput(result ScanResult) (parsers.FindingSummary, parsers.ResultP
 	return summary, parser
 }
 
+// printDependencyScopes prints the runtime/dev split of a grype result's
+// matches when any are in dev-scoped dependencies
+func printDependencyScopes(parser parsers.ResultParser, result ScanResult) {
+	grype, ok := parser.(*parsers.GrypeParser)
+	if !ok {
+		return
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		return
+	}
+	scopes, err := grype.DependencyScopes(data)
+	if err != nil || scopes.Dev == 0 {
+		return
+	}
+	excluded := ""
+	if grype.ExcludeDevDeps {
+		excluded = " (excluded)"
+	}
+	fmt.Printf("     %sRuntime: %d, dev: %d%s%s\n", ColorDim, scopes.Runtime, scopes.Dev, excluded, ColorReset)
+}
+
+// heuristicScanOutput counts the findings of a scanner without a parser with
+// parsers.HeuristicParser. ok is false when the output can't be read or
+// parsed, or has no severity fields to count.
+func heuristicScanOutput(result ScanResult) (summary parsers.FindingSummary, ok bool) {
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		return summary, false
+	}
+	summary, err = parsers.HeuristicParser{ScannerName: result.Scanner}.Parse(data)
+	return summary, err == nil && summary.Total > 0
+}
+
+// parseScanOutputByType is parseScanOutput split by scan type: a
+// MultiTypeParser's per-type summaries, or the one summary under the parser's
+// Type(). Returns nil summaries when there is no parser or the output can't be
+// read.
+func parseScanOutputByType(result ScanResult) (map[string]parsers.FindingSummary, parsers.ResultParser) {
+	parser, ok := parsers.Get(result.Scanner)
+	if !ok {
+		return nil, nil
+	}
+	data, err := os.ReadFile(result.OutputPath)
+	if err != nil {
+		return nil, parser
+	}
+
+	if multi, ok := parser.(parsers.MultiTypeParser); ok {
+		summaries, _ := multi.ParseByType(data)
+		return summaries, parser
+	}
+	summary, _ := parser.Parse(data)
+	return map[string]parsers.FindingSummary{parser.Type(): summary}, parser
+}
+
+// formatTrend compares the critical count of a result against the previous
+// run and returns a colored arrow (e.g. "↑3" in red), or "" when there is
+// no previous result to compare against.
+func formatTrend(summary parsers.FindingSummary, result ScanResult) string {
+	previous, ok := previousSummaries[trendKey(result.Repository, result.Subproject, result.Scanner)]
+	if !ok {
+		return ""
+	}
+	delta := summary.Critical - previous.Critical
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("%s%s%d%s", ColorRed, theme.TrendUp, delta, ColorReset)
+	case delta < 0:
+		return fmt.Sprintf("%s%s%d%s", ColorGreen, theme.TrendDown, -delta, ColorReset)
+	default:
+		return fmt.Sprintf("%s%s%s", ColorDim, theme.TrendSame, ColorReset)
+	}
+}
+
+// printScannerHeader prints the icon, name, and type of a scanner, followed
+// by the critical-count trend when one is available.
+func printScannerHeader(parser parsers.ResultParser, trend string) {
+	if trend != "" {
+		trend = " " + trend
+	}
+	fmt.Printf("  %s %s%s%s (%s%s%s)%s\n", theme.Icon(parser), ColorBold, parser.Name(), ColorReset, ColorDim, strings.Join(parsers.ScanTypes(parser), ", "), ColorReset, trend)
+}
+
 // printScannerSummary displays findings for a single scanner
-func printScannerSummary(parser parsers.ResultParser, summary parsers.FindingSummary) {
-	// Use parser metadata for display
-	icon := parser.Icon()
+func printScannerSummary(parser parsers.ResultParser, summary parsers.FindingSummary, trend string) {
 	scanType := parser.Type()
-	scannerName := parser.Name()
 
 	// Scanner header
-	fmt.Printf("  %s %s%s%s (%s%s%s)\n", icon, ColorBold, scannerName, ColorReset, ColorDim, scanType, ColorReset)
+	printScannerHeader(parser, trend)
 
 	if summary.Total == 0 {
-		fmt.Printf("     %s✨ No findings%s\n", ColorGreen, ColorReset)
+		fmt.Printf("     %s%s No findings%s\n", ColorGreen, theme.NoFindings, ColorReset)
 		return
 	}
 
@@ -444,38 +884,62 @@ func printScannerSummary(parser parsers.ResultParser, summary parsers.FindingSum
 	if scanType == "Secrets" {
 		var findings []string
 		if summary.Critical > 0 {
-			findings = append(findings, fmt.Sprintf("%s%s🔴 Verified: %d%s", ColorRed, ColorBold, summary.Critical, ColorReset))
+			findings = append(findings, fmt.Sprintf("%s%s%s Verified: %d%s", ColorRed, ColorBold, theme.Critical, summary.Critical, ColorReset))
 		}
 		if summary.Medium > 0 {
-			findings = append(findings, fmt.Sprintf("%s🟡 Unverified: %d%s", ColorYellow, summary.Medium, ColorReset))
+			findings = append(findings, fmt.Sprintf("%s%s Unverified: %d%s", ColorYellow, theme.Medium, summary.Medium, ColorReset))
 		}
 		fmt.Printf("     %s\n", strings.Join(findings, "  "))
 		fmt.Printf("     %sTotal: %d secrets%s\n", ColorDim, summary.Total, ColorReset)
 		return
 	}
 
-	// Build findings line for SCA/SAST
+	// Print findings line for SCA/SAST
+	fmt.Printf("     %s\n", strings.Join(severityFindings(summary), "  "))
+	fmt.Printf("     %sTotal: %d findings%s\n", ColorDim, summary.Total, ColorReset)
+}
+
+// printMultiTypeScannerSummary displays findings for a MultiTypeParser's
+// scanner, one line per scan type with findings. summary is the overall count.
+func printMultiTypeScannerSummary(parser parsers.ResultParser, summaries map[string]parsers.FindingSummary, summary parsers.FindingSummary, trend string) {
+	printScannerHeader(parser, trend)
+
+	if summary.Total == 0 {
+		fmt.Printf("     %s%s No findings%s\n", ColorGreen, theme.NoFindings, ColorReset)
+		return
+	}
+
+	for _, scanType := range parsers.ScanTypes(parser) {
+		typeSummary := summaries[scanType]
+		if typeSummary.Total == 0 {
+			continue
+		}
+		fmt.Printf("     %s%s:%s %s\n", ColorDim, scanType, ColorReset, strings.Join(severityFindings(typeSummary), "  "))
+	}
+	fmt.Printf("     %sTotal: %d findings%s\n", ColorDim, summary.Total, ColorReset)
+}
+
+// severityFindings formats the non-zero severity counts of a summary, most
+// severe first, e.g. "🔴 Critical: 1"
+func severityFindings(summary parsers.FindingSummary) []string {
 	var findings []string
 
 	if summary.Critical > 0 {
-		findings = append(findings, fmt.Sprintf("%s%s🔴 Critical: %d%s", ColorRed, ColorBold, summary.Critical, ColorReset))
+		findings = append(findings, fmt.Sprintf("%s%s%s %s: %d%s", ColorRed, ColorBold, theme.Critical, severityLabels["critical"], summary.Critical, ColorReset))
 	}
 	if summary.High > 0 {
-		findings = append(findings, fmt.Sprintf("%s🟠 High: %d%s", ColorRed, summary.High, ColorReset))
+		findings = append(findings, fmt.Sprintf("%s%s %s: %d%s", ColorRed, theme.High, severityLabels["high"], summary.High, ColorReset))
 	}
 	if summary.Medium > 0 {
-		findings = append(findings, fmt.Sprintf("%s🟡 Medium: %d%s", ColorYellow, summary.Medium, ColorReset))
+		findings = append(findings, fmt.Sprintf("%s%s %s: %d%s", ColorYellow, theme.Medium, severityLabels["medium"], summary.Medium, ColorReset))
 	}
 	if summary.Low > 0 {
-		findings = append(findings, fmt.Sprintf("%s🟢 Low: %d%s", ColorGreen, summary.Low, ColorReset))
+		findings = append(findings, fmt.Sprintf("%s%s %s: %d%s", ColorGreen, theme.Low, severityLabels["low"], summary.Low, ColorReset))
 	}
 	if summary.Info > 0 {
-		findings = append(findings, fmt.Sprintf("%s⚪ Info: %d%s", ColorDim, summary.Info, ColorReset))
+		findings = append(findings, fmt.Sprintf("%s%s %s: %d%s", ColorDim, theme.Info, severityLabels["info"], summary.Info, ColorReset))
 	}
-
-	// Print findings
-	fmt.Printf("     %s\n", strings.Join(findings, "  "))
-	fmt.Printf("     %sTotal: %d findings%s\n", ColorDim, summary.Total, ColorReset)
+	return findings
 }
 
 // findGovulncheckOutput returns the output path of a successful, non-SARIF
@@ -490,29 +954,33 @@ func findGovulncheckOutput(results []ScanResult) string {
 }
 
 // buildReachabilityIndexFromResults builds a ReachabilityIndex from govulncheck
-// output found in the scan results, then expands it using OSV-scanner's ID groups
+// output and osv-scanner call analysis found in the scan results (nil when
+// neither is available), then expands it using OSV-scanner's ID groups
 // as a pivot point. OSV-scanner groups correlate vulnerability IDs across naming
 // schemes (GO-xxxx, CVE-xxxx, GHSA-xxxx), enabling cross-reference with scanners
 // like grype that use different ID formats than govulncheck.
 func buildReachabilityIndexFromResults(results []ScanResult) parsers.ReachabilityIndex {
-	path := findGovulncheckOutput(results)
-	if path == "" {
-		return nil
+	var idx parsers.ReachabilityIndex
+	if path := findGovulncheckOutput(results); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			idx = parsers.BuildReachabilityIndex(data)
+		}
 	}
-	data, err := os.ReadFile(path)
+
+	osvPath := findOSVScannerOutput(results)
+	if osvPath == "" {
+		return idx
+	}
+	osvData, err := os.ReadFile(osvPath)
 	if err != nil {
-		return nil
+		return idx
 	}
-	idx := parsers.BuildReachabilityIndex(data)
+
+	// Add osv-scanner's own call analysis (--experimental-call-analysis), if present
+	idx = idx.Merge(parsers.BuildOSVCallAnalysisIndex(osvData))
 
 	// Use OSV-scanner output as a pivot to expand the index with alias groups
-	osvPath := findOSVScannerOutput(results)
-	if osvPath != "" {
-		if osvData, err := os.ReadFile(osvPath); err == nil {
-			groups := parsers.ExtractOSVScannerAliasGroups(osvData)
-			idx.ExpandWithAliasGroups(groups)
-		}
-	}
+	idx.ExpandWithAliasGroups(parsers.ExtractOSVScannerAliasGroups(osvData))
 
 	return idx
 }
@@ -531,25 +999,12 @@ func findOSVScannerOutput(results []ScanResult) string {
 // enrichSCAResult reads an SCA result's output, extracts findings, and cross-references
 // with the reachability index. Returns nil if no enrichment is possible.
 func enrichSCAResult(result ScanResult, idx parsers.ReachabilityIndex) *parsers.EnrichedSummary {
-	if idx == nil || result.IsSarif || !result.Success {
+	if idx == nil {
 		return nil
 	}
 
-	data, err := os.ReadFile(result.OutputPath)
-	if err != nil {
-		return nil
-	}
-
-	var findings []parsers.SCAFinding
-	switch result.Scanner {
-	case "grype":
-		findings, err = parsers.ExtractGrypeFindings(data)
-	case "osv-scanner":
-		findings, err = parsers.ExtractOSVScannerFindings(data)
-	default:
-		return nil
-	}
-	if err != nil || len(findings) == 0 {
+	findings, ok := extractSCAFindings(result)
+	if !ok || len(findings) == 0 {
 		return nil
 	}
 
@@ -562,15 +1017,11 @@ func enrichSCAResult(result ScanResult, idx parsers.ReachabilityIndex) *parsers.
 }
 
 // printEnrichedScannerSummary displays findings for an SCA scanner with reachability annotations.
-func printEnrichedScannerSummary(parser parsers.ResultParser, enriched *parsers.EnrichedSummary) {
-	icon := parser.Icon()
-	scanType := parser.Type()
-	scannerName := parser.Name()
-
-	fmt.Printf("  %s %s%s%s (%s%s%s)\n", icon, ColorBold, scannerName, ColorReset, ColorDim, scanType, ColorReset)
+func printEnrichedScannerSummary(parser parsers.ResultParser, enriched *parsers.EnrichedSummary, trend string) {
+	printScannerHeader(parser, trend)
 
 	if enriched.Total == 0 {
-		fmt.Printf("     %s✨ No findings%s\n", ColorGreen, ColorReset)
+		fmt.Printf("     %s%s No findings%s\n", ColorGreen, theme.NoFindings, ColorReset)
 		return
 	}
 
@@ -578,35 +1029,35 @@ func printEnrichedScannerSummary(parser parsers.ResultParser, enriched *parsers.
 	var findings []string
 
 	if enriched.Critical > 0 {
-		s := fmt.Sprintf("%s%s🔴 Critical: %d%s", ColorRed, ColorBold, enriched.Critical, ColorReset)
+		s := fmt.Sprintf("%s%s%s %s: %d%s", ColorRed, ColorBold, theme.Critical, severityLabels["critical"], enriched.Critical, ColorReset)
 		if enriched.CriticalReachable > 0 {
 			s += fmt.Sprintf(" %s(%d reachable)%s", ColorDim, enriched.CriticalReachable, ColorReset)
 		}
 		findings = append(findings, s)
 	}
 	if enriched.High > 0 {
-		s := fmt.Sprintf("%s🟠 High: %d%s", ColorRed, enriched.High, ColorReset)
+		s := fmt.Sprintf("%s%s %s: %d%s", ColorRed, theme.High, severityLabels["high"], enriched.High, ColorReset)
 		if enriched.HighReachable > 0 {
 			s += fmt.Sprintf(" %s(%d reachable)%s", ColorDim, enriched.HighReachable, ColorReset)
 		}
 		findings = append(findings, s)
 	}
 	if enriched.Medium > 0 {
-		s := fmt.Sprintf("%s🟡 Medium: %d%s", ColorYellow, enriched.Medium, ColorReset)
+		s := fmt.Sprintf("%s%s %s: %d%s", ColorYellow, theme.Medium, severityLabels["medium"], enriched.Medium, ColorReset)
 		if enriched.MediumReachable > 0 {
 			s += fmt.Sprintf(" %s(%d reachable)%s", ColorDim, enriched.MediumReachable, ColorReset)
 		}
 		findings = append(findings, s)
 	}
 	if enriched.Low > 0 {
-		s := fmt.Sprintf("%s🟢 Low: %d%s", ColorGreen, enriched.Low, ColorReset)
+		s := fmt.Sprintf("%s%s %s: %d%s", ColorGreen, theme.Low, severityLabels["low"], enriched.Low, ColorReset)
 		if enriched.LowReachable > 0 {
 			s += fmt.Sprintf(" %s(%d reachable)%s", ColorDim, enriched.LowReachable, ColorReset)
 		}
 		findings = append(findings, s)
 	}
 	if enriched.Info > 0 {
-		s := fmt.Sprintf("%s⚪ Info: %d%s", ColorDim, enriched.Info, ColorReset)
+		s := fmt.Sprintf("%s%s %s: %d%s", ColorDim, theme.Info, severityLabels["info"], enriched.Info, ColorReset)
 		if enriched.InfoReachable > 0 {
 			s += fmt.Sprintf(" (%d reachable)", enriched.InfoReachable)
 		}
@@ -614,25 +1065,102 @@ func printEnrichedScannerSummary(parser parsers.ResultParser, enriched *parsers.
 	}
 
 	fmt.Printf("     %s\n", strings.Join(findings, "  "))
-	fmt.Printf("     %sTotal: %d findings%s\n", ColorDim, enriched.Total, ColorReset)
+	fmt.Printf("     %sTotal: %d findings (%d reachable)%s\n", ColorDim, enriched.Total, enriched.Breakdown.Reachable, ColorReset)
 }
 
 // printReachabilitySummary displays reachability analysis results
 func printReachabilitySummary(parser parsers.ResultParser, summary parsers.FindingSummary) {
-	fmt.Printf("  %s %s%s%s (%s%s%s)\n", parser.Icon(), ColorBold, parser.Name(), ColorReset, ColorDim, parser.Type(), ColorReset)
+	fmt.Printf("  %s %s%s%s (%s%s%s)\n", theme.Icon(parser), ColorBold, parser.Name(), ColorReset, ColorDim, parser.Type(), ColorReset)
 
 	if summary.Total == 0 {
-		fmt.Printf("     %s✨ No vulnerabilities found%s\n", ColorGreen, ColorReset)
+		fmt.Printf("     %s%s No vulnerabilities found%s\n", ColorGreen, theme.NoFindings, ColorReset)
 		return
 	}
 
 	if summary.Critical > 0 {
-		fmt.Printf("     %s🔴 Reachable: %d%s %s(called — fix these first)%s\n",
-			ColorRed, summary.Critical, ColorReset, ColorDim, ColorReset)
+		fmt.Printf("     %s%s Reachable: %d%s %s(called - fix these first)%s\n",
+			ColorRed, theme.Critical, summary.Critical, ColorReset, ColorDim, ColorReset)
 	}
 	if summary.Info > 0 {
-		fmt.Printf("     ⚪ Unreachable: %d %s(imported/dependency only)%s\n",
-			summary.Info, ColorDim, ColorReset)
+		fmt.Printf("     %s Unreachable: %d %s(imported/dependency only)%s\n",
+			theme.Info, summary.Info, ColorDim, ColorReset)
 	}
 	fmt.Printf("     %sTotal: %d unique vulnerabilities%s\n", ColorDim, summary.Total, ColorReset)
 }
+
+// repoRef returns how a repository is checked out: its ref type (tag, commit
+// or branch, in cloneRepository's precedence) and value
+func repoRef(repo RepositoryConfig) (refType, ref string) {
+	switch {
+	case repo.Version != "":
+		return "tag", repo.Version
+	case repo.Commit != "":
+		return "commit", repo.Commit
+	case repo.Branch != "":
+		return "branch", repo.Branch
+	}
+	return "branch", "main"
+}
+
+// repoScannerCount returns how many scanners would be selected for a
+// repository before language filtering: the --scan filter, else the enabled
+// scanners its scanners list or bundle names, else all enabled scanners
+func repoScannerCount(config *Config, repo RepositoryConfig) int {
+	if len(config.Global.ScanFilter) > 0 {
+		return len(config.Global.ScanFilter)
+	}
+	names := repoScannerNames(config.Global, repo)
+	if len(names) == 0 {
+		return countEnabledScanners(config)
+	}
+	count := 0
+	for _, name := range names {
+		for _, scanner := range config.Scanners {
+			if scanner.Name == name && scanner.Enabled {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// listRepositories prints the repositories a run would scan (--list-repos):
+// URL, ref type and value, the number of enabled scanners and how many
+// scanners the repo names itself (scanners list or bundle). Disabled repos
+// are listed with a [disabled] annotation unless --include-disabled is set.
+// The footer estimates the run's duration from durations (see estimateRun).
+func listRepositories(repos []RepositoryConfig, config *Config, durations map[string]time.Duration, w io.Writer) {
+	urlWidth := len("URL")
+	for _, repo := range repos {
+		urlWidth = max(urlWidth, len(repo.URL))
+	}
+
+	fmt.Fprintf(w, "%-*s  %-8s %-24s %-8s %s\n", urlWidth, "URL", "REF TYPE", "REF", "SCANNERS", "OVERRIDES")
+	disabled := 0
+	for _, repo := range repos {
+		refType, ref := repoRef(repo)
+		line := fmt.Sprintf("%-*s  %-8s %-24s %-8d %d", urlWidth, repo.URL, refType, ref,
+			repoScannerCount(config, repo), len(repoScannerNames(config.Global, repo)))
+		if repo.Disabled && !config.Global.IncludeDisabled {
+			line += "  [disabled]"
+			disabled++
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	fmt.Fprintf(w, "\n%d repositories", len(repos))
+	if disabled > 0 {
+		fmt.Fprintf(w, " (%d disabled)", disabled)
+	}
+	fmt.Fprintln(w)
+
+	estimate := estimateRun(config, repos, durations)
+	if estimate.Repos == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Estimated ~%s (%d repos × %.1f avg scanners)\n", formatEstimate(estimate.Total), estimate.Repos, estimate.AvgScanners)
+	if len(estimate.FromTimeouts) > 0 {
+		fmt.Fprintf(w, "No recorded duration for %s: estimated at their timeout\n", strings.Join(estimate.FromTimeouts, ", "))
+	}
+}
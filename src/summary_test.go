@@ -1,6 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"allscan/parsers"
@@ -10,10 +17,11 @@ import (
 type testParser struct {
 	name     string
 	scanType string
+	summary  parsers.FindingSummary // returned by Parse
 }
 
 func (p *testParser) Parse(data []byte) (parsers.FindingSummary, error) {
-	return parsers.FindingSummary{}, nil
+	return p.summary, nil
 }
 func (p *testParser) Type() string { return p.scanType }
 func (p *testParser) Icon() string { return "🔧" }
@@ -72,6 +80,90 @@ func TestFindGovulncheckOutput(t *testing.T) {
 	}
 }
 
+func TestEnrichSCAResultWithOSVCallAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	writeOutput := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	withAnalysis := writeOutput("osv-analysis.json", `{"results": [{"packages": [{"groups": [
+		{"ids": ["GO-2024-0001"], "aliases": ["CVE-2024-1111"], "max_severity": "CRITICAL",
+		 "experimentalAnalysis": {"GO-2024-0001": {"called": true}}},
+		{"ids": ["GO-2024-0002"], "aliases": ["CVE-2024-2222"], "max_severity": "HIGH",
+		 "experimentalAnalysis": {"GO-2024-0002": {"called": false}}},
+		{"ids": ["GHSA-aaaa-bbbb-cccc"], "max_severity": "LOW"}
+	]}]}]}`)
+	withoutAnalysis := writeOutput("osv-plain.json", `{"results": [{"packages": [{"groups": [
+		{"ids": ["GO-2024-0001"], "max_severity": "CRITICAL"}
+	]}]}]}`)
+	grypePath := writeOutput("grype.json", `{"matches": [
+		{"vulnerability": {"id": "CVE-2024-1111", "severity": "Critical"}},
+		{"vulnerability": {"id": "CVE-2024-9999", "severity": "Medium"}}
+	]}`)
+
+	tests := []struct {
+		name          string
+		osvPath       string
+		scanner       string
+		wantEnriched  bool
+		wantBreakdown parsers.ReachabilityBreakdown
+		wantCritReach int
+	}{
+		{
+			name:          "osv-scanner with call analysis",
+			osvPath:       withAnalysis,
+			scanner:       "osv-scanner",
+			wantEnriched:  true,
+			wantBreakdown: parsers.ReachabilityBreakdown{Reachable: 1, Unreachable: 1, Unknown: 1},
+			wantCritReach: 1,
+		},
+		{
+			name:          "grype cross-referenced with osv call analysis",
+			osvPath:       withAnalysis,
+			scanner:       "grype",
+			wantEnriched:  true,
+			wantBreakdown: parsers.ReachabilityBreakdown{Reachable: 1, Unknown: 1},
+			wantCritReach: 1,
+		},
+		{
+			name:         "osv-scanner without call analysis keeps plain counts",
+			osvPath:      withoutAnalysis,
+			scanner:      "osv-scanner",
+			wantEnriched: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			osvResult := ScanResult{Scanner: "osv-scanner", Success: true, OutputPath: tt.osvPath}
+			results := []ScanResult{osvResult, {Scanner: "grype", Success: true, OutputPath: grypePath}}
+			idx := buildReachabilityIndexFromResults(results)
+
+			result := osvResult
+			if tt.scanner == "grype" {
+				result = results[1]
+			}
+			enriched := enrichSCAResult(result, idx)
+			if (enriched != nil) != tt.wantEnriched {
+				t.Fatalf("enrichSCAResult() = %+v, want enriched %v", enriched, tt.wantEnriched)
+			}
+			if enriched == nil {
+				return
+			}
+			if enriched.Breakdown != tt.wantBreakdown {
+				t.Errorf("Breakdown = %+v, want %+v", enriched.Breakdown, tt.wantBreakdown)
+			}
+			if enriched.CriticalReachable != tt.wantCritReach {
+				t.Errorf("CriticalReachable = %d, want %d", enriched.CriticalReachable, tt.wantCritReach)
+			}
+		})
+	}
+}
+
 func TestComputeCoverage(t *testing.T) {
 	// Register test parsers and clean up after
 	testParsers := map[string]*testParser{
@@ -81,14 +173,27 @@ func TestComputeCoverage(t *testing.T) {
 		"test-scorecard":       {name: "test-scorecard", scanType: "Scorecard"},
 		"test-sast-universal":  {name: "test-sast-universal", scanType: "SAST"},
 		"test-reach-go":        {name: "test-reach-go", scanType: "Reachability"},
+		"test-binary":          {name: "test-binary", scanType: "Binary"},
+		"test-sast-info":       {name: "test-sast-info", scanType: "SAST", summary: parsers.FindingSummary{Info: 2, Low: 1, Total: 3}},
+		"test-sast-high":       {name: "test-sast-high", scanType: "SAST", summary: parsers.FindingSummary{High: 1, Info: 2, Total: 3}},
 	}
 	for name, p := range testParsers {
-		parsers.Register(name, p)
+		if err := parsers.Register(name, p); err != nil {
+			t.Fatalf("Register(%q) failed: %v", name, err)
+		}
 	}
 	t.Cleanup(func() {
-		// Re-register real parsers that were potentially shadowed (none in this case)
+		for name := range testParsers {
+			parsers.Unregister(name)
+		}
 	})
 
+	// Parsers only run when the output file exists; contents are ignored by testParser
+	outputPath := filepath.Join(t.TempDir(), "output.json")
+	if err := os.WriteFile(outputPath, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
 		name     string
 		ctx      RepoScanContext
@@ -120,8 +225,8 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go":     {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone},
-				"python": {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone},
+				"go":     {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
+				"python": {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -136,8 +241,8 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go":     {"SCA": CoverageNone, "SAST": CoverageOK, "Reachability": CoverageNone},
-				"python": {"SCA": CoverageNone, "SAST": CoverageNone, "Reachability": CoverageNone},
+				"go":     {"SCA": CoverageNone, "SAST": CoverageOK, "Reachability": CoverageNone, "Binary": CoverageNone},
+				"python": {"SCA": CoverageNone, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -152,7 +257,7 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go": {"SCA": CoverageNone, "SAST": CoverageFailed, "Reachability": CoverageNone},
+				"go": {"SCA": CoverageNone, "SAST": CoverageFailed, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -167,7 +272,7 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go": {"SCA": CoverageNone, "SAST": CoverageNone, "Reachability": CoverageNone},
+				"go": {"SCA": CoverageNone, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -188,9 +293,9 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go":     {"SCA": CoverageOK, "SAST": CoverageOK, "Reachability": CoverageNone},
-				"python": {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone},
-				"shell":  {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone},
+				"go":     {"SCA": CoverageOK, "SAST": CoverageOK, "Reachability": CoverageNone, "Binary": CoverageNone},
+				"python": {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
+				"shell":  {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -209,8 +314,8 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go":     {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone},
-				"elixir": {"SCA": CoverageConditional, "SAST": CoverageNone, "Reachability": CoverageNone},
+				"go":     {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
+				"elixir": {"SCA": CoverageConditional, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -234,7 +339,7 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"elixir": {"SCA": CoverageOK, "SAST": CoverageConditional, "Reachability": CoverageNone},
+				"elixir": {"SCA": CoverageOK, "SAST": CoverageConditional, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -251,7 +356,7 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go": {"SCA": CoverageNone, "SAST": CoverageOK, "Reachability": CoverageNone},
+				"go": {"SCA": CoverageNone, "SAST": CoverageOK, "Reachability": CoverageNone, "Binary": CoverageNone},
 			},
 		},
 		{
@@ -268,14 +373,67 @@ func TestComputeCoverage(t *testing.T) {
 				},
 			},
 			expected: map[string]map[string]CoverageState{
-				"go": {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageOK},
+				"go": {"SCA": CoverageOK, "SAST": CoverageNone, "Reachability": CoverageOK, "Binary": CoverageNone},
+			},
+		},
+		{
+			name: "info-only findings show CoverageInfo",
+			ctx: RepoScanContext{
+				Languages: &DetectedLanguages{Languages: []string{"go"}},
+				Scanners: []ScannerConfig{
+					{Name: "test-sast-info", Languages: []string{"go"}},
+				},
+				Results: []ScanResult{
+					{Scanner: "test-sast-info", Success: true, OutputPath: outputPath},
+				},
+			},
+			expected: map[string]map[string]CoverageState{
+				"go": {"SCA": CoverageNone, "SAST": CoverageInfo, "Reachability": CoverageNone, "Binary": CoverageNone},
+			},
+		},
+		{
+			name: "info-only overrides failure but not OK",
+			ctx: RepoScanContext{
+				Languages: &DetectedLanguages{Languages: []string{"go", "python"}},
+				Scanners: []ScannerConfig{
+					{Name: "test-sast-go", Languages: []string{"go"}},
+					{Name: "test-sast-high", Languages: []string{"python"}},
+					{Name: "test-sast-info", Languages: []string{}},
+				},
+				Results: []ScanResult{
+					{Scanner: "test-sast-go", Success: false},
+					{Scanner: "test-sast-high", Success: true, OutputPath: outputPath},
+					{Scanner: "test-sast-info", Success: true, OutputPath: outputPath},
+				},
+			},
+			expected: map[string]map[string]CoverageState{
+				"go":     {"SCA": CoverageNone, "SAST": CoverageInfo, "Reachability": CoverageNone, "Binary": CoverageNone},
+				"python": {"SCA": CoverageNone, "SAST": CoverageOK, "Reachability": CoverageNone, "Binary": CoverageNone},
+			},
+		},
+		{
+			name: "universal binary scanner covers every language",
+			ctx: RepoScanContext{
+				Languages: &DetectedLanguages{Languages: []string{"go", "python"}},
+				Scanners: []ScannerConfig{
+					{Name: "test-binary", Languages: []string{}},
+					{Name: "test-secrets", Languages: []string{}},
+				},
+				Results: []ScanResult{
+					{Scanner: "test-binary", Success: true},
+					{Scanner: "test-secrets", Success: true},
+				},
+			},
+			expected: map[string]map[string]CoverageState{
+				"go":     {"SCA": CoverageNone, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageOK},
+				"python": {"SCA": CoverageNone, "SAST": CoverageNone, "Reachability": CoverageNone, "Binary": CoverageOK},
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := computeCoverage(tt.ctx)
+			got := computeCoverage(tt.ctx, defaultCoverageScanTypes)
 
 			if tt.expected == nil {
 				if got != nil {
@@ -313,3 +471,690 @@ func TestComputeCoverage(t *testing.T) {
 		})
 	}
 }
+
+func TestCoverageScanTypes(t *testing.T) {
+	testParsers := map[string]*testParser{
+		"test-sca-cov": {name: "test-sca-cov", scanType: "SCA"},
+		"test-iac-cov": {name: "test-iac-cov", scanType: "IaC"},
+	}
+	for name, p := range testParsers {
+		if err := parsers.Register(name, p); err != nil {
+			t.Fatalf("Register(%q) failed: %v", name, err)
+		}
+	}
+	t.Cleanup(func() {
+		for name := range testParsers {
+			parsers.Unregister(name)
+		}
+	})
+
+	outputPath := filepath.Join(t.TempDir(), "output.json")
+	if err := os.WriteFile(outputPath, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := RepoScanContext{
+		Languages: &DetectedLanguages{Languages: []string{"hcl"}, FileCounts: map[string]int{"hcl": 4}},
+		Scanners: []ScannerConfig{
+			{Name: "test-sca-cov"},
+			{Name: "test-iac-cov", Languages: []string{"hcl"}},
+		},
+		Results: []ScanResult{
+			{Scanner: "test-sca-cov", Success: true, OutputPath: outputPath},
+			{Scanner: "test-iac-cov", Success: true, OutputPath: outputPath},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		scanTypes     []string
+		wantColumns   []string
+		wantRepoLevel bool // test-iac-cov listed under Repo-Level Scanners
+	}{
+		{
+			name:          "default types list IaC as repo-level",
+			scanTypes:     defaultCoverageScanTypes,
+			wantColumns:   []string{"SCA", "SAST", "Reachability", "Binary"},
+			wantRepoLevel: true,
+		},
+		{
+			name:          "IaC column when configured",
+			scanTypes:     []string{"SCA", "IaC"},
+			wantColumns:   []string{"SCA", "IaC"},
+			wantRepoLevel: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coverage := computeCoverage(ctx, tt.scanTypes)
+			if len(coverage["hcl"]) != len(tt.wantColumns) {
+				t.Fatalf("coverage[hcl] = %v, want columns %v", coverage["hcl"], tt.wantColumns)
+			}
+			for _, st := range tt.wantColumns {
+				if _, ok := coverage["hcl"][st]; !ok {
+					t.Errorf("coverage[hcl] missing column %q", st)
+				}
+			}
+			if containsScanType(tt.scanTypes, "IaC") && coverage["hcl"]["IaC"] != CoverageOK {
+				t.Errorf("coverage[hcl][IaC] = %d, want %d", coverage["hcl"]["IaC"], CoverageOK)
+			}
+
+			out := captureStdout(t, func() { printCoverageMatrix(ctx, tt.scanTypes) })
+			gotRepoLevel := strings.Contains(out, "test-iac-cov (")
+			if gotRepoLevel != tt.wantRepoLevel {
+				t.Errorf("repo-level listing of test-iac-cov = %v, want %v\n%s", gotRepoLevel, tt.wantRepoLevel, out)
+			}
+			if strings.Contains(out, "test-sca-cov (") {
+				t.Errorf("SCA scanner listed as repo-level:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestComputeCoverageMultiTypeParser(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "trivy.json")
+	report := `{"Results": [
+		{"Target": "go.mod", "Vulnerabilities": [{"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"}]},
+		{"Target": ".env", "Secrets": [{"RuleID": "github-pat", "Severity": "HIGH"}]},
+		{"Target": "deploy/web.yaml", "Misconfigurations": [{"ID": "KSV003", "Severity": "LOW", "Status": "FAIL"}]}
+	]}`
+	if err := os.WriteFile(outputPath, []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := RepoScanContext{
+		Languages: &DetectedLanguages{Languages: []string{"go"}, FileCounts: map[string]int{"go": 10}},
+		Scanners:  []ScannerConfig{{Name: "trivy"}},
+		Results:   []ScanResult{{Scanner: "trivy", Success: true, OutputPath: outputPath}},
+	}
+	scanTypes := []string{"SCA", "SAST", "Secrets", "IaC"}
+
+	coverage := computeCoverage(ctx, scanTypes)
+	want := map[string]CoverageState{
+		"SCA":     CoverageOK,
+		"SAST":    CoverageNone,
+		"Secrets": CoverageOK,
+		"IaC":     CoverageInfo, // only a low misconfiguration
+	}
+	for st, state := range want {
+		if coverage["go"][st] != state {
+			t.Errorf("coverage[go][%s] = %d, want %d", st, coverage["go"][st], state)
+		}
+	}
+
+	out := captureStdout(t, func() { printCoverageMatrix(ctx, scanTypes) })
+	if !strings.Contains(out, "trivy ("+ColorDim+"License"+ColorReset+")") {
+		t.Errorf("License findings not listed as repo-level:\n%s", out)
+	}
+
+	parser, _ := parsers.Get("trivy")
+	summaries, _ := parseScanOutputByType(ctx.Results[0])
+	summary, _ := parseScanOutput(ctx.Results[0])
+	out = captureStdout(t, func() { printMultiTypeScannerSummary(parser, summaries, summary, "") })
+	for _, want := range []string{"SCA, Secrets, IaC, License", "SCA:", "Secrets:", "IaC:", "Total: 3 findings"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trivy summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "License:") {
+		t.Errorf("trivy summary shows License without license findings:\n%s", out)
+	}
+}
+
+func TestUseCompactMatrix(t *testing.T) {
+	tests := []struct {
+		name       string
+		tableWidth int
+		termWidth  int
+		want       bool
+	}{
+		{"unknown width keeps the table", 62, 0, false},
+		{"wide terminal", 62, 120, false},
+		{"exact fit", 62, 62, false},
+		{"one column short", 62, 61, true},
+		{"narrow terminal", 62, 40, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := useCompactMatrix(tt.tableWidth, tt.termWidth); got != tt.want {
+				t.Errorf("useCompactMatrix(%d, %d) = %v, want %v", tt.tableWidth, tt.termWidth, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagWidth int
+		columns   string
+		want      int
+	}{
+		{"flag wins", 100, "80", 100},
+		{"COLUMNS", 0, "80", 80},
+		{"COLUMNS with spaces", 0, " 72\n", 72},
+		{"unset", 0, "", 0},
+		{"not a number", 0, "wide", 0},
+		{"zero columns", 0, "0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := terminalWidth(tt.flagWidth, tt.columns); got != tt.want {
+				t.Errorf("terminalWidth(%d, %q) = %d, want %d", tt.flagWidth, tt.columns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintCoverageMatrixCompact(t *testing.T) {
+	ctx := RepoScanContext{
+		Languages: &DetectedLanguages{Languages: []string{"hcl"}, FileCounts: map[string]int{"hcl": 4}},
+	}
+	defer func(w int) { summaryWidth = w }(summaryWidth)
+
+	tests := []struct {
+		name        string
+		width       int
+		wantCompact bool
+	}{
+		{"unknown width", 0, false},
+		{"wide terminal", 200, false},
+		{"narrow terminal", 30, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaryWidth = tt.width
+			out := captureStdout(t, func() { printCoverageMatrix(ctx, []string{"SCA", "SAST", "Reachability"}) })
+			gotCompact := strings.Contains(out, "hcl (100%): SCA ")
+			if gotCompact != tt.wantCompact {
+				t.Fatalf("compact layout = %v, want %v\n%s", gotCompact, tt.wantCompact, out)
+			}
+			if tt.wantCompact {
+				want := ", SAST " + ColorRed + theme.None + ColorReset + ", Reach "
+				if !strings.Contains(out, want) {
+					t.Errorf("compact listing missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanner ScannerConfig
+		want    bool
+	}{
+		{"matching languages", ScannerConfig{Name: "gosec", Languages: []string{"Go"}}, false},
+		{"wrong language", ScannerConfig{Name: "gosec", Languages: []string{"python"}}, true},
+		{"universal parser restricted in config", ScannerConfig{Name: "trufflehog", Languages: []string{"go"}}, true},
+		{"conditional languages count", ScannerConfig{Name: "kubescape", Languages: []string{"kubernetes"}, LanguagesConditional: []string{"helm"}}, false},
+		{"parser without declared languages", ScannerConfig{Name: "grype", Languages: []string{"anything"}}, false},
+		{"unknown scanner", ScannerConfig{Name: "custom-tool", Languages: []string{"go"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := languageMismatch(tt.scanner); got != tt.want {
+				t.Errorf("languageMismatch(%s %v) = %v, want %v", tt.scanner.Name, tt.scanner.Languages, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("shipped scanners.yaml matches the parsers", func(t *testing.T) {
+		config, err := loadConfig("../scanners.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, scanner := range config.Scanners {
+			if supported, mismatch := languageMismatch(scanner); mismatch {
+				t.Errorf("%s languages = %v, parser supports %v", scanner.Name, configuredLanguages(scanner), supported)
+			}
+		}
+	})
+}
+
+func TestFormatLanguagesLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		detected *DetectedLanguages
+		want     string
+	}{
+		{
+			name:     "nil",
+			detected: nil,
+			want:     "",
+		},
+		{
+			name:     "no languages",
+			detected: &DetectedLanguages{Languages: []string{}, Source: "filesystem"},
+			want:     "",
+		},
+		{
+			name: "GitHub API sorted by share",
+			detected: &DetectedLanguages{
+				Languages:  []string{"python", "go"},
+				FileCounts: map[string]int{"go": 72, "python": 28},
+				Source:     "github-api",
+			},
+			want: "go (72%), python (28%) [via GitHub API]",
+		},
+		{
+			name: "filesystem with tiny fraction",
+			detected: &DetectedLanguages{
+				Languages:  []string{"go", "shell"},
+				FileCounts: map[string]int{"go": 995, "shell": 5},
+				Source:     "filesystem",
+			},
+			want: "go (100%), shell (<1%) [via filesystem]",
+		},
+		{
+			name: "language without count shown by name",
+			detected: &DetectedLanguages{
+				Languages:  []string{"go", "rust"},
+				FileCounts: map[string]int{"go": 3},
+				Source:     "filesystem",
+			},
+			want: "go (100%), rust [via filesystem]",
+		},
+		{
+			name: "ignored languages listed last",
+			detected: &DetectedLanguages{
+				Languages:  []string{"go"},
+				FileCounts: map[string]int{"go": 195, "ruby": 1, "shell": 4},
+				Ignored:    []string{"ruby", "shell"},
+				Source:     "filesystem",
+			},
+			want: "go (98%), ignored: shell (2%), ruby (<1%) [via filesystem]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatLanguagesLine(tt.detected); got != tt.want {
+				t.Errorf("formatLanguagesLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// captureStdout runs fn and returns everything it wrote to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestPrintSummary_PlainTheme(t *testing.T) {
+	orig := theme
+	theme = parsers.PlainTheme
+	t.Cleanup(func() { theme = orig })
+
+	dir := t.TempDir()
+	writeOutput := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	grypePath := writeOutput("grype.json", `{"matches": [
+		{"vulnerability": {"id": "CVE-1", "severity": "Critical"}},
+		{"vulnerability": {"id": "CVE-2", "severity": "High"}},
+		{"vulnerability": {"id": "CVE-3", "severity": "Medium"}},
+		{"vulnerability": {"id": "CVE-4", "severity": "Low"}},
+		{"vulnerability": {"id": "CVE-5", "severity": "Negligible"}}
+	]}`)
+	trufflehogPath := writeOutput("trufflehog.json", `{"DetectorName": "AWS", "Verified": true}
+{"DetectorName": "Slack", "Verified": false}`)
+	govulncheckPath := writeOutput("govulncheck.json", `{"osv": {"id": "GO-2024-0001", "aliases": ["CVE-1"]}}
+{"finding": {"osv": "GO-2024-0001", "trace": [{"position": {"filename": "main.go", "line": 1}}]}}`)
+	scorecardPath := writeOutput("scorecard.json", `{"score": 6.0, "checks": [{"name": "Maintained", "score": 10}, {"name": "SAST", "score": 0}]}`)
+	gosecPath := writeOutput("gosec.json", `{"Issues": []}`)
+
+	contexts := []RepoScanContext{{
+		RepoURL:   "https://github.com/acme/widget",
+		Languages: &DetectedLanguages{Languages: []string{"go", "python"}, FileCounts: map[string]int{"go": 10, "python": 1}},
+		Scanners: []ScannerConfig{
+			{Name: "grype"},
+			{Name: "gosec", Languages: []string{"go"}},
+			{Name: "govulncheck", Languages: []string{"go"}},
+			{Name: "trufflehog"},
+			{Name: "scorecard"},
+			{Name: "binary-detector"},
+		},
+		Results: []ScanResult{
+			{Scanner: "grype", Success: true, OutputPath: grypePath},
+			{Scanner: "gosec", Success: true, OutputPath: gosecPath},
+			{Scanner: "govulncheck", Success: true, OutputPath: govulncheckPath},
+			{Scanner: "trufflehog", Success: true, OutputPath: trufflehogPath},
+			{Scanner: "scorecard", Success: true, OutputPath: scorecardPath},
+			{Scanner: "binary-detector", Success: false, Error: errors.New("walk failed")},
+			{Scanner: "osv-scanner", Success: true, IsSarif: true, OutputPath: "/tmp/osv.sarif"},
+			{Scanner: "custom-tool", Success: true, OutputPath: gosecPath},
+		},
+		SBOMPath: "/tmp/widget.cdx.json",
+	}}
+
+	out := captureStdout(t, func() { printSummary(contexts) })
+
+	for i := 0; i < len(out); i++ {
+		if out[i] >= 0x80 {
+			t.Fatalf("plain summary contains non-ASCII byte at %d: %q", i, out[max(0, i-40):min(len(out), i+40)])
+		}
+	}
+	for _, want := range []string{"[OK]", "[x]", "[C] Critical: 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plain summary missing %q", want)
+		}
+	}
+}
+
+func TestPrintScannerSummary_CustomSeverityLabels(t *testing.T) {
+	origTheme, origLabels := theme, severityLabels
+	theme = parsers.PlainTheme.WithSeverityIcons(map[string]string{"critical": "[B]"})
+	severityLabels = withSeverityLabels(map[string]string{"critical": "Blocker", "high": "Critical", "medium": "Major"})
+	t.Cleanup(func() { theme, severityLabels = origTheme, origLabels })
+
+	parser, _ := parsers.Get("grype")
+	summary := parsers.FindingSummary{Critical: 2, High: 1, Medium: 3, Low: 1, Total: 7}
+	out := captureStdout(t, func() { printScannerSummary(parser, summary, "") })
+
+	for _, want := range []string{"[B] Blocker: 2", "[H] Critical: 1", "[M] Major: 3", "[L] Low: 1", "Total: 7 findings"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary %q missing %q", out, want)
+		}
+	}
+	if strings.Contains(out, "Medium") {
+		t.Errorf("summary %q still shows the default Medium label", out)
+	}
+}
+
+func TestFormatTrend(t *testing.T) {
+	origTheme, origPrev := theme, previousSummaries
+	t.Cleanup(func() { theme, previousSummaries = origTheme, origPrev })
+	theme = parsers.EmojiTheme
+
+	repo := "https://github.com/org/repo"
+	previousSummaries = map[string]parsers.FindingSummary{
+		trendKey(repo, "", "grype"): {Critical: 2, Total: 5},
+	}
+	result := ScanResult{Scanner: "grype", Repository: repo}
+
+	tests := []struct {
+		name     string
+		critical int
+		result   ScanResult
+		want     string
+	}{
+		{name: "increase", critical: 5, result: result, want: ColorRed + "↑3" + ColorReset},
+		{name: "decrease", critical: 0, result: result, want: ColorGreen + "↓2" + ColorReset},
+		{name: "unchanged", critical: 2, result: result, want: ColorDim + "=" + ColorReset},
+		{name: "no previous result", critical: 4, result: ScanResult{Scanner: "gosec", Repository: repo}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatTrend(parsers.FindingSummary{Critical: tt.critical}, tt.result)
+			if got != tt.want {
+				t.Errorf("formatTrend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no previous run", func(t *testing.T) {
+		previousSummaries = nil
+		if got := formatTrend(parsers.FindingSummary{Critical: 1}, result); got != "" {
+			t.Errorf("formatTrend() = %q, want empty", got)
+		}
+	})
+}
+
+func TestSummaryShowsCoverageGap(t *testing.T) {
+	contexts := []RepoScanContext{
+		{RepoURL: "https://github.com/org/docs", Languages: &DetectedLanguages{}, CoverageGap: gapNoCompatibleScanners},
+		{RepoURL: "https://github.com/org/widget", Languages: &DetectedLanguages{}},
+	}
+
+	tests := []struct {
+		name    string
+		require bool
+		want    string
+	}{
+		{"warning by default", false, "No scanners ran" + ColorReset + " - " + gapNoCompatibleScanners},
+		{"failure with require_coverage", true, "FAILED" + ColorReset + " - " + gapNoCompatibleScanners},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := requireCoverage
+			requireCoverage = tt.require
+			t.Cleanup(func() { requireCoverage = orig })
+
+			out := captureStdout(t, func() { printSummary(contexts) })
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("summary missing %q:\n%s", tt.want, out)
+			}
+			if n := strings.Count(out, "No scanners ran"); n != 1 {
+				t.Errorf("summary reports %d uncovered targets, want 1", n)
+			}
+		})
+	}
+
+	if got := uncoveredTargets(contexts); !reflect.DeepEqual(got, []string{"https://github.com/org/docs"}) {
+		t.Errorf("uncoveredTargets() = %v, want only org/docs", got)
+	}
+	contexts = append(contexts, RepoScanContext{RepoURL: "https://github.com/org/gone", CloneError: errors.New("repository not found")})
+	if got := uncoveredTargets(contexts); !reflect.DeepEqual(got, []string{"https://github.com/org/docs", "https://github.com/org/gone"}) {
+		t.Errorf("uncoveredTargets() = %v, want org/docs and the clone failure", got)
+	}
+	if got := cloneFailures(contexts); got != 1 {
+		t.Errorf("cloneFailures() = %d, want 1", got)
+	}
+}
+
+func TestSummaryShowsSBOMFailure(t *testing.T) {
+	contexts := []RepoScanContext{{
+		RepoURL:   "https://github.com/org/widget",
+		Languages: &DetectedLanguages{},
+		SBOMError: errors.New("syft scan failed: exit status 1\n[0000] ERROR could not determine source"),
+	}}
+
+	out := captureStdout(t, func() { printSummary(contexts) })
+	if !strings.Contains(out, "failed (syft scan failed: exit status 1)") {
+		t.Errorf("summary does not show the SBOM failure:\n%s", out)
+	}
+	if strings.Contains(out, "could not determine source") {
+		t.Errorf("summary shows syft output beyond the first line:\n%s", out)
+	}
+
+	report := buildRunReport(contexts)
+	if len(report.SBOMs) != 1 || report.SBOMs[0].Error != "syft scan failed: exit status 1" {
+		t.Errorf("run report SBOMs = %+v, want the failure reason", report.SBOMs)
+	}
+}
+
+func TestSummaryHeuristicCounts(t *testing.T) {
+	dir := t.TempDir()
+	outputs := map[string]string{
+		"newtool.json": `{"results": [{"severity": "critical"}, {"severity": "low"}]}`,
+		"quiet.json":   `{"files_scanned": 3}`,
+	}
+	for name, content := range outputs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	contexts := []RepoScanContext{{
+		RepoURL: "https://github.com/org/widget",
+		Results: []ScanResult{
+			{Scanner: "newtool", Success: true, OutputPath: filepath.Join(dir, "newtool.json")},
+			{Scanner: "quiet", Success: true, OutputPath: filepath.Join(dir, "quiet.json")},
+		},
+	}}
+
+	out := captureStdout(t, func() { printSummary(contexts) })
+	for _, want := range []string{"newtool" + ColorReset + " (" + ColorDim + "heuristic", "Total: 2 findings", "counted from severity fields", "quiet" + ColorReset + " (" + ColorDim + "Unknown"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+
+	report := buildRunReport(contexts)
+	if got := report.Results[0]; !got.Heuristic || got.Summary.Critical != 1 || got.Summary.Total != 2 {
+		t.Errorf("newtool report entry = %+v, want heuristic counts", got)
+	}
+	if got := report.Results[1]; got.Heuristic || got.Summary.Total != 0 {
+		t.Errorf("quiet report entry = %+v, want no counts", got)
+	}
+}
+
+func TestSummaryGroupsByCategory(t *testing.T) {
+	failed := func(scanner string) ScanResult {
+		return ScanResult{Scanner: scanner, Error: errors.New("exit status 1")}
+	}
+	contexts := []RepoScanContext{{
+		RepoURL: "https://github.com/org/widget",
+		Results: []ScanResult{failed("trufflehog"), failed("grype"), failed("newtool"), failed("gosec"), failed("kubescape"), failed("osv-scanner")},
+	}}
+
+	out := captureStdout(t, func() { printSummary(contexts) })
+
+	// Each category header appears once, followed by its scanners in run order
+	header := func(category string) string { return "  " + ColorBold + ColorCyan + category + ColorReset + "\n" }
+	order := []string{
+		header("SCA"), "grype" + ColorReset + ": ", "osv-scanner" + ColorReset + ": ",
+		header("SAST"), "gosec" + ColorReset + ": ",
+		header("Secrets"), "trufflehog" + ColorReset + ": ",
+		header("IaC"), "kubescape" + ColorReset + ": ",
+		header(otherCategory), "newtool" + ColorReset + ": ",
+	}
+	pos := 0
+	for _, want := range order {
+		i := strings.Index(out[pos:], want)
+		if i < 0 {
+			t.Fatalf("summary missing %q after position %d:\n%s", want, pos, out)
+		}
+		pos += i + len(want)
+	}
+	for _, category := range []string{"SCA", "SAST", "Secrets", "IaC", otherCategory} {
+		if n := strings.Count(out, header(category)); n != 1 {
+			t.Errorf("%s header shown %d times, want 1", category, n)
+		}
+	}
+}
+
+func TestSummaryCloneFailures(t *testing.T) {
+	contexts := []RepoScanContext{
+		{RepoURL: "https://github.com/org/api", Results: []ScanResult{{Scanner: "gosec", Success: true, OutputPath: filepath.Join(t.TempDir(), "missing.json")}}},
+		{RepoURL: "https://github.com/org/gone", CloneError: errors.New("git clone failed: repository not found")},
+		{RepoURL: "https://github.com/org/private", CloneError: errors.New("authentication required")},
+	}
+
+	out := captureStdout(t, func() { printSummary(contexts) })
+	section := strings.Index(out, "FAILED TO SCAN (2)")
+	if section < 0 {
+		t.Fatalf("summary has no FAILED TO SCAN section:\n%s", out)
+	}
+	if strings.Contains(out[:section], "org/gone") {
+		t.Errorf("failed repo shown as a scanned repo:\n%s", out)
+	}
+	for _, want := range []string{
+		"https://github.com/org/gone" + ColorReset + ": " + ColorRed + "clone failed" + ColorReset + " - git clone failed: repository not found",
+		"https://github.com/org/private" + ColorReset + ": " + ColorRed + "clone failed" + ColorReset + " - authentication required",
+	} {
+		if !strings.Contains(out[section:], want) {
+			t.Errorf("FAILED TO SCAN section missing %q:\n%s", want, out[section:])
+		}
+	}
+	if !strings.Contains(out, "Failed:         "+ColorRed+ColorBold+"2") {
+		t.Errorf("clone failures not counted as failed scans:\n%s", out)
+	}
+
+	report := buildRunReport(contexts)
+	want := []FailedRepo{
+		{URL: "https://github.com/org/gone", Error: "git clone failed: repository not found"},
+		{URL: "https://github.com/org/private", Error: "authentication required"},
+	}
+	if len(report.FailedRepos) != len(want) || report.FailedRepos[0] != want[0] || report.FailedRepos[1] != want[1] {
+		t.Errorf("report.FailedRepos = %+v, want %+v", report.FailedRepos, want)
+	}
+	if len(report.Results) != 1 {
+		t.Errorf("report has %d results, want only the scanned repo's", len(report.Results))
+	}
+}
+
+func TestListRepositories(t *testing.T) {
+	config := &Config{
+		Global: GlobalConfig{ScannerBundles: map[string][]string{"go": {"gosec", "govulncheck", "retired"}}},
+		Scanners: []ScannerConfig{
+			{Name: "gosec", Enabled: true},
+			{Name: "govulncheck", Enabled: true},
+			{Name: "grype", Enabled: true},
+			{Name: "retired", Enabled: false},
+		},
+	}
+	repos := []RepositoryConfig{
+		{URL: "https://github.com/org/default-branch"},
+		{URL: "https://github.com/org/tagged", Version: "v1.2.0", Commit: "abc1234"},
+		{URL: "https://github.com/org/pinned", Commit: "abc1234", Scanners: []string{"grype"}},
+		{URL: "https://github.com/org/bundled", Branch: "develop", Bundle: "go"},
+		{URL: "https://github.com/org/paused", Branch: "main", Disabled: true},
+	}
+
+	var buf bytes.Buffer
+	listRepositories(repos, config, nil, &buf)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	tests := []struct {
+		line int
+		want []string
+	}{
+		{1, []string{"org/default-branch", "branch", "main", " 3 ", " 0"}},
+		{2, []string{"org/tagged", "tag", "v1.2.0", " 3 ", " 0"}},
+		{3, []string{"org/pinned", "commit", "abc1234", " 1 ", " 1"}},
+		{4, []string{"org/bundled", "branch", "develop", " 2 ", " 3"}},
+		{5, []string{"org/paused", "[disabled]"}},
+	}
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			if !strings.Contains(lines[tt.line], want) {
+				t.Errorf("line %d = %q, want it to contain %q", tt.line, lines[tt.line], want)
+			}
+		}
+	}
+	if strings.Contains(lines[4], "[disabled]") {
+		t.Errorf("enabled repo annotated as disabled: %q", lines[4])
+	}
+	if footer := lines[7]; footer != "5 repositories (1 disabled)" {
+		t.Errorf("footer = %q, want %q", footer, "5 repositories (1 disabled)")
+	}
+	if estimate := lines[8]; !strings.HasPrefix(estimate, "Estimated ~0s (4 repos × 2.2 avg scanners)") {
+		t.Errorf("estimate = %q, want 4 enabled repos with 9 scanners between them", estimate)
+	}
+
+	// With --include-disabled the disabled repo would be scanned
+	config.Global.IncludeDisabled = true
+	buf.Reset()
+	listRepositories(repos, config, nil, &buf)
+	if strings.Contains(buf.String(), "[disabled]") {
+		t.Errorf("--include-disabled listing still marks repos disabled:\n%s", buf.String())
+	}
+}
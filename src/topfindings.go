@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"allscan/parsers"
+)
+
+// topFindingsLimit is how many Critical/High findings the summary lists per
+// repository; set from global.top_findings or --top-findings in main
+// (0 = section not shown)
+var topFindingsLimit int
+
+// topFinding is a finding listed in the summary with the scanner that
+// reported it
+type topFinding struct {
+	Scanner string
+	parsers.Finding
+}
+
+// topFindingSeverities ranks the severities listed in the section, most
+// severe first
+var topFindingSeverities = map[string]int{"critical": 0, "high": 1}
+
+// topFindings returns up to limit Critical and High findings of the
+// repository's successful results, critical first, and how many there are in
+// all. Only scanners whose parser lists individual findings
+// (parsers.DetailedParser) contribute; ok is false when none of them ran.
+func topFindings(ctx RepoScanContext, limit int) (top []topFinding, total int, ok bool) {
+	for _, result := range ctx.Results {
+		if !result.Success || result.IsSarif {
+			continue
+		}
+		parser, found := parsers.Get(result.Scanner)
+		if !found {
+			continue
+		}
+		detailed, isDetailed := parser.(parsers.DetailedParser)
+		if !isDetailed {
+			continue
+		}
+		data, err := os.ReadFile(result.OutputPath)
+		if err != nil {
+			continue
+		}
+		findings, err := parsers.ListFindings(detailed, data, result.ScanRoot)
+		if err != nil {
+			continue
+		}
+		ok = true
+		for _, f := range findings {
+			if _, listed := topFindingSeverities[f.Severity]; listed {
+				top = append(top, topFinding{Scanner: result.Scanner, Finding: f})
+			}
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		a, b := top[i], top[j]
+		if ra, rb := topFindingSeverities[a.Severity], topFindingSeverities[b.Severity]; ra != rb {
+			return ra < rb
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.ID < b.ID
+	})
+	total = len(top)
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top, total, ok
+}
+
+// findingLocation formats a finding's file:line, the file alone when the
+// line is unknown, or "" for findings without a file
+func findingLocation(f parsers.Finding) string {
+	if f.File == "" || f.Line <= 0 {
+		return f.File
+	}
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// printTopFindings lists the repository's worst findings with their rule ID,
+// location and message. Nothing is printed when the section is off, no
+// scanner reported individual findings, or there are no Critical/High ones.
+func printTopFindings(ctx RepoScanContext, limit int) {
+	if limit <= 0 {
+		return
+	}
+	top, total, ok := topFindings(ctx, limit)
+	if !ok || total == 0 {
+		return
+	}
+
+	heading := fmt.Sprintf("Top %s/%s findings", severityLabels["critical"], severityLabels["high"])
+	if total > len(top) {
+		heading += fmt.Sprintf(" (%d of %d)", len(top), total)
+	}
+	fmt.Printf("\n  %s%s%s:\n", ColorBold, heading, ColorReset)
+	for _, f := range top {
+		color, icon := ColorRed+ColorBold, theme.Critical
+		if f.Severity == "high" {
+			color, icon = ColorRed, theme.High
+		}
+		parts := []string{fmt.Sprintf("%s%s %s%s", color, icon, f.ID, ColorReset)}
+		if location := findingLocation(f.Finding); location != "" {
+			parts = append(parts, location)
+		}
+		if f.Title != "" {
+			parts = append(parts, f.Title)
+		}
+		fmt.Printf("     %s %s(%s)%s\n", strings.Join(parts, "  "), ColorDim, f.Scanner, ColorReset)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTopFindings(t *testing.T) {
+	dir := t.TempDir()
+	outputs := map[string]string{
+		"grype.json": `{"matches": [
+			{"vulnerability": {"id": "CVE-2024-0002", "severity": "High"}, "artifact": {"name": "yaml", "version": "2.0", "locations": [{"path": "/go.sum"}]}},
+			{"vulnerability": {"id": "CVE-2024-0001", "severity": "Critical"}, "artifact": {"name": "net", "version": "1.0", "locations": [{"path": "/go.sum"}]}},
+			{"vulnerability": {"id": "CVE-2024-0003", "severity": "Medium"}, "artifact": {"name": "text", "version": "0.1"}}
+		]}`,
+		"gosec.json": `{"Issues": [
+			{"severity": "HIGH", "rule_id": "G402", "details": "TLS InsecureSkipVerify set true.", "file": "main.go", "line": "12"},
+			{"severity": "HIGH", "rule_id": "G101", "details": "Potential hardcoded credentials", "file": "/work/org/repo/config.go", "line": "40-42"},
+			{"severity": "LOW", "rule_id": "G104", "details": "Errors unhandled.", "file": "main.go", "line": "7"}
+		]}`,
+		"trufflehog.json": `{"SourceMetadata": {}, "Verified": true}`,
+	}
+	for name, content := range outputs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result := func(scanner string) ScanResult {
+		return ScanResult{Scanner: scanner, OutputPath: filepath.Join(dir, scanner+".json"), Success: true, ScanRoot: "/work/org/repo"}
+	}
+
+	tests := []struct {
+		name      string
+		results   []ScanResult
+		limit     int
+		wantIDs   []string
+		wantTotal int
+		wantOK    bool
+	}{
+		{
+			name:      "critical first, then by location",
+			results:   []ScanResult{result("gosec"), result("grype")},
+			limit:     10,
+			wantIDs:   []string{"CVE-2024-0001", "G101", "CVE-2024-0002", "G402"},
+			wantTotal: 4,
+			wantOK:    true,
+		},
+		{
+			name:      "limited to N",
+			results:   []ScanResult{result("gosec"), result("grype")},
+			limit:     2,
+			wantIDs:   []string{"CVE-2024-0001", "G101"},
+			wantTotal: 4,
+			wantOK:    true,
+		},
+		{
+			name:    "failed and SARIF results are skipped",
+			results: []ScanResult{{Scanner: "grype", OutputPath: filepath.Join(dir, "grype.json")}, {Scanner: "gosec", OutputPath: filepath.Join(dir, "gosec.json"), Success: true, IsSarif: true}},
+			limit:   10,
+		},
+		{
+			name:    "no detailed parser",
+			results: []ScanResult{result("trufflehog")},
+			limit:   10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, total, ok := topFindings(RepoScanContext{Results: tt.results}, tt.limit)
+			if ok != tt.wantOK || total != tt.wantTotal {
+				t.Errorf("topFindings() total = %d, ok = %v; want %d, %v", total, ok, tt.wantTotal, tt.wantOK)
+			}
+			var ids []string
+			for _, f := range top {
+				ids = append(ids, f.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("topFindings() IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	t.Run("summary section", func(t *testing.T) {
+		ctx := RepoScanContext{RepoURL: "https://github.com/org/repo", Results: []ScanResult{result("gosec"), result("grype")}}
+		out := captureStdout(t, func() { printTopFindings(ctx, 3) })
+		for _, want := range []string{"Top Critical/High findings (3 of 4)", "G101" + ColorReset + "  config.go:40  Potential hardcoded credentials", "(gosec)"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("summary section missing %q:\n%s", want, out)
+			}
+		}
+		if out := captureStdout(t, func() { printTopFindings(ctx, 0) }); out != "" {
+			t.Errorf("section shown with top_findings 0:\n%s", out)
+		}
+	})
+}
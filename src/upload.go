@@ -2,35 +2,154 @@ package main
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"allscan/parsers"
 )
 
-// uploadResults uploads all successful scan results to DefectDojo.
-// If idx is non-nil, SCA scanner uploads are tagged with reachability information.
+// defaultTokenEnvVar holds the DefectDojo API token for upload_endpoint and
+// for upload targets without env_token_var
+const defaultTokenEnvVar = "VULN_MGMT_API_TOKEN"
+
+// tokenEnvVar returns the env var holding the target's API token
+func (t UploadTarget) tokenEnvVar() string {
+	if t.EnvTokenVar != "" {
+		return t.EnvTokenVar
+	}
+	return defaultTokenEnvVar
+}
+
+// uploadTargets returns the DefectDojo instances to upload to: upload_targets
+// when set, otherwise upload_endpoint for every repo (none if neither is set)
+func uploadTargets(global GlobalConfig) []UploadTarget {
+	if len(global.UploadTargets) > 0 {
+		return global.UploadTargets
+	}
+	if global.UploadEndpoint != "" {
+		return []UploadTarget{{Endpoint: global.UploadEndpoint}}
+	}
+	return nil
+}
+
+// selectUploadTargets returns the targets whose repo_filter matches repoURL
+func selectUploadTargets(targets []UploadTarget, repoURL string) []UploadTarget {
+	var selected []UploadTarget
+	for _, target := range targets {
+		if target.matchesRepo(repoURL) {
+			selected = append(selected, target)
+		}
+	}
+	return selected
+}
+
+// matchesRepo reports whether the target takes results for repoURL. A target
+// without repo_filter takes every repo.
+func (t UploadTarget) matchesRepo(repoURL string) bool {
+	if len(t.RepoFilter) == 0 {
+		return true
+	}
+	for _, pattern := range t.RepoFilter {
+		if repoFilterMatches(pattern, repoURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoFilterMatches reports whether a repo_filter glob matches a repo URL.
+// Both are compared without their scheme, after normalizeGitURL, so
+// "github.com/org/*" matches https://github.com/org/repo.git and
+// git@github.com:org/repo alike. As in path.Match, * does not cross a /.
+func repoFilterMatches(pattern, repoURL string) bool {
+	matched, _ := path.Match(repoFilterPattern(pattern), strings.TrimPrefix(normalizeGitURL(repoURL), "https://"))
+	return matched
+}
+
+// repoFilterPattern strips the scheme from a repo_filter pattern
+func repoFilterPattern(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "https://")
+	return strings.TrimPrefix(pattern, "http://")
+}
+
+// missingUploadTokens returns the unset token env vars of the upload targets
+// that at least one configured repository is uploaded to
+func missingUploadTokens(config *Config) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, repo := range config.Repositories {
+		for _, target := range selectUploadTargets(uploadTargets(config.Global), repo.URL) {
+			name := target.tokenEnvVar()
+			if !seen[name] && os.Getenv(name) == "" {
+				missing = append(missing, name)
+			}
+			seen[name] = true
+		}
+	}
+	return missing
+}
+
+// uploadResults uploads all successful scan results to DefectDojo: to each
+// upload target, the results of the repos its repo_filter selects. A failure
+// on one target doesn't stop uploads to the others. If idx is non-nil, SCA
+// scanner uploads are tagged with reachability information. With
+// global.dojo.upload_state set, uploads confirmed by an earlier run are skipped.
 func uploadResults(config *Config, results []ScanResult, idx parsers.ReachabilityIndex) {
-	log.Printf("\n📤 Uploading results to %s", config.Global.UploadEndpoint)
+	transport, err := newTLSTransport(&config.Global)
+	if err != nil {
+		log.Printf("❌ Failed to configure upload TLS: %v", err)
+		return
+	}
+
+	state, err := loadUploadState(config.Global.Dojo.UploadState)
+	if err != nil {
+		log.Printf("⚠️  Failed to load upload state, uploading everything: %v", err)
+		state = nil
+	}
+
+	for _, target := range uploadTargets(config.Global) {
+		var selected []ScanResult
+		for _, result := range results {
+			if target.matchesRepo(result.Repository) {
+				selected = append(selected, result)
+			}
+		}
+		if len(selected) == 0 {
+			log.Printf("\n⏭️  No results for %s (no repo matches its repo_filter)", target.Endpoint)
+			continue
+		}
+		uploadToTarget(config, target, selected, idx, transport, state)
+	}
+}
+
+// uploadToTarget uploads scan results (and SBOMs, if enabled) to one
+// DefectDojo instance
+func uploadToTarget(config *Config, target UploadTarget, results []ScanResult, idx parsers.ReachabilityIndex, transport http.RoundTripper, state *UploadState) {
+	log.Printf("\n📤 Uploading results to %s", target.Endpoint)
 
 	// Get authorization token from environment
-	authToken := os.Getenv("VULN_MGMT_API_TOKEN")
+	authToken := os.Getenv(target.tokenEnvVar())
 	if authToken == "" {
-		log.Printf("⚠️  VULN_MGMT_API_TOKEN not set, skipping upload")
+		log.Printf("⚠️  %s not set, skipping upload", target.tokenEnvVar())
 		return
 	}
 
-	successCount := 0
-	failCount := 0
-
+	var jobs []uploadJob
 	for _, result := range results {
 		if !result.Success {
 			log.Printf("  ⏭️  Skipping %s (scan failed)", result.OutputPath)
@@ -48,17 +167,145 @@ func uploadResults(config *Config, results []ScanResult, idx parsers.Reachabilit
 		if idx != nil && (result.Scanner == "grype" || result.Scanner == "osv-scanner") {
 			tags = computeReachabilityTags(result, idx)
 		}
+		jobs = append(jobs, uploadJob{result: result, tags: tags})
+	}
+
+	dojo := config.Global.Dojo
+	successCount, failCount := runUploads(jobs, dojo.UploadConcurrency, newUploadLimiter(dojo.UploadRateLimit), func(job uploadJob) error {
+		return uploadSingleResult(config, job.result, target.Endpoint, authToken, job.tags, transport, state)
+	})
+
+	log.Printf("\n📊 Upload Summary: %d successful, %d failed", successCount, failCount)
 
-		if err := uploadSingleResult(config, result, authToken, tags); err != nil {
-			log.Printf("  ❌ Failed to upload %s: %v", result.OutputPath, err)
-			failCount++
-		} else {
-			log.Printf("  ✅ Uploaded %s", result.OutputPath)
-			successCount++
+	if config.Global.SBOMUpload {
+		uploadSBOMs(config, results, target.Endpoint, authToken, transport, state)
+	}
+}
+
+// sbomDojoScanType is the DefectDojo parser for CycloneDX SBOMs
+const sbomDojoScanType = "CycloneDX Scan"
+
+// uploadSBOMs uploads each scanned target's SBOM once, after the scanner
+// results, and logs its own summary. SBOMs are imported as "CycloneDX Scan"
+// tests in a <product>-sbom engagement, so DefectDojo lists the components
+// and any vulnerabilities embedded in the SBOM.
+func uploadSBOMs(config *Config, results []ScanResult, endpoint, authToken string, transport http.RoundTripper, state *UploadState) {
+	var jobs []uploadJob
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if result.SBOMPath == "" || seen[result.SBOMPath] {
+			continue
 		}
+		seen[result.SBOMPath] = true
+		jobs = append(jobs, uploadJob{result: sbomUploadResult(result)})
+	}
+	if len(jobs) == 0 {
+		return
 	}
 
-	log.Printf("\n📊 Upload Summary: %d successful, %d failed", successCount, failCount)
+	log.Printf("\n📋 Uploading %d SBOM(s)", len(jobs))
+	dojo := config.Global.Dojo
+	successCount, failCount := runUploads(jobs, dojo.UploadConcurrency, newUploadLimiter(dojo.UploadRateLimit), func(job uploadJob) error {
+		return uploadSingleResult(config, job.result, endpoint, authToken, nil, transport, state)
+	})
+	log.Printf("📊 SBOM Upload Summary: %d successful, %d failed", successCount, failCount)
+}
+
+// sbomUploadResult describes the SBOM of a scan result's target as a result of
+// its own, so it shares the product, version and sub-project naming of the
+// scanner uploads
+func sbomUploadResult(result ScanResult) ScanResult {
+	return ScanResult{
+		Scanner:      "sbom",
+		Repository:   result.Repository,
+		OutputPath:   result.SBOMPath,
+		Success:      true,
+		DojoScanType: sbomDojoScanType,
+		CommitHash:   result.CommitHash,
+		BranchTag:    result.BranchTag,
+		Subproject:   result.Subproject,
+		SBOMPath:     result.SBOMPath,
+	}
+}
+
+// uploadJob is one scan result queued for upload with its reachability tags
+type uploadJob struct {
+	result ScanResult
+	tags   []string
+}
+
+// runUploads uploads jobs on up to concurrency workers (at least one), starting
+// each upload only when the limiter allows, and returns the success and
+// failure counts
+func runUploads(jobs []uploadJob, concurrency int, limiter *uploadLimiter, upload func(uploadJob) error) (successCount, failCount int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	queue := make(chan uploadJob)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				limiter.wait()
+				err := upload(job)
+
+				mu.Lock()
+				if err != nil {
+					log.Printf("  ❌ Failed to upload %s: %v", job.result.OutputPath, err)
+					failCount++
+				} else {
+					log.Printf("  ✅ Uploaded %s", job.result.OutputPath)
+					successCount++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	return successCount, failCount
+}
+
+// uploadLimiter spaces upload starts evenly so that no more than the
+// configured number begin per second, shared by all upload workers.
+// A nil limiter never waits.
+type uploadLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // earliest start time of the next upload
+}
+
+// newUploadLimiter returns a limiter for perSecond uploads per second, or nil
+// (unlimited) when perSecond is not positive
+func newUploadLimiter(perSecond float64) *uploadLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &uploadLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait blocks until the caller may start its upload
+func (l *uploadLimiter) wait() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	start := time.Now()
+	if l.next.After(start) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(start))
 }
 
 // computeReachabilityTags reads an SCA scanner's output and returns DefectDojo tags
@@ -92,9 +339,79 @@ func computeReachabilityTags(result ScanResult, idx parsers.ReachabilityIndex) [
 	return tags
 }
 
-// uploadSingleResult uploads a single scan result to DefectDojo.
-// Optional tags are added to the upload form fields.
-func uploadSingleResult(config *Config, result ScanResult, authToken string, tags []string) error {
+// newTLSTransport builds the HTTP transport used for uploads. A custom CA
+// certificate (tls_ca_cert) is added to the system pool, a client certificate
+// (tls_client_cert and tls_client_key) is presented to mTLS endpoints, and
+// tls_skip_verify disables certificate verification entirely for self-signed
+// test instances.
+func newTLSTransport(config *GlobalConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.TLSCACert != "" {
+		pool, err := loadCAPool(config.TLSCACert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.TLSClientCert != "" {
+		cert, err := loadClientCert(config.TLSClientCert, config.TLSClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.TLSSkipVerify {
+		log.Printf("⚠️  tls_skip_verify is enabled: upload endpoint certificates will NOT be verified")
+		tlsConfig.InsecureSkipVerify = true // #nosec G402 -- explicitly opted in via tls_skip_verify
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// loadCAPool returns the system certificate pool with the PEM certificates in
+// caFile added
+func loadCAPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(filepath.Clean(caFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid certificates found in %s", caFile)
+	}
+	return pool, nil
+}
+
+// loadClientCert loads a PEM client certificate and private key for mTLS
+func loadClientCert(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Clean(certFile), filepath.Clean(keyFile))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("loading client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// validateClientCert rejects a tls_client_cert without tls_client_key and
+// vice versa
+func validateClientCert(certFile, keyFile string) error {
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("tls_client_cert and tls_client_key must be set together")
+	}
+	return nil
+}
+
+// uploadSingleResult uploads a single scan result to the DefectDojo endpoint.
+// Optional tags are added to the upload form fields. Uploads already
+// confirmed in state are skipped, and successful ones are recorded there.
+func uploadSingleResult(config *Config, result ScanResult, endpoint, authToken string, tags []string, transport http.RoundTripper, state *UploadState) error {
 	// Open the scan result file
 	file, err := os.Open(result.OutputPath)
 	if err != nil {
@@ -103,7 +420,7 @@ func uploadSingleResult(config *Config, result ScanResult, authToken string, tag
 	defer file.Close()
 
 	// For NDJSON output, convert to a JSON array that DefectDojo can parse
-	var uploadReader io.Reader = file
+	var data []byte
 	if result.NDJSON {
 		converted, convertErr := ndjsonToJSONArray(file)
 		if convertErr != nil {
@@ -114,25 +431,74 @@ func uploadSingleResult(config *Config, result ScanResult, authToken string, tag
 			log.Printf("  ⏭️  Skipping %s (no findings to upload)", filepath.Base(result.OutputPath))
 			return nil
 		}
-		uploadReader = bytes.NewReader(converted)
+		data = converted
+	} else if data, err = io.ReadAll(file); err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	fields := buildUploadFields(config, result, tags)
+	key := uploadIdempotencyKey(data, fields)
+	if state.confirmed(endpoint, key) {
+		log.Printf("  ⏭️  Skipping %s (already uploaded)", filepath.Base(result.OutputPath))
+		return nil
+	}
+
+	// Build upload request using the Fluent Builder pattern
+	builder := BuildUploadRequest().
+		WithFile(bytes.NewReader(data), filepath.Base(result.OutputPath)).
+		WithAuthToken(authToken).
+		WithEndpoint(endpoint).
+		WithReimport(config.Global.Dojo.Reimport).
+		WithTransport(transport).
+		WithRetry(config.Global.Dojo.UploadRetries+1, uploadRetryDelay).
+		WithIdempotencyKey(key).
+		AddFields(fields)
+	if err := builder.Send(); err != nil {
+		return err
+	}
+	if err := state.record(endpoint, key, time.Now().UTC()); err != nil {
+		log.Printf("  ⚠️  Failed to record upload of %s: %v", filepath.Base(result.OutputPath), err)
 	}
+	return nil
+}
+
+// defaultProductTypeName is the DefectDojo product type of uploads without
+// --product-type
+const defaultProductTypeName = "Research and Development"
+
+// autoCreateContext reports whether uploads to a product type may create a
+// missing product and engagement, per dojo.auto_create_context. Product
+// types not listed there auto-create, so new repos show up without setup.
+func autoCreateContext(dojo DojoConfig, productTypeName string) bool {
+	allowed, ok := dojo.AutoCreateContext[productTypeName]
+	return !ok || allowed
+}
 
+// buildUploadFields assembles the DefectDojo form fields for a scan result.
+// product_name, engagement_name, and scan_type together identify the test that
+// a reimport updates, so they must stay stable across runs of the same scanner.
+func buildUploadFields(config *Config, result ScanResult, tags []string) map[string]string {
 	productName := extractProductName(result.Repository)
 	if config.Global.ProductOverride != "" {
 		productName = config.Global.ProductOverride
 	}
 
-	productTypeName := "Research and Development"
+	productTypeName := defaultProductTypeName
 	if config.Global.ProductTypeOverride != "" {
 		productTypeName = config.Global.ProductTypeOverride
 	}
 
+	engagementName := fmt.Sprintf("%s-%s", productName, result.Scanner)
+	if suffix := subprojectSuffix(result.Subproject); suffix != "" {
+		engagementName = fmt.Sprintf("%s-%s-%s", productName, suffix, result.Scanner)
+	}
+
 	fields := map[string]string{
 		"scan_date":           time.Now().Format("2006-01-02"),
 		"product_name":        productName,
-		"engagement_name":     fmt.Sprintf("%s-%s", productName, result.Scanner),
+		"engagement_name":     engagementName,
 		"scan_type":           result.DojoScanType,
-		"auto_create_context": "true",
+		"auto_create_context": strconv.FormatBool(autoCreateContext(config.Global.Dojo, productTypeName)),
 		"product_type_name":   productTypeName,
 		"do_not_reactivate":   "true",
 	}
@@ -151,13 +517,17 @@ func uploadSingleResult(config *Config, result ScanResult, authToken string, tag
 		fields["tags"] = strings.Join(tags, ",")
 	}
 
-	// Build upload request using the Fluent Builder pattern
-	builder := BuildUploadRequest().
-		WithFile(uploadReader, filepath.Base(result.OutputPath)).
-		WithAuthToken(authToken).
-		WithEndpoint(config.Global.UploadEndpoint).
-		AddFields(fields)
-	return builder.Send()
+	return fields
+}
+
+// reimportEndpoint rewrites a DefectDojo import-scan endpoint to the matching
+// reimport-scan endpoint. Endpoints that don't end in import-scan are returned unchanged.
+func reimportEndpoint(endpoint string) string {
+	trimmed := strings.TrimSuffix(endpoint, "/")
+	if base, ok := strings.CutSuffix(trimmed, "/import-scan"); ok {
+		return base + "/reimport-scan/"
+	}
+	return endpoint
 }
 
 // containsOSVEntries reports whether a JSON array (from ndjsonToJSONArray) contains
@@ -291,6 +661,9 @@ func extractProductName(repoURL string) string {
 // Upload Request Builder - Fluent Builder Pattern
 // ============================================================================
 
+// uploadRetryDelay is the pause between retries of a failed upload
+var uploadRetryDelay = 5 * time.Second
+
 // UploadRequestBuilder constructs multipart form requests for DefectDojo uploads
 type UploadRequestBuilder struct {
 	fields    map[string]string
@@ -298,14 +671,22 @@ type UploadRequestBuilder struct {
 	filename  string
 	authToken string
 	endpoint  string
+	reimport  bool
 	timeout   time.Duration
+	transport http.RoundTripper
+	attempts  int           // total tries, including the first
+	retryWait time.Duration // pause between tries
+	idemKey   string        // sent as the Idempotency-Key header when set
+	tls       GlobalConfig  // tls_* settings from WithRootCA and WithClientCert
+	tlsErr    error         // loading them failed; returned by Build
 }
 
 // BuildUploadRequest creates a new upload request builder with sensible defaults
 func BuildUploadRequest() *UploadRequestBuilder {
 	return &UploadRequestBuilder{
-		fields:  make(map[string]string),
-		timeout: 30 * time.Second,
+		fields:   make(map[string]string),
+		timeout:  30 * time.Second,
+		attempts: 1,
 	}
 }
 
@@ -328,12 +709,63 @@ func (b *UploadRequestBuilder) WithEndpoint(endpoint string) *UploadRequestBuild
 	return b
 }
 
+// WithReimport targets DefectDojo's reimport-scan API, which updates the existing
+// test matched by product, engagement, and scan type instead of creating a new one
+func (b *UploadRequestBuilder) WithReimport(reimport bool) *UploadRequestBuilder {
+	b.reimport = reimport
+	return b
+}
+
 // WithTimeout sets a custom timeout (default: 30s)
 func (b *UploadRequestBuilder) WithTimeout(timeout time.Duration) *UploadRequestBuilder {
 	b.timeout = timeout
 	return b
 }
 
+// WithTransport sets a custom HTTP transport (default: http.DefaultTransport)
+func (b *UploadRequestBuilder) WithTransport(transport http.RoundTripper) *UploadRequestBuilder {
+	b.transport = transport
+	return b
+}
+
+// WithRootCA trusts the PEM CA certificates in caFile, in addition to the
+// system pool, when verifying the endpoint. It replaces the transport with
+// one from newTLSTransport, as tls_ca_cert does.
+func (b *UploadRequestBuilder) WithRootCA(caFile string) *UploadRequestBuilder {
+	b.tls.TLSCACert = caFile
+	b.transport, b.tlsErr = newTLSTransport(&b.tls)
+	return b
+}
+
+// WithClientCert presents a PEM client certificate and key to endpoints that
+// require mutual TLS. It replaces the transport with one from
+// newTLSTransport, as tls_client_cert and tls_client_key do.
+func (b *UploadRequestBuilder) WithClientCert(certFile, keyFile string) *UploadRequestBuilder {
+	b.tls.TLSClientCert = certFile
+	b.tls.TLSClientKey = keyFile
+	b.transport, b.tlsErr = newTLSTransport(&b.tls)
+	return b
+}
+
+// WithRetry makes Send try up to maxAttempts times, waiting delay between
+// tries, when the upload fails with a network error or a 5xx response
+// (default: 1 attempt, no retry). 4xx responses are never retried.
+func (b *UploadRequestBuilder) WithRetry(maxAttempts int, delay time.Duration) *UploadRequestBuilder {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	b.attempts = maxAttempts
+	b.retryWait = delay
+	return b
+}
+
+// WithIdempotencyKey sends key as the Idempotency-Key header, the same on
+// every retry
+func (b *UploadRequestBuilder) WithIdempotencyKey(key string) *UploadRequestBuilder {
+	b.idemKey = key
+	return b
+}
+
 // AddFields adds multiple form fields to the request
 func (b *UploadRequestBuilder) AddFields(fields map[string]string) *UploadRequestBuilder {
 	for name, value := range fields {
@@ -344,6 +776,9 @@ func (b *UploadRequestBuilder) AddFields(fields map[string]string) *UploadReques
 
 // Build constructs the HTTP request without sending it
 func (b *UploadRequestBuilder) Build() (*http.Request, error) {
+	if b.tlsErr != nil {
+		return nil, b.tlsErr
+	}
 	if b.endpoint == "" {
 		return nil, fmt.Errorf("upload endpoint not set")
 	}
@@ -377,8 +812,13 @@ func (b *UploadRequestBuilder) Build() (*http.Request, error) {
 		return nil, fmt.Errorf("closing writer: %w", err)
 	}
 
+	endpoint := b.endpoint
+	if b.reimport {
+		endpoint = reimportEndpoint(endpoint)
+	}
+
 	// Create HTTP request
-	req, err := http.NewRequest("POST", b.endpoint, body)
+	req, err := http.NewRequest("POST", endpoint, body)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -388,33 +828,78 @@ func (b *UploadRequestBuilder) Build() (*http.Request, error) {
 	if b.authToken != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Token %s", b.authToken))
 	}
+	if b.idemKey != "" {
+		req.Header.Set(idempotencyKeyHeader, b.idemKey)
+	}
 
 	return req, nil
 }
 
-// Send builds and sends the request
+// Send builds and sends the request, retrying transient failures when
+// WithRetry is set
 func (b *UploadRequestBuilder) Send() error {
+	// The file reader is consumed by each Build, so keep a copy to rebuild
+	// the request for retries
+	var fileData []byte
+	if b.attempts > 1 && b.file != nil {
+		data, err := io.ReadAll(b.file)
+		if err != nil {
+			return fmt.Errorf("reading file data: %w", err)
+		}
+		fileData = data
+	}
+
+	for attempt := 1; ; attempt++ {
+		if fileData != nil {
+			b.file = bytes.NewReader(fileData)
+		}
+		retryable, err := b.send()
+		if err == nil || !retryable || attempt >= b.attempts {
+			return err
+		}
+		log.Printf("  ⏳ Upload of %s failed (attempt %d/%d), retrying in %s: %v", b.filename, attempt, b.attempts, b.retryWait, err)
+		time.Sleep(b.retryWait)
+	}
+}
+
+// send builds and sends the request once. The error is retryable for network
+// errors and 5xx responses, which may succeed once DefectDojo recovers.
+func (b *UploadRequestBuilder) send() (retryable bool, err error) {
 	req, err := b.Build()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Create HTTP client with timeout
 	client := &http.Client{
-		Timeout: b.timeout,
+		Timeout:   b.timeout,
+		Transport: b.transport,
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending request: %w", err)
+		return isTransientNetError(err), fmt.Errorf("sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return resp.StatusCode >= 500, fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
-	return nil
+	return false, nil
+}
+
+// isTransientNetError reports whether a request error is a network failure
+// worth retrying: a timeout, DNS failure, or refused/reset connection. TLS
+// certificate errors and malformed URLs are not.
+func isTransientNetError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr)
 }
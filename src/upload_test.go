@@ -1,10 +1,31 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"allscan/parsers"
 )
@@ -104,6 +125,465 @@ func TestUploadRequestBuilder_Build(t *testing.T) {
 	})
 }
 
+func TestReimportEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+	}{
+		{
+			name:     "import-scan with trailing slash",
+			endpoint: "https://dojo.example.com/api/v2/import-scan/",
+			want:     "https://dojo.example.com/api/v2/reimport-scan/",
+		},
+		{
+			name:     "import-scan without trailing slash",
+			endpoint: "https://dojo.example.com/api/v2/import-scan",
+			want:     "https://dojo.example.com/api/v2/reimport-scan/",
+		},
+		{
+			name:     "already reimport-scan",
+			endpoint: "https://dojo.example.com/api/v2/reimport-scan/",
+			want:     "https://dojo.example.com/api/v2/reimport-scan/",
+		},
+		{
+			name:     "custom endpoint left unchanged",
+			endpoint: "https://proxy.example.com/upload",
+			want:     "https://proxy.example.com/upload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reimportEndpoint(tt.endpoint)
+			if got != tt.want {
+				t.Errorf("reimportEndpoint(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadRequestBuilder_BuildReimport(t *testing.T) {
+	t.Run("reimport rewrites endpoint", func(t *testing.T) {
+		req, err := BuildUploadRequest().
+			WithEndpoint("https://example.com/api/v2/import-scan/").
+			WithFile(strings.NewReader("test data"), "test.json").
+			WithReimport(true).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		if req.URL.String() != "https://example.com/api/v2/reimport-scan/" {
+			t.Errorf("URL = %q, want %q", req.URL.String(), "https://example.com/api/v2/reimport-scan/")
+		}
+	})
+
+	t.Run("reimport disabled keeps endpoint", func(t *testing.T) {
+		req, err := BuildUploadRequest().
+			WithEndpoint("https://example.com/api/v2/import-scan/").
+			WithFile(strings.NewReader("test data"), "test.json").
+			WithReimport(false).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		if req.URL.String() != "https://example.com/api/v2/import-scan/" {
+			t.Errorf("URL = %q, want %q", req.URL.String(), "https://example.com/api/v2/import-scan/")
+		}
+	})
+
+	t.Run("reimport fields identify the test", func(t *testing.T) {
+		config := &Config{Global: GlobalConfig{Dojo: DojoConfig{Reimport: true}}}
+		result := ScanResult{
+			Scanner:      "grype",
+			Repository:   "https://github.com/acme/widget",
+			DojoScanType: "Anchore Grype",
+			BranchTag:    "v1.2.3",
+		}
+		req, err := BuildUploadRequest().
+			WithEndpoint("https://example.com/api/v2/import-scan/").
+			WithFile(strings.NewReader("test data"), "test.json").
+			WithReimport(config.Global.Dojo.Reimport).
+			AddFields(buildUploadFields(config, result, nil)).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm() error = %v", err)
+		}
+		want := map[string]string{
+			"product_name":        "acme/widget",
+			"engagement_name":     "acme/widget-grype",
+			"scan_type":           "Anchore Grype",
+			"auto_create_context": "true",
+			"version":             "v1.2.3",
+		}
+		for field, value := range want {
+			if got := req.FormValue(field); got != value {
+				t.Errorf("field %s = %q, want %q", field, got, value)
+			}
+		}
+	})
+}
+
+func TestBuildUploadFieldsAutoCreateContext(t *testing.T) {
+	policy := map[string]bool{"Production": false, "Research and Development": true}
+	result := ScanResult{Scanner: "grype", Repository: "https://github.com/acme/widget", DojoScanType: "Anchore Grype"}
+
+	tests := []struct {
+		name        string
+		policy      map[string]bool
+		productType string // --product-type
+		want        string
+	}{
+		{name: "no policy auto-creates", want: "true"},
+		{name: "default product type allowed", policy: policy, want: "true"},
+		{name: "production requires an existing product", policy: policy, productType: "Production", want: "false"},
+		{name: "unlisted product type auto-creates", policy: policy, productType: "Staging", want: "true"},
+		{name: "policy for the default product type", policy: map[string]bool{"Research and Development": false}, want: "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Global: GlobalConfig{ProductTypeOverride: tt.productType, Dojo: DojoConfig{AutoCreateContext: tt.policy}}}
+			fields := buildUploadFields(config, result, nil)
+			if got := fields["auto_create_context"]; got != tt.want {
+				t.Errorf("auto_create_context = %q, want %q", got, tt.want)
+			}
+			wantType := tt.productType
+			if wantType == "" {
+				wantType = defaultProductTypeName
+			}
+			if got := fields["product_type_name"]; got != wantType {
+				t.Errorf("product_type_name = %q, want %q", got, wantType)
+			}
+		})
+	}
+}
+
+func TestDojoScanTypePerRepo(t *testing.T) {
+	semgrep := ScannerConfig{Name: "semgrep", DojoScanType: "Semgrep JSON Report", RequiredEnv: []string{"ALLSCAN_TEST_UNSET"}}
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		want      string
+	}{
+		{name: "scanner default", want: "Semgrep JSON Report"},
+		{name: "repo override wins", overrides: map[string]string{"semgrep": "Semgrep Pro JSON Report"}, want: "Semgrep Pro JSON Report"},
+		{name: "override for another scanner", overrides: map[string]string{"gosec": "Gosec Scanner"}, want: "Semgrep JSON Report"},
+		{name: "empty override opts out of uploads", overrides: map[string]string{"semgrep": ""}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := RepositoryConfig{URL: "https://github.com/acme/widget", DojoScanTypes: tt.overrides}
+
+			// runScanner stamps the resolved type on every result, even skipped ones
+			result := runScanner(&Config{}, semgrep, repo, t.TempDir(), "abc1234", "main", "", nil, nil)
+			if result.DojoScanType != tt.want {
+				t.Errorf("ScanResult.DojoScanType = %q, want %q", result.DojoScanType, tt.want)
+			}
+			if got := buildUploadFields(&Config{}, result, nil)["scan_type"]; got != tt.want {
+				t.Errorf("scan_type field = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadRequestBuilder_SendRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // response per attempt; the last one repeats
+		attempts     int   // 0 = no WithRetry
+		wantErr      bool
+		wantAttempts int
+	}{
+		{name: "no retry by default", statuses: []int{503, 201}, wantErr: true, wantAttempts: 1},
+		{name: "5xx retried until success", statuses: []int{503, 502, 201}, attempts: 3, wantAttempts: 3},
+		{name: "5xx gives up after max attempts", statuses: []int{500}, attempts: 2, wantErr: true, wantAttempts: 2},
+		{name: "4xx not retried", statuses: []int{400}, attempts: 3, wantErr: true, wantAttempts: 1},
+		{name: "success needs no retry", statuses: []int{201}, attempts: 3, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				file, _, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("attempt without file: %v", err)
+					return
+				}
+				body := mustReadAll(t, file)
+				mu.Lock()
+				bodies = append(bodies, body)
+				status := tt.statuses[min(len(bodies), len(tt.statuses))-1]
+				mu.Unlock()
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			builder := BuildUploadRequest().
+				WithEndpoint(server.URL).
+				WithFile(strings.NewReader(`{"matches": []}`), "grype.json")
+			if tt.attempts > 0 {
+				builder.WithRetry(tt.attempts, 0)
+			}
+			err := builder.Send()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(bodies) != tt.wantAttempts {
+				t.Fatalf("server got %d attempts, want %d", len(bodies), tt.wantAttempts)
+			}
+			for i, body := range bodies {
+				if body != `{"matches": []}` {
+					t.Errorf("attempt %d uploaded %q, want the full file", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+// mustReadAll reads r to the end, failing the test on error
+func mustReadAll(t *testing.T, r io.Reader) string {
+	t.Helper()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestIsTransientNetError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection reset", err: &url.Error{Op: "Post", URL: "https://dojo", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, want: true},
+		{name: "DNS failure", err: &url.Error{Op: "Post", URL: "https://dojo", Err: &net.DNSError{Err: "no such host", Name: "dojo"}}, want: true},
+		{name: "client timeout", err: &url.Error{Op: "Post", URL: "https://dojo", Err: context.DeadlineExceeded}, want: true},
+		{name: "certificate error", err: &url.Error{Op: "Post", URL: "https://dojo", Err: x509.UnknownAuthorityError{}}, want: false},
+		{name: "unsupported scheme", err: &url.Error{Op: "Post", URL: "ftp://dojo", Err: errors.New("unsupported protocol scheme")}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientNetError(tt.err); got != tt.want {
+				t.Errorf("isTransientNetError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTLSTransport(t *testing.T) {
+	// Self-signed TLS server standing in for a DefectDojo instance
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	caPath := filepath.Join(t.TempDir(), "ca.crt")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(transport http.RoundTripper) error {
+		return BuildUploadRequest().
+			WithEndpoint(server.URL).
+			WithFile(strings.NewReader("{}"), "test.json").
+			WithTransport(transport).
+			Send()
+	}
+
+	t.Run("default config rejects self-signed cert", func(t *testing.T) {
+		transport, err := newTLSTransport(&GlobalConfig{})
+		if err != nil {
+			t.Fatalf("newTLSTransport() error = %v", err)
+		}
+		if err := send(transport); err == nil {
+			t.Error("Send() error = nil, want certificate verification error")
+		}
+	})
+
+	t.Run("custom CA cert trusts server", func(t *testing.T) {
+		transport, err := newTLSTransport(&GlobalConfig{TLSCACert: caPath})
+		if err != nil {
+			t.Fatalf("newTLSTransport() error = %v", err)
+		}
+		if transport.TLSClientConfig.InsecureSkipVerify {
+			t.Error("InsecureSkipVerify = true, want false")
+		}
+		if err := send(transport); err != nil {
+			t.Errorf("Send() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("skip verify accepts self-signed cert", func(t *testing.T) {
+		transport, err := newTLSTransport(&GlobalConfig{TLSSkipVerify: true})
+		if err != nil {
+			t.Fatalf("newTLSTransport() error = %v", err)
+		}
+		if err := send(transport); err != nil {
+			t.Errorf("Send() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("missing CA file returns error", func(t *testing.T) {
+		_, err := newTLSTransport(&GlobalConfig{TLSCACert: filepath.Join(t.TempDir(), "missing.crt")})
+		if err == nil {
+			t.Error("newTLSTransport() error = nil, want error")
+		}
+	})
+
+	t.Run("invalid PEM returns error", func(t *testing.T) {
+		badPath := filepath.Join(t.TempDir(), "bad.crt")
+		if err := os.WriteFile(badPath, []byte("not a certificate"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := newTLSTransport(&GlobalConfig{TLSCACert: badPath})
+		if err == nil {
+			t.Error("newTLSTransport() error = nil, want error")
+		}
+	})
+}
+
+// writeTestClientCert writes a self-signed client certificate and its key as
+// PEM files in dir and returns their paths and the parsed certificate
+func writeTestClientCert(t *testing.T, dir string) (certPath, keyPath string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "allscan-uploader"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath = filepath.Join(dir, "client.crt")
+	keyPath = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath, cert
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath, clientCert := writeTestClientCert(t, dir)
+
+	// DefectDojo instance behind mTLS: only the test client certificate is accepted
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caPath := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	request := func() *UploadRequestBuilder {
+		return BuildUploadRequest().WithEndpoint(server.URL).WithFile(strings.NewReader("{}"), "test.json")
+	}
+
+	t.Run("config transport loads the client cert", func(t *testing.T) {
+		transport, err := newTLSTransport(&GlobalConfig{TLSCACert: caPath, TLSClientCert: certPath, TLSClientKey: keyPath})
+		if err != nil {
+			t.Fatalf("newTLSTransport() error = %v", err)
+		}
+		certs := transport.TLSClientConfig.Certificates
+		if len(certs) != 1 || !bytes.Equal(certs[0].Certificate[0], clientCert.Raw) {
+			t.Fatalf("transport certificates = %d, want the client certificate", len(certs))
+		}
+		if err := request().WithTransport(transport).Send(); err != nil {
+			t.Errorf("Send() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("builder with root CA and client cert", func(t *testing.T) {
+		builder := request().WithRootCA(caPath).WithClientCert(certPath, keyPath)
+		certs := builder.transport.(*http.Transport).TLSClientConfig.Certificates
+		if len(certs) != 1 || !bytes.Equal(certs[0].Certificate[0], clientCert.Raw) {
+			t.Fatalf("transport certificates = %d, want the client certificate", len(certs))
+		}
+		if err := builder.Send(); err != nil {
+			t.Errorf("Send() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("builder without a client cert is rejected", func(t *testing.T) {
+		if err := request().WithRootCA(caPath).Send(); err == nil {
+			t.Error("Send() error = nil, want handshake failure")
+		}
+	})
+
+	t.Run("without a client cert the server rejects the upload", func(t *testing.T) {
+		transport, err := newTLSTransport(&GlobalConfig{TLSCACert: caPath})
+		if err != nil {
+			t.Fatalf("newTLSTransport() error = %v", err)
+		}
+		if err := request().WithTransport(transport).Send(); err == nil {
+			t.Error("Send() error = nil, want handshake failure")
+		}
+	})
+
+	t.Run("key not matching the cert returns error", func(t *testing.T) {
+		otherCert, _, _ := writeTestClientCert(t, t.TempDir())
+		if err := request().WithClientCert(otherCert, keyPath).Send(); err == nil {
+			t.Error("Send() error = nil, want client certificate error")
+		}
+		if _, err := newTLSTransport(&GlobalConfig{TLSClientCert: otherCert, TLSClientKey: keyPath}); err == nil {
+			t.Error("newTLSTransport() error = nil, want client certificate error")
+		}
+	})
+}
+
+func TestValidateClientCert(t *testing.T) {
+	tests := []struct {
+		cert, key string
+		wantErr   bool
+	}{
+		{"", "", false},
+		{"client.crt", "client.key", false},
+		{"client.crt", "", true},
+		{"", "client.key", true},
+	}
+	for _, tt := range tests {
+		if err := validateClientCert(tt.cert, tt.key); (err != nil) != tt.wantErr {
+			t.Errorf("validateClientCert(%q, %q) error = %v, wantErr %v", tt.cert, tt.key, err, tt.wantErr)
+		}
+	}
+}
+
 func TestNdjsonToJSONArray(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -295,3 +775,303 @@ func TestComputeReachabilityTags(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadResultsConcurrency(t *testing.T) {
+	t.Setenv("VULN_MGMT_API_TOKEN", "test-token")
+
+	tests := []struct {
+		name        string
+		concurrency int
+		files       int
+		failing     int // uploads rejected by the server
+	}{
+		{"serial by default", 0, 4, 0},
+		{"bounded worker pool", 3, 8, 0},
+		{"failures counted per upload", 2, 5, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := max(tt.concurrency, 1)
+			var inFlight, peak, arrived, received atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				arrived.Add(1)
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				// Hold the request until the pool is saturated (or every upload has
+				// arrived) so the peak is deterministic
+				deadline := time.Now().Add(2 * time.Second)
+				for inFlight.Load() < int32(limit) && arrived.Load() < int32(tt.files) && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+				if received.Add(1) <= int32(tt.failing) {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			t.Cleanup(server.Close)
+
+			dir := t.TempDir()
+			var results []ScanResult
+			for i := 0; i < tt.files; i++ {
+				path := filepath.Join(dir, fmt.Sprintf("result-%d.json", i))
+				if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				results = append(results, ScanResult{
+					Scanner:      "grype",
+					Repository:   "https://github.com/org/repo",
+					OutputPath:   path,
+					Success:      true,
+					DojoScanType: "Anchore Grype",
+				})
+			}
+			config := &Config{Global: GlobalConfig{
+				UploadEndpoint: server.URL,
+				Dojo:           DojoConfig{UploadConcurrency: tt.concurrency},
+			}}
+
+			uploadResults(config, results, nil)
+
+			if got := received.Load(); got != int32(tt.files) {
+				t.Errorf("server received %d uploads, want %d", got, tt.files)
+			}
+			if got := peak.Load(); got != int32(limit) {
+				t.Errorf("peak in-flight uploads = %d, want %d", got, limit)
+			}
+		})
+	}
+}
+
+func TestUploadResultsSBOM(t *testing.T) {
+	t.Setenv("VULN_MGMT_API_TOKEN", "test-token")
+
+	type upload struct {
+		scanType, engagement, filename string
+	}
+	tests := []struct {
+		name       string
+		sbomUpload bool
+		want       []upload
+	}{
+		{
+			name:       "SBOMs uploaded once per target after scanner results",
+			sbomUpload: true,
+			want: []upload{
+				{"Anchore Grype", "org/repo-grype", "grype.json"},
+				{"Gosec Scanner", "org/repo-gosec", "gosec.json"},
+				{"CycloneDX Scan", "org/repo-sbom", "repo_abc1234.cdx.json"},
+			},
+		},
+		{
+			name:       "SBOMs not uploaded unless enabled",
+			sbomUpload: false,
+			want: []upload{
+				{"Anchore Grype", "org/repo-grype", "grype.json"},
+				{"Gosec Scanner", "org/repo-gosec", "gosec.json"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got []upload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("ParseMultipartForm() error = %v", err)
+				}
+				_, header, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("FormFile() error = %v", err)
+					return
+				}
+				mu.Lock()
+				got = append(got, upload{r.FormValue("scan_type"), r.FormValue("engagement_name"), header.Filename})
+				mu.Unlock()
+				w.WriteHeader(http.StatusCreated)
+			}))
+			t.Cleanup(server.Close)
+
+			dir := t.TempDir()
+			sbomPath := filepath.Join(dir, "repo_abc1234.cdx.json")
+			for _, name := range []string{"grype.json", "gosec.json", "repo_abc1234.cdx.json"} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			results := []ScanResult{
+				{Scanner: "grype", Repository: "https://github.com/org/repo", OutputPath: filepath.Join(dir, "grype.json"),
+					Success: true, DojoScanType: "Anchore Grype", SBOMPath: sbomPath},
+				{Scanner: "gosec", Repository: "https://github.com/org/repo", OutputPath: filepath.Join(dir, "gosec.json"),
+					Success: true, DojoScanType: "Gosec Scanner", SBOMPath: sbomPath},
+			}
+			config := &Config{Global: GlobalConfig{UploadEndpoint: server.URL, SBOMUpload: tt.sbomUpload}}
+
+			uploadResults(config, results, nil)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("uploads = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("upload %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSelectUploadTargets(t *testing.T) {
+	targets := []UploadTarget{
+		{Endpoint: "https://dojo-all/"},
+		{Endpoint: "https://dojo-prod/", RepoFilter: []string{"github.com/org/*"}},
+		{Endpoint: "https://dojo-dev/", RepoFilter: []string{"https://github.com/sandbox/*", "gitlab.com/team/*/app"}},
+	}
+
+	tests := []struct {
+		repoURL string
+		want    []string
+	}{
+		{"https://github.com/org/api", []string{"https://dojo-all/", "https://dojo-prod/"}},
+		{"git@github.com:org/api.git", []string{"https://dojo-all/", "https://dojo-prod/"}},
+		{"https://github.com/sandbox/demo", []string{"https://dojo-all/", "https://dojo-dev/"}},
+		{"https://gitlab.com/team/web/app", []string{"https://dojo-all/", "https://dojo-dev/"}},
+		{"https://github.com/other/repo", []string{"https://dojo-all/"}},
+		{"https://github.com/org/nested/repo", []string{"https://dojo-all/"}}, // * does not cross /
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			var got []string
+			for _, target := range selectUploadTargets(targets, tt.repoURL) {
+				got = append(got, target.Endpoint)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("selectUploadTargets(%q) = %v, want %v", tt.repoURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadTargets(t *testing.T) {
+	if got := uploadTargets(GlobalConfig{}); len(got) != 0 {
+		t.Errorf("uploadTargets() with no endpoint = %+v, want none", got)
+	}
+	got := uploadTargets(GlobalConfig{UploadEndpoint: "https://dojo/"})
+	if len(got) != 1 || got[0].Endpoint != "https://dojo/" || got[0].tokenEnvVar() != "VULN_MGMT_API_TOKEN" {
+		t.Errorf("uploadTargets() from upload_endpoint = %+v, want one target using VULN_MGMT_API_TOKEN", got)
+	}
+	targets := []UploadTarget{{Endpoint: "https://prod/", EnvTokenVar: "PROD_TOKEN"}}
+	got = uploadTargets(GlobalConfig{UploadEndpoint: "https://dojo/", UploadTargets: targets})
+	if len(got) != 1 || got[0].Endpoint != "https://prod/" || got[0].tokenEnvVar() != "PROD_TOKEN" {
+		t.Errorf("uploadTargets() = %+v, want upload_targets to replace upload_endpoint", got)
+	}
+}
+
+func TestUploadResultsMultipleTargets(t *testing.T) {
+	t.Setenv("PROD_TOKEN", "prod-token")
+	t.Setenv("DEV_TOKEN", "dev-token")
+	t.Setenv("STAGING_TOKEN", "")
+
+	var mu sync.Mutex
+	got := make(map[string][]string) // instance -> "token product" per upload
+	newServer := func(name string, status int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("ParseMultipartForm() error = %v", err)
+			}
+			mu.Lock()
+			got[name] = append(got[name], r.Header.Get("Authorization")+" "+r.FormValue("product_name"))
+			mu.Unlock()
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	prod := newServer("prod", http.StatusInternalServerError) // failures must not block dev
+	dev := newServer("dev", http.StatusCreated)
+	staging := newServer("staging", http.StatusCreated)
+
+	dir := t.TempDir()
+	var results []ScanResult
+	for _, repo := range []string{"https://github.com/org/api", "https://github.com/sandbox/demo"} {
+		path := filepath.Join(dir, filepath.Base(repo)+".json")
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, ScanResult{Scanner: "grype", Repository: repo, OutputPath: path,
+			Success: true, DojoScanType: "Anchore Grype"})
+	}
+	config := &Config{Global: GlobalConfig{UploadTargets: []UploadTarget{
+		{Endpoint: prod.URL, EnvTokenVar: "PROD_TOKEN", RepoFilter: []string{"github.com/org/*"}},
+		{Endpoint: staging.URL, EnvTokenVar: "STAGING_TOKEN"}, // token unset: skipped
+		{Endpoint: dev.URL, EnvTokenVar: "DEV_TOKEN"},
+	}}}
+
+	uploadResults(config, results, nil)
+
+	want := map[string][]string{
+		"prod": {"Token prod-token org/api"},
+		"dev":  {"Token dev-token org/api", "Token dev-token sandbox/demo"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("uploads = %v, want %v", got, want)
+	}
+	for name, uploads := range want {
+		if strings.Join(got[name], ",") != strings.Join(uploads, ",") {
+			t.Errorf("%s uploads = %v, want %v", name, got[name], uploads)
+		}
+	}
+}
+
+func TestRunUploadsCounts(t *testing.T) {
+	jobs := make([]uploadJob, 10)
+	for i := range jobs {
+		jobs[i].result.OutputPath = fmt.Sprintf("result-%d.json", i)
+	}
+	success, fail := runUploads(jobs, 4, nil, func(job uploadJob) error {
+		if strings.HasSuffix(job.result.OutputPath, "-3.json") || strings.HasSuffix(job.result.OutputPath, "-7.json") {
+			return fmt.Errorf("rejected")
+		}
+		return nil
+	})
+	if success != 8 || fail != 2 {
+		t.Errorf("runUploads() = (%d, %d), want (8, 2)", success, fail)
+	}
+}
+
+func TestUploadLimiter(t *testing.T) {
+	if newUploadLimiter(0) != nil {
+		t.Error("newUploadLimiter(0) should be nil (unlimited)")
+	}
+	var unlimited *uploadLimiter
+	unlimited.wait() // must not block or panic
+
+	// 4 uploads at 50/s across 4 workers: starts spaced 20ms apart
+	jobs := make([]uploadJob, 4)
+	begin := time.Now()
+	startTimes := make(chan time.Time, len(jobs))
+	runUploads(jobs, 4, newUploadLimiter(50), func(uploadJob) error {
+		startTimes <- time.Now()
+		return nil
+	})
+	close(startTimes)
+
+	var last time.Time
+	for ts := range startTimes {
+		if ts.After(last) {
+			last = ts
+		}
+	}
+	if elapsed := last.Sub(begin); elapsed < 55*time.Millisecond {
+		t.Errorf("last upload started after %v, want at least 60ms with a 50/s limit", elapsed)
+	}
+}
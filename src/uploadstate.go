@@ -0,0 +1,130 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// idempotencyKeyHeader carries an upload's idempotency key, so a proxy or
+// DefectDojo instance that honors it can drop a duplicate retry
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// uploadStateMaxAge is how long confirmed uploads are remembered. Keys include
+// the scan date, so older entries can't match a new upload anyway.
+const uploadStateMaxAge = 7 * 24 * time.Hour
+
+// UploadState is the persistent record of confirmed uploads configured with
+// global.dojo.upload_state. An upload whose idempotency key was already
+// confirmed by an endpoint is skipped, so rerunning a partly failed upload
+// doesn't import the same results twice. A nil *UploadState records nothing.
+type UploadState struct {
+	UpdatedAt time.Time                       `json:"updated_at"`
+	Uploaded  map[string]map[string]time.Time `json:"uploaded"` // endpoint → idempotency key → confirmed at
+
+	mu   sync.Mutex
+	path string
+}
+
+// uploadIdempotencyKey identifies an upload by the uploaded file contents and
+// the product, engagement and scan date it is imported into
+func uploadIdempotencyKey(data []byte, fields map[string]string) string {
+	h := sha256.New()
+	h.Write(data)
+	for _, name := range []string{"product_name", "engagement_name", "scan_date"} {
+		h.Write([]byte{0})
+		h.Write([]byte(fields[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadUploadState reads the upload state at path. A missing file yields an
+// empty state, as on the first run; an empty path yields nil.
+func loadUploadState(path string) (*UploadState, error) {
+	if path == "" {
+		return nil, nil
+	}
+	state := &UploadState{Uploaded: make(map[string]map[string]time.Time), path: path}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading upload state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing upload state: %w", err)
+	}
+	if state.Uploaded == nil {
+		state.Uploaded = make(map[string]map[string]time.Time)
+	}
+	state.prune(time.Now().UTC())
+	return state, nil
+}
+
+// confirmed reports whether endpoint already accepted the upload with key
+func (s *UploadState) confirmed(endpoint, key string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.Uploaded[endpoint][key]
+	return ok
+}
+
+// record marks the upload with key as accepted by endpoint and saves the
+// state right away, so the record survives an interrupted run
+func (s *UploadState) record(endpoint, key string, now time.Time) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Uploaded[endpoint] == nil {
+		s.Uploaded[endpoint] = make(map[string]time.Time)
+	}
+	s.Uploaded[endpoint][key] = now
+	s.UpdatedAt = now
+	return s.save()
+}
+
+// prune forgets uploads confirmed more than uploadStateMaxAge before now
+func (s *UploadState) prune(now time.Time) {
+	for endpoint, keys := range s.Uploaded {
+		for key, at := range keys {
+			if now.Sub(at) > uploadStateMaxAge {
+				delete(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			delete(s.Uploaded, endpoint)
+		}
+	}
+}
+
+// save writes the state to its path, creating the parent directory if needed.
+// The caller holds s.mu.
+func (s *UploadState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding upload state: %w", err)
+	}
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("creating upload state directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("writing upload state: %w", err)
+	}
+	return nil
+}
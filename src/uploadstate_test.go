@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUploadIdempotencyKey(t *testing.T) {
+	fields := map[string]string{
+		"product_name":    "org/repo",
+		"engagement_name": "org/repo-grype",
+		"scan_date":       "2026-01-02",
+		"scan_type":       "Anchore Grype",
+	}
+	base := uploadIdempotencyKey([]byte("{}"), fields)
+	if len(base) != 64 {
+		t.Fatalf("key %q is not a hex SHA-256", base)
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		field  string // field changed from the base fields, if any
+		value  string
+		sameAs bool
+	}{
+		{name: "same inputs", data: "{}", sameAs: true},
+		{name: "fields outside the key", data: "{}", field: "scan_type", value: "Grype JSON", sameAs: true},
+		{name: "file contents", data: `{"matches": []}`},
+		{name: "product", data: "{}", field: "product_name", value: "org/other"},
+		{name: "engagement", data: "{}", field: "engagement_name", value: "org/repo-trivy"},
+		{name: "scan date", data: "{}", field: "scan_date", value: "2026-01-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := make(map[string]string)
+			for name, value := range fields {
+				changed[name] = value
+			}
+			if tt.field != "" {
+				changed[tt.field] = tt.value
+			}
+			got := uploadIdempotencyKey([]byte(tt.data), changed)
+			if (got == base) != tt.sameAs {
+				t.Errorf("key equal to base = %v, want %v", got == base, tt.sameAs)
+			}
+		})
+	}
+}
+
+func TestUploadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "uploads.json")
+	now := time.Now().UTC()
+
+	state, err := loadUploadState(path)
+	if err != nil {
+		t.Fatalf("loadUploadState() on a missing file: %v", err)
+	}
+	if state.confirmed("https://dojo/", "key1") {
+		t.Error("empty state confirms an upload")
+	}
+	if err := state.record("https://dojo/", "key1", now); err != nil {
+		t.Fatalf("record() error = %v", err)
+	}
+	if err := state.record("https://dojo/", "stale", now.Add(-uploadStateMaxAge-time.Hour)); err != nil {
+		t.Fatalf("record() error = %v", err)
+	}
+
+	reloaded, err := loadUploadState(path)
+	if err != nil {
+		t.Fatalf("loadUploadState() error = %v", err)
+	}
+	tests := []struct {
+		endpoint, key string
+		want          bool
+	}{
+		{"https://dojo/", "key1", true},
+		{"https://other-dojo/", "key1", false}, // confirmed per endpoint
+		{"https://dojo/", "key2", false},
+		{"https://dojo/", "stale", false}, // pruned on load
+	}
+	for _, tt := range tests {
+		if got := reloaded.confirmed(tt.endpoint, tt.key); got != tt.want {
+			t.Errorf("confirmed(%q, %q) = %v, want %v", tt.endpoint, tt.key, got, tt.want)
+		}
+	}
+
+	if state, err := loadUploadState(""); state != nil || err != nil {
+		t.Errorf("loadUploadState(\"\") = %v, %v; want nil, nil", state, err)
+	}
+	var disabled *UploadState
+	if disabled.confirmed("https://dojo/", "key1") || disabled.record("https://dojo/", "key1", now) != nil {
+		t.Error("nil state should confirm nothing and record without error")
+	}
+}
+
+func TestUploadResultsSkipsConfirmedUploads(t *testing.T) {
+	t.Setenv("VULN_MGMT_API_TOKEN", "test-token")
+
+	var received, failing atomic.Int32
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		if failing.Load() > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "grype.json")
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results := []ScanResult{{Scanner: "grype", Repository: "https://github.com/org/repo", OutputPath: path,
+		Success: true, DojoScanType: "Anchore Grype"}}
+	config := &Config{Global: GlobalConfig{
+		UploadEndpoint: server.URL,
+		Dojo:           DojoConfig{UploadState: filepath.Join(dir, "upload-state.json")},
+	}}
+
+	// A failed upload is not recorded, so the next run tries again
+	failing.Store(1)
+	uploadResults(config, results, nil)
+	failing.Store(0)
+	uploadResults(config, results, nil)
+	if got := received.Load(); got != 2 {
+		t.Fatalf("server received %d uploads after a failure and a success, want 2", got)
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("idempotency keys = %q, want the same non-empty key on both tries", keys)
+	}
+
+	// Once confirmed, the same file is not uploaded again
+	uploadResults(config, results, nil)
+	if got := received.Load(); got != 2 {
+		t.Errorf("server received %d uploads, want the confirmed upload skipped", got)
+	}
+
+	// Changed results have a new key and are uploaded
+	if err := os.WriteFile(path, []byte(`{"matches": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	uploadResults(config, results, nil)
+	if got := received.Load(); got != 3 {
+		t.Errorf("server received %d uploads, want changed results uploaded", got)
+	}
+}